@@ -1,6 +1,7 @@
 // Command router 演示 pkg/router 的栈式导航：一个收件箱列表，点邮件 Push 进详情屏，
 // 详情屏可「返回」(Pop)、看「下一封」(Replace，深度不变)。左上角标题栏根据栈深度
-// 显示返回按钮。
+// 显示返回按钮。头像是 SharedElement：进出详情屏时从列表行飞到详情页头部。
+// 收件箱与「设置」是两个标签（router.Tabs）：切到设置再切回，收件箱的栈原样还在。
 //
 //	go run ./example/router
 package main
@@ -32,13 +33,22 @@ func App(_ struct{}) *ui.Node {
 	th := ui.LightTheme
 	return ui.ThemeProvider(th,
 		ui.Div(ui.Style(ui.Fill, ui.Bg(th.Background), ui.TextColor(th.Foreground)),
-			router.Router(router.Props{
-				Initial: "list",
-				Screens: map[string]router.Screen{
-					"list":   listScreen,
-					"detail": detailScreen,
-				},
-			})))
+			router.Tabs(router.TabsProps{Tabs: []router.Tab{
+				{Name: "inbox", Label: "收件箱", Screen: inboxTab},
+				{Name: "settings", Label: "设置", Screen: settingsScreen},
+			}})))
+}
+
+// inboxTab 是收件箱标签：一段自己的导航栈。
+func inboxTab(_ router.Params) *ui.Node {
+	return router.Router(router.Props{
+		Initial: "list",
+		Screens: map[string]router.Screen{
+			"list":   listScreen,
+			"detail": detailScreen,
+		},
+		Transition: router.TransitionSlide, // 详情屏从右侧推入，左边缘右滑返回
+	})
 }
 
 // ---------- 列表屏 ----------
@@ -70,7 +80,7 @@ func emailRow(p rowProps) *ui.Node {
 	}
 	return ui.VStack(0,
 		ui.HStack(12, ui.Style(ui.PaddingXY(20, 14), ui.Bg(bg)), ui.OnClick(p.onOpen), ia,
-			shadcn.Avatar(p.e.initials, 40),
+			router.SharedElement("avatar-"+p.e.id, shadcn.Avatar(p.e.initials, 40)),
 			ui.VStack(2, ui.Text(p.e.from, ui.FontSize(14), ui.Medium), shadcn.TextMuted(p.e.subject, 13)),
 			ui.Spacer(),
 			ui.Icon(ui.IconChevronRight, 16, shadcn.MutedColor())),
@@ -92,7 +102,8 @@ func detailScreen(p router.Params) *ui.Node {
 
 	body := ui.VStack(18, ui.Style(ui.PaddingXY(24, 20)),
 		ui.Text(e.subject, ui.FontSize(22), ui.Semibold),
-		ui.HStack(12, shadcn.Avatar(e.initials, 40),
+		// 与列表行里同标签的头像：Push/Pop 时头像在两屏之间飞过去。
+		ui.HStack(12, router.SharedElement("avatar-"+e.id, shadcn.Avatar(e.initials, 56)),
 			ui.VStack(2, ui.Text(e.from, ui.FontSize(14), ui.Medium), shadcn.TextMuted("发送至：我", 12))),
 		shadcn.Separator(shadcn.SeparatorProps{}),
 		ui.Text(e.body, ui.FontSize(14)),
@@ -108,6 +119,19 @@ func detailScreen(p router.Params) *ui.Node {
 		body)
 }
 
+// ---------- 设置标签 ----------
+
+// settingsScreen 的开关状态在切换标签后保留（标签切走时只是隐藏）。
+func settingsScreen(_ router.Params) *ui.Node {
+	notify, setNotify := ui.UseState(true)
+	return screen(
+		topBar(ui.Text("设置", ui.FontSize(20), ui.Semibold)),
+		ui.VStack(12, ui.Style(ui.PaddingXY(24, 20)),
+			ui.HStack(12,
+				shadcn.Switch(shadcn.SwitchProps{Checked: notify, OnChange: setNotify, Label: "新邮件通知"}),
+				ui.Text("新邮件通知", ui.FontSize(14)))))
+}
+
 func findEmail(id string) (email, int) {
 	for i, e := range inbox {
 		if e.id == id {
@@ -121,7 +145,8 @@ func findEmail(id string) (email, int) {
 
 // screen 是标准屏布局：固定顶栏 + 分隔线 + 可滚动内容。
 func screen(bar, content *ui.Node) *ui.Node {
-	return ui.Div(ui.Style(ui.Column, ui.Fill),
+	// 自带背景：切屏动画期间两屏叠放，上层屏要遮住下层。
+	return ui.Div(ui.Style(ui.Column, ui.Fill, ui.Bg(ui.UseTheme().Background)),
 		bar,
 		shadcn.Separator(shadcn.SeparatorProps{}),
 		ui.ScrollView(ui.Style(ui.Grow(1)), content))
@@ -20,9 +20,32 @@
 //	nav.Pop()                                      // 返回
 //	nav.Replace("home", nil)                       // 原地替换
 //	r := router.UseRoute()                         // r.Name, r.Params["id"]
+//
+// 两屏里同标签的 SharedElement 在切屏时会从旧屏的位置飞到新屏的位置（共享元素过渡）：
+//
+//	router.SharedElement("avatar-"+id, shadcn.Avatar(initials, 40)) // 列表屏
+//	router.SharedElement("avatar-"+id, shadcn.Avatar(initials, 96)) // 详情屏
+//
+// Props.Transition 给切屏加动画（Slide 推入、Fade 淡入、Modal 底部升起），Transitions
+// 按屏名覆盖。过渡期间新旧两屏同时挂载、各自的 UseRoute 读到自己的路由；Slide 屏可从
+// 左边缘右滑返回、Modal 屏可从顶边下拉关闭，进度跟手，松手过半才真正出栈。
+//
+// 除了栈，还有两种在几项之间切换的导航器：Tabs（底部标签栏）与 Drawer（shadcn Sheet
+// 抽屉菜单）。打开过的项切走后只是隐藏，其中的栈与屏内状态都保留。导航器可以互相嵌套，
+// 处理不了的动作向外冒泡；UseNavigate(name) 可直接取外层某个具名导航器：
+//
+//	router.Tabs(router.TabsProps{Tabs: []router.Tab{
+//	    {Name: "inbox", Label: "收件箱", Screen: inboxStack}, // 返回一个 Router
+//	    {Name: "settings", Label: "设置", Screen: settingsScreen},
+//	}})
+//	router.UseNavigate("root").Push("compose", nil) // 在最外层的栈里入栈
 package router
 
-import ui "github.com/sjm1327605995/tenon/pkg/ui"
+import (
+	"strconv"
+
+	ui "github.com/sjm1327605995/tenon/pkg/ui"
+)
 
 // Params 是一次导航携带的参数。
 type Params map[string]string
@@ -31,6 +54,7 @@ type Params map[string]string
 type Route struct {
 	Name   string
 	Params Params
+	key    int // 屏实例的身份：换屏或有切屏动画时换新，无动画的同屏导航沿用（复用同一 fiber）
 }
 
 // Screen 按参数渲染一个屏。
@@ -38,49 +62,123 @@ type Screen = func(Params) *ui.Node
 
 // Props 配置一个 Router。
 type Props struct {
+	Name    string            // 导航器名字（可选），嵌套时供内层用 UseNavigate(name) 找到它
 	Initial string            // 初始屏名字
 	Params  Params            // 初始屏参数（可选）
 	Screens map[string]Screen // 名字 -> 屏
+
+	SharedMs float32 // SharedElement 飞行时长（毫秒），0 取 DefaultSharedMs
+
+	Transition   Transition            // 切屏动画，默认 TransitionNone（立即切换）；配置了动画的 Router 占满父容器
+	Transitions  map[string]Transition // 按屏名覆盖 Transition：入栈用新屏的，出栈用被关闭屏的
+	TransitionMs float32               // 切屏动画时长（毫秒），0 取 DefaultTransitionMs
+	NoSwipeBack  bool                  // 关闭 Slide/Modal 屏的拖动返回手势
 }
 
 // Navigator 是屏内的导航接口（用 UseNavigate 获取）。其方法改变路由栈并触发重渲染。
+//
+// 导航器可以嵌套（Router、Tabs、Drawer 互相包含），每个导航器记着外层的导航器。
+// 自己处理不了的动作向外冒泡：标签/抽屉导航器上的 Push/Pop 交给最近的外层栈，
+// JumpTo 交给最近一个含该项的标签/抽屉导航器，OpenDrawer 交给最近的抽屉。
 type Navigator struct {
-	stack []Route
-	set   func([]Route)
+	name   string
+	parent *Navigator
+
+	// 栈式导航器（Router）：
+	stack  []Route
+	set    func(navState)
+	shared *sharedRegistry
+	seq    *int // 路由 key 与过渡 id 的发号器
+	style  func(name string) Transition
+
+	// 标签/抽屉导航器（Tabs、Drawer）：stack 只有当前项一个路由，set 为 nil。
+	sw *switcher
 }
 
+// Name 返回导航器的名字（Props.Name 等），未命名为空。
+func (n *Navigator) Name() string { return n.name }
+
+// Parent 返回外层导航器；最外层返回 nil。
+func (n *Navigator) Parent() *Navigator { return n.parent }
+
 // Current 返回栈顶路由；Depth 是栈深度；CanPop 表示能否返回（深度 > 1）。
+// 标签/抽屉导航器的 Current 是当前项，深度恒为 1。
 func (n *Navigator) Current() Route { return n.stack[len(n.stack)-1] }
 func (n *Navigator) Depth() int     { return len(n.stack) }
 func (n *Navigator) CanPop() bool   { return len(n.stack) > 1 }
 
 // Push 入栈一个新屏。
 func (n *Navigator) Push(name string, params Params) {
-	n.set(append(n.clone(), Route{Name: name, Params: params}))
+	if n = n.stackNav(); n != nil {
+		n.navigate(navPush, append(n.clone(), n.route(name, params)))
+	}
 }
 
 // Replace 用一个新屏替换栈顶（不改变深度）。
 func (n *Navigator) Replace(name string, params Params) {
-	s := n.clone()
-	s[len(s)-1] = Route{Name: name, Params: params}
-	n.set(s)
+	if n = n.stackNav(); n != nil {
+		s := n.clone()
+		s[len(s)-1] = n.route(name, params)
+		n.navigate(navPush, s)
+	}
 }
 
 // Pop 返回上一屏（仅当 CanPop 时生效）。
 func (n *Navigator) Pop() {
-	if n.CanPop() {
+	if n = n.stackNav(); n != nil && n.CanPop() {
 		s := n.clone()
-		n.set(s[:len(s)-1])
+		n.navigate(navPop, s[:len(s)-1])
 	}
 }
 
 // PopToRoot 一路返回到栈底屏。
 func (n *Navigator) PopToRoot() {
-	if n.CanPop() {
-		n.set([]Route{n.stack[0]})
+	if n = n.stackNav(); n != nil && n.CanPop() {
+		n.navigate(navPop, []Route{n.stack[0]})
 	}
 }
 
+// stackNav 返回 n 起向外最近的栈式导航器（没有则 nil）。
+func (n *Navigator) stackNav() *Navigator {
+	for ; n != nil; n = n.parent {
+		if n.set != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+// route 构造一个带新 key 的路由。
+func (n *Navigator) route(name string, params Params) Route {
+	return Route{Name: name, Params: params, key: n.next()}
+}
+
+func (n *Navigator) next() int {
+	*n.seq++
+	return *n.seq
+}
+
+// navigate 换上新栈，并按切屏动画设定开始一次过渡：入栈（含替换）用新屏的动画，
+// 出栈用被关闭的屏的动画。
+func (n *Navigator) navigate(kind navKind, stack []Route) {
+	n.shared.depart()
+	st := navState{stack: stack}
+	out, in := n.Current(), stack[len(stack)-1]
+	style := n.style(in.Name)
+	if kind == navPop {
+		style = n.style(out.Name)
+	}
+	if style == TransitionNone && in.Name == out.Name {
+		// 无动画时同屏换参数复用同一 fiber、以新 props 重渲染，屏内 UseState 保留。
+		stack[len(stack)-1].key = out.key
+		in = stack[len(stack)-1]
+	}
+	if style != TransitionNone && in.key != out.key {
+		st.tr = &transition{id: n.next(), kind: kind, style: style, in: in, out: out, to: 1}
+	}
+	n.set(st)
+}
+
 func (n *Navigator) clone() []Route {
 	c := make([]Route, len(n.stack))
 	copy(c, n.stack)
@@ -91,28 +189,115 @@ func (n *Navigator) clone() []Route {
 // UseNavigate 会得到 nil）。
 var navCtx = ui.CreateContext[*Navigator](nil)
 
+// routeCtx 把每个屏自己的路由传给它的子树：切屏动画期间新旧两屏同时挂载，
+// 旧屏读到的仍是它自己的路由，而不是新的栈顶。
+var routeCtx = ui.CreateContext[*Route](nil)
+
 // Router 渲染栈顶屏，并向子树提供导航能力。Router 可嵌套（内层 Provider 会遮蔽外层）。
+// 配置了切屏动画时，屏放在一个占满父容器的裁剪容器里，切屏期间新旧两屏叠放其中；
+// 没有配置时屏直接挂在 Router 的位置上，不额外包容器。
 func Router(p Props) *ui.Node { return ui.Use(routerImpl, p) }
 
 func routerImpl(p Props) *ui.Node {
-	stack, setStack := ui.UseState([]Route{{Name: p.Initial, Params: p.Params}})
-	nav := &Navigator{stack: stack, set: setStack}
-	cur := nav.Current()
+	seq := ui.UseRef(1)
+	st, set := ui.UseState(navState{stack: []Route{{Name: p.Initial, Params: p.Params, key: 1}}})
+	latest := ui.UseRef(navState{})
+	*latest = st
+	reg := ui.UseRef(sharedRegistry{})
+	if reg.live == nil {
+		reg.live = map[string]*sharedEntry{}
+	}
+	reg.ms = p.SharedMs
+	if reg.ms <= 0 {
+		reg.ms = DefaultSharedMs
+	}
+	style := func(name string) Transition {
+		if t, ok := p.Transitions[name]; ok {
+			return t
+		}
+		return p.Transition
+	}
+	nav := &Navigator{name: p.Name, parent: ui.UseContext(navCtx), stack: st.stack, set: set, shared: reg, seq: seq, style: style}
+	ref, box := ui.UseMeasure()
+	at := useTransitionProgress(st.tr, p.TransitionMs, latest, set)
+	swipeDist := ui.UseRef(float32(0))
+
+	// 用 ui.Use 把屏挂成独立子组件，按路由 key 区分身份：换屏时旧屏卸载、新屏挂载，
+	// 各屏 hooks 隔离；同屏换参数（无动画）沿用 key，复用同一 fiber、以新 props 重渲染。
+	// 过渡期间两屏各自保持挂载，动画结束才卸载旧屏。
+	body := func(r Route) *ui.Node {
+		var b *ui.Node
+		if fn := p.Screens[r.Name]; fn != nil {
+			b = ui.Use(fn, r.Params)
+		} else {
+			b = ui.Text(`router: 未注册的路由 "` + r.Name + `"`)
+		}
+		rc := r
+		return routeCtx.Provider(&rc, b)
+	}
+	if !animated(p) {
+		// 没有切屏动画：与不带过渡的布局一致，屏就是 Router 本身占的位置。
+		cur := nav.Current()
+		return navCtx.Provider(nav, ui.Keyed(strconv.Itoa(cur.key), body(cur)), ui.Use(flightsC, reg))
+	}
+	screen := func(r Route, s ui.StyleOpt) *ui.Node {
+		return ui.Keyed(strconv.Itoa(r.key), ui.Div(ui.Style(ui.Fill, s), body(r)))
+	}
+	var screens []*ui.Node
+	if tr := st.tr; tr == nil {
+		screens = []*ui.Node{screen(nav.Current(), ui.Styles())}
+	} else {
+		in, out := tr.styles(at, box)
+		if tr.kind == navPop {
+			screens = []*ui.Node{screen(tr.in, in), screen(tr.out, out)} // 被关闭的屏在上层离场
+		} else {
+			screens = []*ui.Node{screen(tr.out, out), screen(tr.in, in)}
+		}
+	}
+	if !p.NoSwipeBack {
+		screens = append(screens, swipeBack(nav, st, latest, set, box, swipeDist))
+	}
+	// 共享元素的飞行副本画在浮层里，跨过新旧两屏。
+	return navCtx.Provider(nav,
+		ui.Div(append([]*ui.Node{ref, ui.Style(ui.Fill, ui.Clip)}, screens...)...),
+		ui.Use(flightsC, reg))
+}
 
-	screen := p.Screens[cur.Name]
-	if screen == nil {
-		return navCtx.Provider(nav, ui.Text(`router: 未注册的路由 "`+cur.Name+`"`))
+// animated 报告 Router 是否配置了切屏动画（默认或按屏名的任一个不是 TransitionNone）。
+func animated(p Props) bool {
+	if p.Transition != TransitionNone {
+		return true
+	}
+	for _, t := range p.Transitions {
+		if t != TransitionNone {
+			return true
+		}
 	}
-	// 用 ui.Use 把屏挂成独立子组件：切屏时 screen 函数指针变化 -> 旧屏卸载、新屏挂载，
-	// 各屏 hooks 隔离；同屏换参数则复用同一 fiber、以新 props 重渲染。
-	return navCtx.Provider(nav, ui.Use(screen, cur.Params))
+	return false
 }
 
-// UseNavigate 返回当前 Router 的导航器。仅可在 Router 子树内调用。
-func UseNavigate() *Navigator { return ui.UseContext(navCtx) }
+// UseNavigate 返回最近的导航器（Router、Tabs 或 Drawer）。给了 name 时沿外层向上找
+// 同名的导航器，找不到返回 nil；在任何导航器之外调用也返回 nil。
+//
+//	router.UseNavigate("root").Push("settings", nil) // 越过所在标签的栈，在最外层入栈
+func UseNavigate(name ...string) *Navigator {
+	n := ui.UseContext(navCtx)
+	if len(name) == 0 {
+		return n
+	}
+	for ; n != nil; n = n.parent {
+		if n.name == name[0] {
+			return n
+		}
+	}
+	return nil
+}
 
-// UseRoute 返回当前路由（名字 + 参数）。
+// UseRoute 返回所在屏的路由（名字 + 参数）。
 func UseRoute() Route {
+	if r := ui.UseContext(routeCtx); r != nil {
+		return *r
+	}
 	if n := ui.UseContext(navCtx); n != nil {
 		return n.Current()
 	}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"strconv"
 	"testing"
 
 	ui "github.com/sjm1327605995/tenon/pkg/ui"
@@ -45,9 +46,39 @@ func TestStackNavigation(t *testing.T) {
 	}
 }
 
+// 没有切屏动画时：同屏 Replace 复用同一屏实例（屏内状态保留），Router 也不额外包一层
+// 占满父容器的容器，列里排在它下面的兄弟元素位置不变。
+func TestNoTransitionKeepsInstanceAndLayout(t *testing.T) {
+	var nav *Navigator
+	screen := func(p Params) *ui.Node {
+		nav = UseNavigate()
+		n, setN := ui.UseState(0)
+		return ui.Div(ui.Style(ui.Height(50)),
+			ui.Button(ui.OnClick(func() { setN(n + 1) }), ui.Text("n="+strconv.Itoa(n)+" id="+p["id"])))
+	}
+	h := ui.Mount(ui.Div(ui.Style(ui.Column),
+		Router(Props{Initial: "s", Params: Params{"id": "1"}, Screens: map[string]Screen{"s": screen}}),
+		ui.Text("below")), 400, 400)
+
+	clickText(t, h, "n=0 id=1")
+	nav.Replace("s", Params{"id": "2"})
+	h.Step(0)
+	if !h.Root().ByText("n=1 id=2").Exists() {
+		t.Fatalf("同屏 Replace 应保留屏内状态；texts=%v", h.Root().Texts())
+	}
+	if b := h.Root().ByText("below").Bounds(); b.Y != 50 {
+		t.Fatalf("Router 下方的兄弟应紧跟在屏之后：Y=%v", b.Y)
+	}
+}
+
 func clickText(t *testing.T, h *ui.Harness, s string) {
 	t.Helper()
 	q := h.Root().ByText(s)
+	for _, o := range h.Overlays() { // 浮层（如抽屉）盖在主树之上，优先点它
+		if oq := o.ByText(s); oq.Exists() {
+			q = oq
+		}
+	}
 	if !q.Exists() {
 		t.Fatalf("未找到文本 %q", s)
 	}
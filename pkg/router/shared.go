@@ -0,0 +1,202 @@
+package router
+
+import (
+	"strconv"
+
+	ui "github.com/sjm1327605995/tenon/pkg/ui"
+)
+
+// ---- 共享元素过渡 ----
+//
+// 切屏时旧屏卸载、新屏挂载，两屏之间没有视觉上的连续性。SharedElement 给元素打一个
+// 标签：导航（Push/Pop/Replace/PopToRoot）前记下当前屏里每个标签元素的矩形与内容，
+// 新屏里同标签的元素测得位置后，在浮层（Portal）里放一份旧内容的副本，从旧矩形飞到
+// 新矩形；飞行期间新元素隐藏，落地后副本移除、新元素显形。
+//
+// 副本只靠变换运动（与 Animated 的 FLIP 布局动画走同一条变换合成路径）：布局留在起点，
+// 平移 + 缩放补间到终点，飞行中不触发任何重新布局。
+
+// DefaultSharedMs 是共享元素飞行的默认时长（毫秒）。
+const DefaultSharedMs = 300
+
+// sharedEntry 是一个已挂载的 SharedElement：最近测得的矩形与它渲染的内容。
+type sharedEntry struct {
+	tag  string
+	rect ui.Rect
+	node *ui.Node
+	born int // 出现（或换标签）时的导航代数，只有导航后新出现的元素才接住飞行
+	hide func(bool)
+}
+
+// flight 是一次飞行：from 为旧屏元素的矩形，to 指向新屏元素（矩形随其重新测量更新）。
+type flight struct {
+	id   int
+	from ui.Rect
+	node *ui.Node // 旧屏元素的内容，副本渲染它
+	to   *sharedEntry
+	src  func(bool) // 隐藏/显示旧屏元素：切屏动画期间旧屏仍在，飞行中不能留一个原件
+	land func()     // 落地回调：让新元素显形
+}
+
+// sharedRegistry 是一个 Router 的共享元素登记表，跨渲染存活（UseRef）。
+type sharedRegistry struct {
+	live     map[string]*sharedEntry // 当前屏上的标签元素
+	outgoing map[string]sharedEntry  // 最近一次导航前的快照，等新屏同标签元素认领
+	gen      int                     // 导航代数
+	flights  []*flight
+	nextID   int
+	redraw   func() // 让浮层组件重渲染
+	ms       float32
+}
+
+// depart 在导航改栈之前调用：快照当前屏的标签元素。
+func (r *sharedRegistry) depart() {
+	if r == nil {
+		return
+	}
+	r.gen++
+	r.outgoing = make(map[string]sharedEntry, len(r.live))
+	for tag, e := range r.live {
+		if e.rect.W > 0 || e.rect.H > 0 {
+			r.outgoing[tag] = *e
+		}
+	}
+}
+
+// awaiting 报告 e 是否在等一次飞行（导航后新出现、旧屏有同标签元素）。
+func (r *sharedRegistry) awaiting(e *sharedEntry) bool {
+	_, ok := r.outgoing[e.tag]
+	return ok && e.born == r.gen
+}
+
+// launch 从快照里认领 e 的起点，开始飞行。
+func (r *sharedRegistry) launch(e *sharedEntry, land func()) {
+	out := r.outgoing[e.tag]
+	delete(r.outgoing, e.tag)
+	r.nextID++
+	r.flights = append(r.flights, &flight{id: r.nextID, from: out.rect, node: out.node, to: e, src: out.hide, land: land})
+	out.hide(true)
+	r.redraw()
+}
+
+// finish 移除落地的飞行。
+func (r *sharedRegistry) finish(f *flight) {
+	for i, x := range r.flights {
+		if x == f {
+			r.flights = append(r.flights[:i], r.flights[i+1:]...)
+			break
+		}
+	}
+	f.src(false)
+	f.land()
+	r.redraw()
+}
+
+type sharedProps struct {
+	tag   string
+	child *ui.Node
+}
+
+// SharedElement 把 child 标记为共享元素 tag。导航时若旧屏与新屏都有同标签的
+// SharedElement，旧屏元素的内容会从它的位置与尺寸飞到新屏元素上（Push、Pop、Replace
+// 都适用，返回时反向飞回）。只在 Router 子树内生效；同一屏内标签应唯一。
+//
+//	// 列表屏
+//	router.SharedElement("avatar-"+id, shadcn.Avatar(initials, 40))
+//	// 详情屏
+//	router.SharedElement("avatar-"+id, shadcn.Avatar(initials, 96))
+func SharedElement(tag string, child *ui.Node) *ui.Node {
+	return ui.Use(sharedElementC, sharedProps{tag: tag, child: child})
+}
+
+func sharedElementC(p sharedProps) *ui.Node {
+	nav := UseNavigate()
+	ref, rect := ui.UseMeasure()
+	flying, setFlying := ui.UseState(false)
+	e := ui.UseRef(sharedEntry{})
+	var reg *sharedRegistry
+	if nav != nil {
+		reg = nav.shared
+	}
+	if reg != nil && e.tag != p.tag {
+		e.tag, e.born = p.tag, reg.gen
+	}
+	e.rect, e.node, e.hide = rect, p.child, setFlying
+
+	ui.UseEffect(func() ui.Cleanup {
+		if reg == nil {
+			return nil
+		}
+		reg.live[p.tag] = e
+		return func() {
+			if reg.live[p.tag] == e {
+				delete(reg.live, p.tag)
+			}
+		}
+	}, reg, p.tag)
+	// 测得位置后认领旧屏的起点（测量前的第一帧矩形为零，还不能飞）。
+	ui.UseEffect(func() ui.Cleanup {
+		if reg != nil && (rect.W > 0 || rect.H > 0) && reg.awaiting(e) {
+			setFlying(true)
+			reg.launch(e, func() { setFlying(false) })
+		}
+		return nil
+	}, reg, p.tag, rect)
+
+	hidden := flying || (reg != nil && reg.awaiting(e))
+	// 测量挂在 child 自己身上（而不是外包一层会被拉伸的 Div），矩形才是元素的真实尺寸。
+	return ui.With(p.child, ref, ui.Style(ui.StyleIf(hidden, ui.Opacity(0))))
+}
+
+// flightsC 把进行中的飞行渲染到浮层里。
+func flightsC(reg *sharedRegistry) *ui.Node {
+	_, setTick := ui.UseState(0)
+	tick := ui.UseRef(0)
+	reg.redraw = func() { *tick++; setTick(*tick) }
+	if len(reg.flights) == 0 {
+		return nil
+	}
+	kids := make([]*ui.Node, len(reg.flights))
+	for i, f := range reg.flights {
+		kids[i] = ui.Keyed(strconv.Itoa(f.id), ui.Use(flightC, flightProps{f: f, reg: reg}))
+	}
+	return ui.Portal(kids...)
+}
+
+type flightProps struct {
+	f   *flight
+	reg *sharedRegistry
+}
+
+// flightC 渲染一次飞行的副本：布局固定在起点、保持旧元素的尺寸（内容按原样排版），
+// 平移与缩放从恒等补间到终点。终点每帧重读，目标元素在飞行中移动时副本跟着改道。
+func flightC(p flightProps) *ui.Node {
+	f, ms := p.f, p.reg.ms
+	t := ui.UseKeyframes([]ui.AnimTrack{ui.Keyframes(
+		ui.Keyframe{At: 0, Value: 0},
+		ui.Keyframe{At: ms, Value: 1, Ease: ui.EaseInOut},
+	)})[0]
+	ui.UseEffect(func() ui.Cleanup {
+		if t >= 1 {
+			p.reg.finish(f)
+		}
+		return nil
+	}, t)
+
+	from, to := f.from, f.to.rect
+	// 宽高各按各的比例缩放：两端宽高比不同时（如方形缩略图飞成横幅）才能严丝合缝落在终点。
+	sx, sy := float32(1), float32(1)
+	if from.W > 0 {
+		sx = to.W / from.W
+	}
+	if from.H > 0 {
+		sy = to.H / from.H
+	}
+	// 缩放绕中心进行，平移只需对齐两者的中心。
+	dx := (to.X + to.W/2) - (from.X + from.W/2)
+	dy := (to.Y + to.H/2) - (from.Y + from.H/2)
+	return ui.Div(ui.Style(ui.Absolute, ui.Clip,
+		ui.Left(from.X), ui.Top(from.Y), ui.Width(from.W), ui.Height(from.H),
+		ui.TranslateXY(dx*t, dy*t), ui.ScaleXY(1+(sx-1)*t, 1+(sy-1)*t)),
+		f.node)
+}
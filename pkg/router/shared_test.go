@@ -0,0 +1,150 @@
+package router
+
+import (
+	"testing"
+
+	ui "github.com/sjm1327605995/tenon/pkg/ui"
+)
+
+var sharedRed = ui.Hex("#dc2828")
+
+// sharedApp：list 屏左上角一个 40×40 的共享方块，detail 屏在 (100,200) 处一个 100×100 的。
+func sharedApp(nav **Navigator) *ui.Node {
+	box := func(size float32) *ui.Node {
+		return ui.Div(ui.Style(ui.Width(size), ui.Height(size), ui.Bg(sharedRed)))
+	}
+	list := func(_ Params) *ui.Node {
+		*nav = UseNavigate()
+		return ui.Div(SharedElement("box", box(40)))
+	}
+	detail := func(_ Params) *ui.Node {
+		*nav = UseNavigate()
+		return ui.Div(ui.Style(ui.Padding(0), ui.MarginXY(100, 200)), SharedElement("box", box(100)))
+	}
+	return Router(Props{
+		Initial:  "list",
+		Screens:  map[string]Screen{"list": list, "detail": detail},
+		SharedMs: 200,
+	})
+}
+
+// flightRect 返回浮层里飞行副本的可见范围（截图中红色像素的外接矩形，含变换）；
+// 没有副本时 ok=false。
+func flightRect(h *ui.Harness) (r ui.Rect, ok bool) {
+	if len(h.Overlays()) == 0 {
+		return r, false
+	}
+	img, err := h.Screenshot()
+	if err != nil {
+		panic(err)
+	}
+	b := img.Bounds()
+	x0, y0, x1, y1 := b.Max.X, b.Max.Y, -1, -1
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			if c.R > 200 && c.G < 80 && c.B < 80 {
+				x0, y0, x1, y1 = min(x0, x), min(y0, y), max(x1, x), max(y1, y)
+			}
+		}
+	}
+	if x1 < 0 {
+		return r, false
+	}
+	return ui.Rect{X: float32(x0), Y: float32(y0), W: float32(x1 - x0 + 1), H: float32(y1 - y0 + 1)}, true
+}
+
+// Push：副本从列表里的小方块飞到详情屏的大方块，落地后移除；Pop 反向飞回。
+func TestSharedElementFlight(t *testing.T) {
+	var nav *Navigator
+	h := ui.Mount(sharedApp(&nav), 400, 400)
+	if _, ok := flightRect(h); ok {
+		t.Fatal("初始不应有飞行副本")
+	}
+
+	nav.Push("detail", nil)
+	h.Step(0)
+	r, ok := flightRect(h)
+	if !ok || r.W != 40 || r.X != 0 || r.Y != 0 {
+		t.Fatalf("起飞时副本应与旧元素重合：%+v ok=%v", r, ok)
+	}
+	h.Step(100)
+	r, _ = flightRect(h)
+	if r.W <= 40 || r.W >= 100 || r.X <= 0 || r.X >= 100 || r.Y <= 0 || r.Y >= 200 {
+		t.Fatalf("飞行途中副本应在两者之间：%+v", r)
+	}
+	h.Step(150)
+	if _, ok := flightRect(h); ok {
+		t.Fatal("落地后副本应移除")
+	}
+	if q := h.Root().Find(func(q *ui.Query) bool { b := q.Bounds(); return b.W == 100 && b.H == 100 }); !q.Exists() {
+		t.Fatal("详情屏的元素应在原位")
+	}
+
+	nav.Pop()
+	h.Step(0)
+	if r, ok := flightRect(h); !ok || r.W != 100 || r.X != 100 || r.Y != 200 {
+		t.Fatalf("Pop 时副本应从详情屏的元素起飞：%+v ok=%v", r, ok)
+	}
+	h.Step(300)
+	if _, ok := flightRect(h); ok {
+		t.Fatal("Pop 落地后副本应移除")
+	}
+}
+
+// 宽高比不同的两端：副本宽、高各按各的比例缩放，临落地时与目标矩形重合。
+func TestSharedElementFlightAspect(t *testing.T) {
+	var nav *Navigator
+	box := func(w, h float32) *ui.Node {
+		return ui.Div(ui.Style(ui.Width(w), ui.Height(h), ui.Bg(sharedRed)))
+	}
+	list := func(_ Params) *ui.Node {
+		nav = UseNavigate()
+		return ui.Div(SharedElement("box", box(40, 40)))
+	}
+	detail := func(_ Params) *ui.Node {
+		return ui.Div(ui.Style(ui.MarginXY(100, 200)), SharedElement("box", box(200, 50)))
+	}
+	h := ui.Mount(Router(Props{Initial: "list", Screens: map[string]Screen{"list": list, "detail": detail},
+		SharedMs: 200}), 400, 400)
+
+	nav.Push("detail", nil)
+	h.Step(0)
+	h.Step(196)
+	r, ok := flightRect(h)
+	if !ok || abs(r.X-100) > 2 || abs(r.Y-200) > 2 || abs(r.W-200) > 2 || abs(r.H-50) > 2 {
+		t.Fatalf("临落地时副本应与 200×50 的目标重合：%+v ok=%v", r, ok)
+	}
+}
+
+// 只有标签相同、且在导航后新出现的元素才接住飞行：同屏原地换参数复用的是同一个元素。
+func TestSharedElementMatchesByTag(t *testing.T) {
+	var nav *Navigator
+	screen := func(p Params) *ui.Node {
+		nav = UseNavigate()
+		size := float32(20)
+		if p["big"] != "" {
+			size = 60
+		}
+		return SharedElement(p["tag"], ui.Div(ui.Style(ui.Width(size), ui.Height(size), ui.Bg(sharedRed))))
+	}
+	other := func(p Params) *ui.Node { return screen(p) }
+	h := ui.Mount(Router(Props{Initial: "s", Params: Params{"tag": "a"},
+		Screens: map[string]Screen{"s": screen, "t": other}}), 200, 200)
+
+	nav.Replace("s", Params{"tag": "b"})
+	h.Step(0)
+	if _, ok := flightRect(h); ok {
+		t.Fatal("标签不同不应起飞")
+	}
+	nav.Replace("s", Params{"tag": "b", "big": "1"})
+	h.Step(0)
+	if _, ok := flightRect(h); ok {
+		t.Fatal("同一个元素原地更新不是导航后新出现的，不应起飞")
+	}
+	nav.Push("t", Params{"tag": "b"})
+	h.Step(0)
+	if r, ok := flightRect(h); !ok || r.W != 60 {
+		t.Fatalf("Push 到另一屏的同标签元素应从 60×60 起飞：%+v ok=%v", r, ok)
+	}
+}
@@ -0,0 +1,221 @@
+package router
+
+import (
+	"slices"
+
+	"github.com/sjm1327605995/tenon/pkg/shadcn"
+	ui "github.com/sjm1327605995/tenon/pkg/ui"
+)
+
+// ---- 标签与抽屉导航器 ----
+//
+// Tabs 与 Drawer 都是「在几项之间切换」的导航器：同一时刻只显示一项，但打开过的项
+// 一直挂着（切走时只是隐藏），所以每项里的栈、滚动位置与 UseState 在切回来时原样还在。
+// 隐藏的项不接收输入：其中的 UseHotkey 不生效，切走时焦点也移出该项。
+// 项的内容通常是一个 Router，从而每个标签各有一段自己的导航栈。
+
+// Tab 是 Tabs 的一个标签（也用作 Drawer 的一项）。
+type Tab struct {
+	Name   string // 项名，JumpTo 用它切换
+	Label  string // 标签栏/抽屉菜单上的文字，空则用 Name
+	Screen Screen // 该项的内容，常返回一个 Router
+	Params Params // 传给 Screen 的参数（可选）
+}
+
+func (t Tab) label() string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return t.Name
+}
+
+// TabsProps 配置一个标签导航器。
+type TabsProps struct {
+	Name    string // 导航器名字（可选），供内层 UseNavigate(name) 找到它
+	Tabs    []Tab
+	Initial string // 初始标签名，空则取第一个
+}
+
+// DrawerProps 配置一个抽屉导航器。
+type DrawerProps struct {
+	Name    string // 导航器名字（可选），供内层 UseNavigate(name) 找到它
+	Items   []Tab
+	Initial string  // 初始项名，空则取第一个
+	Title   string  // 抽屉顶部的标题（可选）
+	Right   bool    // 从右侧滑出（默认左侧）
+	Width   float32 // 抽屉宽度，0 取 Sheet 的默认值
+}
+
+// switcher 是标签/抽屉导航器挂在 Navigator 上的操作。
+type switcher struct {
+	names  []string
+	jump   func(name string)
+	drawer func(open bool) // 仅抽屉导航器非 nil
+	open   bool
+}
+
+// switchState 是标签/抽屉导航器的状态。
+type switchState struct {
+	active  string
+	visited []string // 打开过的项：首次切到时才挂载，之后一直挂着
+	open    bool     // 抽屉是否展开
+}
+
+// JumpTo 切到名为 name 的标签或抽屉项，该项的栈与屏内状态原样保留。
+// 由向外最近一个含该项的 Tabs/Drawer 处理；抽屉导航器切换后收起抽屉。
+func (n *Navigator) JumpTo(name string) {
+	for ; n != nil; n = n.parent {
+		if n.sw != nil && slices.Contains(n.sw.names, name) {
+			n.sw.jump(name)
+			return
+		}
+	}
+}
+
+// OpenDrawer / CloseDrawer / ToggleDrawer 展开、收起、切换向外最近的 Drawer 的抽屉；
+// DrawerOpen 报告它是否展开。不在 Drawer 内时什么也不做（DrawerOpen 为 false）。
+func (n *Navigator) OpenDrawer()  { n.setDrawer(func(bool) bool { return true }) }
+func (n *Navigator) CloseDrawer() { n.setDrawer(func(bool) bool { return false }) }
+func (n *Navigator) ToggleDrawer() {
+	n.setDrawer(func(open bool) bool { return !open })
+}
+func (n *Navigator) DrawerOpen() bool {
+	d := n.drawerNav()
+	return d != nil && d.sw.open
+}
+
+func (n *Navigator) setDrawer(fn func(open bool) bool) {
+	if d := n.drawerNav(); d != nil {
+		d.sw.drawer(fn(d.sw.open))
+	}
+}
+
+func (n *Navigator) drawerNav() *Navigator {
+	for ; n != nil; n = n.parent {
+		if n.sw != nil && n.sw.drawer != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+// useSwitcher 是 Tabs 与 Drawer 共用的状态：当前项、打开过的项与抽屉开合。
+func useSwitcher(name string, items []Tab, initial string, drawer bool) (*Navigator, switchState) {
+	if initial == "" && len(items) > 0 {
+		initial = items[0].Name
+	}
+	st, set := ui.UseState(switchState{active: initial, visited: []string{initial}})
+	latest := ui.UseRef(switchState{})
+	*latest = st
+	// 同一帧里连续的动作要叠加，所以每次都基于 latest 改，并立即写回。
+	update := func(fn func(*switchState)) {
+		cur := *latest
+		fn(&cur)
+		*latest = cur
+		set(cur)
+	}
+
+	sw := &switcher{names: make([]string, len(items)), open: st.open}
+	for i, it := range items {
+		sw.names[i] = it.Name
+	}
+	sw.jump = func(to string) {
+		update(func(s *switchState) {
+			if !slices.Contains(s.visited, to) {
+				s.visited = append(slices.Clip(s.visited), to)
+			}
+			s.active, s.open = to, false
+		})
+	}
+	if drawer {
+		sw.drawer = func(open bool) { update(func(s *switchState) { s.open = open }) }
+	}
+
+	cur := Route{Name: st.active}
+	for _, it := range items {
+		if it.Name == st.active {
+			cur.Params = it.Params
+		}
+	}
+	return &Navigator{name: name, parent: ui.UseContext(navCtx), stack: []Route{cur}, sw: sw}, st
+}
+
+// panels 渲染打开过的各项：当前项显示，其余隐藏但保持挂载。
+func panels(items []Tab, st switchState) *ui.Node {
+	kids := []*ui.Node{ui.Style(ui.Grow(1), ui.Clip)}
+	for _, it := range items {
+		if !slices.Contains(st.visited, it.Name) {
+			continue
+		}
+		var body *ui.Node
+		if it.Screen != nil {
+			body = ui.Use(it.Screen, it.Params)
+		} else {
+			body = ui.Text(`router: 未提供内容的项 "` + it.Name + `"`)
+		}
+		r := Route{Name: it.Name, Params: it.Params}
+		kids = append(kids, ui.Keyed(it.Name, ui.Div(
+			ui.Style(ui.Fill, ui.StyleIf(it.Name != st.active, ui.Hidden)),
+			routeCtx.Provider(&r, body))))
+	}
+	return ui.Div(kids...)
+}
+
+// Tabs 渲染标签导航器：内容区在上，底部是标签栏（shadcn Tabs）。每个标签打开过后
+// 一直挂着，切换标签不会丢失其中的栈与状态。
+//
+//	router.Tabs(router.TabsProps{Name: "tabs", Tabs: []router.Tab{
+//	    {Name: "inbox", Label: "收件箱", Screen: inboxStack}, // inboxStack 返回一个 Router
+//	    {Name: "settings", Label: "设置", Screen: settingsScreen},
+//	}})
+func Tabs(p TabsProps) *ui.Node { return ui.Use(tabsImpl, p) }
+
+func tabsImpl(p TabsProps) *ui.Node {
+	nav, st := useSwitcher(p.Name, p.Tabs, p.Initial, false)
+	labels := make([]string, len(p.Tabs))
+	active := 0
+	for i, t := range p.Tabs {
+		labels[i] = t.label()
+		if t.Name == st.active {
+			active = i
+		}
+	}
+	bar := ui.Div(ui.Style(ui.Row, ui.JustifyCenter, ui.Padding(8)),
+		shadcn.Tabs(shadcn.TabsProps{Tabs: labels, Active: active,
+			OnChange: func(i int) { nav.sw.jump(p.Tabs[i].Name) }}))
+	return navCtx.Provider(nav, ui.Div(ui.Style(ui.Column, ui.Fill),
+		panels(p.Tabs, st),
+		shadcn.Separator(shadcn.SeparatorProps{}),
+		bar))
+}
+
+// Drawer 渲染抽屉导航器：内容区占满，各项的菜单放在从边缘滑出的 shadcn Sheet 里。
+// 屏内用 UseNavigate().OpenDrawer() 打开抽屉（如顶栏的菜单按钮），点菜单项切换并收起；
+// 与 Tabs 一样，打开过的项切走后保持挂载。
+func Drawer(p DrawerProps) *ui.Node { return ui.Use(drawerImpl, p) }
+
+func drawerImpl(p DrawerProps) *ui.Node {
+	nav, st := useSwitcher(p.Name, p.Items, p.Initial, true)
+	menu := []*ui.Node{ui.Style(ui.Column, ui.Gap(4))}
+	for _, it := range p.Items {
+		v := shadcn.Ghost
+		if it.Name == st.active {
+			v = shadcn.Secondary
+		}
+		name := it.Name
+		menu = append(menu, shadcn.Button(shadcn.ButtonProps{Variant: v, OnClick: func() { nav.JumpTo(name) }},
+			ui.Text(it.label())))
+	}
+	var title *ui.Node
+	if p.Title != "" {
+		title = shadcn.SheetTitle(p.Title)
+	}
+	side := shadcn.SheetLeft
+	if p.Right {
+		side = shadcn.SheetRight
+	}
+	return navCtx.Provider(nav,
+		ui.Div(ui.Style(ui.Column, ui.Fill), panels(p.Items, st)),
+		shadcn.Sheet(shadcn.SheetProps{Open: st.open, OnClose: nav.CloseDrawer, Side: side, Width: p.Width},
+			title, ui.Div(menu...)))
+}
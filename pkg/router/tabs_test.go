@@ -0,0 +1,174 @@
+package router
+
+import (
+	"strconv"
+	"testing"
+
+	ui "github.com/sjm1327605995/tenon/pkg/ui"
+)
+
+// counter 是带本地状态的屏：点 "+1" 计数加一，用来验证切换后状态是否还在。
+func counter(label string) Screen {
+	return func(_ Params) *ui.Node {
+		n, setN := ui.UseState(0)
+		return ui.Div(ui.Text(label+" "+strconv.Itoa(n)),
+			ui.Button(ui.OnClick(func() { setN(n + 1) }), ui.Text("+1")))
+	}
+}
+
+// inboxStack 是「收件箱」标签里的一段栈：list -> detail。
+func inboxStack(_ Params) *ui.Node {
+	list := func(_ Params) *ui.Node {
+		nav := UseNavigate()
+		return ui.Button(ui.OnClick(func() { nav.Push("detail", Params{"id": "7"}) }), ui.Text("open"))
+	}
+	detail := func(p Params) *ui.Node {
+		return ui.Text("detail " + p["id"])
+	}
+	return Router(Props{Initial: "list", Screens: map[string]Screen{"list": list, "detail": detail}})
+}
+
+// 每个标签各有一段栈；切走再切回，栈与屏内状态都还在，后台标签不可见。
+func TestTabsKeepStacksAlive(t *testing.T) {
+	h := ui.MountDefault(Tabs(TabsProps{Tabs: []Tab{
+		{Name: "inbox", Label: "收件箱", Screen: inboxStack},
+		{Name: "settings", Label: "设置", Screen: counter("count")},
+	}}))
+
+	clickText(t, h, "open") // 收件箱的栈：Push 进详情
+	clickText(t, h, "设置")
+	if h.Root().ByText("detail 7").Exists() || !h.Root().ByText("count 0").Exists() {
+		t.Fatalf("切到设置：只显示设置标签；texts=%v", h.Root().Texts())
+	}
+	clickText(t, h, "+1")
+
+	clickText(t, h, "收件箱")
+	if !h.Root().ByText("detail 7").Exists() {
+		t.Fatalf("切回收件箱应停在原来的详情屏；texts=%v", h.Root().Texts())
+	}
+	clickText(t, h, "设置")
+	if !h.Root().ByText("count 1").Exists() {
+		t.Fatalf("切回设置时计数应保留；texts=%v", h.Root().Texts())
+	}
+}
+
+// 后台标签隐藏但仍挂载：它注册的快捷键不生效（同组合时不会抢走当前标签的），
+// 切走时焦点也移出被隐藏的标签。
+func TestHiddenTabHotkeysAndFocus(t *testing.T) {
+	var log []string
+	var nav *Navigator
+	tab := func(name string) Screen {
+		return func(_ Params) *ui.Node {
+			nav = UseNavigate()
+			ui.UseHotkey("Ctrl+K", func() { log = append(log, name) })
+			return ui.Div(ui.Text(name), ui.Input(ui.Placeholder(name+"-in")))
+		}
+	}
+	h := ui.MountDefault(Tabs(TabsProps{Tabs: []Tab{
+		{Name: "a", Label: "A", Screen: tab("a")},
+		{Name: "b", Label: "B", Screen: tab("b")},
+	}}))
+
+	clickText(t, h, "B")
+	clickText(t, h, "A") // b 打开过，此时隐藏在后台，且它的快捷键注册得更晚
+	h.Key("Ctrl+K")
+	if len(log) != 1 || log[0] != "a" {
+		t.Fatalf("只应触发当前标签的快捷键：%v", log)
+	}
+
+	h.Root().ByPlaceholder("a-in").Focus()
+	nav.JumpTo("b") // 不经点击切换：焦点须由隐藏本身移出
+	h.Step(0)
+	h.Key("Ctrl+K")
+	if len(log) != 2 || log[1] != "b" {
+		t.Fatalf("切到 B 后应触发 B 的快捷键：%v", log)
+	}
+	if h.Focused().Exists() {
+		t.Fatal("焦点应移出被隐藏的标签")
+	}
+}
+
+// 嵌套：屏内 UseNavigate(name) 越过所在标签找到外层栈；标签屏上的 Push 冒泡到外层栈，
+// JumpTo 冒泡到标签导航器。
+func TestNestedNavigators(t *testing.T) {
+	var inner, root, top *Navigator
+	home := func(_ Params) *ui.Node {
+		inner, root = UseNavigate(), UseNavigate("root")
+		if UseNavigate("nope") != nil {
+			t.Error("找不到的名字应返回 nil")
+		}
+		return ui.Text("home")
+	}
+	tabs := func(_ Params) *ui.Node {
+		return Tabs(TabsProps{Name: "tabs", Tabs: []Tab{
+			{Name: "home", Screen: home},
+			{Name: "other", Screen: counter("other")},
+		}})
+	}
+	h := ui.MountDefault(Router(Props{Name: "root", Initial: "tabs", Screens: map[string]Screen{
+		"tabs":     tabs,
+		"settings": func(_ Params) *ui.Node { top = UseNavigate(); return ui.Text("settings") },
+	}}))
+
+	if inner.Name() != "tabs" || root.Parent() != nil || inner.Parent() != root {
+		t.Fatalf("导航器链：inner=%q parent=%v", inner.Name(), inner.Parent())
+	}
+	if got := inner.Current(); got.Name != "home" || inner.CanPop() {
+		t.Fatalf("标签导航器的 Current 是当前标签：%+v", got)
+	}
+
+	inner.JumpTo("other")
+	h.Step(0)
+	if !h.Root().ByText("other 0").Exists() {
+		t.Fatalf("JumpTo 应切到 other；texts=%v", h.Root().Texts())
+	}
+	inner.Push("settings", nil) // 标签导航器没有栈：交给外层 Router
+	h.Step(0)
+	if got := h.Root().Texts(); len(got) != 1 || got[0] != "settings" || top.Name() != "root" || top.Depth() != 2 {
+		t.Fatalf("Push 应冒泡到外层栈：%v", got)
+	}
+	inner.JumpTo("nope") // 没有导航器含这一项：什么也不做
+	top.Pop()
+	h.Step(0)
+	if !h.Root().ByText("home").Exists() {
+		t.Fatalf("返回外层栈的标签屏；texts=%v", h.Root().Texts())
+	}
+}
+
+// 抽屉：屏内 OpenDrawer 打开 Sheet，点菜单项切换并收起；打开过的项保持挂载。
+func TestDrawerNavigator(t *testing.T) {
+	var nav *Navigator
+	home := func(_ Params) *ui.Node {
+		nav = UseNavigate()
+		return counter("home")(nil)
+	}
+	h := ui.MountDefault(Drawer(DrawerProps{Title: "菜单", Items: []Tab{
+		{Name: "home", Label: "首页", Screen: home},
+		{Name: "about", Label: "关于", Screen: func(_ Params) *ui.Node { return ui.Text("about") }},
+	}}))
+	clickText(t, h, "+1")
+
+	nav.OpenDrawer()
+	h.Step(0)
+	h.Step(300)
+	if !nav.DrawerOpen() || len(h.Overlays()) == 0 {
+		t.Fatal("OpenDrawer 后抽屉应展开")
+	}
+	clickText(t, h, "关于")
+	h.Step(300)
+	if nav.DrawerOpen() || len(h.Overlays()) != 0 {
+		t.Fatal("点菜单项后抽屉应收起")
+	}
+	if !h.Root().ByText("about").Exists() || h.Root().ByText("home 1").Exists() {
+		t.Fatalf("应切到关于；texts=%v", h.Root().Texts())
+	}
+
+	nav.ToggleDrawer()
+	h.Step(0)
+	h.Step(300)
+	clickText(t, h, "首页")
+	h.Step(300)
+	if !h.Root().ByText("home 1").Exists() {
+		t.Fatalf("切回首页时状态应保留；texts=%v", h.Root().Texts())
+	}
+}
@@ -0,0 +1,182 @@
+package router
+
+import ui "github.com/sjm1327605995/tenon/pkg/ui"
+
+// ---- 切屏动画与拖动返回 ----
+//
+// 导航改栈的同时记下一次过渡（transition）：新屏与旧屏在过渡期间都保持挂载，按进度
+// 0→1 施加平移/透明度，进度到头才丢掉旧屏。拖动返回手势在不改栈的前提下先开始一次
+// 「预演出栈」的过渡，进度跟随手指；松手时过半则真正出栈并把剩下的动画播完，
+// 不过半则把进度播回 0，栈保持原样。
+
+// Transition 是切屏动画的样式。
+type Transition int
+
+const (
+	TransitionNone  Transition = iota // 立即切换（默认）
+	TransitionSlide                   // 新屏从右侧推入、旧屏略向左移；左边缘右滑返回
+	TransitionFade                    // 新屏淡入叠在旧屏上
+	TransitionModal                   // 新屏从底部升起、旧屏不动；顶边下拉关闭
+)
+
+// DefaultTransitionMs 是切屏动画的默认时长（毫秒）。
+const DefaultTransitionMs = 300
+
+// swipeEdge 是拖动返回的感应带宽度（逻辑像素）：Slide 屏的左边缘、Modal 屏的顶边。
+const swipeEdge = 16
+
+// slideParallax 是 Slide 过渡中下层屏跟着移动的比例（相对容器宽度）。
+const slideParallax = 0.3
+
+type navKind int
+
+const (
+	navPush navKind = iota // 入栈与替换：新屏进场
+	navPop                 // 出栈：被关闭的屏离场
+)
+
+// navState 是 Router 的状态：路由栈与进行中的过渡（nil 表示静止）。二者放在一起，
+// 一次导航只改一次状态。
+type navState struct {
+	stack []Route
+	tr    *transition
+}
+
+// transition 是一次进行中的切屏：in 为过渡结束后留下的屏，out 为离开的屏。
+// 进度从 from 播到 to（正常导航 0→1；拖动返回松手后从手指位置播到 1 或 0）；
+// dragging 时进度就是 at，由手指驱动、不播放。
+type transition struct {
+	id       int
+	kind     navKind
+	style    Transition
+	in, out  Route
+	from, to float32
+	dragging bool
+	at       float32
+}
+
+// useTransitionProgress 播放 tr 的进度并返回当前值；播到终点后清掉过渡（丢弃旧屏）。
+func useTransitionProgress(tr *transition, ms float32, latest *navState, set func(navState)) float32 {
+	if ms <= 0 {
+		ms = DefaultTransitionMs
+	}
+	var tracks []ui.AnimTrack
+	id := 0
+	if tr != nil {
+		id = tr.id
+		if !tr.dragging {
+			tracks = []ui.AnimTrack{ui.Keyframes(
+				ui.Keyframe{At: 0, Value: tr.from},
+				ui.Keyframe{At: ms * abs(tr.to-tr.from), Value: tr.to, Ease: ui.EaseOut},
+			)}
+		}
+	}
+	vals := ui.UseKeyframes(tracks, id)
+	var at float32
+	switch {
+	case tr == nil:
+	case tr.dragging:
+		at = tr.at
+	default:
+		at = vals[0]
+	}
+	ui.UseEffect(func() ui.Cleanup {
+		// 只结束自己这一次：期间若又导航了，latest 里已是新的过渡。
+		if cur := latest.tr; cur != nil && cur.id == id && !cur.dragging && at == cur.to {
+			set(navState{stack: latest.stack})
+		}
+		return nil
+	}, id, at)
+	return at
+}
+
+// styles 返回进度 p 时新屏与旧屏各自的样式。box 是 Router 容器的尺寸。
+func (tr *transition) styles(p float32, box ui.Rect) (in, out ui.StyleOpt) {
+	stacked := ui.Styles(ui.Absolute, ui.Left(0), ui.Top(0))
+	if tr.kind == navPop { // 出栈：上层是离开的旧屏，动画倒着放
+		switch tr.style {
+		case TransitionSlide:
+			return ui.TranslateXY(-slideParallax*box.W*(1-p), 0), ui.Styles(stacked, ui.TranslateXY(box.W*p, 0))
+		case TransitionFade:
+			return ui.Styles(), ui.Styles(stacked, ui.Opacity(1-p))
+		case TransitionModal:
+			return ui.Styles(), ui.Styles(stacked, ui.TranslateXY(0, box.H*p))
+		}
+		return ui.Styles(), stacked
+	}
+	switch tr.style {
+	case TransitionSlide:
+		return ui.TranslateXY(box.W*(1-p), 0), ui.Styles(stacked, ui.TranslateXY(-slideParallax*box.W*p, 0))
+	case TransitionFade:
+		return ui.Opacity(p), stacked
+	case TransitionModal:
+		return ui.TranslateXY(0, box.H*(1-p)), stacked
+	}
+	return ui.Styles(), stacked
+}
+
+// swipeBack 渲染拖动返回的感应带：Slide 屏在左边缘横向拖，Modal 屏在顶边纵向拖。
+// 栈顶不能返回、样式不支持手势、或正在播放过渡时不渲染（拖动中保留，否则手势会断）。
+// dist 累计本次拖动的位移（跨渲染存活）。
+func swipeBack(nav *Navigator, st navState, latest *navState, set func(navState), box ui.Rect, dist *float32) *ui.Node {
+	if len(st.stack) < 2 || (st.tr != nil && !st.tr.dragging) {
+		return nil
+	}
+	top := st.stack[len(st.stack)-1]
+	style := nav.style(top.Name)
+	vertical := style == TransitionModal
+	if style != TransitionSlide && !vertical {
+		return nil
+	}
+	size := box.W
+	edge := ui.Styles(ui.Absolute, ui.Left(0), ui.Top(0), ui.Width(swipeEdge), ui.HeightPct(100))
+	if vertical {
+		size = box.H
+		edge = ui.Styles(ui.Absolute, ui.Left(0), ui.Top(0), ui.WidthPct(100), ui.Height(swipeEdge))
+	}
+	return ui.Div(ui.Style(edge),
+		ui.OnDrag(func(dx, dy float32) {
+			cur := *latest
+			if len(cur.stack) < 2 || size <= 0 || (cur.tr != nil && !cur.tr.dragging) {
+				return
+			}
+			if vertical {
+				*dist += dy
+			} else {
+				*dist += dx
+			}
+			s := cur.stack
+			t := transition{kind: navPop, style: style, in: s[len(s)-2], out: s[len(s)-1],
+				dragging: true, at: min(max(*dist/size, 0), 1)}
+			if cur.tr != nil {
+				t.id = cur.tr.id
+			} else {
+				t.id = nav.next()
+			}
+			set(navState{stack: s, tr: &t})
+		}),
+		ui.OnPress(func(down bool) {
+			cur := *latest
+			*dist = 0
+			if down || cur.tr == nil || !cur.tr.dragging {
+				return
+			}
+			t := *cur.tr
+			t.id, t.dragging, t.from = nav.next(), false, t.at
+			s := cur.stack
+			if t.at >= 0.5 { // 过半：出栈，从手指位置把离场播完
+				t.to = 1
+				set(navState{stack: append([]Route(nil), s[:len(s)-1]...), tr: &t})
+				return
+			}
+			t.to = 0 // 不过半：取消，播回原位
+			set(navState{stack: s, tr: &t})
+		}))
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
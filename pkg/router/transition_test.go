@@ -0,0 +1,154 @@
+package router
+
+import (
+	"image/color"
+	"testing"
+
+	ui "github.com/sjm1327605995/tenon/pkg/ui"
+)
+
+var (
+	listBg   = ui.Hex("#dc2828")
+	detailBg = ui.Hex("#2828dc")
+)
+
+// transitionApp：list 屏红底、detail 屏蓝底，都把 UseRoute 读到的名字写进文本。
+func transitionApp(nav **Navigator, p Props) *ui.Node {
+	page := func(bg ui.Color) Screen {
+		return func(_ Params) *ui.Node {
+			*nav = UseNavigate()
+			return ui.Div(ui.Style(ui.Fill, ui.Bg(bg)), ui.Text("on "+UseRoute().Name))
+		}
+	}
+	p.Initial = "list"
+	p.Screens = map[string]Screen{"list": page(listBg), "detail": page(detailBg)}
+	return Router(p)
+}
+
+// pixel 判断截图 (x,y) 处是红（list）还是蓝（detail）。
+func pixel(t *testing.T, h *ui.Harness, x, y int) string {
+	t.Helper()
+	img, err := h.Screenshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch c := img.At(x, y).(color.RGBA); {
+	case c.R > 200 && c.B < 80:
+		return "list"
+	case c.B > 200 && c.R < 80:
+		return "detail"
+	}
+	return "?"
+}
+
+// Slide：过渡期间两屏都挂着、各自读到自己的路由；新屏从右侧推入，播完旧屏卸载。Pop 反向。
+func TestSlideTransition(t *testing.T) {
+	var nav *Navigator
+	h := ui.Mount(transitionApp(&nav, Props{Transition: TransitionSlide}), 200, 100)
+
+	nav.Push("detail", nil)
+	h.Step(0)
+	if got := h.Root().Texts(); len(got) != 2 || got[0] != "on list" || got[1] != "on detail" {
+		t.Fatalf("过渡中两屏都应挂载、UseRoute 各读各的：%v", got)
+	}
+	if pixel(t, h, 195, 50) != "list" {
+		t.Fatal("起点：新屏还在右侧屏外")
+	}
+	h.Step(100)
+	if pixel(t, h, 5, 50) != "list" || pixel(t, h, 195, 50) != "detail" {
+		t.Fatalf("途中：左边是旧屏、右边是推入的新屏：%s %s", pixel(t, h, 5, 50), pixel(t, h, 195, 50))
+	}
+	h.Step(300)
+	if got := h.Root().Texts(); len(got) != 1 || got[0] != "on detail" {
+		t.Fatalf("播完旧屏应卸载：%v", got)
+	}
+
+	nav.Pop()
+	h.Step(0)
+	h.Step(100)
+	if got := h.Root().Texts(); len(got) != 2 || pixel(t, h, 5, 50) != "list" || pixel(t, h, 195, 50) != "detail" {
+		t.Fatalf("Pop 途中：详情屏向右滑出、露出下层列表：%v", got)
+	}
+	h.Step(300)
+	if got := h.Root().Texts(); len(got) != 1 || got[0] != "on list" {
+		t.Fatalf("Pop 播完：%v", got)
+	}
+}
+
+// 按屏名覆盖动画：detail 是 Modal，从底部升起；Fade 渐显；None 立即切换。
+func TestPerRouteTransitions(t *testing.T) {
+	var nav *Navigator
+	h := ui.Mount(transitionApp(&nav, Props{Transitions: map[string]Transition{"detail": TransitionModal}}), 200, 100)
+	nav.Push("detail", nil)
+	h.Step(0)
+	h.Step(100)
+	if pixel(t, h, 100, 5) != "list" || pixel(t, h, 100, 95) != "detail" {
+		t.Fatal("Modal 途中：上面是旧屏、下面是升起的新屏")
+	}
+	h.Step(300)
+	nav.Pop()
+	h.Step(0)
+	h.Step(100)
+	if len(h.Root().Texts()) != 2 || pixel(t, h, 100, 5) != "list" || pixel(t, h, 100, 95) != "detail" {
+		t.Fatal("关闭 Modal 屏也应播放（用被关闭屏的动画）：新屏下沉、上面露出旧屏")
+	}
+	h.Step(400)
+
+	h = ui.Mount(transitionApp(&nav, Props{Transition: TransitionFade}), 200, 100)
+	nav.Push("detail", nil)
+	h.Step(0)
+	if pixel(t, h, 100, 50) != "list" {
+		t.Fatal("Fade 起点：新屏透明")
+	}
+	h.Step(100)
+	if pixel(t, h, 100, 50) != "?" {
+		t.Fatal("Fade 途中：两屏混色")
+	}
+	h.Step(400)
+	if pixel(t, h, 100, 50) != "detail" {
+		t.Fatal("Fade 终点：新屏完全显现")
+	}
+
+	h = ui.Mount(transitionApp(&nav, Props{}), 200, 100)
+	nav.Push("detail", nil)
+	h.Step(0)
+	if got := h.Root().Texts(); len(got) != 1 || got[0] != "on detail" {
+		t.Fatalf("默认 None 应立即切换：%v", got)
+	}
+}
+
+// 左边缘右滑返回：进度跟手；不过半松手取消（栈不变），过半松手出栈。
+func TestSwipeBack(t *testing.T) {
+	var nav *Navigator
+	h := ui.Mount(transitionApp(&nav, Props{Transition: TransitionSlide}), 200, 100)
+	nav.Push("detail", nil)
+	h.Step(0)
+	h.Step(400)
+
+	h.PointerDown(5, 50)
+	h.PointerMove(45, 50)
+	h.PointerMove(65, 50) // 60px = 30%
+	if got := h.Root().Texts(); len(got) != 2 {
+		t.Fatalf("拖动中下层屏应挂出来：%v", got)
+	}
+	if pixel(t, h, 30, 50) == "detail" || pixel(t, h, 195, 50) != "detail" {
+		t.Fatal("拖动中详情屏跟着手指右移，左侧露出列表")
+	}
+	h.PointerUp(65, 50)
+	h.Step(400)
+	if got := h.Root().Texts(); len(got) != 1 || got[0] != "on detail" || nav.Depth() != 2 {
+		t.Fatalf("不过半松手应取消返回：%v depth=%d", got, nav.Depth())
+	}
+
+	h.PointerDown(5, 50)
+	h.PointerMove(60, 50)
+	h.PointerMove(125, 50) // 120px = 60%
+	h.PointerUp(125, 50)
+	if nav.Depth() != 1 {
+		t.Fatalf("过半松手应出栈：depth=%d", nav.Depth())
+	}
+	h.Step(400)
+	if got := h.Root().Texts(); len(got) != 1 || got[0] != "on list" {
+		t.Fatalf("出栈动画播完应只剩列表：%v", got)
+	}
+}
@@ -28,7 +28,7 @@ func AlertDialog(p AlertDialogProps) *ui.Node {
 	if p.Destructive {
 		av = Destructive
 	}
-	return Dialog(DialogProps{Open: p.Open, OnClose: p.OnCancel},
+	return Dialog(DialogProps{Open: p.Open, OnClose: p.OnCancel, Label: p.Title, alert: true},
 		DialogTitle(p.Title),
 		DialogDescription(p.Description),
 		ui.Div(ui.Style(ui.Row, ui.JustifyEnd, ui.Gap(8)),
@@ -43,5 +43,5 @@ func badge(p BadgeProps) *ui.Node {
 	if bordered {
 		st = append(st, ui.Border(1, border))
 	}
-	return ui.Div(append([]*ui.Node{ui.Style(st...)}, p.children...)...)
+	return ui.Div(append([]*ui.Node{ui.Style(st...), ui.Class("badge")}, p.children...)...)
 }
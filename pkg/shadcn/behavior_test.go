@@ -110,6 +110,24 @@ func TestButtonPaintsBackgroundAndLabel(t *testing.T) {
 	}
 }
 
+// TestStyleSheetRestylesButton: an Important rule keyed by the component's
+// class names overrides its inline colors without touching the component.
+func TestStyleSheetRestylesButton(t *testing.T) {
+	brand := ui.Hex("#7c3aed")
+	sheet := ui.NewStyleSheet().Important(".button.outline", ui.Bg(brand))
+	h := ui.MountDefault(ui.ProvideStyleSheet(sheet,
+		ui.Div(Button(ButtonProps{Variant: Outline}, ui.Text("Restyled")), Button(ButtonProps{}, ui.Text("Plain")))))
+	found := 0
+	for _, op := range h.Paint() {
+		if op.Kind == "rect" && op.Color == brand {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Fatalf("only the outline button should use the sheet color; got %d rects", found)
+	}
+}
+
 // Phase-2 表单组件构造并渲染。
 func TestFormComponentsRender(t *testing.T) {
 	clicks := 0
@@ -414,6 +432,29 @@ func TestContextMenuRightClick(t *testing.T) {
 	}
 }
 
+// DropdownMenu：带 Shortcut 的菜单项收起时也能用快捷键触发，展开后行内显示提示。
+func TestDropdownMenuShortcut(t *testing.T) {
+	sel := ""
+	h := ui.MountDefault(ui.Use(func(_ struct{}) *ui.Node {
+		return DropdownMenu(ui.Text("File"), []MenuItem{
+			{Label: "Save", Shortcut: "Ctrl+S", OnSelect: func() { sel = "Save" }},
+			{Label: "Close"},
+		})
+	}, struct{}{}))
+	h.Key("Ctrl+S")
+	if sel != "Save" {
+		t.Fatalf("Ctrl+S 应触发 Save，sel = %q", sel)
+	}
+	h.Root().ByText("File").Click()
+	ovs := h.Overlays()
+	if len(ovs) == 0 {
+		t.Fatal("menu did not open")
+	}
+	if !ovs[0].ByText(ui.HotkeyText("Ctrl+S")).Exists() {
+		t.Fatalf("菜单行应显示快捷键提示，实际 %q", ovs[0].AllText())
+	}
+}
+
 // Phase-4: DataTable 分页/搜索基础渲染 + 比较函数。
 func TestDataTablePaging(t *testing.T) {
 	rows := []map[string]string{
@@ -454,6 +495,40 @@ func TestSidebarRenders(t *testing.T) {
 	}
 }
 
+// TestSidebarCollapsesToSheet: below MobileBelow the sidebar leaves the layout
+// and opens as a left Sheet; widening the window brings it back inline.
+func TestSidebarCollapsesToSheet(t *testing.T) {
+	h := ui.Mount(ui.Use(func(_ struct{}) *ui.Node {
+		open, setOpen := ui.UseState(false)
+		return ui.Div(ui.Style(ui.Row, ui.Fill),
+			Sidebar(SidebarProps{
+				Groups:      []SidebarGroup{{Items: []SidebarItem{{Label: "Home"}}}},
+				MobileBelow: ui.BreakpointMd, MobileOpen: open, OnMobileClose: func() { setOpen(false) },
+			}),
+			ui.Button(ui.OnClick(func() { setOpen(true) }), ui.Text("Menu")),
+		)
+	}, struct{}{}), 1000, 600)
+	if !h.Root().ByText("Home").Exists() {
+		t.Fatal("wide window should show the sidebar inline")
+	}
+
+	h.Resize(600, 600)
+	if h.Root().ByText("Home").Exists() || len(h.Overlays()) != 0 {
+		t.Fatal("narrow window should hide the sidebar until the sheet opens")
+	}
+	h.Root().ByText("Menu").Click()
+	h.Step(300)
+	if len(h.Overlays()) != 1 || !h.Overlays()[0].ByText("Home").Exists() {
+		t.Fatal("opening on a narrow window should show the sidebar in a sheet")
+	}
+
+	h.Resize(1000, 600)
+	h.Step(300)
+	if !h.Root().ByText("Home").Exists() || len(h.Overlays()) != 0 {
+		t.Fatal("widening the window should move the sidebar back inline")
+	}
+}
+
 // Phase-4: Line/Area/Pie 通过 Vector 画出描边/填充路径。
 func TestChartsDrawPaths(t *testing.T) {
 	h := ui.MountDefault(ui.Use(func(_ struct{}) *ui.Node {
@@ -512,3 +587,99 @@ func TestFormValidation(t *testing.T) {
 		t.Fatalf("OnSubmit not called with valid form: %v", submitted)
 	}
 }
+
+// 组件的无障碍语义：角色、状态随受控属性变化，浮层里的选项与菜单项可按角色找到。
+func TestComponentsExposeAccessibilitySemantics(t *testing.T) {
+	app := func(_ struct{}) *ui.Node {
+		on, setOn := ui.UseState(false)
+		return ui.Div(
+			Checkbox(CheckboxProps{Checked: on, OnChange: setOn, Label: "同意条款"}),
+			Switch(SwitchProps{Checked: true, Disabled: true, Label: "通知"}),
+			Tabs(TabsProps{Tabs: []string{"A", "B"}, Active: 1, OnChange: func(int) {}}),
+			Select(SelectProps{Value: "梨", Options: []string{"苹果", "梨"}}),
+		)
+	}
+	h := ui.MountDefault(ui.Use(app, struct{}{}))
+
+	tree := h.AccessibilityTree()
+	cb := tree.Find(ui.RoleCheckbox, "同意条款")
+	if cb == nil || cb.Checked != ui.AriaFalse {
+		t.Fatalf("复选框语义不对：\n%s", tree)
+	}
+	if sw := tree.Find(ui.RoleSwitch, "通知"); sw == nil || sw.Checked != ui.AriaTrue || !sw.Disabled {
+		t.Fatalf("开关语义不对：\n%s", tree)
+	}
+	if tab := tree.Find(ui.RoleTab, "B"); tab == nil || tab.Selected != ui.AriaTrue ||
+		tree.Find(ui.RoleTab, "A").Selected != ui.AriaFalse {
+		t.Fatalf("标签页选中态不对：\n%s", tree)
+	}
+
+	h.ByRole(ui.RoleCheckbox, "同意条款").Click()
+	if cb := h.AccessibilityTree().Find(ui.RoleCheckbox, "同意条款"); cb.Checked != ui.AriaTrue {
+		t.Fatalf("点击后 checked=%v want true", cb.Checked)
+	}
+
+	combo := h.ByRole(ui.RoleCombobox, "")
+	combo.Click()
+	tree = h.AccessibilityTree()
+	if c := tree.Find(ui.RoleCombobox, ""); c.Expanded != ui.AriaTrue {
+		t.Fatalf("展开后 expanded=%v", c.Expanded)
+	}
+	lb := tree.Find(ui.RoleListbox, "")
+	if lb == nil || len(lb.FindAll(ui.RoleOption)) != 2 || lb.Find(ui.RoleOption, "梨").Selected != ui.AriaTrue {
+		t.Fatalf("列表框选项不对：\n%s", tree)
+	}
+}
+
+// 对话框是 dialog（AlertDialog 是 alertdialog 且以标题为名），遮罩不是按钮。
+func TestDialogAccessibilityRole(t *testing.T) {
+	h := ui.MountDefault(AlertDialog(AlertDialogProps{Open: true, Title: "删除文件？", Description: "无法撤销"}))
+	h.Step(200)
+	tree := h.AccessibilityTree()
+	d := tree.Find(ui.RoleAlertDialog, "删除文件？")
+	if d == nil {
+		t.Fatalf("找不到 alertdialog：\n%s", tree)
+	}
+	if len(tree.FindAll(ui.RoleButton)) != 2 {
+		t.Fatalf("按钮应只有取消/确定两个（遮罩与卡片不算）：\n%s", tree)
+	}
+}
+
+// Visual: a switch and a progress bar before and after a click, compared pixel
+// by pixel against testdata/form-controls/*.png (regenerate with `TENON_UPDATE_GOLDEN=1 go test`).
+// Neither draws text, so the goldens do not depend on glyph rendering.
+func TestFormControlsGolden(t *testing.T) {
+	app := func(_ struct{}) *ui.Node {
+		on, setOn := ui.UseState(false)
+		level := float32(0.3)
+		if on {
+			level = 0.8
+		}
+		return ui.Div(ui.Style(ui.Column, ui.Gap(12), ui.Padding(12), ui.Bg(ui.Hex("#ffffff"))),
+			Switch(SwitchProps{Checked: on, OnChange: setOn, Label: "switch"}),
+			Progress(level),
+		)
+	}
+	h := ui.Mount(ui.Use(app, struct{}{}), 270, 70)
+	h.AssertGolden(t, "form-controls/off")
+
+	h.ByRole(ui.RoleSwitch, "switch").Click()
+	h.Step(300) // let the switch thumb finish its tween
+	h.AssertGolden(t, "form-controls/on")
+}
+
+// Structure: the render tree of text-free display and form components, compared
+// against testdata/controls.snap (regenerate with `TENON_UPDATE_GOLDEN=1 go test`) so style
+// or layout refactors show up as a reviewable text diff.
+func TestControlsSnapshot(t *testing.T) {
+	h := ui.Mount(ui.Use(func(_ struct{}) *ui.Node {
+		return ui.Div(ui.Style(ui.Column, ui.Gap(8), ui.Padding(8)),
+			Switch(SwitchProps{Checked: true, Label: "on"}),
+			Switch(SwitchProps{Disabled: true, Label: "off"}),
+			Progress(0.25),
+			Separator(SeparatorProps{}),
+			Skeleton(120, 16),
+		)
+	}, struct{}{}), 280, 160)
+	h.AssertSnapshot(t, "controls")
+}
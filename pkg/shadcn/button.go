@@ -17,6 +17,9 @@ const (
 	Link
 )
 
+// variantClass 是各变体的类名，样式表（ui.StyleSheet）可按 .button.outline 等选中。
+var variantClass = [...]string{"default", "destructive", "outline", "secondary", "ghost", "link"}
+
 // Size 是按钮尺寸。
 type Size int
 
@@ -112,7 +115,7 @@ func button(p ButtonProps) *ui.Node {
 		style = append(style, ui.Opacity(0.5))
 	}
 
-	attrs := []*ui.Node{ui.Style(style...)}
+	attrs := []*ui.Node{ui.Style(style...), ui.Class("button " + variantClass[p.Variant]), ui.AriaDisabled(p.Disabled)}
 	if !p.Disabled {
 		attrs = append(attrs, ui.OnClick(p.OnClick), ia)
 	}
@@ -15,21 +15,21 @@ func card(p nodesProps) *ui.Node {
 	base := ui.Style(ui.Column, ui.Gap(24), ui.Padding(24),
 		ui.Bg(th.Card), ui.TextColor(th.CardForeground),
 		ui.Border(1, th.Border), ui.Radius(radiusXl(th)), shadowSm())
-	return ui.Div(append([]*ui.Node{base}, p.children...)...)
+	return ui.Div(append([]*ui.Node{base, ui.Class("card")}, p.children...)...)
 }
 
 // CardHeader / CardContent / CardFooter 是可选的布局分区（纵向分组，间距由 Card 的 gap-6 提供，
 // 水平内边距由 Card 容器统一提供）。
 func CardHeader(children ...*ui.Node) *ui.Node {
-	return ui.Div(append([]*ui.Node{ui.Style(ui.Column, ui.Gap(6))}, children...)...)
+	return ui.Div(append([]*ui.Node{ui.Style(ui.Column, ui.Gap(6)), ui.Class("card-header")}, children...)...)
 }
 
 func CardContent(children ...*ui.Node) *ui.Node {
-	return ui.Div(append([]*ui.Node{ui.Style(ui.Column)}, children...)...)
+	return ui.Div(append([]*ui.Node{ui.Style(ui.Column), ui.Class("card-content")}, children...)...)
 }
 
 func CardFooter(children ...*ui.Node) *ui.Node {
-	return ui.Div(append([]*ui.Node{ui.Style(ui.Row, ui.Gap(8), ui.ItemsCenter)}, children...)...)
+	return ui.Div(append([]*ui.Node{ui.Style(ui.Row, ui.Gap(8), ui.ItemsCenter), ui.Class("card-footer")}, children...)...)
 }
 
 // CardTitle 继承 Card 前景色（text-base font-semibold）。
@@ -27,10 +27,10 @@ func contextMenu(p contextMenuProps) *ui.Node {
 		return ui.Fragment(wrapped)
 	}
 
-	rows := []*ui.Node{ui.ArrowNav(ui.NavVertical)}
+	rows := []*ui.Node{ui.ArrowNav(ui.NavVertical), ui.Role(ui.RoleMenu)}
 	for _, it := range p.items {
 		item := it
-		rows = append(rows, menuRow(item.Label, func() {
+		rows = append(rows, itemRow(item, func() {
 			if item.OnSelect != nil {
 				item.OnSelect()
 			}
@@ -45,7 +45,7 @@ func contextMenu(p contextMenuProps) *ui.Node {
 	}, rows...)
 
 	menu := ui.Portal(
-		ui.Div(ui.Style(ui.Grow(1)), ui.OnClick(func() { setOpen(false) }), // 点空白关闭
+		ui.Div(ui.Style(ui.Grow(1)), ui.Role(ui.RolePresentation), ui.OnClick(func() { setOpen(false) }), // 点空白关闭
 			ui.Div(panel...)),
 	)
 	return ui.Fragment(wrapped, menu)
@@ -5,7 +5,9 @@ import ui "github.com/sjm1327605995/tenon/pkg/ui"
 type DialogProps struct {
 	Open     bool
 	OnClose  func()
+	Label    string // 无障碍名称（通常与 DialogTitle 相同）
 	children []*ui.Node
+	alert    bool // AlertDialog：角色为 alertdialog
 }
 
 // Dialog 是模态对话框：Open 为真时通过 Portal 渲染，带进出场动画；点击遮罩关闭。
@@ -25,7 +27,12 @@ func dialog(p DialogProps) *ui.Node {
 	if !mounted {
 		return nil
 	}
+	role := ui.RoleDialog
+	if p.alert {
+		role = ui.RoleAlertDialog
+	}
 	card := append([]*ui.Node{
+		ui.Role(role), ui.AriaLabel(p.Label),
 		ui.Style(ui.Column, ui.Gap(12), ui.Padding(24), ui.MinWidth(320),
 			ui.Bg(th.Background), ui.TextColor(th.Foreground), ui.Border(1, th.Border),
 			ui.Radius(th.Radius+4), ui.Scale(0.96+0.04*prog)),
@@ -36,6 +43,7 @@ func dialog(p DialogProps) *ui.Node {
 		ui.Div(
 			ui.Style(ui.Grow(1), ui.ItemsCenter, ui.JustifyCenter,
 				ui.Bg(ui.Color{R: 0, G: 0, B: 0, A: 140}), ui.Opacity(prog)),
+			ui.Role(ui.RolePresentation), // 遮罩可点击关闭，但不是给读屏的按钮
 			ui.OnClick(func() {
 				if p.OnClose != nil {
 					p.OnClose()
@@ -40,12 +40,14 @@ func drawer(p DrawerProps) *ui.Node {
 		ui.TranslateXY(0, ty), ui.Bg(th.Background), ui.TextColor(th.Foreground),
 		ui.Border(1, th.Border), ui.Radius(th.Radius + 6),
 	}
-	content := append([]*ui.Node{ui.Style(panel...), ui.OnClick(func() {}), handle}, p.children...)
+	content := append([]*ui.Node{ui.Style(panel...), ui.Role(ui.RoleDialog), ui.OnClick(func() {}), handle},
+		p.children...)
 
 	return ui.Portal(
 		ui.TrapFocus(),
 		ui.Div(
 			ui.Style(ui.Grow(1), ui.Column, ui.JustifyEnd, ui.Bg(ui.Color{A: uint8(140 * prog)})),
+			ui.Role(ui.RolePresentation),
 			ui.OnClick(func() {
 				if p.OnClose != nil {
 					p.OnClose()
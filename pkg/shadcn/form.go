@@ -8,6 +8,7 @@ type CheckboxProps struct {
 	Checked  bool
 	OnChange func(bool)
 	Disabled bool
+	Label    string // 无障碍名称（复选框旁的文字标签通常是另一个节点）
 }
 
 func Checkbox(p CheckboxProps) *ui.Node { return ui.Use(checkbox, p) }
@@ -23,7 +24,8 @@ func checkbox(p CheckboxProps) *ui.Node {
 	if p.Disabled {
 		st = append(st, ui.Opacity(0.5))
 	}
-	kids := []*ui.Node{ui.Style(st...)}
+	kids := []*ui.Node{ui.Style(st...), ui.Role(ui.RoleCheckbox), ui.AriaChecked(p.Checked),
+		ui.AriaDisabled(p.Disabled), ui.AriaLabel(p.Label)}
 	if !p.Disabled {
 		kids = append(kids, ui.OnClick(func() {
 			if p.OnChange != nil {
@@ -32,7 +34,8 @@ func checkbox(p CheckboxProps) *ui.Node {
 		}))
 	}
 	if p.Checked {
-		kids = append(kids, ui.Text("✓", ui.FontSize(11), ui.TextColor(th.PrimaryForeground)))
+		kids = append(kids, ui.Div(ui.AriaHidden(),
+			ui.Text("✓", ui.FontSize(11), ui.TextColor(th.PrimaryForeground))))
 	}
 	return ui.Div(kids...)
 }
@@ -43,6 +46,7 @@ type SwitchProps struct {
 	Checked  bool
 	OnChange func(bool)
 	Disabled bool
+	Label    string // 无障碍名称
 }
 
 func Switch(p SwitchProps) *ui.Node { return ui.Use(switchC, p) }
@@ -60,7 +64,8 @@ func switchC(p SwitchProps) *ui.Node {
 	if p.Disabled {
 		st = append(st, ui.Opacity(0.5))
 	}
-	attrs := []*ui.Node{ui.Style(st...)}
+	attrs := []*ui.Node{ui.Style(st...), ui.Role(ui.RoleSwitch), ui.AriaChecked(p.Checked),
+		ui.AriaDisabled(p.Disabled), ui.AriaLabel(p.Label)}
 	if !p.Disabled {
 		attrs = append(attrs, ui.OnClick(func() {
 			if p.OnChange != nil {
@@ -85,7 +90,7 @@ func RadioGroup(p RadioGroupProps) *ui.Node { return ui.Use(radioGroup, p) }
 
 func radioGroup(p RadioGroupProps) *ui.Node {
 	th := ui.UseTheme()
-	kids := []*ui.Node{ui.Style(ui.Column, ui.Gap(10))}
+	kids := []*ui.Node{ui.Style(ui.Column, ui.Gap(10)), ui.Role(ui.RoleRadioGroup)}
 	for _, opt := range p.Options {
 		o := opt
 		selected := opt == p.Value
@@ -98,6 +103,7 @@ func radioGroup(p RadioGroupProps) *ui.Node {
 		}
 		kids = append(kids, ui.Div(
 			ui.Style(ui.Row, ui.Gap(8), ui.ItemsCenter),
+			ui.Role(ui.RoleRadio), ui.AriaChecked(selected),
 			ui.OnClick(func() {
 				if p.OnChange != nil {
 					p.OnChange(o)
@@ -22,3 +22,13 @@ func KbdGroup(keys ...string) *ui.Node {
 	}
 	return ui.Div(kids...)
 }
+
+// KbdHotkey 按 ui.UseHotkey 的写法渲染快捷键键帽（"Mod+K" 在 macOS 上为 ⌘ K，其他平台为 Ctrl K）；
+// 组合键的每一步一组。
+func KbdHotkey(combo string) *ui.Node {
+	kids := []*ui.Node{ui.Style(ui.Row, ui.ItemsCenter, ui.Gap(8))}
+	for _, keys := range ui.HotkeyKeys(combo) {
+		kids = append(kids, KbdGroup(keys...))
+	}
+	return ui.Div(kids...)
+}
@@ -13,7 +13,7 @@ func Menubar(menus []MenubarMenu) *ui.Node { return ui.Use(menubar, menus) }
 func menubar(menus []MenubarMenu) *ui.Node {
 	th := ui.UseTheme()
 	kids := []*ui.Node{ui.Style(ui.Row, ui.ItemsCenter, ui.Gap(2), ui.Padding(3),
-		ui.Border(1, th.Border), ui.Radius(radiusMd(th)), ui.Bg(th.Background))}
+		ui.Border(1, th.Border), ui.Radius(radiusMd(th)), ui.Bg(th.Background)), ui.Role(ui.RoleMenuBar)}
 	for _, m := range menus {
 		kids = append(kids, DropdownMenu(ui.Use(menubarTrigger, m.Label), m.Items))
 	}
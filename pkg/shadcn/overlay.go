@@ -35,11 +35,14 @@ func floatPanelC(p floatProps) *ui.Node {
 		ui.Border(1, p.th.Border), ui.Radius(p.th.Radius),
 	}, p.extra...)
 
+	// 遮罩与面板的点击只为关闭/吞掉冒泡，不是按钮；面板的角色由 content 里的 Role 决定
+	// （菜单、列表框），它排在 RolePresentation 之后，会覆盖掉。
 	return ui.Portal(
 		ui.Div(
-			ui.Style(ui.Grow(1)),
+			ui.Style(ui.Grow(1)), ui.Role(ui.RolePresentation),
 			ui.OnClick(p.onClose),
-			ui.Div(append([]*ui.Node{ui.Style(panel...), pref, ui.OnClick(func() {})}, p.content...)...),
+			ui.Div(append([]*ui.Node{ui.Style(panel...), pref, ui.Role(ui.RolePresentation), ui.OnClick(func() {})},
+				p.content...)...),
 		),
 	)
 }
@@ -47,12 +50,25 @@ func floatPanelC(p floatProps) *ui.Node {
 // ---- 菜单行（悬停高亮的可点击项，供 Select / DropdownMenu 复用）----
 
 type menuRowProps struct {
-	label   string
-	onClick func()
+	label    string
+	shortcut string // 右侧的快捷键提示；菜单项为空时按 label 去快捷键注册表里找
+	onClick  func()
+	role     ui.AriaRole // 菜单里是 menuitem，Select 里是 option
+	selected bool
 }
 
 func menuRow(label string, onClick func()) *ui.Node {
-	return ui.Use(menuRowImpl, menuRowProps{label: label, onClick: onClick})
+	return ui.Use(menuRowImpl, menuRowProps{label: label, onClick: onClick, role: ui.RoleMenuItem})
+}
+
+// itemRow 是 MenuItem 的菜单行，带快捷键提示。
+func itemRow(item MenuItem, onClick func()) *ui.Node {
+	return ui.Use(menuRowImpl, menuRowProps{label: item.Label, shortcut: item.Shortcut, onClick: onClick, role: ui.RoleMenuItem})
+}
+
+// optionRow 是 Select 的选项行：角色为 option，并标出当前选中项。
+func optionRow(label string, selected bool, onClick func()) *ui.Node {
+	return ui.Use(menuRowImpl, menuRowProps{label: label, onClick: onClick, role: ui.RoleOption, selected: selected})
 }
 
 func menuRowImpl(p menuRowProps) *ui.Node {
@@ -62,8 +78,21 @@ func menuRowImpl(p menuRowProps) *ui.Node {
 	if hovered {
 		st = append(st, ui.Bg(th.Accent), ui.TextColor(th.AccentForeground))
 	}
-	return ui.Div(ui.Style(st...), ui.OnClick(p.onClick), ia,
-		ui.Text(p.label, ui.FontSize(14)))
+	state := ui.AriaSelected(p.selected)
+	if p.role != ui.RoleOption {
+		state = nil
+	}
+	shortcut := ui.UseHotkeyFor(p.label)
+	if p.shortcut != "" {
+		shortcut = p.shortcut
+	}
+	var hint *ui.Node
+	if p.role == ui.RoleMenuItem && shortcut != "" {
+		st = append(st, ui.JustifyBetween, ui.Gap(24))
+		hint = ui.Text(ui.HotkeyText(shortcut), ui.FontSize(12), ui.TextColor(th.MutedForeground))
+	}
+	return ui.Div(ui.Style(st...), ui.Role(p.role), state, ui.OnClick(p.onClick), ia,
+		ui.Text(p.label, ui.FontSize(14)), hint)
 }
 
 // ---- Popover ----
@@ -112,6 +141,7 @@ func tooltip(p tooltipProps) *ui.Node {
 			ui.Div(
 				ui.Style(ui.Absolute, ui.Left(rect.X), ui.Top(rect.Y-32),
 					ui.Bg(th.Foreground), ui.Radius(6), ui.PaddingXY(10, 5)),
+				ui.Role(ui.RoleTooltip),
 				ui.Text(p.text, ui.FontSize(12), ui.TextColor(th.Background)),
 			),
 		)),
@@ -155,6 +185,9 @@ func hoverCard(p hoverCardProps) *ui.Node {
 type MenuItem struct {
 	Label    string
 	OnSelect func()
+	// Shortcut 是快捷键组合（写法同 ui.UseHotkey，如 "Mod+S"）。DropdownMenu 会在挂载期间
+	// 注册它（菜单收起时也生效），菜单行右侧显示其提示。
+	Shortcut string
 }
 
 type dropdownProps struct {
@@ -173,20 +206,35 @@ func dropdown(p dropdownProps) *ui.Node {
 	ref, rect := ui.UseMeasure()
 	ui.UseEscape(open, func() { setOpen(false) })
 	rows := make([]*ui.Node, 0, len(p.items))
+	var keys []*ui.Node
 	for _, it := range p.items {
 		item := it
-		rows = append(rows, menuRow(item.Label, func() {
+		rows = append(rows, itemRow(item, func() {
 			if item.OnSelect != nil {
 				item.OnSelect()
 			}
 			setOpen(false)
 		}))
+		if item.Shortcut != "" {
+			keys = append(keys, ui.Use(menuHotkey, item))
+		}
 	}
 	// 上下方向键在菜单项间移动焦点（ArrowNav 属性会落到浮层面板的 Div 上）
-	content := append([]*ui.Node{ui.ArrowNav(ui.NavVertical)}, rows...)
+	content := append([]*ui.Node{ui.ArrowNav(ui.NavVertical), ui.Role(ui.RoleMenu)}, rows...)
 	return ui.Fragment(
-		ui.Div(ref, ui.OnClick(func() { setOpen(!open) }), p.trigger),
+		ui.Fragment(keys...),
+		ui.Div(ref, ui.AriaExpanded(open), ui.OnClick(func() { setOpen(!open) }), p.trigger),
 		ui.If(open, floatPanel(th, rect, func() { setOpen(false) },
 			[]ui.StyleOpt{ui.Column, ui.Padding(4), ui.MinWidth(max(rect.W, 160))}, content...)),
 	)
 }
+
+// menuHotkey 注册菜单项的快捷键；不渲染任何东西，放在浮层之外，菜单收起时同样生效。
+func menuHotkey(item MenuItem) *ui.Node {
+	ui.UseHotkey(item.Shortcut, func() {
+		if item.OnSelect != nil {
+			item.OnSelect()
+		}
+	}, ui.HotkeyLabel(item.Label))
+	return nil
+}
@@ -23,7 +23,7 @@ func selectC(p SelectProps) *ui.Node {
 		label, labelColor = p.Placeholder, th.MutedForeground
 	}
 
-	trigger := ui.Div(ref,
+	trigger := ui.Div(ref, ui.Role(ui.RoleCombobox), ui.AriaExpanded(open),
 		ui.Style(ui.Row, ui.ItemsCenter, ui.JustifyBetween, ui.Gap(8), ui.Height(38),
 			ui.PaddingXY(12, 0), ui.Radius(th.Radius), ui.Border(1, th.Input),
 			ui.Bg(th.Background), ui.MinWidth(180)),
@@ -32,10 +32,11 @@ func selectC(p SelectProps) *ui.Node {
 		ui.Icon(ui.IconChevronDown, 16, ui.TextColor(th.MutedForeground)),
 	)
 
-	rows := make([]*ui.Node, 0, len(p.Options))
+	rows := make([]*ui.Node, 0, len(p.Options)+1)
+	rows = append(rows, ui.Role(ui.RoleListbox))
 	for _, opt := range p.Options {
 		o := opt
-		rows = append(rows, menuRow(o, func() {
+		rows = append(rows, optionRow(o, o == p.Value, func() {
 			if p.OnChange != nil {
 				p.OnChange(o)
 			}
@@ -54,12 +54,14 @@ func sheet(p SheetProps) *ui.Node {
 		ui.TrapFocus(), // 模态：键盘焦点限制在抽屉内
 		ui.Div(
 			ui.Style(ui.Grow(1), ui.Row, justify, ui.Bg(ui.Color{R: 0, G: 0, B: 0, A: uint8(140 * prog)})),
+			ui.Role(ui.RolePresentation),
 			ui.OnClick(func() {
 				if p.OnClose != nil {
 					p.OnClose()
 				}
 			}),
-			ui.Div(append([]*ui.Node{ui.Style(panel...), ui.OnClick(func() {})}, p.children...)...),
+			ui.Div(append([]*ui.Node{ui.Style(panel...), ui.Role(ui.RoleDialog), ui.OnClick(func() {})},
+				p.children...)...),
 		),
 	)
 }
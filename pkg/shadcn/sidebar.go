@@ -20,23 +20,43 @@ type SidebarProps struct {
 	Footer    *ui.Node // 底部（用户/设置），可为 nil
 	Collapsed bool     // 折叠为图标条
 	Width     float32  // 展开宽度（默认 240）
+
+	// 窄窗口：窗口宽度低于 MobileBelow 时不占位，改为从左侧滑出的 Sheet（0 = 不启用）。
+	// MobileOpen / OnMobileClose 控制这个 Sheet，触发按钮由应用自己放（如顶栏的菜单图标）。
+	MobileBelow   ui.Breakpoint
+	MobileOpen    bool
+	OnMobileClose func()
 }
 
 // Sidebar 是可折叠的应用侧边栏：顶部 + 分组菜单 + 底部；折叠时只显示图标。
-// 放在填满窗口的 Row 里，与主内容并列。
+// 放在填满窗口的 Row 里，与主内容并列；设了 MobileBelow 时窄窗口下收进 Sheet。
 func Sidebar(p SidebarProps) *ui.Node { return ui.Use(sidebar, p) }
 
+// SidebarIsMobile 报告当前窗口是否窄于 below（Sidebar 此时收进 Sheet），
+// 供应用决定要不要显示打开侧边栏的按钮；跨过断点时调用它的组件重渲染。
+func SidebarIsMobile(below ui.Breakpoint) bool {
+	return ui.UseMediaQuery(0, below.MinWidth()) && below > ui.BreakpointBase
+}
+
 func sidebar(p SidebarProps) *ui.Node {
 	th := ui.UseTheme()
+	mobile := SidebarIsMobile(p.MobileBelow)
 	w := p.Width
 	if w <= 0 {
 		w = 240
 	}
+	if mobile {
+		p.Collapsed = false // 抽屉里总是完整展开
+	}
 	if p.Collapsed {
 		w = 60
 	}
-	kids := []*ui.Node{ui.Style(ui.Column, ui.Width(w), ui.HeightPct(100), ui.Gap(4),
-		ui.Padding(8), ui.Bg(th.Card), ui.TextColor(th.CardForeground), ui.Clip)}
+	panel := []ui.StyleOpt{ui.Column, ui.Width(w), ui.HeightPct(100), ui.Gap(4),
+		ui.Padding(8), ui.Bg(th.Card), ui.TextColor(th.CardForeground), ui.Clip}
+	if mobile {
+		panel = []ui.StyleOpt{ui.Column, ui.Gap(4), ui.Grow(1)} // Sheet 自带底色、边框与内边距
+	}
+	kids := []*ui.Node{ui.Style(panel...)}
 
 	if p.Header != nil {
 		kids = append(kids, ui.Div(ui.Style(ui.Row, ui.ItemsCenter, ui.PaddingXY(8, 10)), p.Header))
@@ -57,6 +77,10 @@ func sidebar(p SidebarProps) *ui.Node {
 	if p.Footer != nil {
 		kids = append(kids, ui.Div(ui.Style(ui.Row, ui.ItemsCenter, ui.PaddingXY(8, 8)), p.Footer))
 	}
+	if mobile {
+		return Sheet(SheetProps{Open: p.MobileOpen, OnClose: p.OnMobileClose, Side: SheetLeft, Width: w},
+			ui.Div(kids...))
+	}
 	// 面板 + 右侧 1px 分隔线（区分主内容；引擎无单边 border）
 	return ui.Div(ui.Style(ui.Row, ui.HeightPct(100)),
 		ui.Div(kids...),
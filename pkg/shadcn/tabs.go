@@ -18,7 +18,8 @@ func tabs(p TabsProps) *ui.Node {
 	// shadcn v4: list bg-muted rounded-lg p-[3px]；trigger rounded-md, active bg-background shadow-sm。
 	kids := []*ui.Node{ui.Style(ui.Row, ui.ItemsCenter, ui.Gap(4), ui.Padding(3),
 		ui.Radius(radiusLg(th)), ui.Bg(th.Muted)),
-		ui.ArrowNav(ui.NavHorizontal)} // 左右方向键在标签间移动焦点（WAI-ARIA tabs）
+		ui.ArrowNav(ui.NavHorizontal), // 左右方向键在标签间移动焦点（WAI-ARIA tabs）
+		ui.Role(ui.RoleTabList)}
 	for i, label := range p.Tabs {
 		active := i == p.Active
 		idx := i
@@ -30,6 +31,7 @@ func tabs(p TabsProps) *ui.Node {
 		}
 		kids = append(kids, ui.Button(
 			ui.Style(st...),
+			ui.Role(ui.RoleTab), ui.AriaSelected(active),
 			ui.OnClick(func() {
 				if p.OnChange != nil {
 					p.OnChange(idx)
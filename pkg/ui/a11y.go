@@ -0,0 +1,419 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ---- 无障碍树 ----
+//
+// renderNode 只描述「画什么」，读屏软件要的是「这是什么」：角色、名称、状态、在 Tab 顺序里
+// 的位置。这里从 renderNode 树派生一份快照（AccessNode 树），规则对齐 WAI-ARIA 的常识：
+//
+//   - 角色：显式 Role(...) 优先；否则按节点推断 —— 输入框是 textbox、图片是 img、文本是
+//     text、带 OnClick 的是 button，其余容器是「无语义」的，不进树，子节点上提到父级。
+//   - 名称：AriaLabel 优先；按钮、复选框、标签页、菜单项这类控件取自身的文本内容；
+//     输入框退回 placeholder。
+//   - 描述：AriaDescribedBy(id) 引用同一窗口里 Id(id) 节点的文本内容。
+//   - 模态：有 TrapFocus 浮层时树里只有它（aria-modal 的语义），与 Tab 顺序一致。
+//
+// 还没有平台桥接（AccessKit / UIA / AX），先让测试能断言语义：
+//
+//	ui.Div(ui.Role(ui.RoleCheckbox), ui.AriaChecked(on), ui.AriaLabel("记住我"), ui.OnClick(toggle))
+//
+//	tree := h.AccessibilityTree()
+//	cb := tree.Find(ui.RoleCheckbox, "记住我") // cb.Checked == ui.AriaTrue
+
+// AriaRole 是节点的无障碍角色（取值同 WAI-ARIA 的 role 名）。
+type AriaRole string
+
+const (
+	RoleNone         AriaRole = ""             // 未声明：按节点推断
+	RolePresentation AriaRole = "presentation" // 显式无语义：节点不进树，子节点上提（遮罩、布局壳）
+	RoleWindow       AriaRole = "window"       // 树根
+	RoleText         AriaRole = "text"
+	RoleHeading      AriaRole = "heading"
+	RoleImage        AriaRole = "img"
+	RoleButton       AriaRole = "button"
+	RoleLink         AriaRole = "link"
+	RoleCheckbox     AriaRole = "checkbox"
+	RoleSwitch       AriaRole = "switch"
+	RoleRadio        AriaRole = "radio"
+	RoleRadioGroup   AriaRole = "radiogroup"
+	RoleTextbox      AriaRole = "textbox"
+	RoleSlider       AriaRole = "slider"
+	RoleProgressBar  AriaRole = "progressbar"
+	RoleTabList      AriaRole = "tablist"
+	RoleTab          AriaRole = "tab"
+	RoleTabPanel     AriaRole = "tabpanel"
+	RoleDialog       AriaRole = "dialog"
+	RoleAlertDialog  AriaRole = "alertdialog"
+	RoleAlert        AriaRole = "alert"
+	RoleCombobox     AriaRole = "combobox"
+	RoleListbox      AriaRole = "listbox"
+	RoleOption       AriaRole = "option"
+	RoleMenu         AriaRole = "menu"
+	RoleMenuBar      AriaRole = "menubar"
+	RoleMenuItem     AriaRole = "menuitem"
+	RoleList         AriaRole = "list"
+	RoleListItem     AriaRole = "listitem"
+	RoleGroup        AriaRole = "group"
+	RoleTooltip      AriaRole = "tooltip"
+)
+
+// leafRole 报告该角色的子节点是否「展示性」的：按钮里的文字、图标只构成它的名称，
+// 不单独进树（否则读屏会把「保存」读两遍）。
+func leafRole(r AriaRole) bool {
+	switch r {
+	case RoleText, RoleImage, RoleButton, RoleLink, RoleCheckbox, RoleSwitch, RoleRadio,
+		RoleTextbox, RoleSlider, RoleProgressBar, RoleTab, RoleOption, RoleMenuItem:
+		return true
+	}
+	return false
+}
+
+// nameFromContent 报告该角色在没有 AriaLabel 时是否以自身文本内容为名称。
+func nameFromContent(r AriaRole) bool {
+	switch r {
+	case RoleButton, RoleLink, RoleCheckbox, RoleSwitch, RoleRadio, RoleTab, RoleOption, RoleMenuItem,
+		RoleHeading, RoleTooltip:
+		return true
+	}
+	return false
+}
+
+// AriaState 是可缺省的布尔状态：AriaUnset 表示节点没有这个状态（普通按钮没有 checked），
+// 与「有、但为假」（未勾选的复选框）不同。
+type AriaState int8
+
+const (
+	AriaUnset AriaState = iota
+	AriaFalse
+	AriaTrue
+)
+
+func ariaState(b bool) AriaState {
+	if b {
+		return AriaTrue
+	}
+	return AriaFalse
+}
+
+func (s AriaState) String() string {
+	switch s {
+	case AriaTrue:
+		return "true"
+	case AriaFalse:
+		return "false"
+	}
+	return "unset"
+}
+
+// a11yProps 是 host 元素上声明的无障碍属性（hostProps 与 renderNode 各持一份）。
+type a11yProps struct {
+	role        AriaRole
+	label       string
+	describedBy string
+	checked     AriaState
+	expanded    AriaState
+	selected    AriaState
+	disabled    bool
+	hidden      bool
+}
+
+// Role 声明节点的无障碍角色，覆盖推断出的角色。
+func Role(r AriaRole) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.role = r }}
+}
+
+// AriaLabel 设置节点的无障碍名称（图标按钮、无文字控件必备）。
+func AriaLabel(s string) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.label = s }}
+}
+
+// AriaDescribedBy 以 Id(id) 节点的文本内容作为本节点的描述（表单项的提示、错误信息）。
+func AriaDescribedBy(id string) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.describedBy = id }}
+}
+
+// AriaChecked / AriaExpanded / AriaSelected / AriaDisabled 声明控件状态。
+func AriaChecked(on bool) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.checked = ariaState(on) }}
+}
+func AriaExpanded(on bool) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.expanded = ariaState(on) }}
+}
+func AriaSelected(on bool) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.selected = ariaState(on) }}
+}
+func AriaDisabled(on bool) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.disabled = on }}
+}
+
+// AriaHidden 把节点连同子树从无障碍树里拿掉，也不参与名称计算（装饰性的勾号、分隔符）。
+func AriaHidden() *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.a11y.hidden = true }}
+}
+
+// AccessNode 是无障碍树上的一个节点（快照，不随界面更新）。
+type AccessNode struct {
+	Role        AriaRole
+	Name        string
+	Description string
+	Value       string // 输入框的当前内容
+	Bounds      Rect   // 逻辑像素，窗口坐标
+	Focusable   bool
+	Focused     bool
+	TabIndex    int // 在 Tab 顺序中的位置（0 起）；不在 Tab 顺序里为 -1
+	Checked     AriaState
+	Expanded    AriaState
+	Selected    AriaState
+	Disabled    bool
+	Children    []*AccessNode
+
+	rn *renderNode
+}
+
+// Find 深度优先返回第一个角色为 role、名称为 name 的节点（name 为空则不限名称）；找不到为 nil。
+func (n *AccessNode) Find(role AriaRole, name string) *AccessNode {
+	var hit *AccessNode
+	n.Walk(func(c *AccessNode) bool {
+		if hit == nil && c.Role == role && (name == "" || c.Name == name) {
+			hit = c
+		}
+		return hit == nil
+	})
+	return hit
+}
+
+// FindAll 按树序返回所有角色为 role 的节点。
+func (n *AccessNode) FindAll(role AriaRole) []*AccessNode {
+	var out []*AccessNode
+	n.Walk(func(c *AccessNode) bool {
+		if c.Role == role {
+			out = append(out, c)
+		}
+		return true
+	})
+	return out
+}
+
+// Walk 先序遍历子树；fn 返回 false 时停止。
+func (n *AccessNode) Walk(fn func(*AccessNode) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !fn(n) {
+		return false
+	}
+	for _, c := range n.Children {
+		if !c.Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// String 把子树缩进打印成一行一节点，便于调试与快照断言：
+//
+//	window "Tenon UI"
+//	  checkbox "记住我" checked tab=0
+func (n *AccessNode) String() string {
+	var b strings.Builder
+	n.dump(&b, 0)
+	return b.String()
+}
+
+func (n *AccessNode) dump(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(string(n.Role))
+	if n.Name != "" {
+		fmt.Fprintf(b, " %q", n.Name)
+	}
+	if n.Value != "" {
+		fmt.Fprintf(b, " value=%q", n.Value)
+	}
+	if n.Description != "" {
+		fmt.Fprintf(b, " desc=%q", n.Description)
+	}
+	for _, st := range []struct {
+		name string
+		s    AriaState
+	}{{"checked", n.Checked}, {"expanded", n.Expanded}, {"selected", n.Selected}} {
+		switch st.s {
+		case AriaTrue:
+			b.WriteString(" " + st.name)
+		case AriaFalse:
+			b.WriteString(" !" + st.name)
+		}
+	}
+	if n.Disabled {
+		b.WriteString(" disabled")
+	}
+	if n.Focused {
+		b.WriteString(" focused")
+	}
+	if n.TabIndex >= 0 {
+		fmt.Fprintf(b, " tab=%d", n.TabIndex)
+	}
+	b.WriteByte('\n')
+	for _, c := range n.Children {
+		c.dump(b, depth+1)
+	}
+}
+
+// AccessibilityTree 返回窗口当前的无障碍树快照。须在渲染线程调用。
+func (w *Window) AccessibilityTree() *AccessNode { return w.g.accessibilityTree() }
+
+func (g *game) accessibilityTree() *AccessNode {
+	root := &AccessNode{Role: RoleWindow, TabIndex: -1,
+		Bounds: Rect{W: float32(g.w) / uiScale, H: float32(g.h) / uiScale}}
+	if g.win != nil {
+		root.Name = g.win.Title()
+		root.Bounds = g.win.Viewport()
+	}
+	// 布局矩形是物理像素，树里一律换成逻辑像素（与根节点的 Viewport 同一量纲）。
+	k := uiScale
+	if g.scale > 0 {
+		k = g.scale
+	}
+	b := a11yBuilder{g: g, k: k, tab: map[*renderNode]int{}, ids: map[string]*renderNode{}}
+	for i, rn := range g.focusOrder() {
+		b.tab[rn] = i
+	}
+	var tops []*renderNode
+	if scope := g.trapScope(); scope != nil {
+		tops = []*renderNode{scope} // 模态：其余内容对辅助技术不可见
+	} else {
+		tops = append(tops, g.rootRN)
+		for _, pf := range g.portals {
+			tops = append(tops, pf.overlayRoot)
+		}
+	}
+	for _, rn := range tops {
+		b.indexIDs(rn)
+	}
+	for _, rn := range tops {
+		b.add(root, rn)
+	}
+	return root
+}
+
+type a11yBuilder struct {
+	g   *game
+	k   float32 // 物理像素 / 逻辑像素
+	tab map[*renderNode]int
+	ids map[string]*renderNode
+}
+
+func (b *a11yBuilder) indexIDs(rn *renderNode) {
+	if rn == nil {
+		return
+	}
+	if rn.id != "" {
+		if _, dup := b.ids[rn.id]; !dup {
+			b.ids[rn.id] = rn
+		}
+	}
+	for _, c := range rn.children {
+		b.indexIDs(c)
+	}
+}
+
+// add 把 rn 派生出的节点挂到 parent 下；无语义的容器不建节点，子节点直接挂到 parent。
+func (b *a11yBuilder) add(parent *AccessNode, rn *renderNode) {
+	if rn == nil || rn.a11y.hidden || rn.hidden {
+		return
+	}
+	role := implicitRole(rn)
+	if role == RoleNone || role == RolePresentation {
+		for _, c := range rn.children {
+			b.add(parent, c)
+		}
+		return
+	}
+	n := &AccessNode{
+		Role:      role,
+		Name:      rn.a11y.label,
+		Bounds:    Rect{X: rn.bounds.X / b.k, Y: rn.bounds.Y / b.k, W: rn.bounds.W / b.k, H: rn.bounds.H / b.k},
+		Focusable: rn.focusable,
+		TabIndex:  -1,
+		Checked:   rn.a11y.checked,
+		Expanded:  rn.a11y.expanded,
+		Selected:  rn.a11y.selected,
+		Disabled:  rn.a11y.disabled,
+		rn:        rn,
+	}
+	if i, ok := b.tab[rn]; ok {
+		n.TabIndex = i
+	}
+	n.Focused = b.g.focusedFiber != nil && b.g.focusedFiber.rnode == rn
+	switch {
+	case n.Name != "":
+	case rn.kind == rnText:
+		n.Name = rn.text
+	case rn.kind == rnInput:
+		n.Name = rn.placeholder
+	case nameFromContent(role):
+		n.Name = textContent(rn)
+	}
+	if rn.kind == rnInput {
+		n.Value = rn.value
+	}
+	if ref := b.ids[rn.a11y.describedBy]; ref != nil && rn.a11y.describedBy != "" {
+		n.Description = textContent(ref)
+	}
+	parent.Children = append(parent.Children, n)
+	if leafRole(role) {
+		return
+	}
+	for _, c := range rn.children {
+		b.add(n, c)
+	}
+}
+
+// implicitRole 返回节点的有效角色：显式声明优先，否则按节点种类推断。
+func implicitRole(rn *renderNode) AriaRole {
+	if rn.a11y.role != RoleNone {
+		return rn.a11y.role
+	}
+	switch {
+	case rn.kind == rnText:
+		if rn.text == "" {
+			return RoleNone
+		}
+		return RoleText
+	case rn.kind == rnInput:
+		return RoleTextbox
+	case rn.kind == rnImage:
+		return RoleImage
+	case rn.onClick != nil:
+		return RoleButton
+	case rn.a11y.label != "":
+		return RoleGroup // 有名称的容器（「收货地址」一组输入框）
+	}
+	return RoleNone
+}
+
+// textContent 把子树里的文本按树序以空格连接（无障碍名称/描述的来源）。
+func textContent(rn *renderNode) string {
+	var parts []string
+	var walk func(*renderNode)
+	walk = func(n *renderNode) {
+		if n.a11y.hidden && n != rn {
+			return
+		}
+		if n.a11y.label != "" && n != rn {
+			parts = append(parts, n.a11y.label) // 子节点声明的名称（图标的 AriaLabel）替代其内容
+			return
+		}
+		if n.kind == rnText && n.text != "" {
+			parts = append(parts, n.text)
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(rn)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+// 推断的角色与名称：按钮取文字内容、输入框取 placeholder、纯布局容器不进树。
+func TestAccessibilityTreeImplicitRoles(t *testing.T) {
+	h := Mount(Div(Style(Column),
+		Div(Style(Row), // 布局壳：不该出现在树里
+			Button(Style(Width(60), Height(24)), OnClick(func() {}), Text("保存")),
+			Input(Placeholder("搜索")),
+		),
+		Text("说明文字"),
+	), 300, 200)
+
+	tree := h.AccessibilityTree()
+	if tree.Role != RoleWindow {
+		t.Fatalf("根角色 %q want window", tree.Role)
+	}
+	var roles []string
+	for _, c := range tree.Children {
+		roles = append(roles, string(c.Role)+":"+c.Name)
+	}
+	if got := strings.Join(roles, ","); got != "button:保存,textbox:搜索,text:说明文字" {
+		t.Fatalf("顶层节点 %s —— 布局容器应被拍平、按钮里的文字不该重复出现", got)
+	}
+	if b := tree.Find(RoleButton, "保存"); b.Bounds.W != 60 || b.Bounds.H != 24 {
+		t.Fatalf("按钮 bounds=%+v want 60x24", b.Bounds)
+	}
+}
+
+// 设备缩放不为 1 时，节点 bounds 与根节点一样是逻辑像素。
+func TestAccessibilityTreeBoundsAreLogical(t *testing.T) {
+	defer func() { uiScale = 1 }()
+	uiScale = 2
+	g := newGame() // 400x400 物理像素 = 200x200 逻辑像素
+	g.mountRoot(Div(Style(Padding(10)),
+		Button(Style(Width(60), Height(24)), OnClick(func() {}), Text("保存"))))
+
+	tree := g.accessibilityTree()
+	if tree.Bounds.W != 200 || tree.Bounds.H != 200 {
+		t.Fatalf("根 bounds=%+v want 200x200", tree.Bounds)
+	}
+	if b := tree.Find(RoleButton, "保存").Bounds; b != (Rect{X: 10, Y: 10, W: 60, H: 24}) {
+		t.Fatalf("按钮 bounds=%+v want 逻辑像素 {10 10 60 24}", b)
+	}
+}
+
+// 显式角色、状态、描述；焦点顺序与 Tab 一致。
+func TestAccessibilityTreeStatesAndFocusOrder(t *testing.T) {
+	h := Mount(Div(
+		Div(Role(RoleCheckbox), AriaChecked(true), AriaLabel("记住我"), AriaDescribedBy("hint"),
+			OnClick(func() {}), Div(AriaHidden(), Text("✓"))),
+		Button(OnClick(func() {}), AriaExpanded(false), AriaDisabled(true), Text("更多")),
+		Div(Id("hint"), Text("30 天内免登录")),
+	), 300, 200)
+
+	cb := h.AccessibilityTree().Find(RoleCheckbox, "记住我")
+	if cb == nil {
+		t.Fatalf("找不到复选框：\n%s", h.AccessibilityTree())
+	}
+	if cb.Checked != AriaTrue || cb.Expanded != AriaUnset || cb.Description != "30 天内免登录" {
+		t.Fatalf("checked=%v expanded=%v desc=%q", cb.Checked, cb.Expanded, cb.Description)
+	}
+	more := h.AccessibilityTree().Find(RoleButton, "更多")
+	if more.Expanded != AriaFalse || !more.Disabled {
+		t.Fatalf("更多：expanded=%v disabled=%v", more.Expanded, more.Disabled)
+	}
+	if cb.TabIndex != 0 || more.TabIndex != 1 {
+		t.Fatalf("TabIndex=%d,%d want 0,1", cb.TabIndex, more.TabIndex)
+	}
+
+	h.Tab()
+	h.Tab()
+	tree := h.AccessibilityTree()
+	if !tree.Find(RoleButton, "更多").Focused || tree.Find(RoleCheckbox, "").Focused {
+		t.Fatalf("焦点没反映到树上：\n%s", tree)
+	}
+	if strings.Contains(tree.String(), "✓") {
+		t.Fatalf("AriaHidden 的勾号进了树：\n%s", tree)
+	}
+}
+
+// 模态浮层打开时，树里只有浮层（背景对辅助技术不可见），浮层按打开顺序排在主树后。
+func TestAccessibilityTreeModalHidesBackground(t *testing.T) {
+	app := func(_ struct{}) *Node {
+		open, set := UseState(false)
+		return Div(
+			Button(OnClick(func() { set(true) }), Text("打开")),
+			If(open, Portal(TrapFocus(), Div(Role(RoleDialog), AriaLabel("设置"),
+				Button(OnClick(func() {}), Text("确定"))))),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 300, 200)
+	h.ByRole(RoleButton, "打开").Click()
+
+	tree := h.AccessibilityTree()
+	if tree.Find(RoleButton, "打开") != nil {
+		t.Fatalf("模态打开时背景仍在树里：\n%s", tree)
+	}
+	if d := tree.Find(RoleDialog, "设置"); d == nil || d.Find(RoleButton, "确定") == nil {
+		t.Fatalf("对话框或其按钮不在树里：\n%s", tree)
+	}
+}
+
+// Query 侧的角色与名称与树一致。
+func TestQueryRoleAndAccessibleName(t *testing.T) {
+	h := Mount(Button(OnClick(func() {}), AriaLabel("完成"), Icon(IconCheck, 16)), 100, 100)
+	q := h.ByRole(RoleButton, "完成")
+	if !q.Exists() || q.Role() != RoleButton || q.AccessibleName() != "完成" {
+		t.Fatalf("ByRole exists=%v role=%q name=%q", q.Exists(), q.Role(), q.AccessibleName())
+	}
+}
@@ -20,6 +20,21 @@ func EaseInOut(t float32) float32 {
 	return 1 - u*u*u/2
 }
 
+// animation 是 game.anims 里的一个活动动画（UseTween、UseTransition、UseSpring、UseKeyframes）。
+// 有活动动画时引擎每帧推进并重渲染其组件；全部结束后 anims 为空，静止帧不再重绘。
+type animation interface {
+	advance(dt float32) bool // 推进 dt 毫秒，返回是否仍在进行
+	owner() *Fiber
+	running() bool
+}
+
+// startAnim 把 a 登记到 f 所在窗口的动画列表（调用方保证 a 尚未登记）。
+func startAnim(f *Fiber, a animation) {
+	if g := gameOf(f); g != nil {
+		g.anims = append(g.anims, a)
+	}
+}
+
 type tweenHook struct {
 	cur, from, target float32
 	elapsed, duration float32
@@ -52,9 +67,7 @@ func UseTween(target, durationMs float32, ease Easing) float32 {
 		h.elapsed = 0
 		if !h.active {
 			h.active = true
-			if activeGame != nil {
-				activeGame.anims = append(activeGame.anims, h)
-			}
+			startAnim(f, h)
 		}
 	}
 	return h.cur
@@ -74,8 +87,8 @@ func UseElapsed() float32 {
 	h.fiber = f
 	if !h.active {
 		h.active = true
-		if activeGame != nil {
-			activeGame.loops = append(activeGame.loops, h)
+		if g := gameOf(f); g != nil {
+			g.loops = append(g.loops, h)
 		}
 	}
 	return h.elapsed
@@ -125,9 +138,7 @@ func UseTransition(visible bool, durationMs float32) (bool, float32) {
 		tw.elapsed = 0
 		if !tw.active {
 			tw.active = true
-			if activeGame != nil {
-				activeGame.anims = append(activeGame.anims, tw)
-			}
+			startAnim(f, tw)
 		}
 	}
 	if !visible && !tw.active && tw.cur == 0 {
@@ -136,6 +147,9 @@ func UseTransition(visible bool, durationMs float32) (bool, float32) {
 	return h.mounted, tw.cur
 }
 
+func (h *tweenHook) owner() *Fiber { return h.fiber }
+func (h *tweenHook) running() bool { return h.active }
+
 // advance 推进动画一帧，返回是否仍在进行。
 func (h *tweenHook) advance(dt float32) bool {
 	h.elapsed += dt
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ---- 多窗口 ----
+//
+// 一个窗口就是一个 game：它本来就独占 fiber 根、浮层、焦点、Esc 栈与悬停链，所以多窗口
+// 不需要把这些状态再拆一遍，只要让每个窗口各有一个 game，并解决两件事：
+//
+//   - 状态归属：setter 过去一律排进 activeGame，可窗口 B 里的按钮完全可能调用窗口 A 里
+//     组件的 setter（检查器改主文档就是这样）。所以 fiber 记住自己属于哪个 game
+//     （Fiber.g），标脏时排进它自己的队列，必要时叫醒那个窗口（见 markFiberDirty）。
+//   - 单线程：渲染依赖包级的 activeGame / uiScale / input，后端必须保证同一时刻只有一个
+//     窗口在跑帧，并在跑帧前用 activate 切到该窗口。gio 后端用 uiMu 串行化各窗口循环。
+//
+//	app := ui.NewApp()
+//	app.OpenWindow(ui.Use(Editor, props), ui.WithTitle("文档"))
+//	app.Run() // 阻塞到所有窗口都关闭
+//
+//	// 组件里弹出一个检查器窗口：
+//	win := ui.UseWindow()
+//	ui.Button(ui.OnClick(func() {
+//	    win.App().OpenWindow(ui.Use(Inspector, p), ui.WithTitle("检查器"), ui.WithSize(320, 480))
+//	}), ui.Text("检查"))
+
+// App 是一组窗口的宿主。Run 之前打开的窗口在 Run 时一起出现；运行中打开的窗口立即出现。
+type App struct {
+	mu       sync.Mutex // 保护 windows 与各窗口的 handle（wakeAll/Post 可在任意 goroutine 调用）
+	windows  []*Window
+	running  bool
+	headless bool             // 无头（Harness）：窗口不交给后端，就地挂载
+	rec      *sessionRecorder // TENON_RECORD：录制主窗口的输入
+	wg       sync.WaitGroup
+}
+
+// Window 是 App 里的一个窗口句柄。方法须在渲染线程调用（回调/effect 内），Post 除外。
+type Window struct {
+	app    *App
+	g      *game
+	cfg    windowConfig
+	handle windowHandle // 后端窗口；未启动或无头时为 nil
+	closed atomic.Bool  // teardown 在渲染线程写，Windows 等可能在别的 goroutine 读
+	h      *Harness     // 无头时该窗口的驱动器
+
+	postMu sync.Mutex
+	posts  []func()
+}
+
+// windowHandle 是后端窗口的操作面（gio 为 *gioWindow）。方法可从渲染线程调用。
+type windowHandle interface {
+	setTitle(title string)
+	close()
+	wake()
+}
+
+// WindowOpt 配置 OpenWindow 打开的窗口。
+type WindowOpt func(*windowConfig)
+
+// WithSize 设置窗口的初始逻辑尺寸（非正值忽略）。
+func WithSize(w, h int) WindowOpt {
+	return func(c *windowConfig) {
+		if w > 0 && h > 0 {
+			c.w, c.h = w, h
+		}
+	}
+}
+
+// WithTitle 设置窗口标题。
+func WithTitle(t string) WindowOpt { return func(c *windowConfig) { c.title = t } }
+
+// WithMinSize / WithMaxSize 限制窗口的逻辑尺寸（0=不限制）。
+func WithMinSize(w, h int) WindowOpt { return func(c *windowConfig) { c.minW, c.minH = w, h } }
+func WithMaxSize(w, h int) WindowOpt { return func(c *windowConfig) { c.maxW, c.maxH = w, h } }
+
+// WithFullscreen 让窗口以全屏打开。
+func WithFullscreen(on bool) WindowOpt { return func(c *windowConfig) { c.fullscreen = on } }
+
+// WithInspectorKey 让 Ctrl+Shift+I 开关该窗口的检查器面板。默认关闭：这个组合键照常交给
+// OnKeyDown 与 UseHotkey，检查器只能用 Window.ShowInspector 打开。
+func WithInspectorKey(on bool) WindowOpt { return func(c *windowConfig) { c.inspectKey = on } }
+
+// NewApp 新建一个还没有窗口的应用。
+func NewApp() *App { return &App{} }
+
+// OpenWindow 打开一个以 root 为根的窗口并返回其句柄。未设置的选项取默认值
+// （800×600、标题 "Tenon UI"），不受 WindowSize/WindowTitle 等全局设置影响。
+func (a *App) OpenWindow(root *Node, opts ...WindowOpt) *Window {
+	cfg := windowConfig{w: 800, h: 600, title: "Tenon UI", sync: FrameSync}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return a.openWindow(root, cfg)
+}
+
+func (a *App) openWindow(root *Node, cfg windowConfig) *Window {
+	w := &Window{app: a, cfg: cfg}
+	w.g = &game{root: root, w: cfg.w, h: cfg.h, win: w, scale: 1}
+	a.mu.Lock()
+	a.windows = append(a.windows, w)
+	a.mu.Unlock()
+	switch {
+	case a.headless:
+		w.h = mountHeadless(w)
+	case a.running:
+		w.setHandle(backendOpenWindow(w))
+	}
+	return w
+}
+
+// Run 显示所有窗口并驱动它们的事件循环，直到全部关闭才返回。
+func (a *App) Run() {
+	if a.headless || a.running {
+		return
+	}
+	backendWake = a.wakeAll // 后台 Post 不知道要给哪个窗口，全部叫醒，谁先出帧谁排空
+	defer func() { backendWake = nil }()
+	a.running = true
+	a.serveInspectorFromEnv()
+	a.recordFromEnv()
+	a.mu.Lock()
+	pending := append([]*Window(nil), a.windows...)
+	a.mu.Unlock()
+	for _, w := range pending {
+		if !w.closed.Load() && w.backend() == nil {
+			w.setHandle(backendOpenWindow(w))
+		}
+	}
+	a.wg.Wait()
+	a.rec.close()
+	a.running = false
+}
+
+// Windows 返回仍打开着的窗口（按打开顺序）。
+func (a *App) Windows() []*Window {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]*Window, 0, len(a.windows))
+	for _, w := range a.windows {
+		if !w.closed.Load() {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func (a *App) wakeAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, w := range a.windows {
+		if w.handle != nil {
+			w.handle.wake()
+		}
+	}
+}
+
+func (w *Window) backend() windowHandle {
+	w.app.mu.Lock()
+	defer w.app.mu.Unlock()
+	return w.handle
+}
+
+func (w *Window) setHandle(h windowHandle) {
+	w.app.mu.Lock()
+	w.handle = h
+	w.app.mu.Unlock()
+}
+
+// App 返回窗口所属的应用（用于从一个窗口里再打开别的窗口）。
+func (w *Window) App() *App { return w.app }
+
+// Title 返回窗口当前的标题。
+func (w *Window) Title() string { return w.cfg.title }
+
+// SetTitle 修改窗口标题。
+func (w *Window) SetTitle(t string) {
+	w.cfg.title = t
+	if h := w.backend(); h != nil {
+		h.setTitle(t)
+	}
+}
+
+// Viewport 返回该窗口视口的逻辑尺寸（全局 Viewport 的按窗口版本）。
+func (w *Window) Viewport() Rect {
+	s := w.g.scale
+	if s <= 0 {
+		s = 1
+	}
+	return Rect{W: float32(w.g.w) / s, H: float32(w.g.h) / s}
+}
+
+// Closed 报告窗口是否已关闭。
+func (w *Window) Closed() bool { return w.closed.Load() }
+
+// Close 关闭窗口：卸载它的组件树（effect 的清理照常执行），并让后端销毁窗口。
+// 其余窗口不受影响；最后一个窗口关闭后 Run 返回。
+func (w *Window) Close() {
+	if w.closed.Load() {
+		return
+	}
+	if h := w.backend(); h != nil {
+		h.close() // 后端收到销毁事件后调用 teardown
+		return
+	}
+	w.teardown()
+}
+
+// teardown 卸载窗口的组件树并把它从应用里摘掉。须在渲染线程调用。
+func (w *Window) teardown() {
+	if w.closed.Swap(true) {
+		return
+	}
+	g := w.g
+	prev := activeGame
+	g.activate()
+	if g.rootFiber != nil {
+		unmount(g.rootFiber)
+		g.rootFiber, g.rootRN = nil, nil
+	}
+	if g.dragLayer != nil {
+		unmount(g.dragLayer)
+		g.dragLayer = nil
+	}
+	if g.inspectLayer != nil {
+		unmount(g.inspectLayer)
+		g.inspectLayer = nil
+	}
+	g.dnd = nil
+	g.portals, g.escStack, g.hovered = nil, nil, nil
+	g.focusedFiber, g.pressedNode, g.dragging = nil, nil, nil
+	if prev != nil && prev != g {
+		prev.activate()
+	}
+	w.setHandle(nil)
+}
+
+// Post 把 fn 排队到该窗口的下一帧执行（可从任意 goroutine 调用）。与全局 Post 的区别是
+// fn 执行时当前窗口一定是 w —— Viewport、UseWindow 等按窗口取值的 API 会取到 w。
+func (w *Window) Post(fn func()) {
+	if fn == nil {
+		return
+	}
+	w.postMu.Lock()
+	w.posts = append(w.posts, fn)
+	w.postMu.Unlock()
+	if h := w.backend(); h != nil {
+		h.wake()
+	}
+}
+
+// drainPosts 在渲染线程执行该窗口已排队的函数。
+func (w *Window) drainPosts() {
+	w.postMu.Lock()
+	fns := w.posts
+	w.posts = nil
+	w.postMu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+func (w *Window) hasPosts() bool {
+	w.postMu.Lock()
+	defer w.postMu.Unlock()
+	return len(w.posts) > 0
+}
+
+// UseWindow 返回调用组件所在的窗口（用 Mount 挂载的树也有一个无头窗口）。
+func UseWindow() *Window {
+	if g := renderingGame(); g != nil {
+		return g.win
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+)
+
+// 检查器窗口改主窗口的状态：setter 必须排进主窗口自己的队列并让它重渲染，
+// 而不是排进「当前窗口」（那样主窗口永远不会刷新）。
+func TestWindowCrossWindowSetState(t *testing.T) {
+	var setMain func(int)
+	main := func(_ struct{}) *Node {
+		n, set := UseState(0)
+		setMain = set
+		return Text(fmt.Sprintf("main=%d", n))
+	}
+	inspector := func(_ struct{}) *Node {
+		return Button(Style(Width(60), Height(24)), OnClick(func() { setMain(7) }), Text("set"))
+	}
+
+	h := Mount(Use(main, struct{}{}), 200, 100)
+	h2 := h.OpenWindow(Use(inspector, struct{}{}), WithSize(100, 50))
+
+	if !h2.Root().ByText("set").Click() {
+		t.Fatal("检查器窗口的按钮没点到")
+	}
+	if !h.Root().ByText("main=7").Exists() {
+		t.Fatalf("主窗口没有重渲染：texts=%v", h.Root().Texts())
+	}
+	if len(h.Windows()) != 2 {
+		t.Fatalf("Windows()=%d want 2", len(h.Windows()))
+	}
+}
+
+// 焦点与 Esc 栈按窗口隔离：一个窗口里 Tab、按 Esc 不该影响另一个窗口。
+func TestWindowFocusAndEscapeArePerWindow(t *testing.T) {
+	escA, escB := 0, 0
+	win := func(esc *int) *Node {
+		return Use(func(_ struct{}) *Node {
+			UseEscape(true, func() { *esc++ })
+			return Div(Button(Style(Width(40), Height(20)), OnClick(func() {}), Text("b")))
+		}, struct{}{})
+	}
+	h := Mount(win(&escA), 100, 100)
+	h2 := h.OpenWindow(win(&escB))
+
+	if !h.Tab().Exists() {
+		t.Fatal("窗口 A 的 Tab 没聚焦到按钮")
+	}
+	if h2.Focused().Exists() {
+		t.Fatal("窗口 A 的焦点漏到了窗口 B")
+	}
+
+	h2.Escape()
+	if escA != 0 || escB != 1 {
+		t.Fatalf("escA=%d escB=%d want 0,1 —— Esc 只该交给本窗口栈顶", escA, escB)
+	}
+}
+
+// Close 卸载该窗口（effect 清理照常执行），其余窗口不受影响。
+func TestWindowCloseUnmountsOnlyThatWindow(t *testing.T) {
+	cleaned := map[string]bool{}
+	comp := func(name string) *Node {
+		return Use(func(_ struct{}) *Node {
+			UseEffect(func() Cleanup { return func() { cleaned[name] = true } })
+			return Text(name)
+		}, struct{}{})
+	}
+	h := Mount(comp("a"), 100, 100)
+	h2 := h.OpenWindow(comp("b"))
+
+	h2.Window().Close()
+	if !cleaned["b"] || cleaned["a"] {
+		t.Fatalf("cleaned=%v want 只有 b", cleaned)
+	}
+	if !h2.Window().Closed() || len(h.Windows()) != 1 {
+		t.Fatalf("关闭后 Closed=%v Windows=%d", h2.Window().Closed(), len(h.Windows()))
+	}
+	h.Resize(120, 100) // 剩下的窗口照常工作
+	if !h.Root().ByText("a").Exists() {
+		t.Fatal("窗口 A 被连带卸载了")
+	}
+}
+
+// UseWindow 拿到组件所在的窗口；Viewport 与标题都是按窗口的。
+func TestUseWindowTitleAndViewport(t *testing.T) {
+	var got *Window
+	comp := func(_ struct{}) *Node {
+		got = UseWindow()
+		return Text("x")
+	}
+	h := Mount(Text("main"), 300, 200)
+	h2 := h.OpenWindow(Use(comp, struct{}{}), WithSize(320, 480), WithTitle("检查器"))
+
+	if got != h2.Window() {
+		t.Fatal("UseWindow 没返回组件所在的窗口")
+	}
+	if v := got.Viewport(); v.W != 320 || v.H != 480 {
+		t.Fatalf("Viewport=%+v want 320x480", v)
+	}
+	if v := h.Window().Viewport(); v.W != 300 || v.H != 200 {
+		t.Fatalf("主窗口 Viewport=%+v want 300x200", v)
+	}
+	got.SetTitle("新标题")
+	if got.Title() != "新标题" || h.Window().Title() != "Tenon UI" {
+		t.Fatalf("标题 %q / %q", got.Title(), h.Window().Title())
+	}
+}
+
+// Window.Post 的函数执行时当前窗口就是目标窗口（Viewport 等按窗口取值）。
+func TestWindowPostRunsInTargetWindow(t *testing.T) {
+	h := Mount(Text("a"), 100, 100)
+	h2 := h.OpenWindow(Text("b"), WithSize(40, 30))
+
+	var vp Rect
+	h2.Window().Post(func() { vp = Viewport() })
+	if vp != (Rect{}) {
+		t.Fatal("Post 不该同步执行")
+	}
+	h.Flush()
+	if vp.W != 40 || vp.H != 30 {
+		t.Fatalf("Viewport=%+v want 40x30 —— Post 没切到目标窗口", vp)
+	}
+}
+
+// App.Run 把每个窗口连同各自的配置交给后端。
+func TestAppRunOpensEveryWindow(t *testing.T) {
+	old := backendOpenWindow
+	t.Cleanup(func() { backendOpenWindow = old })
+
+	var titles []string
+	backendOpenWindow = func(w *Window) windowHandle {
+		titles = append(titles, w.cfg.title)
+		return nil
+	}
+	a := NewApp()
+	a.OpenWindow(Text("a"), WithTitle("一"))
+	a.OpenWindow(Text("b"), WithTitle("二"), WithSize(10, 10))
+	a.Run()
+
+	if fmt.Sprint(titles) != "[一 二]" {
+		t.Fatalf("后端收到的窗口 %v want [一 二]", titles)
+	}
+}
+
+// 渲染 panic 被 ErrorBoundary 接住后 currentFiber 不残留：渲染之外的 Viewport 取当前窗口，
+// 而不是那棵出错的树所在的窗口。
+func TestViewportOutsideRenderAfterPanic(t *testing.T) {
+	boom := func(_ struct{}) *Node { panic("boom") }
+	Mount(ErrorBoundary(func(any, func()) *Node { return Text("caught") }, Use(boom, struct{}{})), 200, 100)
+	if currentFiber != nil {
+		t.Fatal("渲染结束后 currentFiber 应复原为 nil")
+	}
+	h := Mount(Text("a"), 40, 30)
+	if vp := Viewport(); vp.W != h.Window().Viewport().W || vp.H != h.Window().Viewport().H {
+		t.Fatalf("渲染之外 Viewport=%+v，应为当前窗口的 %+v", vp, h.Window().Viewport())
+	}
+}
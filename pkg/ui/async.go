@@ -0,0 +1,172 @@
+package ui
+
+import "context"
+
+// ---- 异步数据：UseAsync + Suspense ----
+//
+// 以前每个界面都要手写 UseEffect + goroutine + Post，再各自维护 loading / error 状态。
+// UseAsync 把这一套收进一个 hook：
+//
+//	user := ui.UseAsync(func(ctx context.Context) (User, error) {
+//	    return api.GetUser(ctx, p.ID)
+//	}, p.ID)
+//	if user.Err != nil { ... }
+//	if user.Pending { ... }
+//	return ui.Text(user.Value.Name)
+//
+// fn 在后台 goroutine 中执行，结果回到渲染线程后触发重渲染；deps 变化时取消上一次的 ctx
+// 并重新请求，组件卸载时同样取消。被取消的请求即使返回了结果也会被丢弃。
+//
+// 放在 Suspense 里时，挂起中的 UseAsync 会让最近的 Suspense 显示 fallback，
+// 组件本身不必再处理 Pending。
+
+// Async 是 UseAsync 的当前结果。
+type Async[T any] struct {
+	Value   T     // 最近一次成功的结果；重新请求期间保留旧值
+	Err     error // 最近一次请求的错误（成功或重新请求时清空）
+	Pending bool  // 请求进行中
+}
+
+type asyncHook struct {
+	deps    []any
+	started bool
+	gen     int // 每次（重新）请求加一；回来的结果代号不符即为过期
+	value   any
+	err     error
+	pending bool
+
+	fiber    *Fiber
+	boundary *Fiber // 挂起时登记到的 Suspense
+}
+
+// UseAsync 在后台执行 fn 并返回其状态；deps 变化时取消并重新执行（语义同 UseEffect 的 deps）。
+// fn 应当尊重 ctx：卸载或 deps 变化后 ctx 会被取消。
+func UseAsync[T any](fn func(ctx context.Context) (T, error), deps ...any) Async[T] {
+	f := currentFiber
+	_, raw := nextHook(f, func() any { return &asyncHook{fiber: f} })
+	h := raw.(*asyncHook)
+	if !h.started || !depsEqual(h.deps, deps) {
+		h.started = true
+		h.deps = deps
+		h.gen++
+		h.err = nil
+		// 渲染期就进入挂起，Suspense 在同一帧内切到 fallback，不会先闪一下半成品
+		h.suspend()
+	}
+	gen := h.gen
+	UseEffect(func() Cleanup {
+		ctx, cancel := context.WithCancel(context.Background())
+		g := gameOf(f)
+		g.async.Add(1)
+		go func() {
+			defer g.async.Done()
+			v, err := fn(ctx)
+			g.post(func() {
+				if h.gen != gen || ctx.Err() != nil {
+					return // 已被新请求取代，或已取消
+				}
+				if f.unmounted {
+					h.resume()
+					return
+				}
+				if err == nil {
+					h.value = v
+				}
+				h.err = err
+				h.resume()
+				markFiberDirty(f)
+			})
+		}()
+		return func() {
+			cancel()
+			if f.unmounted {
+				h.resume()
+			}
+		}
+	}, gen)
+
+	out := Async[T]{Err: h.err, Pending: h.pending}
+	if v, ok := h.value.(T); ok {
+		out.Value = v
+	}
+	return out
+}
+
+// suspend 标记请求进行中，并登记到最近的 Suspense。
+func (h *asyncHook) suspend() {
+	h.pending = true
+	if h.boundary == nil {
+		h.boundary = nearestSuspense(h.fiber)
+	}
+	if b := h.boundary; b != nil {
+		if len(b.suspended) == 0 {
+			markFiberDirty(b)
+		}
+		b.suspended[h] = struct{}{}
+	}
+}
+
+// resume 结束挂起；Suspense 的最后一个挂起项结束时让它切回子内容。
+func (h *asyncHook) resume() {
+	h.pending = false
+	b := h.boundary
+	if b == nil {
+		return
+	}
+	if _, ok := b.suspended[h]; !ok {
+		return
+	}
+	delete(b.suspended, h)
+	if len(b.suspended) == 0 {
+		markFiberDirty(b)
+	}
+}
+
+// nearestSuspense 返回 f 最近的 Suspense 祖先。
+func nearestSuspense(f *Fiber) *Fiber {
+	for a := f.parent; a != nil; a = a.parent {
+		if a.suspended != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+type suspenseProps struct {
+	fallback *Node
+	children []*Node
+}
+
+// Suspense 在子树中有 UseAsync 挂起时显示 fallback，全部就绪后显示子内容（类似 ErrorBoundary
+// 之于 panic）。挂起期间子树保持挂载、只是隐藏，所以请求不会因切换而被取消，状态也不会丢。
+// 子元素放在一个纵向容器里。
+//
+//	ui.Suspense(ui.Text("加载中…"),
+//	    Use(UserCard, id),
+//	    Use(UserPosts, id),
+//	)
+func Suspense(fallback *Node, children ...*Node) *Node {
+	return Use(suspenseC, suspenseProps{fallback: fallback, children: children})
+}
+
+func suspenseC(p suspenseProps) *Node {
+	f := currentFiber
+	if f.suspended == nil {
+		f.suspended = map[*asyncHook]struct{}{} // 必须在子树首次渲染前就位，子组件才找得到它
+	}
+	pending := len(f.suspended) > 0
+	content := Div(Style(func(s *StyleProps) { s.hidden = pending }), Fragment(p.children...))
+	if !pending {
+		return Fragment(content)
+	}
+	return Fragment(content, p.fallback)
+}
+
+// post 把 fn 排队到 g 所属窗口的下一帧；没有窗口时退回全局 Post。
+func (g *game) post(fn func()) {
+	if g.win != nil {
+		g.win.Post(fn)
+		return
+	}
+	Post(fn)
+}
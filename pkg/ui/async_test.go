@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// 请求进行中 Pending 为真；放行后 WaitAsync 拿到结果并重渲染。
+func TestUseAsyncPendingThenValue(t *testing.T) {
+	release := make(chan struct{})
+	app := func(_ struct{}) *Node {
+		r := UseAsync(func(ctx context.Context) (string, error) {
+			<-release
+			return "loaded", nil
+		})
+		if r.Pending {
+			return Text("loading")
+		}
+		return Text(r.Value)
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	if !h.Root().ByText("loading").Exists() {
+		t.Fatal("请求未返回时应显示 loading")
+	}
+	close(release)
+	h.WaitAsync()
+	if !h.Root().ByText("loaded").Exists() {
+		t.Fatalf("WaitAsync 后应显示结果，实际 %q", h.Root().AllText())
+	}
+}
+
+// 错误通过 Err 返回，Pending 同时结束。
+func TestUseAsyncError(t *testing.T) {
+	app := func(_ struct{}) *Node {
+		r := UseAsync(func(ctx context.Context) (int, error) { return 0, errors.New("boom") })
+		if r.Err != nil && !r.Pending {
+			return Text("err: " + r.Err.Error())
+		}
+		return Text("…")
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	h.WaitAsync()
+	if !h.Root().ByText("err: boom").Exists() {
+		t.Fatalf("应显示错误，实际 %q", h.Root().AllText())
+	}
+}
+
+// deps 变化取消上一次的 ctx，旧请求的结果被丢弃；卸载同样取消。
+func TestUseAsyncCancelsOnDepsChangeAndUnmount(t *testing.T) {
+	cancelled := make(chan int, 4)
+	var setID func(int)
+	var setShow func(bool)
+	child := func(id int) *Node {
+		r := UseAsync(func(ctx context.Context) (int, error) {
+			if id == 1 {
+				<-ctx.Done() // 只会因取消而返回
+				cancelled <- id
+				return -1, ctx.Err()
+			}
+			return id * 10, nil
+		}, id)
+		if r.Pending {
+			return Text("pending")
+		}
+		if r.Err != nil {
+			return Text("stale error leaked")
+		}
+		return Text("v" + strconv.Itoa(r.Value))
+	}
+	app := func(_ struct{}) *Node {
+		id, set := UseState(1)
+		show, sh := UseState(true)
+		setID, setShow = set, sh
+		if !show {
+			return Text("gone")
+		}
+		return Use(child, id)
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	setID(2)
+	h.Flush() // 重渲染才会取消旧请求；此前旧请求一直阻塞
+	h.WaitAsync()
+	if got := <-cancelled; got != 1 {
+		t.Fatalf("cancelled %d", got)
+	}
+	if !h.Root().ByText("v20").Exists() {
+		t.Fatalf("应只显示新请求的结果，实际 %q", h.Root().AllText())
+	}
+
+	setID(1)
+	h.Flush()
+	setShow(false)
+	h.Flush()
+	h.WaitAsync()
+	if got := <-cancelled; got != 1 {
+		t.Fatal("卸载后 ctx 应被取消")
+	}
+}
+
+// Suspense：任一后代挂起时显示 fallback，子内容保持挂载但不可见；全部就绪后切回。
+func TestSuspenseShowsFallbackUntilAllReady(t *testing.T) {
+	gates := map[string]chan struct{}{"a": make(chan struct{}), "b": make(chan struct{})}
+	card := func(name string) *Node {
+		r := UseAsync(func(ctx context.Context) (string, error) {
+			<-gates[name]
+			return "ok", nil
+		}, name)
+		return Text("card " + name + ":" + r.Value)
+	}
+	h := Mount(Div(Suspense(Text("loading"), Use(card, "a"), Use(card, "b"))), 200, 100)
+	if !h.Root().ByText("loading").Exists() {
+		t.Fatalf("挂起时应显示 fallback，实际 %q", h.Root().AllText())
+	}
+	for _, op := range h.Paint() {
+		if op.Kind == "text" && op.Text != "loading" {
+			t.Fatalf("挂起中的子内容不应绘制，却画了 %q", op.Text)
+		}
+	}
+
+	close(gates["a"])
+	close(gates["b"])
+	h.WaitAsync()
+	if h.Root().ByText("loading").Exists() {
+		t.Fatal("全部就绪后 fallback 应消失")
+	}
+	if !h.Root().ByText("card a:ok").Exists() || !h.Root().ByText("card b:ok").Exists() {
+		t.Fatalf("应显示子内容，实际 %q", h.Root().AllText())
+	}
+}
@@ -45,4 +45,5 @@ var (
 	backendNewBitmap  func(img image.Image) bitmap                       // 解码后的图像 -> 位图句柄
 	backendNewVecPath func(svgPath string, scale float32) vecPath        // SVG 路径 d -> 矢量句柄；无内容返回 nil
 	backendRun        func(root *Node, cfg windowConfig)                 // 启动窗口与渲染/事件循环（阻塞）
+	backendOpenWindow func(w *Window) windowHandle                       // App 运行中为 w 建窗并起事件循环（不阻塞）
 )
@@ -0,0 +1,551 @@
+package ui
+
+import (
+	"slices"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+
+	"github.com/sjm1327605995/tenon/yoga"
+)
+
+// ---- 双向文本（BiDi，UAX#9） ----
+//
+// 折行始终按逻辑顺序（字节偏移不变，光标/选区都以它为准）；折好的每一行再切成方向一致的段
+// （bidiPiece），按视觉顺序从左到右排好 x。绘制时逐段交给后端 —— 单方向的一段由后端整体
+// 塑形（阿拉伯文连写照常），段与段之间的先后由这里决定。
+//
+// 段落方向：Direction(RTL/LTR) 显式指定，可继承；未指定时按首个强方向字符判断
+// （与 HTML 的 dir="auto" 相同），所以一段纯阿拉伯文不设任何样式也会右对齐、从右往左排。
+// Direction 同时设置 yoga 的布局方向：Row 镜像、JustifyStart 贴右。
+//
+//	ui.Div(ui.Style(ui.Row, ui.Direction(ui.RTL)), ui.Text("שלום"), ui.Input(...))
+
+// TextDirection 是文本与布局的书写方向。
+type TextDirection int
+
+const (
+	DirAuto TextDirection = iota // 未指定：继承，否则按首个强方向字符
+	LTR
+	RTL
+)
+
+// Direction 设置元素的书写方向：后代文本的段落方向、文本起始对齐，以及 yoga 布局方向。
+func Direction(d TextDirection) StyleOpt {
+	return func(s *StyleProps) { s.textDir = d }
+}
+
+// yogaDirection 把书写方向映射为 yoga 的布局方向（未指定即继承父节点）。
+func yogaDirection(d TextDirection) yoga.Direction {
+	switch d {
+	case LTR:
+		return yoga.DirectionLTR
+	case RTL:
+		return yoga.DirectionRTL
+	}
+	return yoga.DirectionInherit
+}
+
+// bidiPiece 是一行里方向一致的一段：逻辑字节区间 [lo,hi)（相对行文本）与其视觉位置。
+type bidiPiece struct {
+	lo, hi int
+	rtl    bool
+	x, w   float32 // 相对行首（视觉左端）
+}
+
+// bidiLine 是排好视觉顺序的一行。
+type bidiLine struct {
+	text   string
+	rtl    bool        // 段落方向
+	pieces []bidiPiece // 视觉顺序，左到右
+	width  float32
+}
+
+// hasRTL 报告 s 是否含从右往左的字符；不含时整行就是一段 LTR，走快路径。
+func hasRTL(s string) bool {
+	for i := 0; i < len(s); {
+		if s[i] < 0x80 {
+			i++
+			continue
+		}
+		p, sz := bidi.LookupString(s[i:])
+		switch p.Class() {
+		case bidi.R, bidi.AL, bidi.AN, bidi.RLE, bidi.RLO, bidi.RLI:
+			return true
+		}
+		if sz <= 0 {
+			sz = 1
+		}
+		i += sz
+	}
+	return false
+}
+
+// paragraphRTL 解析段落方向：显式方向优先，否则取首个强方向字符（无强字符为 LTR）。
+func paragraphRTL(s string, dir TextDirection) bool {
+	switch dir {
+	case RTL:
+		return true
+	case LTR:
+		return false
+	}
+	for i := 0; i < len(s); {
+		p, sz := bidi.LookupString(s[i:])
+		switch p.Class() {
+		case bidi.L:
+			return false
+		case bidi.R, bidi.AL:
+			return true
+		}
+		if sz <= 0 {
+			sz = 1
+		}
+		i += sz
+	}
+	return false
+}
+
+// bidiSpan 是按逻辑顺序切出的同层级段（字节区间）。level 为 UAX#9 的解析层级：
+// 段落层级为 0（LTR）或 1（RTL），奇数层级从右往左。
+type bidiSpan struct {
+	lo, hi int
+	level  int
+	rtl    bool
+}
+
+// bidiSpans 把一行切成层级一致的逻辑段。段落方向由调用方给定（rtlBase），不再自动检测。
+//
+// 只实现不含显式嵌入/隔离控制符的 UAX#9 子集（界面文本几乎不会出现这些控制符，
+// 出现时按中性字符处理）：W1–W7 解析弱类型，N1–N2 解析中性字符，I1–I2 定层级，
+// L1 把行尾空白复位到段落层级。
+func bidiSpans(s string, rtlBase bool) []bidiSpan {
+	if s == "" {
+		return nil
+	}
+	if !rtlBase && !hasRTL(s) {
+		return []bidiSpan{{0, len(s), 0, false}}
+	}
+	base := 0
+	if rtlBase {
+		base = 1
+	}
+	levels, offs := bidiLevels(s, base)
+	var spans []bidiSpan
+	for i, lv := range levels {
+		if n := len(spans); n > 0 && spans[n-1].level == lv {
+			spans[n-1].hi = offs[i+1]
+			continue
+		}
+		spans = append(spans, bidiSpan{offs[i], offs[i+1], lv, lv%2 == 1})
+	}
+	return spans
+}
+
+// bidiLevels 返回 s 每个 rune 的解析层级，以及各 rune 的字节偏移（多一个末尾 len(s)）。
+func bidiLevels(s string, base int) ([]int, []int) {
+	var cls []bidi.Class
+	var offs []int
+	for i := 0; i < len(s); {
+		p, sz := bidi.LookupString(s[i:])
+		if sz <= 0 {
+			sz = 1
+		}
+		c := p.Class()
+		switch c {
+		case bidi.L, bidi.R, bidi.AL, bidi.EN, bidi.ES, bidi.ET, bidi.AN, bidi.CS,
+			bidi.NSM, bidi.B, bidi.S, bidi.WS:
+		default:
+			c = bidi.ON // 显式控制符、BN 等一律按中性字符
+		}
+		cls = append(cls, c)
+		offs = append(offs, i)
+		i += sz
+	}
+	offs = append(offs, len(s))
+	orig := append([]bidi.Class(nil), cls...)
+	n := len(cls)
+	sos := bidi.L
+	if base == 1 {
+		sos = bidi.R
+	}
+
+	// W1：NSM 取前一个字符的类型。W2：EN 前面最近的强字符是 AL 时改为 AN。W3：AL 改为 R。
+	lastStrong := sos
+	for i := range cls {
+		if cls[i] == bidi.NSM {
+			if i == 0 {
+				cls[i] = sos
+			} else {
+				cls[i] = cls[i-1]
+			}
+		}
+		switch cls[i] {
+		case bidi.L, bidi.R, bidi.AL:
+			lastStrong = cls[i]
+		case bidi.EN:
+			if lastStrong == bidi.AL {
+				cls[i] = bidi.AN
+			}
+		}
+	}
+	for i := range cls {
+		if cls[i] == bidi.AL {
+			cls[i] = bidi.R
+		}
+	}
+	// W4：数字之间的单个分隔符随数字。
+	for i := 1; i+1 < n; i++ {
+		a, b := cls[i-1], cls[i+1]
+		switch {
+		case cls[i] == bidi.ES && a == bidi.EN && b == bidi.EN:
+			cls[i] = bidi.EN
+		case cls[i] == bidi.CS && a == b && (a == bidi.EN || a == bidi.AN):
+			cls[i] = a
+		}
+	}
+	// W5：挨着 EN 的一串 ET 变为 EN。
+	for i := 0; i < n; {
+		if cls[i] != bidi.ET {
+			i++
+			continue
+		}
+		j := i
+		for j < n && cls[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && cls[i-1] == bidi.EN) || (j < n && cls[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				cls[k] = bidi.EN
+			}
+		}
+		i = j
+	}
+	// W6：剩下的分隔符与终止符变为中性。W7：前面最近的强字符是 L 时 EN 改为 L。
+	lastStrong = sos
+	for i := range cls {
+		switch cls[i] {
+		case bidi.ES, bidi.ET, bidi.CS:
+			cls[i] = bidi.ON
+		case bidi.L, bidi.R:
+			lastStrong = cls[i]
+		case bidi.EN:
+			if lastStrong == bidi.L {
+				cls[i] = bidi.L
+			}
+		}
+	}
+	// N1/N2：一串中性字符两侧方向相同（数字算 R）则随两侧，否则取段落方向。
+	strongDir := func(c bidi.Class) bidi.Class {
+		if c == bidi.EN || c == bidi.AN {
+			return bidi.R
+		}
+		return c
+	}
+	neutral := func(c bidi.Class) bool {
+		return c == bidi.ON || c == bidi.WS || c == bidi.S || c == bidi.B
+	}
+	for i := 0; i < n; {
+		if !neutral(cls[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < n && neutral(cls[j]) {
+			j++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = strongDir(cls[i-1])
+		}
+		if j < n {
+			after = strongDir(cls[j])
+		}
+		dir := sos
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			cls[k] = dir
+		}
+		i = j
+	}
+	// I1/I2：偶数层级上 R 升一级、数字升两级；奇数层级上 L 与数字升一级。
+	levels := make([]int, n)
+	for i, c := range cls {
+		lv := base
+		switch {
+		case base == 0 && c == bidi.R:
+			lv = 1
+		case base == 0 && (c == bidi.EN || c == bidi.AN):
+			lv = 2
+		case base == 1 && (c == bidi.L || c == bidi.EN || c == bidi.AN):
+			lv = 2
+		}
+		levels[i] = lv
+	}
+	// L1：分段符、行尾空白及其前的空白复位到段落层级。
+	trailing := true
+	for i := n - 1; i >= 0; i-- {
+		switch orig[i] {
+		case bidi.S, bidi.B:
+			levels[i] = base
+			trailing = true
+		case bidi.WS:
+			if trailing {
+				levels[i] = base
+			}
+		default:
+			trailing = false
+		}
+	}
+	return levels, offs
+}
+
+// visualOrder 返回逻辑段的视觉顺序（下标）。段内方向由后端塑形处理；段间按 UAX#9 L2：
+// 从最高层级往下，到最低的奇数层级为止，把每一段层级 ≥ k 的连续段整体倒序。
+func visualOrder(spans []bidiSpan) []int {
+	out := make([]int, len(spans))
+	hi, lowOdd := 0, -1
+	for i, sp := range spans {
+		out[i] = i
+		hi = max(hi, sp.level)
+		if sp.level%2 == 1 && (lowOdd < 0 || sp.level < lowOdd) {
+			lowOdd = sp.level
+		}
+	}
+	if lowOdd < 0 {
+		return out
+	}
+	for k := hi; k >= lowOdd; k-- {
+		for i := 0; i < len(out); {
+			if spans[out[i]].level < k {
+				i++
+				continue
+			}
+			j := i
+			for j < len(out) && spans[out[j]].level >= k {
+				j++
+			}
+			slices.Reverse(out[i:j])
+			i = j
+		}
+	}
+	return out
+}
+
+// newBidiLine 为一行文本算出视觉排布。dir 为该行所在段落的方向：折行产生的续行要传
+// 段落解析出的方向（见 paragraphDirAt），DirAuto 只适用于自成一段的单行。
+func newBidiLine(s string, dir TextDirection, face fontFace, lineH float64) bidiLine {
+	l := bidiLine{text: s, rtl: paragraphRTL(s, dir)}
+	spans := bidiSpans(s, l.rtl)
+	x := float32(0)
+	for _, i := range visualOrder(spans) {
+		sp := spans[i]
+		w := measureW(s[sp.lo:sp.hi], face, lineH)
+		l.pieces = append(l.pieces, bidiPiece{sp.lo, sp.hi, sp.rtl, x, w})
+		x += w
+	}
+	l.width = x
+	return l
+}
+
+// startX 返回该行在宽 avail 的框里的起点：RTL 段落贴右（text-align: start）。
+func (l bidiLine) startX(avail float32) float32 {
+	if l.rtl && avail > l.width {
+		return avail - l.width
+	}
+	return 0
+}
+
+// caretX 返回逻辑字节偏移 off 处光标的 x（相对行首）。
+func (l bidiLine) caretX(off int, face fontFace, lineH float64) float32 {
+	if len(l.pieces) == 0 {
+		return 0
+	}
+	off = clampi(off, 0, len(l.text))
+	// 光标落在「下一个字符」所在的段；行尾则取逻辑上最后一段的末端。
+	var pc *bidiPiece
+	for i := range l.pieces {
+		p := &l.pieces[i]
+		if off >= p.lo && off < p.hi {
+			pc = p
+			break
+		}
+	}
+	if pc == nil {
+		for i := range l.pieces {
+			if l.pieces[i].hi == len(l.text) {
+				pc = &l.pieces[i]
+			}
+		}
+		if pc == nil {
+			return 0
+		}
+	}
+	d := measureW(l.text[pc.lo:off], face, lineH)
+	if pc.rtl {
+		return pc.x + pc.w - d
+	}
+	return pc.x + d
+}
+
+// offsetAt 返回行内 x（相对行首）处最近的逻辑字节偏移。
+func (l bidiLine) offsetAt(x float32, face fontFace, lineH float64) int {
+	if len(l.pieces) == 0 {
+		return 0
+	}
+	pc := l.pieces[0]
+	for _, p := range l.pieces {
+		if x >= p.x {
+			pc = p
+		}
+	}
+	rel := x - pc.x
+	if pc.rtl {
+		rel = pc.w - rel
+	}
+	return pc.lo + nearestOffset(l.text[pc.lo:pc.hi], rel, face, lineH)
+}
+
+// rangeXs 返回逻辑区间 [lo,hi) 覆盖的视觉 x 区间（相对行首）；混排时可能不连续。
+func (l bidiLine) rangeXs(lo, hi int, face fontFace, lineH float64) [][2]float32 {
+	var out [][2]float32
+	for _, p := range l.pieces {
+		a, b := max(lo, p.lo), min(hi, p.hi)
+		if a >= b {
+			continue
+		}
+		da := measureW(l.text[p.lo:a], face, lineH)
+		db := measureW(l.text[p.lo:b], face, lineH)
+		if p.rtl {
+			out = append(out, [2]float32{p.x + p.w - db, p.x + p.w - da})
+		} else {
+			out = append(out, [2]float32{p.x + da, p.x + db})
+		}
+	}
+	return out
+}
+
+// draw 逐段绘制一行；(x,y) 为行首左上角。纯 LTR 行只发一次 DrawText。
+func (l bidiLine) draw(p painter, face fontFace, c Color, x, y float32, fauxBold, fauxItalic bool) {
+	for _, pc := range l.pieces {
+		p.DrawText(l.text[pc.lo:pc.hi], face, c, x+pc.x, y, fauxBold, fauxItalic)
+	}
+}
+
+// drawGradient 同 draw，但用渐变填充文字，渐变铺满 box。
+func (l bidiLine) drawGradient(p painter, face fontFace, g *Gradient, box Rect, x, y float32, fauxBold, fauxItalic bool) {
+	for _, pc := range l.pieces {
+		p.DrawTextGradient(l.text[pc.lo:pc.hi], face, g, box, x+pc.x, y, fauxBold, fauxItalic)
+	}
+}
+
+// nearestOffset 返回 s 内前缀宽度最接近 rel 的 rune 边界字节偏移。
+func nearestOffset(s string, rel float32, face fontFace, lineH float64) int {
+	if rel <= 0 || s == "" {
+		return 0
+	}
+	prevIdx, prevW := 0, float32(0)
+	for i := 1; i <= len(s); i++ {
+		if i < len(s) && !utf8.RuneStart(s[i]) {
+			continue
+		}
+		w := measureW(s[:i], face, lineH)
+		if w >= rel {
+			if w-rel < rel-prevW {
+				return i
+			}
+			return prevIdx
+		}
+		prevIdx, prevW = i, w
+	}
+	return len(s)
+}
+
+// paragraphAt 返回 s 中偏移 off 所在段落（'\n' 之间）的字节区间 [lo,hi)。
+func paragraphAt(s string, off int) (lo, hi int) {
+	lo, hi = off, off
+	for lo > 0 && s[lo-1] != '\n' {
+		lo--
+	}
+	for hi < len(s) && s[hi] != '\n' {
+		hi++
+	}
+	return lo, hi
+}
+
+// paragraphDirAt 把 dir 解析为 s 中 off 所在段落的确定方向（UAX#9 P2/P3）。折行后的
+// 各行都按所在段落的方向排，续行不按自己的首个强字符重判 —— 否则 RTL 段落里以拉丁词
+// 开头的续行会翻成 LTR、改为贴左，与按段落解析方向的光标/选区对不上。
+func paragraphDirAt(s string, off int, dir TextDirection) TextDirection {
+	if dir != DirAuto {
+		return dir
+	}
+	lo, hi := paragraphAt(s, off)
+	if paragraphRTL(s[lo:hi], DirAuto) {
+		return RTL
+	}
+	return LTR
+}
+
+// rtlAt 报告逻辑偏移 off 处的文字是否从右往左排（决定左右方向键的逻辑方向）。
+// 只看 off 所在的段落（'\n' 之间）。
+func rtlAt(s string, off int, dir TextDirection) bool {
+	lo, hi := paragraphAt(s, off)
+	para := s[lo:hi]
+	base := paragraphRTL(para, dir)
+	rel := off - lo
+	spans := bidiSpans(para, base)
+	for _, sp := range spans {
+		if rel >= sp.lo && rel < sp.hi {
+			return sp.rtl
+		}
+	}
+	if n := len(spans); n > 0 && rel == len(para) {
+		return spans[n-1].rtl
+	}
+	return base
+}
+
+// reorderRichLine 把富文本一行的段（逻辑顺序）重排为视觉顺序并重算 x：各段按 bidi
+// 边界切开，RTL 层级段内的小段倒序，方向段之间按 visualOrder 排列。
+func reorderRichLine(ln *richLine, runs []textRun, dir TextDirection) {
+	var sb []byte
+	for _, sg := range ln.segs {
+		sb = append(sb, sg.text...)
+	}
+	s := string(sb)
+	ln.rtl = paragraphRTL(s, dir)
+	if !ln.rtl && !hasRTL(s) {
+		return
+	}
+	spans := bidiSpans(s, ln.rtl)
+	groups := make([][]richSeg, len(spans))
+	pos := 0
+	for _, sg := range ln.segs {
+		lo, hi := pos, pos+len(sg.text)
+		pos = hi
+		for i, sp := range spans {
+			a, b := max(lo, sp.lo), min(hi, sp.hi)
+			if a < b {
+				groups[i] = append(groups[i], richSeg{sg.run, s[a:b], 0, sg.off + a - lo, sp.rtl})
+			}
+		}
+	}
+	segs := make([]richSeg, 0, len(ln.segs)+len(spans))
+	x := float32(0)
+	for _, i := range visualOrder(spans) {
+		g := groups[i]
+		for k := range g {
+			sg := g[k]
+			if spans[i].rtl {
+				sg = g[len(g)-1-k]
+			}
+			r := &runs[sg.run]
+			sg.x = x
+			x += measureW(sg.text, r.face, r.lineH)
+			segs = append(segs, sg)
+		}
+	}
+	ln.segs = segs
+}
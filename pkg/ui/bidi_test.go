@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+)
+
+const heb = "שלום" // 4 个希伯来字母，每个 2 字节
+
+// LTR 段落里嵌一段 RTL：切成三段，段序保持逻辑顺序；RTL 段落里整行倒序。
+func TestBidiLineVisualOrder(t *testing.T) {
+	face := backendNewFont(16, 400, false)
+	lineH := 16 * 1.3
+
+	l := newBidiLine("abc "+heb+" def", DirAuto, face, lineH)
+	if l.rtl || len(l.pieces) != 3 {
+		t.Fatalf("rtl=%v pieces=%+v want LTR 三段", l.rtl, l.pieces)
+	}
+	if !l.pieces[1].rtl || l.pieces[0].rtl || l.pieces[2].rtl {
+		t.Fatalf("只有中间一段应是 RTL：%+v", l.pieces)
+	}
+
+	r := newBidiLine(heb+" abc", DirAuto, face, lineH)
+	if !r.rtl {
+		t.Fatal("首个强字符是希伯来文，段落方向应为 RTL")
+	}
+	last := r.pieces[len(r.pieces)-1]
+	if !last.rtl || last.lo != 0 {
+		t.Fatalf("RTL 段落里逻辑上的第一段应排在最右：%+v", r.pieces)
+	}
+	if first := r.pieces[0]; r.text[first.lo:first.hi] != "abc" {
+		t.Fatalf("最左一段 %q want abc", r.text[first.lo:first.hi])
+	}
+}
+
+// pieceTexts 返回一行各段按视觉顺序（左到右）的文本。
+func pieceTexts(l bidiLine) []string {
+	out := make([]string, len(l.pieces))
+	for i, p := range l.pieces {
+		out[i] = l.text[p.lo:p.hi]
+	}
+	return out
+}
+
+// 数字与拉丁文嵌在 RTL 文字中间：按层级做 L2 倒序，而不是整行要么正序要么倒序。
+func TestBidiNestedRunsReorder(t *testing.T) {
+	face := backendNewFont(16, 400, false)
+	lineH := 16 * 1.3
+	cases := []struct {
+		text string
+		dir  TextDirection
+		want string
+	}{
+		// LTR 段落：RTL 串「אב 12 גד」整体倒序，其中的数字仍从左往右。
+		{"x אב 12 גד", DirAuto, `["x " " גד" "12" "אב "]`},
+		// RTL 段落：数字在 RTL 串中间。
+		{"אב 12 גד", DirAuto, `[" גד" "12" "אב "]`},
+		// RTL 段落：拉丁文后面的数字随拉丁文（W7），一起作为一个 LTR 段。
+		{"אב abc 12 גד", DirAuto, `[" גד" "abc 12" "אב "]`},
+		// 显式 LTR 不被首个希伯来字符改成 RTL。
+		{"אב abc", LTR, `["אב" " abc"]`},
+	}
+	for _, c := range cases {
+		l := newBidiLine(c.text, c.dir, face, lineH)
+		if got := fmt.Sprintf("%q", pieceTexts(l)); got != c.want {
+			t.Errorf("%q dir=%d: %s want %s", c.text, c.dir, got, c.want)
+		}
+	}
+}
+
+// RTL 文本里逻辑偏移越大光标越靠左；offsetAt 能把 caretX 映射回原偏移。
+func TestBidiCaretRoundTrip(t *testing.T) {
+	face := backendNewFont(16, 400, false)
+	lineH := 16 * 1.3
+	l := newBidiLine(heb, DirAuto, face, lineH)
+
+	prev := l.caretX(0, face, lineH)
+	if prev < l.width-0.5 {
+		t.Fatalf("RTL 行首光标 x=%v 应在最右（width=%v）", prev, l.width)
+	}
+	for off := 2; off <= len(heb); off += 2 {
+		x := l.caretX(off, face, lineH)
+		if x >= prev {
+			t.Fatalf("caretX(%d)=%v 没有比前一个 %v 更靠左", off, x, prev)
+		}
+		if got := l.offsetAt(x, face, lineH); got != off {
+			t.Fatalf("offsetAt(caretX(%d))=%d", off, got)
+		}
+		prev = x
+	}
+}
+
+// Direction(RTL) 让 yoga 按 RTL 排 Row：第一个子元素在最右。
+func TestDirectionRTLMirrorsRow(t *testing.T) {
+	h := Mount(Div(Style(Row, Width(200), Height(40), Direction(RTL)),
+		Text("first"), Text("second")), 200, 40)
+	a := h.Root().ByText("first").Bounds()
+	b := h.Root().ByText("second").Bounds()
+	if a.X <= b.X {
+		t.Fatalf("first.X=%v second.X=%v —— RTL 的 Row 应从右往左排", a.X, b.X)
+	}
+	if a.X+a.W < 199 {
+		t.Fatalf("first 右缘 %v 应贴着容器右缘", a.X+a.W)
+	}
+}
+
+// 没设方向的纯希伯来文按内容判断为 RTL，贴右绘制；显式 LTR 则贴左。
+func TestRTLTextAlignsRight(t *testing.T) {
+	textX := func(root *Node) float32 {
+		h := Mount(root, 200, 40)
+		for _, op := range h.Paint() {
+			if op.Kind == "text" && op.Text == heb {
+				return op.X0
+			}
+		}
+		t.Fatal("没画出希伯来文")
+		return 0
+	}
+	auto := textX(Div(Style(Width(200)), Text(heb)))
+	ltr := textX(Div(Style(Width(200), Direction(LTR)), Text(heb)))
+	if ltr != 0 || auto <= 100 {
+		t.Fatalf("auto x=%v ltr x=%v —— auto 应贴右，LTR 应贴左", auto, ltr)
+	}
+}
+
+// RTL 输入框里 → 在视觉上往右，也就是逻辑上往回退；← 则前进。
+func TestRTLInputArrowKeysMoveVisually(t *testing.T) {
+	app := func(_ struct{}) *Node {
+		v, setV := UseState("")
+		return Input(Value(v), OnChange(setV), Style(Width(180), Height(30)))
+	}
+	h := Mount(Use(app, struct{}{}), 200, 40)
+	in := h.Root().ByKind("input")
+	b := in.Bounds()
+	h.MouseDown(b.X+b.W/2, b.Y+b.H/2)
+	in.Type(heb)
+
+	rn := h.focusedRN()
+	if rn == nil || rn.caretPos != len(heb) {
+		t.Fatal("输入后光标应在逻辑末尾")
+	}
+	h.pressKey(keyRight)
+	if rn.caretPos != len(heb)-2 {
+		t.Fatalf("→ 后 caret=%d want %d", rn.caretPos, len(heb)-2)
+	}
+	h.pressKey(keyLeft)
+	if rn.caretPos != len(heb) {
+		t.Fatalf("← 后 caret=%d want %d", rn.caretPos, len(heb))
+	}
+
+	// 点击输入框最左端应落在逻辑末尾（RTL 文本的末尾在左边）
+	if got := rn.caretFromX(b.X + 1); got != len(heb) {
+		t.Fatalf("caretFromX(左端)=%d want %d", got, len(heb))
+	}
+}
+
+// RTL 段落折行后，以拉丁词开头的续行仍按段落方向（RTL）排、贴右；
+// 纯文本、多行输入与富文本三条路径都一样。
+func TestBidiWrappedParagraphKeepsDirection(t *testing.T) {
+	face := backendNewFont(16, 400, false)
+	lineH := 16 * 1.3
+	s := heb + " " + heb + " abc def"
+	width := measureW(heb+" "+heb, face, lineH) + 1 // 第二行以 "abc" 开头
+
+	rn := newTextRenderNode(s, newStyleProps(), nil)
+	rn.face, rn.lineH = face, lineH
+	lines := rn.bidiLines(width)
+	if len(lines) != 2 || lines[1].text != "abc def" {
+		t.Fatalf("折行结果 %+v，want 两行且第二行以 abc 开头", lines)
+	}
+	if !lines[0].rtl || !lines[1].rtl {
+		t.Fatalf("两行都应按段落方向 RTL：%v %v", lines[0].rtl, lines[1].rtl)
+	}
+	if x := lines[1].startX(width); x != width-lines[1].width {
+		t.Fatalf("RTL 续行应贴右：startX=%v", x)
+	}
+
+	spans := wrapSpans(s, face, lineH, width)
+	if len(spans) != 2 || !spans[1].bidi(DirAuto, face, lineH).rtl {
+		t.Fatalf("多行输入的续行也应按段落方向 RTL：%+v", spans)
+	}
+
+	runs := []textRun{{text: heb + " " + heb + " ", face: face, lineH: lineH}, {text: "abc def", face: face, lineH: lineH}}
+	rich, _, _ := layoutRuns(runs, width, DirAuto)
+	if len(rich) != 2 || !rich[1].rtl {
+		t.Fatalf("富文本的续行也应按段落方向 RTL：%d 行", len(rich))
+	}
+}
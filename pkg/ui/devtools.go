@@ -0,0 +1,564 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sjm1327605995/tenon/yoga"
+)
+
+// ---- 开发者工具：检查器 ----
+//
+// Window.ShowInspector（或开启了 WithInspectorKey 的窗口里按 Ctrl+Shift+I）在窗口右侧打开检查器面板：
+//
+//   - 上半部是 fiber 树：组件名、host 标签（#id .class）、文本、Provider、Portal。
+//     点一行选中它；悬停一行、或在界面上悬停某个元素，都会在界面上高亮它的盒模型
+//     （yoga 算出的 margin 橙色、padding 绿色、内容蓝色）。
+//   - 下半部是选中节点的详情：props、每个 hook 的当前值、Provider 提供的值、盒模型数字，
+//     以及几项可以现场改的样式（宽高、内外边距、间距、圆角、透明度、背景色）。
+//     改动直接写进该元素的 renderNode，叠在它自己的样式和样式表之后，组件重渲染也保留；
+//     清空输入框即撤销。这是调试手段，不会改源码。
+//
+// 面板本身是窗口自有的浮层 fiber（同拖放预览，不属于用户的树），不用 hook，状态都在 game 上：
+// 用户树每次提交、或检查器里有交互时整体重建一次（syncInspector）。高亮不走面板，
+// 而是在一帧画完后直接画在最上面（paintInspector），所以悬停不会引起任何重渲染。
+//
+// 同一棵树也以 JSON 形式提供给外部工具：Window.InspectorTree 取快照，
+// App.ServeInspector（或环境变量 TENON_INSPECT=127.0.0.1:9229）开一个本地 HTTP 端点，见 devtools_http.go。
+
+// InspectNode 是检查器树里的一个 fiber（也是 HTTP 端点的 JSON 形状）。
+type InspectNode struct {
+	Kind     string         `json:"kind"` // component / host / text / icon / provider / portal / fragment
+	Name     string         `json:"name"` // 组件函数名、host 标签、"Provider<T>" 等
+	Key      string         `json:"key,omitempty"`
+	Props    string         `json:"props,omitempty"`   // 组件 props（%+v）
+	Hooks    []InspectHook  `json:"hooks,omitempty"`   // 组件 hook，按调用顺序
+	Context  string         `json:"context,omitempty"` // Provider 提供的值
+	Text     string         `json:"text,omitempty"`
+	ElemID   string         `json:"id,omitempty"`    // Id(...)
+	Class    string         `json:"class,omitempty"` // Class(...)
+	Box      *InspectBox    `json:"box,omitempty"`   // host/text 元素的布局结果
+	Children []*InspectNode `json:"children,omitempty"`
+
+	fiber *Fiber
+}
+
+// InspectHook 是一个 hook 的种类与当前值。
+type InspectHook struct {
+	Kind  string `json:"kind"` // state / reducer / effect / memo / ref / tween / async / media …
+	Value string `json:"value,omitempty"`
+}
+
+// InspectBox 是元素的盒模型（逻辑像素）：Bounds 为边框盒，四边依次为上、右、下、左。
+type InspectBox struct {
+	Bounds  Rect       `json:"bounds"`
+	Margin  [4]float32 `json:"margin"`
+	Border  [4]float32 `json:"border"`
+	Padding [4]float32 `json:"padding"`
+}
+
+// Walk 深度优先遍历子树；fn 返回 false 时不再进入该节点的子节点。
+func (n *InspectNode) Walk(fn func(*InspectNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+// InspectorTree 返回窗口 fiber 树的快照（含 Portal 内容，不含检查器自身）。须在渲染线程调用。
+func (w *Window) InspectorTree() *InspectNode {
+	if w.g.rootFiber == nil {
+		return nil
+	}
+	return inspectFiber(w.g.rootFiber, 0)
+}
+
+// ShowInspector 打开或关闭窗口的检查器面板。
+func (w *Window) ShowInspector(on bool) {
+	w.g.setInspecting(on)
+	if h := w.backend(); h != nil {
+		h.wake()
+	}
+}
+
+// InspectorVisible 报告检查器面板是否打开。
+func (w *Window) InspectorVisible() bool { return w.g.inspecting }
+
+func (g *game) setInspecting(on bool) {
+	if g.inspecting == on {
+		return
+	}
+	g.inspecting = on
+	g.inspectHover, g.inspectSel = nil, nil
+	g.inspectStale = true
+	g.needsLayout = true
+}
+
+// inspectorKey 开关检查器，先于元素的 OnKeyDown 与快捷键处理。只在窗口开启了
+// WithInspectorKey / WindowInspectorKey 时生效（见 inspectorKeyOn），否则照常分发。
+var inspectorKey = keyStroke{mods: modCtrl | modShift, key: "I"}
+
+// inspectorKeyOn 报告 inspectorKey 是否归检查器所有。
+func (g *game) inspectorKeyOn() bool { return g.win != nil && g.win.cfg.inspectKey }
+
+// inspectMaxDepth 防止病态的深树让快照无限长；正常界面远到不了。
+const inspectMaxDepth = 256
+
+// inspectFiber 把 f 及其子树转成 InspectNode；属性节点不成为树节点。
+func inspectFiber(f *Fiber, depth int) *InspectNode {
+	n := &InspectNode{Key: f.key, fiber: f}
+	switch f.typ {
+	case typeComponent:
+		n.Kind, n.Name = "component", fiberName(f)
+		if f.props != nil {
+			n.Props = inspectValue(f.props)
+		}
+		for _, h := range f.hooks {
+			n.Hooks = append(n.Hooks, hookInfo(h))
+		}
+	case typeHost:
+		n.Kind, n.Name = "host", f.tag
+	case typeText:
+		n.Kind, n.Name = "text", "#text"
+	case typeIcon:
+		n.Kind, n.Name = "icon", "icon"
+	case typeProvider:
+		n.Kind, n.Name = "provider", fmt.Sprintf("Provider<%T>", f.ctxValue)
+		n.Context = inspectValue(f.ctxValue)
+	case typePortal:
+		n.Kind, n.Name = "portal", "Portal"
+	case typeFragment:
+		n.Kind, n.Name = "fragment", "Fragment"
+	}
+	if rn := f.rnode; rn != nil {
+		n.ElemID, n.Class = rn.id, strings.Join(rn.classes, " ")
+		if rn.kind == rnText {
+			n.Text = rn.text
+		}
+		n.Box = boxOf(rn)
+	}
+	if depth < inspectMaxDepth {
+		for _, c := range f.children {
+			if c.typ != typeAttr {
+				n.Children = append(n.Children, inspectFiber(c, depth+1))
+			}
+		}
+	}
+	return n
+}
+
+// fiberName 返回组件函数的短名（包名.函数名），匿名函数形如 "main.App.func1"。
+func fiberName(f *Fiber) string {
+	fn := runtime.FuncForPC(f.fnPtr)
+	if fn == nil {
+		return "component"
+	}
+	name := fn.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// hookInfo 描述一个 hook：已知种类取其值，其余用类型名。
+func hookInfo(h any) InspectHook {
+	switch h := h.(type) {
+	case *stateHook:
+		if h.reducer != nil {
+			return InspectHook{"reducer", inspectValue(h.value)}
+		}
+		return InspectHook{"state", inspectValue(h.value)}
+	case *effectHook:
+		return InspectHook{"effect", "deps " + inspectValue(h.deps)}
+	case *memoHook:
+		return InspectHook{"memo", inspectValue(h.value)}
+	case *refHook:
+		return InspectHook{"ref", inspectValue(h.value)}
+	case *tweenHook:
+		return InspectHook{"tween", strconv.FormatFloat(float64(h.cur), 'g', 4, 32)}
+	case *transitionHook:
+		return InspectHook{"transition", fmt.Sprintf("mounted=%v %.3g", h.mounted, h.tween.cur)}
+	case *springHook:
+		return InspectHook{"spring", fmt.Sprintf("%.4g → %.4g", h.cur, h.target)}
+	case *presenceHook:
+		return InspectHook{"presence", fmt.Sprintf("present=%v done=%v", h.item == nil || !h.item.exiting, h.done)}
+	case *keyframesHook:
+		return InspectHook{"keyframes", fmt.Sprintf("%.0f/%.0fms %s", h.elapsed, h.duration(), inspectValue(h.values))}
+	case *loopHook:
+		return InspectHook{"elapsed", fmt.Sprintf("%.3gs", h.elapsed)}
+	case *asyncHook:
+		if h.err != nil {
+			return InspectHook{"async", "error: " + h.err.Error()}
+		}
+		return InspectHook{"async", inspectValue(h.value)}
+	case *measureHook:
+		return InspectHook{"measure", inspectValue(h.rect)}
+	case *scrollHook:
+		return InspectHook{"scroll", inspectValue(h.info)}
+	case *mediaHook:
+		return InspectHook{"media", inspectValue(h.value)}
+	}
+	name := strings.TrimPrefix(fmt.Sprintf("%T", h), "*ui.")
+	return InspectHook{Kind: strings.TrimSuffix(name, "Hook")}
+}
+
+// inspectValue 把任意值格式化成一行（指针解一层），过长截断。
+func inspectValue(v any) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		v = rv.Elem().Interface()
+	}
+	s := fmt.Sprintf("%+v", v)
+	if len(s) > 200 {
+		s = s[:200] + "…"
+	}
+	return s
+}
+
+// boxOf 读出元素的布局结果（物理像素换算为逻辑像素）。
+func boxOf(rn *renderNode) *InspectBox {
+	k := uiScale
+	edges := func(get func(yoga.Edge) float32) [4]float32 {
+		return [4]float32{get(yoga.EdgeTop) / k, get(yoga.EdgeRight) / k, get(yoga.EdgeBottom) / k, get(yoga.EdgeLeft) / k}
+	}
+	return &InspectBox{
+		Bounds:  Rect{X: rn.bounds.X / k, Y: rn.bounds.Y / k, W: rn.bounds.W / k, H: rn.bounds.H / k},
+		Margin:  edges(rn.yn.LayoutMargin),
+		Border:  edges(rn.yn.LayoutBorder),
+		Padding: edges(rn.yn.LayoutPadding),
+	}
+}
+
+// ---- 面板 ----
+
+// inspectLayerRN 返回检查器浮层的根 renderNode（未打开为 nil）。
+func (g *game) inspectLayerRN() *renderNode {
+	if g.inspectLayer == nil {
+		return nil
+	}
+	var out []*Fiber
+	collectPortals(g.inspectLayer, &out)
+	if len(out) == 0 {
+		return nil
+	}
+	return out[0].overlayRoot
+}
+
+// syncInspector 在布局前把面板协调进窗口自己的浮层 fiber：用户树有提交、布局变了或
+// 检查器里有交互（inspectStale）时重建，关闭时卸掉。
+func (g *game) syncInspector() {
+	if !g.inspecting {
+		if g.inspectLayer != nil {
+			reconcile(nil, g.inspectLayer, nil)
+			g.inspectLayer = nil
+		}
+		return
+	}
+	if g.inspectLayer != nil && !g.inspectStale {
+		return
+	}
+	g.inspectStale = false
+	g.inspectRev++
+	if g.inspectSel != nil && g.inspectSel.unmounted {
+		g.inspectSel = nil
+	}
+	g.inspectLayer = reconcile(nil, g.inspectLayer, Use(inspectorPanel, inspectorProps{g: g, rev: g.inspectRev}))
+	if g.inspectLayer != nil {
+		g.inspectLayer.g = g
+		relink(g.inspectLayer)
+	}
+}
+
+// trackInspectHover 处理指针输入时更新高亮目标：光标在面板上时由面板的行决定，否则取光标下的元素。
+func (g *game) trackInspectHover() {
+	if !g.inspecting {
+		return
+	}
+	x, y := input.cursor()
+	hit := g.hitTop(x, y)
+	layer := g.inspectLayerRN()
+	for c := hit; c != nil; c = c.parent {
+		if c == layer {
+			return
+		}
+	}
+	g.inspectHover = hit
+}
+
+type inspectorProps struct {
+	g   *game
+	rev int // 每次重建加一：面板没有 hook，靠 props 变化重渲染
+}
+
+var (
+	inspectBg     = Color{24, 24, 28, 240}
+	inspectFg     = Color{228, 228, 231, 255}
+	inspectMuted  = Color{161, 161, 170, 255}
+	inspectAccent = Color{96, 165, 250, 255}
+	inspectSelBg  = Color{59, 130, 246, 90}
+)
+
+// inspectorRowLimit 限制树视图的行数，超大的树只列前面的部分（JSON 端点不受限）。
+const inspectorRowLimit = 2000
+
+func inspectorPanel(p inspectorProps) *Node {
+	g := p.g
+	var tree *InspectNode
+	if g.rootFiber != nil {
+		tree = inspectFiber(g.rootFiber, 0)
+	}
+	rows := []*Node{Style(Column)}
+	var sel *InspectNode
+	if tree != nil {
+		var walk func(n *InspectNode, depth int)
+		walk = func(n *InspectNode, depth int) {
+			if len(rows) > inspectorRowLimit {
+				return
+			}
+			if n.fiber == g.inspectSel {
+				sel = n
+			}
+			rows = append(rows, inspectorRow(g, n, depth))
+			for _, c := range n.Children {
+				walk(c, depth+1)
+			}
+		}
+		walk(tree, 0)
+	}
+
+	kids := []*Node{
+		Style(Absolute, Top(0), Right(0), Bottom(0), Width(360), Column,
+			Bg(inspectBg), TextColor(inspectFg), FontSize(12)),
+		Role(RoleDialog), AriaLabel("Inspector"),
+		Div(Style(Row, ItemsCenter, JustifyBetween, PaddingXY(10, 8), Bg(Color{39, 39, 42, 255})),
+			Text("Inspector", Semibold),
+			Button(Style(PaddingXY(6, 2)), OnClick(func() { g.setInspecting(false) }), Text("×", FontSize(14))),
+		),
+		ScrollView(Style(Grow(1), Shrink(1), PaddingXY(0, 4)), Div(rows...)),
+	}
+	if sel != nil {
+		kids = append(kids, inspectorDetails(g, sel))
+	}
+	return Portal(Div(kids...))
+}
+
+// inspectorRow 是树视图的一行：缩进 + 标签；点击选中，悬停高亮对应元素。
+func inspectorRow(g *game, n *InspectNode, depth int) *Node {
+	label, color := n.Name, inspectFg
+	switch n.Kind {
+	case "component":
+		label, color = "<"+n.Name+">", inspectAccent
+	case "host":
+		if n.ElemID != "" {
+			label += "#" + n.ElemID
+		}
+		if n.Class != "" {
+			label += "." + strings.ReplaceAll(n.Class, " ", ".")
+		}
+	case "text":
+		label, color = strconv.Quote(truncate(n.Text, 40)), inspectMuted
+	case "provider", "portal", "fragment":
+		color = inspectMuted
+	}
+	st := []StyleOpt{Row, Height(20), ItemsCenter, PaddingXY(8+float32(depth)*12, 0)}
+	if n.fiber == g.inspectSel {
+		st = append(st, Bg(inspectSelBg))
+	}
+	f := n.fiber
+	return Div(Style(st...),
+		OnClick(func() {
+			g.inspectSel = f
+			g.inspectStale = true
+		}),
+		OnHover(func(on bool) {
+			if on {
+				g.inspectHover = inspectTarget(f)
+			}
+		}),
+		Text(label, TextColor(color)),
+	)
+}
+
+// inspectTarget 返回 fiber 对应的第一个 renderNode（组件取其首个 host 后代）。
+func inspectTarget(f *Fiber) *renderNode {
+	if f.rnode != nil {
+		return f.rnode
+	}
+	var kids []*renderNode
+	collectChildRenderNodes(f, &kids)
+	if len(kids) > 0 {
+		return kids[0]
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
+
+// inspectorDetails 是选中节点的详情：props、hook、context、盒模型与可编辑样式。
+func inspectorDetails(g *game, n *InspectNode) *Node {
+	kids := []*Node{
+		Style(Column, Gap(4), Padding(10), MaxHeight(320), Border(1, Color{63, 63, 70, 255})),
+		Text(n.Name, Semibold, TextColor(inspectAccent)),
+	}
+	line := func(k, v string) *Node {
+		return Div(Style(Row, Gap(6)), Text(k, TextColor(inspectMuted)), Text(v))
+	}
+	if n.Props != "" {
+		kids = append(kids, line("props", n.Props))
+	}
+	for i, h := range n.Hooks {
+		kids = append(kids, line(fmt.Sprintf("%d %s", i, h.Kind), h.Value))
+	}
+	if n.Kind == "provider" {
+		kids = append(kids, line("value", n.Context))
+	}
+	if b := n.Box; b != nil {
+		kids = append(kids,
+			line("bounds", fmt.Sprintf("%g,%g %g×%g", b.Bounds.X, b.Bounds.Y, b.Bounds.W, b.Bounds.H)),
+			line("margin", fmt.Sprint(b.Margin)),
+			line("padding", fmt.Sprint(b.Padding)))
+	}
+	if rn := n.fiber.rnode; rn != nil && n.Kind == "host" {
+		for _, fd := range inspectFields {
+			kids = append(kids, inspectorField(g, rn, fd))
+		}
+	}
+	return ScrollView(Div(kids...))
+}
+
+// inspectField 是面板里可现场修改的一项样式。
+type inspectField struct {
+	name  string
+	apply func(v string) (StyleOpt, bool) // 解析输入；不合法返回 false
+}
+
+func numberField(name string, opt func(float32) StyleOpt) inspectField {
+	return inspectField{name, func(v string) (StyleOpt, bool) {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
+		if err != nil {
+			return nil, false
+		}
+		return opt(float32(f)), true
+	}}
+}
+
+var inspectFields = []inspectField{
+	numberField("width", Width),
+	numberField("height", Height),
+	numberField("padding", Padding),
+	numberField("margin", Margin),
+	numberField("gap", Gap),
+	numberField("radius", Radius),
+	numberField("opacity", Opacity),
+	{"bg", func(v string) (StyleOpt, bool) {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "#")
+		if len(v) != 6 && len(v) != 8 {
+			return nil, false
+		}
+		if _, err := strconv.ParseUint(v, 16, 32); err != nil {
+			return nil, false
+		}
+		return Bg(Hex(v)), true
+	}},
+}
+
+func inspectorField(g *game, rn *renderNode, fd inspectField) *Node {
+	return Div(Style(Row, ItemsCenter, Gap(6)), Key(fd.name),
+		Div(Style(Width(64)), Text(fd.name, TextColor(inspectMuted))),
+		Input(Style(Grow(1), Height(22), PaddingXY(6, 0), Bg(Color{39, 39, 42, 255}), Radius(4)),
+			AriaLabel(fd.name), Value(rn.inspectEdits[fd.name]), Placeholder("—"),
+			OnChange(func(v string) { g.editInspectStyle(rn, fd.name, v) })),
+	)
+}
+
+// editInspectStyle 记下面板里的一项样式修改并立即重算该元素的样式；空串撤销该项。
+func (g *game) editInspectStyle(rn *renderNode, name, v string) {
+	if rn.inspectEdits == nil {
+		rn.inspectEdits = map[string]string{}
+	}
+	if v == "" {
+		delete(rn.inspectEdits, name)
+	} else {
+		rn.inspectEdits[name] = v
+	}
+	rn.inspectStyle = rn.inspectStyle[:0]
+	for _, fd := range inspectFields { // 按固定顺序叠加，与输入先后无关
+		if s, ok := rn.inspectEdits[fd.name]; ok {
+			if o, ok := fd.apply(s); ok {
+				rn.inspectStyle = append(rn.inspectStyle, o)
+			}
+		}
+	}
+	rn.restyle()
+	g.inspectStale = true
+	g.needsLayout = true
+}
+
+// ---- 高亮 ----
+
+var (
+	inspectMarginC  = Color{246, 178, 107, 110}
+	inspectPaddingC = Color{147, 196, 125, 110}
+	inspectContentC = Color{111, 168, 220, 110}
+)
+
+// paintInspector 在一帧最上面画出高亮元素的盒模型：悬停优先，否则是选中的元素。
+func (g *game) paintInspector(p painter) {
+	if !g.inspecting {
+		return
+	}
+	rn := g.inspectHover
+	if rn == nil || rn.owner == nil || rn.owner.unmounted {
+		rn = nil
+		if g.inspectSel != nil && !g.inspectSel.unmounted {
+			rn = inspectTarget(g.inspectSel)
+		}
+	}
+	if rn == nil || rn.owner == nil || rn.owner.unmounted {
+		return
+	}
+	b := rn.bounds
+	yn := rn.yn
+	mt, mr, mb, ml := yn.LayoutMargin(yoga.EdgeTop), yn.LayoutMargin(yoga.EdgeRight), yn.LayoutMargin(yoga.EdgeBottom), yn.LayoutMargin(yoga.EdgeLeft)
+	pt := yn.LayoutPadding(yoga.EdgeTop) + yn.LayoutBorder(yoga.EdgeTop)
+	pr := yn.LayoutPadding(yoga.EdgeRight) + yn.LayoutBorder(yoga.EdgeRight)
+	pb := yn.LayoutPadding(yoga.EdgeBottom) + yn.LayoutBorder(yoga.EdgeBottom)
+	pl := yn.LayoutPadding(yoga.EdgeLeft) + yn.LayoutBorder(yoga.EdgeLeft)
+	outer := Rect{X: b.X - ml, Y: b.Y - mt, W: b.W + ml + mr, H: b.H + mt + mb}
+	inner := Rect{X: b.X + pl, Y: b.Y + pt, W: b.W - pl - pr, H: b.H - pt - pb}
+	fillRing(p, outer, b, inspectMarginC)
+	fillRing(p, b, inner, inspectPaddingC)
+	if inner.W > 0 && inner.H > 0 {
+		p.FillRect(inner.X, inner.Y, inner.W, inner.H, 0, inspectContentC)
+	}
+	p.StrokeRect(b.X, b.Y, b.W, b.H, 0, 1, inspectAccent)
+}
+
+// fillRing 填充 outer 减去 inner 的环形区域（四条边）。
+func fillRing(p painter, outer, inner Rect, c Color) {
+	if inner.W < 0 || inner.H < 0 {
+		p.FillRect(outer.X, outer.Y, outer.W, outer.H, 0, c)
+		return
+	}
+	if h := inner.Y - outer.Y; h > 0 {
+		p.FillRect(outer.X, outer.Y, outer.W, h, 0, c)
+	}
+	if h := outer.Y + outer.H - (inner.Y + inner.H); h > 0 {
+		p.FillRect(outer.X, inner.Y+inner.H, outer.W, h, 0, c)
+	}
+	if w := inner.X - outer.X; w > 0 {
+		p.FillRect(outer.X, inner.Y, w, inner.H, 0, c)
+	}
+	if w := outer.X + outer.W - (inner.X + inner.W); w > 0 {
+		p.FillRect(inner.X+inner.W, inner.Y, w, inner.H, 0, c)
+	}
+}
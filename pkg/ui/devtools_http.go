@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ---- 检查器的 HTTP 端点 ----
+//
+// 给外部工具（浏览器里的树视图、脚本、编辑器插件）读 fiber 树用，只读：
+//
+//	srv, err := app.ServeInspector("127.0.0.1:9229")
+//	// GET http://127.0.0.1:9229/tree -> [{"window":"Tenon UI","tree":{...InspectNode...}}]
+//
+// 或者不改代码：TENON_INSPECT=127.0.0.1:9229 ./app 在 Run 时自动开启。
+// 树只能在渲染线程读，所以每个请求用 Window.Post 排到各窗口的下一帧里取快照，
+// 窗口在 inspectTimeout 内没出帧就返回 503。端点会暴露 props 与状态，只应绑定回环地址。
+
+// inspectTimeout 是等待窗口出帧取快照的上限。
+const inspectTimeout = 2 * time.Second
+
+// InspectWindow 是 HTTP 端点里一个窗口的快照。
+type InspectWindow struct {
+	Window string       `json:"window"` // 窗口标题
+	Tree   *InspectNode `json:"tree"`
+}
+
+// InspectorServer 是 ServeInspector 开启的 HTTP 端点。
+type InspectorServer struct {
+	ln  net.Listener
+	srv *http.Server
+}
+
+// Addr 返回实际监听的地址（addr 用 ":0" 时由系统分配端口）。
+func (s *InspectorServer) Addr() string { return s.ln.Addr().String() }
+
+// Close 关闭端点。
+func (s *InspectorServer) Close() error { return s.srv.Close() }
+
+// ServeInspector 在 addr 上开启只读的检查器 HTTP 端点（GET /tree 返回各窗口 fiber 树的 JSON）。
+func (a *App) ServeInspector(addr string) (*InspectorServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &InspectorServer{ln: ln, srv: &http.Server{Handler: a.InspectorHandler()}}
+	go s.srv.Serve(ln)
+	return s, nil
+}
+
+// InspectorHandler 返回检查器端点的 http.Handler，便于挂到应用自己的服务上。
+func (a *App) InspectorHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tree", func(w http.ResponseWriter, r *http.Request) {
+		out, ok := a.inspectWindows()
+		if !ok {
+			http.Error(w, "ui: window did not render a frame in time", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(out)
+	})
+	return mux
+}
+
+// inspectWindows 在各窗口的渲染线程上取快照（经 Window.Post），等到全部返回或超时。
+func (a *App) inspectWindows() ([]InspectWindow, bool) {
+	wins := a.Windows()
+	type snap struct {
+		i   int
+		win InspectWindow
+	}
+	done := make(chan snap, len(wins)) // 带缓冲：超时后才出帧的窗口不会阻塞
+	for i, w := range wins {
+		w.Post(func() { done <- snap{i, InspectWindow{Window: w.Title(), Tree: w.InspectorTree()}} })
+	}
+	out := make([]InspectWindow, len(wins))
+	timeout := time.After(inspectTimeout)
+	for range wins {
+		select {
+		case s := <-done:
+			out[s.i] = s.win
+		case <-timeout:
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// serveInspectorFromEnv 在设置了 TENON_INSPECT 时开启端点（App.Run 调用）。
+func (a *App) serveInspectorFromEnv() {
+	addr := os.Getenv("TENON_INSPECT")
+	if addr == "" {
+		return
+	}
+	s, err := a.ServeInspector(addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tenon: inspector: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "tenon: inspector at http://%s/tree\n", s.Addr())
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var inspectCtx = CreateContext("light")
+
+func inspectedApp(_ struct{}) *Node {
+	count, _ := UseState(7)
+	return inspectCtx.Provider("dark",
+		Div(Id("box"), Class("card wide"), Style(Width(100), Height(40), Padding(5), Margin(3)),
+			Text("count")),
+		Text(string(rune('0'+count))),
+	)
+}
+
+// 快照：组件名、hook 值、Provider 的值、host 的 id/class 与 yoga 盒模型。
+func TestInspectorTree(t *testing.T) {
+	h := Mount(Use(inspectedApp, struct{}{}), 300, 200)
+	tree := h.Window().InspectorTree()
+	var comp, prov, box *InspectNode
+	tree.Walk(func(n *InspectNode) bool {
+		switch {
+		case n.Kind == "component" && strings.HasSuffix(n.Name, "inspectedApp"):
+			comp = n
+		case n.Kind == "provider":
+			prov = n
+		case n.ElemID == "box":
+			box = n
+		}
+		return true
+	})
+	if comp == nil || len(comp.Hooks) != 1 || comp.Hooks[0] != (InspectHook{"state", "7"}) {
+		t.Fatalf("组件节点 = %+v", comp)
+	}
+	if prov == nil || prov.Name != "Provider<string>" || prov.Context != "dark" {
+		t.Fatalf("provider 节点 = %+v", prov)
+	}
+	if box == nil || box.Class != "card wide" || box.Box == nil {
+		t.Fatalf("host 节点 = %+v", box)
+	}
+	if b := box.Box; b.Bounds.W != 100 || b.Padding != [4]float32{5, 5, 5, 5} || b.Margin != [4]float32{3, 3, 3, 3} {
+		t.Fatalf("盒模型 = %+v", b)
+	}
+}
+
+// 开启 WithInspectorKey 的窗口里 Ctrl+Shift+I 打开面板；悬停元素画出盒模型高亮；
+// 选中后改宽度，元素现场变化且组件重渲染也保留。
+func TestInspectorPanelHighlightAndEdit(t *testing.T) {
+	h := Mount(Div(), 10, 10).OpenWindow(Use(inspectedApp, struct{}{}), WithSize(600, 400), WithInspectorKey(true))
+	h.Key("Ctrl+Shift+I")
+	if !h.Window().InspectorVisible() || len(h.Overlays()) != 1 || !h.Overlays()[0].ByText("Inspector").Exists() {
+		t.Fatal("Ctrl+Shift+I 应打开检查器面板")
+	}
+
+	box := byID(h, "box")
+	b := box.Bounds()
+	h.PointerMove(b.X+b.W/2, b.Y+b.H-2) // 落在 padding 上，不碰文字
+	found := false
+	for _, op := range h.Paint() {
+		if op.Kind == "rect" && op.Color == inspectContentC && op.Rect.W == 90 && op.Rect.H == 30 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("悬停的元素应高亮出内容盒（去掉 padding 后 90×30）")
+	}
+
+	panel := h.Overlays()[0]
+	if !panel.ByText("div#box.card.wide").Click() {
+		t.Fatal("树视图里应有 div#box.card.wide 这一行")
+	}
+	panel = h.Overlays()[0]
+	if !panel.ByText("padding").Exists() {
+		t.Fatal("选中后应显示详情")
+	}
+	h.ByRole(RoleTextbox, "width").SetValue("150")
+	if got := byID(h, "box").Bounds().W; got != 150 {
+		t.Fatalf("改宽度后 W = %v, want 150", got)
+	}
+
+	h.Key("Ctrl+Shift+I")
+	if h.Window().InspectorVisible() || len(h.Overlays()) != 0 {
+		t.Fatal("再按一次 应关闭面板")
+	}
+	if got := byID(h, "box").Bounds().W; got != 150 {
+		t.Fatal("关闭面板不撤销已做的修改")
+	}
+}
+
+// 默认不占用 Ctrl+Shift+I：组合键照常交给应用的快捷键，检查器不打开。
+func TestInspectorKeyIsOptIn(t *testing.T) {
+	fired := 0
+	app := func(_ struct{}) *Node {
+		UseHotkey("Ctrl+Shift+I", func() { fired++ })
+		return Text("app")
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	h.Key("Ctrl+Shift+I")
+	if fired != 1 || h.Window().InspectorVisible() {
+		t.Fatalf("fired=%d inspector=%v —— 未开启时组合键应归应用", fired, h.Window().InspectorVisible())
+	}
+}
+
+// HTTP 端点在渲染线程取快照，返回各窗口的 JSON 树。
+func TestInspectorHTTP(t *testing.T) {
+	h := Mount(Use(inspectedApp, struct{}{}), 300, 200)
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		h.Window().App().InspectorHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tree", nil))
+		close(done)
+	}()
+	for served := false; !served; {
+		h.Flush() // 无头窗口没有后端出帧，由测试代为排空 Post
+		select {
+		case <-done:
+			served = true
+		default:
+		}
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var out []InspectWindow
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0].Window != "Tenon UI" || out[0].Tree == nil {
+		t.Fatalf("响应 = %s", rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"id": "box"`) {
+		t.Fatal("JSON 里应有 host 元素的 id")
+	}
+}
@@ -0,0 +1,201 @@
+package ui
+
+// ---- 拖放（drag and drop） ----
+//
+// OnDrag 只把位移交给被按住的元素；要把一个东西拖到另一个元素上，就得自己用 UseMeasure
+// 做命中。DragSource / DropTarget 把这件事交给引擎：按住源元素拖出一小段距离即开始拖放，
+// 预览跟随光标画在最上层浮层里，光标下（经由 hitTop 命中、向上冒泡）第一个接受该载荷的
+// DropTarget 高亮，松开时收到 onDrop。拖放中按 Esc 取消（走 Esc 栈，优先于其他浮层）。
+//
+//	over, drop := ui.UseDropTarget(
+//	    func(p any) bool { _, ok := p.(Card); return ok },
+//	    func(p any, x, y float32) { moveCard(p.(Card), column) },
+//	)
+//	ui.Div(ui.Style(ui.StyleIf(over, ui.Bg(hl))), drop,
+//	    ui.Div(ui.DragSource(card), ui.Text(card.Title)),
+//	)
+
+// dragThreshold 是按下后要移动多远（逻辑像素）才算开始拖放，之内松开仍是普通点击。
+const dragThreshold = 4
+
+// DragSource 让元素可被拖起，payload 随拖放交给 DropTarget 的 accept / onDrop。
+func DragSource(payload any) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) {
+		hp.dragSource = true
+		hp.dragPayload = payload
+	}}
+}
+
+// DragPreview 指定拖放时跟随光标的预览内容（默认是与源元素同尺寸的半透明方块）。
+func DragPreview(n *Node) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.dragPreview = n }}
+}
+
+// OnDragging 在本元素作为拖放源开始/结束拖放时回调（true=开始），可用来把原位置淡化。
+func OnDragging(fn func(bool)) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.onDragging = fn }}
+}
+
+// DropTarget 让元素接收拖放。accept 决定是否接受某个载荷（nil 接受一切）；
+// onDrop 在松开时回调，x,y 为光标的逻辑坐标（窗口坐标系，同 OnContextMenu）。
+func DropTarget(accept func(payload any) bool, onDrop func(payload any, x, y float32)) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) {
+		hp.dropTarget = true
+		hp.dropAccept = accept
+		hp.onDrop = onDrop
+	}}
+}
+
+// OnDragOver 在可接受的拖放进入/离开本 DropTarget 时回调（true=进入），用于高亮。
+func OnDragOver(fn func(bool)) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.onDragOver = fn }}
+}
+
+// UseDragSource 返回元素是否正被拖着，以及要挂到该元素上的属性（同 UseInteraction）。
+func UseDragSource(payload any) (dragging bool, attrs *Node) {
+	d, setD := UseState(false)
+	return d, Attrs(DragSource(payload), OnDragging(setD))
+}
+
+// UseDropTarget 返回是否有可接受的拖放悬停在元素上，以及要挂到该元素上的属性。
+func UseDropTarget(accept func(payload any) bool, onDrop func(payload any, x, y float32)) (over bool, attrs *Node) {
+	o, setO := UseState(false)
+	return o, Attrs(DropTarget(accept, onDrop), OnDragOver(setO))
+}
+
+// dndSession 是一次拖放（从按下源元素到松开/取消）。坐标都是物理像素。
+type dndSession struct {
+	src      *renderNode
+	payload  any
+	preview  *Node
+	startX   float32
+	startY   float32
+	offX     float32 // 光标相对源元素左上角的偏移，预览保持同样的抓取点
+	offY     float32
+	x, y     float32
+	active   bool // 已越过 dragThreshold；之前只是一次按下
+	over     *renderNode
+	esc      *escEntry
+	srcFiber *Fiber
+}
+
+// dndPress 在左键按下时调用：从命中节点向上找第一个 DragSource，记下候选拖放。
+func (g *game) dndPress(n *renderNode, x, y float32) {
+	g.dnd = nil
+	for c := n; c != nil; c = c.parent {
+		if c.dragSource {
+			b := c.bounds
+			g.dnd = &dndSession{src: c, payload: c.dragPayload, preview: c.dragPreview,
+				startX: x, startY: y, offX: x - b.X, offY: y - b.Y, x: x, y: y, srcFiber: c.owner}
+			return
+		}
+	}
+}
+
+// dndMove 在按住左键移动时调用：越过阈值后开始拖放，之后更新预览位置与悬停目标。
+func (g *game) dndMove(x, y float32) {
+	s := g.dnd
+	if s == nil {
+		return
+	}
+	s.x, s.y = x, y
+	if !s.active {
+		if absf(x-s.startX) < dragThreshold*uiScale && absf(y-s.startY) < dragThreshold*uiScale {
+			return
+		}
+		s.active = true
+		cancel := func() { g.dndEnd(false) }
+		s.esc = &escEntry{fn: &cancel}
+		g.escStack = append(g.escStack, s.esc)
+		if s.src.onDragging != nil {
+			s.src.onDragging(true)
+		}
+	}
+	g.setDropOver(g.dropTargetAt(x, y))
+	g.syncDragLayer()
+}
+
+// dndRelease 在左键松开时调用：有目标则投放，否则视同取消。
+func (g *game) dndRelease(x, y float32) {
+	if s := g.dnd; s != nil && s.active {
+		s.x, s.y = x, y
+		g.setDropOver(g.dropTargetAt(x, y))
+	}
+	g.dndEnd(true)
+}
+
+// dndEnd 结束拖放；drop 为真且有悬停目标时回调 onDrop。
+func (g *game) dndEnd(drop bool) {
+	s := g.dnd
+	if s == nil {
+		return
+	}
+	g.dnd = nil
+	if !s.active {
+		return // 没拖起来：只是一次点击
+	}
+	g.removeEsc(s.esc)
+	target := s.over
+	g.setDropOverIn(s, nil)
+	if drop && target != nil && target.onDrop != nil {
+		target.onDrop(s.payload, s.x/uiScale, s.y/uiScale)
+	}
+	if s.src.onDragging != nil && (s.srcFiber == nil || !s.srcFiber.unmounted) {
+		s.src.onDragging(false)
+	}
+	g.syncDragLayer()
+}
+
+// dropTargetAt 返回 (x,y) 处第一个接受当前载荷的 DropTarget（从命中节点向上冒泡）。
+func (g *game) dropTargetAt(x, y float32) *renderNode {
+	for c := g.hitTop(x, y); c != nil; c = c.parent {
+		if c.dropTarget && (c.dropAccept == nil || c.dropAccept(g.dnd.payload)) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (g *game) setDropOver(rn *renderNode) { g.setDropOverIn(g.dnd, rn) }
+
+// setDropOverIn 切换悬停目标，并给离开/进入的目标回调 onDragOver。
+func (g *game) setDropOverIn(s *dndSession, rn *renderNode) {
+	if s == nil || s.over == rn {
+		return
+	}
+	if old := s.over; old != nil && old.onDragOver != nil && (old.owner == nil || !old.owner.unmounted) {
+		old.onDragOver(false)
+	}
+	s.over = rn
+	if rn != nil && rn.onDragOver != nil {
+		rn.onDragOver(true)
+	}
+}
+
+// syncDragLayer 把拖放预览协调进窗口自己的浮层 fiber（不属于用户的树），拖放结束时卸掉。
+func (g *game) syncDragLayer() {
+	var n *Node
+	if s := g.dnd; s != nil && s.active {
+		n = s.previewNode()
+	}
+	if n == nil && g.dragLayer == nil {
+		return
+	}
+	g.dragLayer = reconcile(nil, g.dragLayer, n)
+	if g.dragLayer != nil {
+		g.dragLayer.g = g
+	}
+	g.needsLayout = true
+}
+
+// previewNode 构造跟随光标的预览：保持按下时的抓取点，略微透明。
+func (s *dndSession) previewNode() *Node {
+	k := uiScale
+	pos := Styles(Absolute, Left((s.x-s.offX)/k), Top((s.y-s.offY)/k), Opacity(0.8))
+	if s.preview != nil {
+		return Portal(Div(Style(pos), s.preview))
+	}
+	b := s.src.bounds
+	return Portal(Div(Style(pos, Width(b.W/k), Height(b.H/k), Radius(s.src.radius/k),
+		Bg(Gray.Alpha(0.25)), Border(1, Gray))))
+}
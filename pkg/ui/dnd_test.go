@@ -0,0 +1,104 @@
+package ui
+
+import "testing"
+
+type card struct{ title string }
+
+// 看板：把卡片拖到另一列，目标收到载荷；不接受的目标不高亮也收不到。
+func TestDragSourceDropTargetDeliversPayload(t *testing.T) {
+	var dropped []string
+	column := func(name string) *Node {
+		over, drop := UseDropTarget(
+			func(p any) bool { _, ok := p.(card); return ok && name != "locked" },
+			func(p any, x, y float32) { dropped = append(dropped, p.(card).title+"->"+name) },
+		)
+		label := name
+		if over {
+			label += "*"
+		}
+		return Div(Style(Width(100), Height(200)), drop, Text(label))
+	}
+	app := func(_ struct{}) *Node {
+		return Div(Style(Row),
+			Div(Style(Width(100), Height(200)),
+				Div(DragSource(card{"task"}), Style(Height(30)), Text("task"))),
+			Use(column, "done"),
+			Use(column, "locked"),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 300, 200)
+
+	src := h.Root().ByText("task")
+	h.PointerDown(20, 15)
+	h.PointerMove(150, 100)
+	if !h.Root().ByText("done*").Exists() {
+		t.Fatal("拖到 done 上方时应高亮")
+	}
+	if len(h.Overlays()) == 0 {
+		t.Fatal("拖放中应在浮层里画出预览")
+	}
+	h.PointerMove(250, 100)
+	if h.Root().ByText("locked*").Exists() || !h.Root().ByText("done").Exists() {
+		t.Fatal("locked 不接受载荷，不应高亮；离开 done 后其高亮应撤销")
+	}
+	h.PointerUp(250, 100)
+	if len(dropped) != 0 {
+		t.Fatalf("落在不接受的目标上不应投放：%v", dropped)
+	}
+
+	if !src.DragTo(h.Root().ByText("done")) {
+		t.Fatal("DragTo 应投放成功")
+	}
+	if len(dropped) != 1 || dropped[0] != "task->done" {
+		t.Fatalf("dropped = %v", dropped)
+	}
+	if len(h.Overlays()) != 0 {
+		t.Fatal("松开后预览应消失")
+	}
+}
+
+// 按下后没移动够阈值就松开：仍是普通点击，不开始拖放。
+func TestDragThresholdKeepsClicks(t *testing.T) {
+	clicks, drops := 0, 0
+	h := Mount(Div(Style(Row),
+		Button(OnClick(func() { clicks++ }), DragSource("x"), Style(Width(50), Height(30)), Text("b")),
+		Div(DropTarget(nil, func(any, float32, float32) { drops++ }), Style(Width(50), Height(30))),
+	), 200, 100)
+	h.PointerDown(10, 10)
+	h.PointerMove(12, 11)
+	h.PointerUp(12, 11)
+	if clicks != 1 || drops != 0 || len(h.Overlays()) != 0 {
+		t.Fatalf("clicks=%d drops=%d overlays=%d", clicks, drops, len(h.Overlays()))
+	}
+}
+
+// 拖放中按 Esc 取消：源的 dragging 复位，松开时不投放，且 Esc 不会落到下层处理器。
+func TestDragCancelOnEscape(t *testing.T) {
+	drops, escaped := 0, 0
+	app := func(_ struct{}) *Node {
+		dragging, src := UseDragSource("p")
+		UseEscape(true, func() { escaped++ })
+		label := "idle"
+		if dragging {
+			label = "dragging"
+		}
+		return Div(Style(Row),
+			Div(src, Style(Width(50), Height(30)), Text(label)),
+			Div(DropTarget(nil, func(any, float32, float32) { drops++ }), Style(Width(50), Height(30))),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	h.PointerDown(10, 10)
+	h.PointerMove(75, 15)
+	if !h.Root().ByText("dragging").Exists() {
+		t.Fatal("越过阈值后源应处于拖动态")
+	}
+	h.Escape()
+	if !h.Root().ByText("idle").Exists() || escaped != 0 {
+		t.Fatalf("Esc 应取消拖放（escaped=%d）", escaped)
+	}
+	h.PointerUp(75, 15)
+	if drops != 0 || len(h.Overlays()) != 0 {
+		t.Fatalf("取消后不应投放：drops=%d overlays=%d", drops, len(h.Overlays()))
+	}
+}
@@ -36,9 +36,7 @@ func errorBoundaryC(p ebProps) *Node {
 		err := f.caughtErr
 		retry := func() {
 			f.caughtErr = nil
-			if activeGame != nil {
-				activeGame.markDirty(f)
-			}
+			markFiberDirty(f)
 		}
 		if p.fallback != nil {
 			return p.fallback(err, retry)
@@ -7,17 +7,15 @@ type escEntry struct{ fn *func() }
 func UseEscape(active bool, fn func()) {
 	ref := UseRef(fn)
 	*ref = fn // 始终保留最新回调
+	// Esc 栈是按窗口的：登记到组件所在的窗口
+	g := renderingGame()
 	UseEffect(func() Cleanup {
-		if !active || activeGame == nil {
+		if !active || g == nil {
 			return nil
 		}
 		e := &escEntry{fn: ref}
-		activeGame.escStack = append(activeGame.escStack, e)
-		return func() {
-			if activeGame != nil {
-				activeGame.removeEsc(e)
-			}
-		}
+		g.escStack = append(g.escStack, e)
+		return func() { g.removeEsc(e) }
 	}, active)
 }
 
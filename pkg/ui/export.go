@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+)
+
+// ---- 矢量导出：SVG / PDF ----
+//
+// RenderSVG / RenderPDF 把一棵 UI 树无头排版后画成矢量文档，不需要窗口也不需要 GPU，
+// 适合报表、仪表盘导出：
+//
+//	svg, err := ui.RenderSVG(ui.Use(Dashboard, props), 1280, 800)
+//	pdf, err := ui.RenderPDF(ui.Use(Dashboard, props), 1280, 800)
+//
+// 两者都是 painter 的又一个实现（export_svg.go / export_pdf.go），与屏幕走同一条 paint 遍历，
+// 所以画出来的就是窗口里看到的那一帧：矩形与圆角、多色标渐变、阴影、文字、图标路径、
+// 图片、裁剪、带变换与透明度的图层（含伪 3D）。坐标单位是物理像素；PDF 按 1px = 1pt 定页面。
+//
+// 各原语的表达方式：
+//
+//   - 文字：用 gio 的 shaper 整形（与屏幕同一份字形位置），只带用到的字形（见 export_font.go）。
+//     合成粗体 / 斜体与屏幕一致：描边加粗、剪切出斜体。
+//   - 渐变：线性、径向用格式自带的渐变；锥形（两种格式都没有）栅格化成贴图。PDF 的渐变
+//     不支持半透明色标，这种也走贴图。
+//   - 阴影：SVG 用高斯模糊滤镜；PDF 没有模糊，按屏幕后端的做法叠几层渐扩的半透明圆角矩形。
+//   - 图层：SVG 是带 transform / opacity / filter 的 <g>；PDF 是透明组（Form XObject），
+//     整组透明度由 ExtGState 给。PDF 没有滤镜，图层上的 Blur / Grayscale 等在 PDF 里被忽略。
+
+// RenderSVG 无头渲染一棵 UI 树，返回 w×h 的 SVG 文档。
+func RenderSVG(root *Node, w, h int) ([]byte, error) {
+	g := Mount(root, w, h).g
+	f, err := newExportFont()
+	if err != nil {
+		return nil, err
+	}
+	p := newSVGPainter(g.w, g.h, f)
+	exportPaint(p, g)
+	return p.bytes(), nil
+}
+
+// RenderPDF 无头渲染一棵 UI 树，返回单页 PDF（页面 w×h pt）。
+func RenderPDF(root *Node, w, h int) ([]byte, error) {
+	g := Mount(root, w, h).g
+	f, err := newExportFont()
+	if err != nil {
+		return nil, err
+	}
+	p := newPDFPainter(g.w, g.h, f)
+	exportPaint(p, g)
+	return p.bytes()
+}
+
+// exportPaint 画一帧：底色，然后主树与各 Portal 浮层。
+func exportPaint(p painter, g *game) {
+	p.FillRect(0, 0, float32(g.w), float32(g.h), 0, canvasBg)
+	for _, r := range g.paintRoots() {
+		paint(p, r)
+	}
+}
+
+// layerMatrix 是图层合回时的仿射：2D 时绕中心缩放、旋转再平移（与 gioPainter.EndLayer
+// 同序），伪 3D 时是 contentAffine（轮廓另由 projCorners 的四边形裁出）。
+func layerMatrix(t layerTransform) affine2D {
+	if t.is3D() {
+		return contentAffine(t)
+	}
+	sx, sy := t.scaleX, t.scaleY
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+	// 先缩放再旋转：R·S，各列分别乘 X、Y 的缩放。
+	sin, cos := math.Sincos(float64(t.rotate) * math.Pi / 180)
+	a := affine2D{sx: sx * float32(cos), hx: -sy * float32(sin), hy: sx * float32(sin), sy: sy * float32(cos)}
+	a.ox = t.cx + t.tx - a.sx*t.cx - a.hx*t.cy
+	a.oy = t.cy + t.ty - a.hy*t.cx - a.sy*t.cy
+	return a
+}
+
+func (a affine2D) identity() bool { return a == affine2D{1, 0, 0, 0, 1, 0} }
+
+// rrectTo 把圆角矩形写进 sink：与 gioPainter.rrectPath 同一套三次贝塞尔圆角。
+func rrectTo(s pathSink, x, y, w, h, r float32) {
+	const q = 4 * (math.Sqrt2 - 1) / 3
+	const iq = 1 - q
+	r = clampRadius(w, h, r)
+	west, north, east, south := x, y, x+w, y+h
+	s.moveTo(west+r, north)
+	s.lineTo(east-r, north)
+	if r > 0 {
+		s.cubeTo(east-r*iq, north, east, north+r*iq, east, north+r)
+	}
+	s.lineTo(east, south-r)
+	if r > 0 {
+		s.cubeTo(east, south-r*iq, east-r*iq, south, east-r, south)
+	}
+	s.lineTo(west+r, south)
+	if r > 0 {
+		s.cubeTo(west+r*iq, south, west, south-r*iq, west, south-r)
+	}
+	s.lineTo(west, north+r)
+	if r > 0 {
+		s.cubeTo(west, north+r*iq, west+r*iq, north, west+r, north)
+	}
+	s.closePath()
+}
+
+// gradientRaster 把渐变栅格化成盒大小的贴图（格式不支持的渐变用它）。
+func gradientRaster(g *Gradient, w, h float32) *image.NRGBA {
+	iw, ih := int(math.Ceil(float64(w))), int(math.Ceil(float64(h)))
+	if iw <= 0 || ih <= 0 {
+		return nil
+	}
+	return g.raster(iw, ih)
+}
+
+// toNRGBA 把任意图像转成非预乘的 NRGBA（PNG 与 PDF 的 SMask 都要非预乘）。
+func toNRGBA(src image.Image) *image.NRGBA {
+	if n, ok := src.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Rect, src, b.Min, draw.Src)
+	return dst
+}
+
+func encodePNG(img image.Image) []byte {
+	var b bytes.Buffer
+	png.Encode(&b, img)
+	return b.Bytes()
+}
+
+// fmtNum 格式化坐标：保留 3 位小数、去掉多余的 0，文档小而稳定。
+func fmtNum(v float32) string {
+	r := math.Round(float64(v)*1000) / 1000
+	if r == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(r, 'f', -1, 64)
+}
+
+// fmtCoef 格式化矩阵与缩放系数：它们要乘上几百像素的坐标，3 位小数不够，写 float32 的
+// 最短精确表示（不用指数记法，PDF 不认）。
+func fmtCoef(v float32) string { return strconv.FormatFloat(float64(v), 'f', -1, 32) }
@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// ---- 矢量导出：内置字体的字形与子集 ----
+//
+// SVG 与 PDF 都只带用到的字形，不带整套字体（内置 OPPOSans 含全部 CJK，十几 MB）：
+//
+//   - SVG 把用到的字形轮廓写成 <defs> 里的 path，文字处用 <use> 引用 —— 不依赖查看器
+//     有没有装这套字体，也绕开浏览器对内嵌 TrueType 的种种校验。
+//   - PDF 嵌入真正的 TrueType 子集（FontFile2）：没用到的字形清空、字形号保持不变，
+//     于是内容流里直接写字形号（Identity-H），不必重新编号。
+//
+// 轮廓与推进量由 golang.org/x/image/font/sfnt 读；子集是表级别的改写，见 subsetTrueType。
+// 软件后端（soft_paint.go）画字也从这里取轮廓。
+
+// exportFont 是一次导出里共用的内置字体：缓存轮廓，记下用到的字形。
+type exportFont struct {
+	sf    *sfnt.Font
+	buf   sfnt.Buffer
+	upem  float32
+	segs  map[uint16][]sfnt.Segment // 字形轮廓（字体单位，y 向下）
+	paths map[uint16]string         // 同一轮廓的 SVG d
+	used  map[uint16]string         // 用到的字形 -> 对应原文（簇首字形才有）
+}
+
+func newExportFont() (*exportFont, error) {
+	sf, err := sfnt.Parse(cjkFont)
+	if err != nil {
+		return nil, err
+	}
+	return &exportFont{
+		sf:    sf,
+		upem:  float32(sf.UnitsPerEm()),
+		segs:  map[uint16][]sfnt.Segment{},
+		paths: map[uint16]string{},
+		used:  map[uint16]string{},
+	}, nil
+}
+
+// use 记下一行用到的字形。
+func (f *exportFont) use(run shapedRun) {
+	for _, g := range run.glyphs {
+		if cur, ok := f.used[g.gid]; !ok || cur == "" {
+			f.used[g.gid] = g.text
+		}
+	}
+}
+
+// ppem 让 sfnt 按「1 像素 = 1 字体单位」出坐标。
+func (f *exportFont) ppem() fixed.Int26_6 { return fixed.Int26_6(f.upem * 64) }
+
+// segments 返回字形轮廓（字体单位，y 向下，原点在基线）；空白字形为 nil。
+func (f *exportFont) segments(gid uint16) []sfnt.Segment {
+	if segs, ok := f.segs[gid]; ok {
+		return segs
+	}
+	segs, err := f.sf.LoadGlyph(&f.buf, sfnt.GlyphIndex(gid), f.ppem(), nil)
+	if err != nil {
+		segs = nil
+	}
+	segs = slices.Clone(segs) // LoadGlyph 的结果复用 buf，要留着就得拷一份
+	f.segs[gid] = segs
+	return segs
+}
+
+// outline 返回字形轮廓的 SVG d（字体单位，y 向下，原点在基线）；空白字形为 ""。
+func (f *exportFont) outline(gid uint16) string {
+	if d, ok := f.paths[gid]; ok {
+		return d
+	}
+	var b strings.Builder
+	if segs := f.segments(gid); segs != nil {
+		for _, s := range segs {
+			switch s.Op {
+			case sfnt.SegmentOpMoveTo:
+				if b.Len() > 0 {
+					b.WriteString("Z")
+				}
+				fmt.Fprintf(&b, "M%s", svgPoints(s.Args[:1]))
+			case sfnt.SegmentOpLineTo:
+				fmt.Fprintf(&b, "L%s", svgPoints(s.Args[:1]))
+			case sfnt.SegmentOpQuadTo:
+				fmt.Fprintf(&b, "Q%s", svgPoints(s.Args[:2]))
+			case sfnt.SegmentOpCubeTo:
+				fmt.Fprintf(&b, "C%s", svgPoints(s.Args[:3]))
+			}
+		}
+		if b.Len() > 0 {
+			b.WriteString("Z")
+		}
+	}
+	f.paths[gid] = b.String()
+	return f.paths[gid]
+}
+
+func svgPoints(ps []fixed.Point26_6) string {
+	parts := make([]string, len(ps))
+	for i, p := range ps {
+		parts[i] = fmtNum(float32(p.X)/64) + " " + fmtNum(float32(p.Y)/64)
+	}
+	return strings.Join(parts, " ")
+}
+
+// advance 返回字形推进量（字体单位）。
+func (f *exportFont) advance(gid uint16) float32 {
+	adv, err := f.sf.GlyphAdvance(&f.buf, sfnt.GlyphIndex(gid), f.ppem(), font.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return float32(adv) / 64
+}
+
+// ---- TrueType 子集 ----
+
+// subsetTables 是子集保留的表：PDF 的 FontFile2 只需要字形与度量，cvt/fpgm/prep 是字形指令
+// 要用的，有就带上。内容流按字形号取字（Identity），用不到 cmap 与字形名：cmap 换成只含结束段
+// 的空表，post 降成不带字形名的 3.0 版 —— 两者仍是必需表，少了它们不少解析器拒收。
+var subsetTables = []string{"cmap", "cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "post", "prep"}
+
+// emptyCmap 是只有一个 (3,1) format 4 子表、其中只有 0xFFFF 结束段的 cmap。
+var emptyCmap = []byte{
+	0, 0, 0, 1, // version, numTables
+	0, 3, 0, 1, 0, 0, 0, 12, // platform 3 / encoding 1，子表偏移 12
+	0, 4, 0, 24, 0, 0, // format 4, length, language
+	0, 2, 0, 2, 0, 0, 0, 0, // segCountX2, searchRange, entrySelector, rangeShift
+	0xff, 0xff, 0, 0, 0xff, 0xff, // endCode, reservedPad, startCode
+	0, 1, 0, 0, // idDelta, idRangeOffset
+}
+
+// subsetTrueType 返回只含 keep 中字形（及其复合字形引用的部件、.notdef）的 TrueType 字体。
+// 字形号不变：没用到的字形在 loca 里长度为 0。loca 一律改写成长格式。
+func subsetTrueType(data []byte, keep map[uint16]string) ([]byte, error) {
+	tables, err := sfntTables(data)
+	if err != nil {
+		return nil, err
+	}
+	head, loca, glyf, maxp := tables["head"], tables["loca"], tables["glyf"], tables["maxp"]
+	if head == nil || loca == nil || glyf == nil || maxp == nil || len(head) < 54 || len(maxp) < 6 {
+		return nil, errors.New("ui: font has no TrueType outlines")
+	}
+	n := int(binary.BigEndian.Uint16(maxp[4:]))
+	long := binary.BigEndian.Uint16(head[50:]) == 1
+	offset := func(i int) int {
+		if long {
+			if 4*i+4 > len(loca) {
+				return len(glyf)
+			}
+			return int(binary.BigEndian.Uint32(loca[4*i:]))
+		}
+		if 2*i+2 > len(loca) {
+			return len(glyf)
+		}
+		return 2 * int(binary.BigEndian.Uint16(loca[2*i:]))
+	}
+	glyph := func(i int) []byte {
+		a, b := offset(i), offset(i+1)
+		if a >= b || b > len(glyf) {
+			return nil
+		}
+		return glyf[a:b]
+	}
+
+	// 闭包：复合字形引用的部件也要留下。
+	want := map[int]bool{}
+	queue := []int{0} // .notdef 必须留着
+	for gid := range keep {
+		queue = append(queue, int(gid))
+	}
+	for len(queue) > 0 {
+		gid := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if gid >= n || want[gid] {
+			continue
+		}
+		want[gid] = true
+		for _, c := range glyphComponents(glyph(gid)) {
+			if !want[c] {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, 4*(n+1))
+	for i := 0; i < n; i++ {
+		binary.BigEndian.PutUint32(newLoca[4*i:], uint32(newGlyf.Len()))
+		if want[i] {
+			newGlyf.Write(glyph(i))
+			for newGlyf.Len()%4 != 0 {
+				newGlyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[4*n:], uint32(newGlyf.Len()))
+
+	head = bytes.Clone(head)
+	binary.BigEndian.PutUint32(head[8:], 0) // checkSumAdjustment，写完再回填
+	binary.BigEndian.PutUint16(head[50:], 1)
+	tables["head"], tables["loca"], tables["glyf"] = head, newLoca, newGlyf.Bytes()
+	tables["cmap"] = emptyCmap
+	if post := tables["post"]; len(post) >= 32 { // 3.0 版：只留度量，不带字形名
+		post = bytes.Clone(post[:32])
+		binary.BigEndian.PutUint32(post, 0x00030000)
+		tables["post"] = post
+	}
+
+	var kept []string
+	for _, tag := range subsetTables {
+		if tables[tag] != nil {
+			kept = append(kept, tag)
+		}
+	}
+	out := writeSfnt(tables, kept)
+	// head 的 checkSumAdjustment = 0xB1B0AFBA - 整个文件的校验和
+	headAt := 12 + 16*len(kept)
+	for _, tag := range kept {
+		if tag == "head" {
+			break
+		}
+		headAt += (len(tables[tag]) + 3) &^ 3
+	}
+	binary.BigEndian.PutUint32(out[headAt+8:], 0xB1B0AFBA-sfntChecksum(out))
+	return out, nil
+}
+
+// sfntTables 读出表目录：表名 -> 表数据。
+func sfntTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, errors.New("ui: font too short")
+	}
+	num := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*num {
+		return nil, errors.New("ui: truncated font table directory")
+	}
+	out := make(map[string][]byte, num)
+	for i := 0; i < num; i++ {
+		rec := data[12+16*i:]
+		off, size := binary.BigEndian.Uint32(rec[8:]), binary.BigEndian.Uint32(rec[12:])
+		if uint64(off)+uint64(size) > uint64(len(data)) {
+			return nil, fmt.Errorf("ui: font table %q out of range", rec[:4])
+		}
+		out[string(rec[:4])] = data[off : off+size]
+	}
+	return out, nil
+}
+
+// glyphComponents 返回复合字形引用的部件字形号；简单字形为 nil。
+func glyphComponents(g []byte) []int {
+	if len(g) < 10 || int16(binary.BigEndian.Uint16(g)) >= 0 {
+		return nil
+	}
+	const (
+		argsAreWords = 0x0001
+		haveScale    = 0x0008
+		moreComps    = 0x0020
+		haveXYScale  = 0x0040
+		haveTwoByTwo = 0x0080
+	)
+	var out []int
+	for p := 10; p+4 <= len(g); {
+		flags := binary.BigEndian.Uint16(g[p:])
+		out = append(out, int(binary.BigEndian.Uint16(g[p+2:])))
+		p += 4
+		if flags&argsAreWords != 0 {
+			p += 4
+		} else {
+			p += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			p += 2
+		case flags&haveXYScale != 0:
+			p += 4
+		case flags&haveTwoByTwo != 0:
+			p += 8
+		}
+		if flags&moreComps == 0 {
+			break
+		}
+	}
+	return out
+}
+
+// writeSfnt 把 tags 列出的表按表名排序后写成一个 TrueType 文件。
+func writeSfnt(tables map[string][]byte, tags []string) []byte {
+	sort.Strings(tags)
+	num := len(tags)
+	entry := 0
+	for 1<<(entry+1) <= num {
+		entry++
+	}
+	var b bytes.Buffer
+	be := func(v any) { binary.Write(&b, binary.BigEndian, v) }
+	be(uint32(0x00010000))
+	be(uint16(num))
+	be(uint16(16 << entry))
+	be(uint16(entry))
+	be(uint16(16*num - 16<<entry))
+	off := 12 + 16*num
+	for _, tag := range tags {
+		t := tables[tag]
+		b.WriteString(tag)
+		be(sfntChecksum(t))
+		be(uint32(off))
+		be(uint32(len(t)))
+		off += (len(t) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		b.Write(tables[tag])
+		for b.Len()%4 != 0 {
+			b.WriteByte(0)
+		}
+	}
+	return b.Bytes()
+}
+
+// sfntChecksum 是 TrueType 的表校验和：按大端 uint32 求和，不足 4 字节补零。
+func sfntChecksum(d []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(d); i += 4 {
+		var w [4]byte
+		copy(w[:], d[i:])
+		sum += binary.BigEndian.Uint32(w[:])
+	}
+	return sum
+}
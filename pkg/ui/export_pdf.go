@@ -0,0 +1,560 @@
+package ui
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// ---- 矢量导出：PDF 后端 ----
+//
+// pdfPainter 把 painter 原语写成 PDF 内容流。页面开头翻转 y 轴（1 0 0 -1 0 H cm），
+// 此后内容流直接用界面的物理像素坐标（y 向下）；只有文字与图片这两处要再翻回来。
+//
+// 每个原语包在 q/Q 里，颜色与透明度（ExtGState）不外泄；PushClip 是 q + 路径 + W n，
+// PopClip 是 Q。BeginLayer 开一条新内容流，EndLayer 把它收成透明组 Form XObject，
+// 在上一层里按图层变换 cm 后 Do —— 整组透明度因此作用在合成后的组上，而不是逐个原语。
+// 页面与所有 Form 共用一个资源字典，文档写完时才落盘。
+
+type pdfPainter struct {
+	w, h    float32
+	font    *exportFont
+	objs    [][]byte        // 间接对象，编号 = 下标 + 1
+	layers  []*bytes.Buffer // [0] 是页面内容流，其上是未合回的图层
+	res     int             // 共享资源字典的对象号（预留，最后写）
+	alphas  map[uint8]string
+	images  map[bitmap]string
+	xobjs   []string // 资源字典里的 /XObject 条目
+	shades  []string // 资源字典里的 /Shading 条目
+	gstates []string // 资源字典里的 /ExtGState 条目
+}
+
+func newPDFPainter(w, h int, f *exportFont) *pdfPainter {
+	p := &pdfPainter{
+		w: float32(w), h: float32(h), font: f,
+		layers: []*bytes.Buffer{{}},
+		alphas: map[uint8]string{},
+		images: map[bitmap]string{},
+	}
+	p.res = p.alloc()
+	fmt.Fprintf(p.out(), "1 0 0 -1 0 %s cm\n", fmtNum(p.h))
+	return p
+}
+
+func (p *pdfPainter) out() *bytes.Buffer { return p.layers[len(p.layers)-1] }
+
+// alloc 预留一个对象号，稍后用 set 填内容。
+func (p *pdfPainter) alloc() int {
+	p.objs = append(p.objs, nil)
+	return len(p.objs)
+}
+
+func (p *pdfPainter) set(n int, body string) { p.objs[n-1] = []byte(body) }
+
+func (p *pdfPainter) add(body string) int {
+	n := p.alloc()
+	p.set(n, body)
+	return n
+}
+
+// stream 加一个 Flate 压缩的流对象；dict 是流字典里除 /Length、/Filter 之外的条目。
+func (p *pdfPainter) stream(dict string, data []byte) int {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(data)
+	zw.Close()
+	if dict != "" {
+		dict += " "
+	}
+	return p.add(fmt.Sprintf("<< %s/Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", dict, z.Len(), z.Bytes()))
+}
+
+// gs 返回透明度 a 对应的 ExtGState 名（填充与描边同值）；不透明返回 ""。
+func (p *pdfPainter) gs(a uint8) string {
+	if a == 255 {
+		return ""
+	}
+	if name, ok := p.alphas[a]; ok {
+		return name
+	}
+	name := fmt.Sprintf("GA%d", a)
+	v := fmtNum(float32(a) / 255)
+	p.gstates = append(p.gstates, fmt.Sprintf("/%s << /ca %s /CA %s >>", name, v, v))
+	p.alphas[a] = name
+	return name
+}
+
+func opacityByte(o float32) uint8 { return uint8(min(max(o, 0), 1)*255 + 0.5) }
+
+// color 写出填充（op="rg"）或描边（op="RG"）颜色，以及透明度。
+func (p *pdfPainter) color(c Color, op string) {
+	if name := p.gs(c.A); name != "" {
+		fmt.Fprintf(p.out(), "/%s gs ", name)
+	}
+	fmt.Fprintf(p.out(), "%s %s %s %s\n", fmtNum(float32(c.R)/255), fmtNum(float32(c.G)/255), fmtNum(float32(c.B)/255), op)
+}
+
+// pdfPathSink 把路径写成 PDF 路径算子；二次曲线升成三次。
+type pdfPathSink struct {
+	b      *bytes.Buffer
+	ox, oy float32
+	cx, cy float32 // 当前点（未平移），升阶要用
+}
+
+func (s *pdfPathSink) pt(x, y float32) string { return fmtNum(x+s.ox) + " " + fmtNum(y+s.oy) }
+func (s *pdfPathSink) moveTo(x, y float32) {
+	fmt.Fprintf(s.b, "%s m\n", s.pt(x, y))
+	s.cx, s.cy = x, y
+}
+func (s *pdfPathSink) lineTo(x, y float32) {
+	fmt.Fprintf(s.b, "%s l\n", s.pt(x, y))
+	s.cx, s.cy = x, y
+}
+func (s *pdfPathSink) cubeTo(x1, y1, x2, y2, x, y float32) {
+	fmt.Fprintf(s.b, "%s %s %s c\n", s.pt(x1, y1), s.pt(x2, y2), s.pt(x, y))
+	s.cx, s.cy = x, y
+}
+func (s *pdfPathSink) quadTo(x1, y1, x, y float32) {
+	s.cubeTo(s.cx+(x1-s.cx)*2/3, s.cy+(y1-s.cy)*2/3, x+(x1-x)*2/3, y+(y1-y)*2/3, x, y)
+}
+func (s *pdfPathSink) closePath() { s.b.WriteString("h\n") }
+
+func (p *pdfPainter) rrect(x, y, w, h, r float32) {
+	if clampRadius(w, h, r) <= 0 {
+		fmt.Fprintf(p.out(), "%s %s %s %s re\n", fmtNum(x), fmtNum(y), fmtNum(w), fmtNum(h))
+		return
+	}
+	rrectTo(&pdfPathSink{b: p.out()}, x, y, w, h, r)
+}
+
+func (p *pdfPainter) FillRect(x, y, w, h, r float32, c Color) {
+	if c.A == 0 || w <= 0 || h <= 0 {
+		return
+	}
+	p.out().WriteString("q ")
+	p.color(c, "rg")
+	p.rrect(x, y, w, h, r)
+	p.out().WriteString("f Q\n")
+}
+
+func (p *pdfPainter) FillGradient(x, y, w, h, r float32, g *Gradient) {
+	if w <= 0 || h <= 0 || len(g.Stops) == 0 {
+		return
+	}
+	p.out().WriteString("q\n")
+	p.rrect(x, y, w, h, r)
+	p.out().WriteString("W n\n")
+	p.paintGradient(g, x, y, w, h)
+	p.out().WriteString("Q\n")
+}
+
+func (p *pdfPainter) StrokeRect(x, y, w, h, r, width float32, c Color) {
+	if c.A == 0 || width <= 0 || w <= 0 || h <= 0 {
+		return
+	}
+	p.out().WriteString("q ")
+	p.color(c, "RG")
+	fmt.Fprintf(p.out(), "%s w\n", fmtNum(width))
+	p.rrect(x, y, w, h, r)
+	p.out().WriteString("S Q\n")
+}
+
+// StrokeGradient：PDF 不能裁到描边，改用「外扩半线宽的圆角矩形 − 内缩半线宽的圆角矩形」
+// 这个环做奇偶裁剪，再铺渐变。
+func (p *pdfPainter) StrokeGradient(x, y, w, h, r, width float32, g *Gradient) {
+	if width <= 0 || w <= 0 || h <= 0 || len(g.Stops) == 0 {
+		return
+	}
+	hw := width / 2
+	p.out().WriteString("q\n")
+	p.rrect(x-hw, y-hw, w+width, h+width, r+hw)
+	if w > width && h > width {
+		p.rrect(x+hw, y+hw, w-width, h-width, max(r-hw, 0))
+	}
+	p.out().WriteString("W* n\n")
+	p.paintGradient(g, x-hw, y-hw, w+width, h+width)
+	p.out().WriteString("Q\n")
+}
+
+// paintGradient 在当前裁剪内铺满渐变，渐变铺满 (x,y,w,h)。
+// 不透明的线性 / 径向渐变用原生 shading；锥形与带透明色标的栅格化成贴图。
+func (p *pdfPainter) paintGradient(g *Gradient, x, y, w, h float32) {
+	opaque := g.Kind != GradientConic
+	for _, s := range g.Stops {
+		opaque = opaque && s.Color.A == 255
+	}
+	if !opaque {
+		if img := gradientRaster(g, w, h); img != nil {
+			p.drawXObject(p.imageXObject(img), x, y, w, h)
+		}
+		return
+	}
+	var coords string
+	kind := 2
+	if g.Kind == GradientRadial {
+		kind = 3
+		cx, cy := fmtNum(x+g.CX*w), fmtNum(y+g.CY*h)
+		coords = fmt.Sprintf("%s %s 0 %s %s %s", cx, cy, cx, cy, fmtNum(g.radialRadius(w, h)))
+	} else {
+		x0, y0, x1, y1 := g.linearSpan(w, h)
+		coords = fmt.Sprintf("%s %s %s %s", fmtNum(x+x0), fmtNum(y+y0), fmtNum(x+x1), fmtNum(y+y1))
+	}
+	n := p.add(fmt.Sprintf("<< /ShadingType %d /ColorSpace /DeviceRGB /Coords [%s] /Function %s /Extend [true true] >>",
+		kind, coords, pdfStopsFunction(g.Stops)))
+	name := fmt.Sprintf("Sh%d", n)
+	p.shades = append(p.shades, fmt.Sprintf("/%s %d 0 R", name, n))
+	fmt.Fprintf(p.out(), "/%s sh\n", name)
+}
+
+// pdfStopsFunction 把色标写成 [0,1] 上的函数：相邻色标间各一段线性插值（Type 2），
+// 多段用 Type 3 拼接。两端之外取端点色，与 colorAt 一致。
+func pdfStopsFunction(stops []ColorStop) string {
+	st := append([]ColorStop(nil), stops...)
+	if st[0].Offset > 0 {
+		st = append([]ColorStop{{0, st[0].Color}}, st...)
+	}
+	if st[len(st)-1].Offset < 1 {
+		st = append(st, ColorStop{1, st[len(st)-1].Color})
+	}
+	rgb := func(c Color) string {
+		return fmt.Sprintf("%s %s %s", fmtNum(float32(c.R)/255), fmtNum(float32(c.G)/255), fmtNum(float32(c.B)/255))
+	}
+	seg := func(a, b Color) string {
+		return fmt.Sprintf("<< /FunctionType 2 /Domain [0 1] /C0 [%s] /C1 [%s] /N 1 >>", rgb(a), rgb(b))
+	}
+	if len(st) == 2 {
+		return seg(st[0].Color, st[1].Color)
+	}
+	var fns, bounds, encode []string
+	for i := 1; i < len(st); i++ {
+		fns = append(fns, seg(st[i-1].Color, st[i].Color))
+		encode = append(encode, "0 1")
+		if i < len(st)-1 {
+			bounds = append(bounds, fmtNum(st[i].Offset))
+		}
+	}
+	return fmt.Sprintf("<< /FunctionType 3 /Domain [0 1] /Functions [%s] /Bounds [%s] /Encode [%s] >>",
+		strings.Join(fns, " "), strings.Join(bounds, " "), strings.Join(encode, " "))
+}
+
+// Shadow：PDF 没有模糊，照 gioPainter.Shadow 叠几层渐扩的半透明圆角矩形。
+func (p *pdfPainter) Shadow(x, y, w, h, r, offX, offY, blur, spread float32, c Color) {
+	if c.A == 0 || w <= 0 || h <= 0 {
+		return
+	}
+	bx, by := x+offX-spread, y+offY-spread
+	bw, bh := w+spread*2, h+spread*2
+	br := max(r+spread, 0)
+	if blur <= 0 {
+		p.FillRect(bx, by, bw, bh, br, c)
+		return
+	}
+	const layers = 5
+	la := c.Alpha(1.0 / float32(layers))
+	for i := layers; i >= 1; i-- {
+		g := blur * float32(i) / float32(layers)
+		p.FillRect(bx-g, by-g, bw+g*2, bh+g*2, br+g, la)
+	}
+	p.FillRect(bx, by, bw, bh, br, la)
+}
+
+func (p *pdfPainter) Line(x0, y0, x1, y1 float32, c Color) {
+	if c.A == 0 {
+		return
+	}
+	p.out().WriteString("q ")
+	p.color(c, "RG")
+	fmt.Fprintf(p.out(), "1 w %s %s m %s %s l S Q\n", fmtNum(x0), fmtNum(y0), fmtNum(x1), fmtNum(y1))
+}
+
+// text 写一行字形的 BT…ET。文字矩阵把字形空间（y 向上、1 = 1em）映射到翻转后的页面：
+// [px 0 c -px x baseline]，c 是合成斜体的剪切。字形逐个按整形结果定位：TJ 里的调整量
+// 补上字体推进量（/W）与整形位置之差，字距、连字后的位置因此与屏幕一致。
+func (p *pdfPainter) text(run shapedRun, x, y float32, mode int, fauxItalic bool) {
+	if len(run.glyphs) == 0 || run.px <= 0 {
+		return
+	}
+	p.font.use(run)
+	b := p.out()
+	var shear float32
+	if fauxItalic {
+		shear = run.px * float32(math.Tan(fauxItalicShear))
+	}
+	g0 := run.glyphs[0]
+	fmt.Fprintf(b, "BT /F0 1 Tf %d Tr ", mode)
+	if mode == 2 { // 合成粗体的描边宽度
+		fmt.Fprintf(b, "%s w ", fmtNum(fauxBoldWidth(run.px)))
+	}
+	fmt.Fprintf(b, "%s 0 %s %s %s %s Tm [", fmtNum(run.px), fmtNum(shear), fmtNum(-run.px), fmtNum(x+g0.x), fmtNum(y+run.ascent+g0.y))
+	for i, g := range run.glyphs {
+		fmt.Fprintf(b, "<%04X>", g.gid)
+		if i+1 < len(run.glyphs) {
+			want := (run.glyphs[i+1].x - g.x) * 1000 / run.px
+			if adj := p.width(g.gid) - want; math.Abs(float64(adj)) > 0.01 {
+				fmt.Fprintf(b, " %s ", fmtNum(adj))
+			}
+		}
+	}
+	b.WriteString("] TJ ET\n")
+}
+
+// width 是字形在 /W 里的宽度（千分之一 em）。
+func (p *pdfPainter) width(gid uint16) float32 { return p.font.advance(gid) * 1000 / p.font.upem }
+
+func (p *pdfPainter) DrawText(s string, face fontFace, c Color, x, y float32, fauxBold, fauxItalic bool) {
+	if s == "" || c.A == 0 {
+		return
+	}
+	p.out().WriteString("q ")
+	p.color(c, "rg")
+	mode := 0
+	if fauxBold { // 填充 + 描边：与屏幕一样在填充外再描一圈
+		p.color(c, "RG")
+		mode = 2
+	}
+	p.text(exportShape(face, s), x, y, mode, fauxItalic)
+	p.out().WriteString("Q\n")
+}
+
+// DrawTextGradient：字形作裁剪（渲染模式 7），再在裁剪里铺满渐变的 box。
+func (p *pdfPainter) DrawTextGradient(s string, face fontFace, g *Gradient, box Rect, x, y float32, fauxBold, fauxItalic bool) {
+	if s == "" || len(g.Stops) == 0 {
+		return
+	}
+	p.out().WriteString("q\n")
+	p.text(exportShape(face, s), x, y, 7, fauxItalic)
+	p.paintGradient(g, box.X, box.Y, box.W, box.H)
+	p.out().WriteString("Q\n")
+}
+
+// imageXObject 把图像写成 Image XObject（RGB + 可选的 SMask 作 alpha），返回资源名。
+func (p *pdfPainter) imageXObject(src *image.NRGBA) string {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	rgb := make([]byte, 0, w*h*3)
+	alpha := make([]byte, 0, w*h)
+	opaque := true
+	for i := 0; i < len(src.Pix); i += 4 {
+		rgb = append(rgb, src.Pix[i], src.Pix[i+1], src.Pix[i+2])
+		alpha = append(alpha, src.Pix[i+3])
+		opaque = opaque && src.Pix[i+3] == 255
+	}
+	dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8", w, h)
+	if !opaque {
+		mask := p.stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8", w, h), alpha)
+		dict += fmt.Sprintf(" /SMask %d 0 R", mask)
+	}
+	n := p.stream(dict, rgb)
+	name := fmt.Sprintf("Im%d", n)
+	p.xobjs = append(p.xobjs, fmt.Sprintf("/%s %d 0 R", name, n))
+	return name
+}
+
+// drawXObject 把图片画进 (x,y,w,h)：图片空间是 y 向上的单位正方形，翻回来再缩放。
+func (p *pdfPainter) drawXObject(name string, x, y, w, h float32) {
+	fmt.Fprintf(p.out(), "q %s 0 0 %s %s %s cm /%s Do Q\n", fmtNum(w), fmtNum(-h), fmtNum(x), fmtNum(y+h), name)
+}
+
+func (p *pdfPainter) DrawImage(img bitmap, d Rect, opacity float32) {
+	if opacity <= 0 || d.W <= 0 || d.H <= 0 {
+		return
+	}
+	name, ok := p.images[img]
+	if !ok {
+		name = p.imageXObject(toNRGBA(exportImage(img)))
+		p.images[img] = name
+	}
+	p.out().WriteString("q ")
+	if gs := p.gs(opacityByte(opacity)); gs != "" {
+		fmt.Fprintf(p.out(), "/%s gs ", gs)
+	}
+	p.drawXObject(name, d.X, d.Y, d.W, d.H)
+	p.out().WriteString("Q\n")
+}
+
+func (p *pdfPainter) path(path vecPath, x, y float32) {
+	d, scale := exportPath(path)
+	parseSVGInto(d, scale, &pdfPathSink{b: p.out(), ox: x, oy: y})
+}
+
+func (p *pdfPainter) FillPath(path vecPath, x, y float32, c Color) {
+	if c.A == 0 {
+		return
+	}
+	p.out().WriteString("q ")
+	p.color(c, "rg")
+	p.path(path, x, y)
+	p.out().WriteString("f Q\n")
+}
+
+func (p *pdfPainter) StrokePath(path vecPath, x, y, width float32, c Color) {
+	if c.A == 0 || width <= 0 {
+		return
+	}
+	p.out().WriteString("q ")
+	p.color(c, "RG")
+	fmt.Fprintf(p.out(), "%s w 1 J 1 j\n", fmtNum(width))
+	p.path(path, x, y)
+	p.out().WriteString("S Q\n")
+}
+
+func (p *pdfPainter) PushClip(r Rect, radius float32) {
+	p.out().WriteString("q\n")
+	p.rrect(r.X, r.Y, r.W, r.H, radius)
+	p.out().WriteString("W n\n")
+}
+
+func (p *pdfPainter) PopClip() { p.out().WriteString("Q\n") }
+
+func (p *pdfPainter) BeginLayer() { p.layers = append(p.layers, &bytes.Buffer{}) }
+
+// EndLayer 把图层收成透明组 Form XObject，按图层变换画回上一层。
+// 伪 3D 先裁到四角投影出的四边形。PDF 没有滤镜，t.filters 被忽略。
+func (p *pdfPainter) EndLayer(t layerTransform) {
+	n := len(p.layers) - 1
+	content := p.layers[n].Bytes()
+	p.layers = p.layers[:n]
+	if t.opacity <= 0 || len(content) == 0 {
+		return
+	}
+	// BBox 取画布四周各扩一整屏：图层内容按画布坐标记录，变换后才可能移进画面。
+	form := p.stream(fmt.Sprintf("/Type /XObject /Subtype /Form /BBox [%s %s %s %s] /Group << /S /Transparency >> /Resources %d 0 R",
+		fmtNum(-p.w), fmtNum(-p.h), fmtNum(2*p.w), fmtNum(2*p.h), p.res), content)
+	name := fmt.Sprintf("Fm%d", form)
+	p.xobjs = append(p.xobjs, fmt.Sprintf("/%s %d 0 R", name, form))
+
+	b := p.out()
+	b.WriteString("q\n")
+	if t.is3D() {
+		d0, d1, d2, d3 := projCorners(t)
+		fmt.Fprintf(b, "%s %s m %s %s l %s %s l %s %s l h W n\n",
+			fmtNum(d0.X), fmtNum(d0.Y), fmtNum(d1.X), fmtNum(d1.Y), fmtNum(d3.X), fmtNum(d3.Y), fmtNum(d2.X), fmtNum(d2.Y))
+	}
+	if a := layerMatrix(t); !a.identity() {
+		fmt.Fprintf(b, "%s %s %s %s %s %s cm\n", fmtCoef(a.sx), fmtCoef(a.hy), fmtCoef(a.hx), fmtCoef(a.sy), fmtNum(a.ox), fmtNum(a.oy))
+	}
+	if gs := p.gs(opacityByte(t.opacity)); gs != "" {
+		fmt.Fprintf(b, "/%s gs ", gs)
+	}
+	fmt.Fprintf(b, "/%s Do Q\n", name)
+}
+
+// ---- 字体与文档结构 ----
+
+// fontObject 写出内置字体的子集：Type0 / CIDFontType2，Identity-H 编码（内容流里直接是
+// 字形号），附 ToUnicode 让复制、搜索得到原文。返回 Type0 字体的对象号。
+func (p *pdfPainter) fontObject() (int, error) {
+	sub, err := subsetTrueType(cjkFont, p.font.used)
+	if err != nil {
+		return 0, err
+	}
+	tables, err := sfntTables(cjkFont)
+	if err != nil {
+		return 0, err
+	}
+	head, hhea := tables["head"], tables["hhea"]
+	if len(head) < 44 || len(hhea) < 8 {
+		return 0, fmt.Errorf("ui: font lacks head/hhea")
+	}
+	em := func(b []byte) string {
+		return fmtNum(float32(int16(binary.BigEndian.Uint16(b))) * 1000 / p.font.upem)
+	}
+	// 子集名前缀：6 个大写字母，由子集内容决定（PDF 约定，区分同一字体的不同子集）
+	tag := make([]byte, 6)
+	for i, sum := 0, crc32.ChecksumIEEE(sub); i < 6; i, sum = i+1, sum/26 {
+		tag[i] = 'A' + byte(sum%26)
+	}
+	name := string(tag) + "+OPPOSans"
+
+	gids := make([]int, 0, len(p.font.used))
+	for gid := range p.font.used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+	var widths, cmap strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%s] ", gid, fmtNum(p.width(uint16(gid))))
+	}
+	var chars []string
+	for _, gid := range gids {
+		if s := p.font.used[uint16(gid)]; s != "" {
+			var hex strings.Builder
+			for _, u := range utf16.Encode([]rune(s)) {
+				fmt.Fprintf(&hex, "%04X", u)
+			}
+			chars = append(chars, fmt.Sprintf("<%04X> <%s>", gid, hex.String()))
+		}
+	}
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for len(chars) > 0 { // bfchar 每段最多 100 条
+		k := min(len(chars), 100)
+		fmt.Fprintf(&cmap, "%d beginbfchar\n%s\nendbfchar\n", k, strings.Join(chars[:k], "\n"))
+		chars = chars[k:]
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMapResource defineresource pop\nend\nend\n")
+
+	file := p.stream(fmt.Sprintf("/Length1 %d", len(sub)), sub)
+	desc := p.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%s %s %s %s] /ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		name, em(head[36:]), em(head[38:]), em(head[40:]), em(head[42:]), em(hhea[4:]), em(hhea[6:]), em(hhea[4:]), file))
+	cid := p.add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+		name, desc, widths.String()))
+	toUni := p.stream("", []byte(cmap.String()))
+	return p.add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cid, toUni)), nil
+}
+
+// bytes 收尾：字体、资源字典、页面树，然后写出对象与交叉引用表。
+func (p *pdfPainter) bytes() ([]byte, error) {
+	var res strings.Builder
+	res.WriteString("<< /ProcSet [/PDF /Text /ImageB /ImageC]")
+	if len(p.font.used) > 0 {
+		font, err := p.fontObject()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&res, " /Font << /F0 %d 0 R >>", font)
+	}
+	for _, d := range []struct {
+		key     string
+		entries []string
+	}{{"ExtGState", p.gstates}, {"Shading", p.shades}, {"XObject", p.xobjs}} {
+		if len(d.entries) > 0 {
+			fmt.Fprintf(&res, " /%s << %s >>", d.key, strings.Join(d.entries, " "))
+		}
+	}
+	res.WriteString(" >>")
+	p.set(p.res, res.String())
+
+	content := p.stream("", p.layers[0].Bytes())
+	pages := p.alloc()
+	page := p.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %d 0 R /Contents %d 0 R >>",
+		pages, fmtNum(p.w), fmtNum(p.h), p.res, content))
+	p.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%d 0 R] /Count 1 >>", page))
+	catalog := p.add(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(p.objs))
+	for i, body := range p.objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		b.Write(body)
+		b.WriteString("\nendobj\n")
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(p.objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(p.objs)+1, catalog, xref)
+	return b.Bytes(), nil
+}
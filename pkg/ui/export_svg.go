@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// ---- 矢量导出：SVG 后端 ----
+//
+// svgPainter 把 painter 原语写成 SVG 元素。裁剪与图层是嵌套的 <g>：PushClip 开一个带
+// clip-path 的 <g>、PopClip 关上；BeginLayer 开一块新缓冲，EndLayer 把它包进带
+// transform / opacity / filter 的 <g> 再并回上一层。渐变、裁剪路径、滤镜、字形都进 <defs>，
+// 用递增的 id 引用。
+
+type svgPainter struct {
+	w, h   int
+	font   *exportFont
+	defs   strings.Builder
+	layers []*strings.Builder // [0] 是文档主体，其上是未合回的图层
+	glyphs map[uint16]bool    // 已写进 defs 的字形
+	images map[bitmap]string  // 已编码的图片 data URI
+	nextID int
+}
+
+func newSVGPainter(w, h int, f *exportFont) *svgPainter {
+	return &svgPainter{
+		w: w, h: h, font: f,
+		layers: []*strings.Builder{{}},
+		glyphs: map[uint16]bool{},
+		images: map[bitmap]string{},
+	}
+}
+
+func (p *svgPainter) out() *strings.Builder { return p.layers[len(p.layers)-1] }
+
+func (p *svgPainter) id(prefix string) string {
+	p.nextID++
+	return fmt.Sprintf("%s%d", prefix, p.nextID)
+}
+
+func (p *svgPainter) bytes() []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", p.w, p.h, p.w, p.h)
+	if p.defs.Len() > 0 {
+		b.WriteString("<defs>\n" + p.defs.String() + "</defs>\n")
+	}
+	b.WriteString(p.layers[0].String())
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
+
+// paintAttr 写出 fill / stroke 的颜色与透明度属性。
+func paintAttr(attr string, c Color) string {
+	s := fmt.Sprintf(` %s="#%02x%02x%02x"`, attr, c.R, c.G, c.B)
+	if c.A < 255 {
+		s += fmt.Sprintf(` %s-opacity="%s"`, attr, fmtNum(float32(c.A)/255))
+	}
+	return s
+}
+
+func svgMatrix(a affine2D) string {
+	return fmt.Sprintf("matrix(%s %s %s %s %s %s)", fmtCoef(a.sx), fmtCoef(a.hy), fmtCoef(a.hx), fmtCoef(a.sy), fmtNum(a.ox), fmtNum(a.oy))
+}
+
+// rectAttrs 是 <rect> 的几何属性；圆角按 clampRadius 钳制，与屏幕一致。
+func rectAttrs(x, y, w, h, r float32) string {
+	s := fmt.Sprintf(`x="%s" y="%s" width="%s" height="%s"`, fmtNum(x), fmtNum(y), fmtNum(w), fmtNum(h))
+	if r = clampRadius(w, h, r); r > 0 {
+		s += fmt.Sprintf(` rx="%s"`, fmtNum(r))
+	}
+	return s
+}
+
+// svgPathSink 把路径写成 SVG 的 d，整体平移 (ox,oy)。
+type svgPathSink struct {
+	b      strings.Builder
+	ox, oy float32
+}
+
+func (s *svgPathSink) pt(x, y float32) string { return fmtNum(x+s.ox) + " " + fmtNum(y+s.oy) }
+func (s *svgPathSink) moveTo(x, y float32)    { s.b.WriteString("M" + s.pt(x, y)) }
+func (s *svgPathSink) lineTo(x, y float32)    { s.b.WriteString("L" + s.pt(x, y)) }
+func (s *svgPathSink) cubeTo(x1, y1, x2, y2, x, y float32) {
+	s.b.WriteString("C" + s.pt(x1, y1) + " " + s.pt(x2, y2) + " " + s.pt(x, y))
+}
+func (s *svgPathSink) quadTo(x1, y1, x, y float32) {
+	s.b.WriteString("Q" + s.pt(x1, y1) + " " + s.pt(x, y))
+}
+func (s *svgPathSink) closePath() { s.b.WriteString("Z") }
+
+// gradient 把渐变写进 defs，铺满 (x,y,w,h)，返回 fill / stroke 可用的 url(#id)。
+// 线性、径向是原生渐变（userSpaceOnUse，坐标与元素同一空间）；锥形栅格化成 pattern 贴图。
+func (p *svgPainter) gradient(g *Gradient, x, y, w, h float32) string {
+	id := p.id("grad")
+	switch g.Kind {
+	case GradientConic:
+		img := gradientRaster(g, w, h)
+		if img == nil {
+			return "none"
+		}
+		fmt.Fprintf(&p.defs, `<pattern id="%s" patternUnits="userSpaceOnUse" %s><image width="%s" height="%s" preserveAspectRatio="none" xlink:href="data:image/png;base64,%s"/></pattern>`+"\n",
+			id, rectAttrs(x, y, w, h, 0), fmtNum(w), fmtNum(h), base64.StdEncoding.EncodeToString(encodePNG(img)))
+		return "url(#" + id + ")"
+	case GradientRadial:
+		fmt.Fprintf(&p.defs, `<radialGradient id="%s" gradientUnits="userSpaceOnUse" cx="%s" cy="%s" r="%s">`,
+			id, fmtNum(x+g.CX*w), fmtNum(y+g.CY*h), fmtNum(g.radialRadius(w, h)))
+	default:
+		x0, y0, x1, y1 := g.linearSpan(w, h)
+		fmt.Fprintf(&p.defs, `<linearGradient id="%s" gradientUnits="userSpaceOnUse" x1="%s" y1="%s" x2="%s" y2="%s">`,
+			id, fmtNum(x+x0), fmtNum(y+y0), fmtNum(x+x1), fmtNum(y+y1))
+	}
+	for _, s := range g.Stops {
+		fmt.Fprintf(&p.defs, `<stop offset="%s" stop-color="#%02x%02x%02x" stop-opacity="%s"/>`,
+			fmtNum(s.Offset), s.Color.R, s.Color.G, s.Color.B, fmtNum(float32(s.Color.A)/255))
+	}
+	if g.Kind == GradientRadial {
+		p.defs.WriteString("</radialGradient>\n")
+	} else {
+		p.defs.WriteString("</linearGradient>\n")
+	}
+	return "url(#" + id + ")"
+}
+
+func (p *svgPainter) FillRect(x, y, w, h, r float32, c Color) {
+	if c.A == 0 || w <= 0 || h <= 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "<rect %s%s/>\n", rectAttrs(x, y, w, h, r), paintAttr("fill", c))
+}
+
+func (p *svgPainter) FillGradient(x, y, w, h, r float32, g *Gradient) {
+	if w <= 0 || h <= 0 || len(g.Stops) == 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "<rect %s fill=\"%s\"/>\n", rectAttrs(x, y, w, h, r), p.gradient(g, x, y, w, h))
+}
+
+func (p *svgPainter) StrokeRect(x, y, w, h, r, width float32, c Color) {
+	if c.A == 0 || width <= 0 || w <= 0 || h <= 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "<rect %s fill=\"none\"%s stroke-width=\"%s\"/>\n", rectAttrs(x, y, w, h, r), paintAttr("stroke", c), fmtNum(width))
+}
+
+func (p *svgPainter) StrokeGradient(x, y, w, h, r, width float32, g *Gradient) {
+	if width <= 0 || w <= 0 || h <= 0 || len(g.Stops) == 0 {
+		return
+	}
+	hw := width / 2 // 与屏幕一致：渐变铺满向外扩半个线宽的盒
+	fmt.Fprintf(p.out(), "<rect %s fill=\"none\" stroke=\"%s\" stroke-width=\"%s\"/>\n",
+		rectAttrs(x, y, w, h, r), p.gradient(g, x-hw, y-hw, w+width, h+width), fmtNum(width))
+}
+
+// Shadow 用高斯模糊：blur 按 CSS box-shadow 的模糊半径理解，标准差取其一半。
+func (p *svgPainter) Shadow(x, y, w, h, r, offX, offY, blur, spread float32, c Color) {
+	if c.A == 0 || w <= 0 || h <= 0 {
+		return
+	}
+	bx, by := x+offX-spread, y+offY-spread
+	bw, bh := w+spread*2, h+spread*2
+	br := max(r+spread, 0)
+	if blur <= 0 {
+		p.FillRect(bx, by, bw, bh, br, c)
+		return
+	}
+	id := p.id("shadow")
+	m := blur * 1.5
+	fmt.Fprintf(&p.defs, `<filter id="%s" filterUnits="userSpaceOnUse" %s><feGaussianBlur stdDeviation="%s"/></filter>`+"\n",
+		id, rectAttrs(bx-m, by-m, bw+2*m, bh+2*m, 0), fmtNum(blur/2))
+	fmt.Fprintf(p.out(), "<rect %s%s filter=\"url(#%s)\"/>\n", rectAttrs(bx, by, bw, bh, br), paintAttr("fill", c), id)
+}
+
+func (p *svgPainter) Line(x0, y0, x1, y1 float32, c Color) {
+	if c.A == 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\"%s stroke-width=\"1\"/>\n",
+		fmtNum(x0), fmtNum(y0), fmtNum(x1), fmtNum(y1), paintAttr("stroke", c))
+}
+
+// glyphUses 把一行字形写成 <use>（字形轮廓进 defs，每个只写一次）。
+// 每个 <use> 自带完整的 transform：基线平移、合成斜体的剪切、字体单位到像素的缩放 ——
+// 这样同一串 <use> 既能放进 <g> 也能放进 <clipPath>（后者不允许 <g>）。
+func (p *svgPainter) glyphUses(run shapedRun, x, y float32, fauxItalic bool) string {
+	k := run.px / p.font.upem
+	tr := fmt.Sprintf("translate(%s %s)", fmtNum(x), fmtNum(y+run.ascent))
+	if fauxItalic {
+		tr += fmt.Sprintf(" skewX(%s)", fmtNum(-fauxItalicShear*180/math.Pi))
+	}
+	tr += fmt.Sprintf(" scale(%s)", fmtCoef(k))
+	var b strings.Builder
+	for _, g := range run.glyphs {
+		d := p.font.outline(g.gid)
+		if d == "" {
+			continue
+		}
+		if !p.glyphs[g.gid] {
+			p.glyphs[g.gid] = true
+			fmt.Fprintf(&p.defs, `<path id="glyph%d" d="%s"/>`+"\n", g.gid, d)
+		}
+		fmt.Fprintf(&b, `<use xlink:href="#glyph%d" transform="%s" x="%s" y="%s"/>`, g.gid, tr, fmtNum(g.x/k), fmtNum(g.y/k))
+	}
+	return b.String()
+}
+
+// DrawText 写一组字形；aria-label 保留原文，便于检索与无障碍。
+func (p *svgPainter) DrawText(s string, face fontFace, c Color, x, y float32, fauxBold, fauxItalic bool) {
+	if s == "" || c.A == 0 {
+		return
+	}
+	run := exportShape(face, s)
+	p.font.use(run)
+	attrs := paintAttr("fill", c)
+	if fauxBold {
+		attrs += paintAttr("stroke", c) + fmt.Sprintf(` stroke-width="%s"`, fmtNum(fauxBoldWidth(run.px)*p.font.upem/run.px))
+	}
+	fmt.Fprintf(p.out(), "<g aria-label=\"%s\"%s>%s</g>\n", html.EscapeString(s), attrs, p.glyphUses(run, x, y, fauxItalic))
+}
+
+// DrawTextGradient 把字形做成裁剪，再在裁剪里铺满渐变的 box。
+func (p *svgPainter) DrawTextGradient(s string, face fontFace, g *Gradient, box Rect, x, y float32, fauxBold, fauxItalic bool) {
+	if s == "" || len(g.Stops) == 0 {
+		return
+	}
+	run := exportShape(face, s)
+	p.font.use(run)
+	id := p.id("text")
+	fmt.Fprintf(&p.defs, "<clipPath id=\"%s\">%s</clipPath>\n", id, p.glyphUses(run, x, y, fauxItalic))
+	fmt.Fprintf(p.out(), "<rect aria-label=\"%s\" %s fill=\"%s\" clip-path=\"url(#%s)\"/>\n",
+		html.EscapeString(s), rectAttrs(box.X, box.Y, box.W, box.H, 0), p.gradient(g, box.X, box.Y, box.W, box.H), id)
+}
+
+// DrawImage 以 PNG data URI 内嵌图片；同一位图只编码一次。
+func (p *svgPainter) DrawImage(img bitmap, d Rect, opacity float32) {
+	if opacity <= 0 || d.W <= 0 || d.H <= 0 {
+		return
+	}
+	uri, ok := p.images[img]
+	if !ok {
+		uri = "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodePNG(exportImage(img)))
+		p.images[img] = uri
+	}
+	op := ""
+	if opacity < 1 {
+		op = fmt.Sprintf(` opacity="%s"`, fmtNum(opacity))
+	}
+	fmt.Fprintf(p.out(), "<image %s preserveAspectRatio=\"none\"%s xlink:href=\"%s\"/>\n", rectAttrs(d.X, d.Y, d.W, d.H, 0), op, uri)
+}
+
+func (p *svgPainter) pathData(path vecPath, x, y float32) string {
+	d, scale := exportPath(path)
+	sink := &svgPathSink{ox: x, oy: y}
+	parseSVGInto(d, scale, sink)
+	return sink.b.String()
+}
+
+func (p *svgPainter) FillPath(path vecPath, x, y float32, c Color) {
+	if c.A == 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "<path d=\"%s\"%s/>\n", p.pathData(path, x, y), paintAttr("fill", c))
+}
+
+func (p *svgPainter) StrokePath(path vecPath, x, y, width float32, c Color) {
+	if c.A == 0 || width <= 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "<path d=\"%s\" fill=\"none\"%s stroke-width=\"%s\" stroke-linecap=\"round\" stroke-linejoin=\"round\"/>\n",
+		p.pathData(path, x, y), paintAttr("stroke", c), fmtNum(width))
+}
+
+func (p *svgPainter) PushClip(r Rect, radius float32) {
+	id := p.id("clip")
+	fmt.Fprintf(&p.defs, "<clipPath id=\"%s\"><rect %s/></clipPath>\n", id, rectAttrs(r.X, r.Y, r.W, r.H, radius))
+	fmt.Fprintf(p.out(), "<g clip-path=\"url(#%s)\">\n", id)
+}
+
+func (p *svgPainter) PopClip() { p.out().WriteString("</g>\n") }
+
+func (p *svgPainter) BeginLayer() { p.layers = append(p.layers, &strings.Builder{}) }
+
+// EndLayer 把图层包成 <g>：外层施加变换与整组透明度，内层施加滤镜 ——
+// 滤镜先于变换（同 CSS 与屏幕后端），且滤镜区域因此落在未变换的画布坐标里。
+// 伪 3D 再在最外面裁到四角投影出的四边形。
+func (p *svgPainter) EndLayer(t layerTransform) {
+	n := len(p.layers) - 1
+	content := p.layers[n].String()
+	p.layers = p.layers[:n]
+	if t.opacity <= 0 || content == "" {
+		return
+	}
+	if len(t.filters) > 0 {
+		content = fmt.Sprintf("<g filter=\"url(#%s)\">\n%s</g>\n", p.filter(t.filters), content)
+	}
+	var attrs string
+	if a := layerMatrix(t); !a.identity() {
+		attrs += fmt.Sprintf(` transform="%s"`, svgMatrix(a))
+	}
+	if t.opacity < 1 {
+		attrs += fmt.Sprintf(` opacity="%s"`, fmtNum(t.opacity))
+	}
+	if attrs != "" {
+		content = fmt.Sprintf("<g%s>\n%s</g>\n", attrs, content)
+	}
+	if t.is3D() {
+		d0, d1, d2, d3 := projCorners(t)
+		id := p.id("quad")
+		fmt.Fprintf(&p.defs, "<clipPath id=\"%s\"><path d=\"M%s %sL%s %sL%s %sL%s %sZ\"/></clipPath>\n", id,
+			fmtNum(d0.X), fmtNum(d0.Y), fmtNum(d1.X), fmtNum(d1.Y), fmtNum(d3.X), fmtNum(d3.Y), fmtNum(d2.X), fmtNum(d2.Y))
+		content = fmt.Sprintf("<g clip-path=\"url(#%s)\">\n%s</g>\n", id, content)
+	}
+	p.out().WriteString(content)
+}
+
+// filter 把滤镜链写成 SVG 滤镜原语（按顺序串接），返回其 id。
+// 颜色运算在 sRGB 里做，与 CPU 实现（applyFilters）一致；区域放宽到画布外一整屏，模糊不被截断。
+func (p *svgPainter) filter(fs []filterOp) string {
+	id := p.id("filter")
+	w, h := float32(p.w), float32(p.h)
+	fmt.Fprintf(&p.defs, `<filter id="%s" filterUnits="userSpaceOnUse" %s color-interpolation-filters="sRGB">`, id, rectAttrs(-w, -h, 3*w, 3*h, 0))
+	for _, f := range fs {
+		switch f.kind {
+		case filterBlur:
+			fmt.Fprintf(&p.defs, `<feGaussianBlur stdDeviation="%s"/>`, fmtNum(f.v))
+		case filterGrayscale:
+			fmt.Fprintf(&p.defs, `<feColorMatrix type="saturate" values="%s"/>`, fmtNum(1-min(max(f.v, 0), 1)))
+		case filterSaturate:
+			fmt.Fprintf(&p.defs, `<feColorMatrix type="saturate" values="%s"/>`, fmtNum(f.v))
+		case filterBrightness:
+			v := fmtNum(f.v)
+			fmt.Fprintf(&p.defs, `<feComponentTransfer><feFuncR type="linear" slope="%s"/><feFuncG type="linear" slope="%s"/><feFuncB type="linear" slope="%s"/></feComponentTransfer>`, v, v, v)
+		}
+	}
+	p.defs.WriteString("</filter>\n")
+	return id
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+// dashboard 覆盖导出要处理的每一类原语：圆角填充、边框、阴影、多色标与锥形渐变、渐变描边、
+// 文字（含粗体 / 斜体 / 渐变字）、图标路径、图片、裁剪，以及带变换、透明度、滤镜与伪 3D 的图层。
+func dashboard() *Node {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	img.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+	rainbow := NewLinearGradient(90, Stop(0, Hex("#6366f1")), Stop(0.5, Hex("#ec4899")), Stop(1, Hex("#f59e0b")))
+	return Div(Style(Column, Gap(8), Padding(10)),
+		Div(Style(Width(200), Height(40), Bg(Hex("#ffffff")), Radius(8), Border(1, Hex("#e5e7eb")), Shadow(Hex("#00000040"), 0, 2, 8, 0))),
+		Div(Style(Width(200), Height(30), BgGradient(rainbow), BorderGradient(rainbow), Border(2, Black))),
+		Div(Style(Width(60), Height(60), BgGradient(NewConicGradient(0, Stop(0, Hex("#ff0000")), Stop(1, Hex("#0000ff")))))),
+		Text("Revenue", Bold, TextColor(Hex("#111827"))),
+		Text("slanted", Italic),
+		Text("Tenon", TextGradient(rainbow)),
+		IconFill("M0 0L10 0L10 10Z", 10, TextColor(Hex("#22c55e"))),
+		Img(SrcImage("mem:export", img), Style(Width(8), Height(8))),
+		Div(Style(Width(80), Height(20), Clip, Radius(4)), Div(Style(Width(200), Height(20), Bg(Hex("#0ea5e9"))))),
+		Div(Style(Width(40), Height(40), Bg(Hex("#f97316")), Rotate(15), Opacity(0.5), Grayscale(1))),
+		Div(Style(Width(40), Height(40), Bg(Hex("#a855f7")), RotateY(30), Perspective(400))),
+	)
+}
+
+// SVG：结构良好，各原语都落成对应的元素；文字是内嵌字形、原文留在 aria-label 里。
+func TestRenderSVG(t *testing.T) {
+	out, err := RenderSVG(dashboard(), 400, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := xml.NewDecoder(bytes.NewReader(out))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG 不是合法的 XML：%v", err)
+		}
+	}
+	s := string(out)
+	for _, want := range []string{
+		`width="400" height="500"`,
+		`<rect x="10" y="10" width="200" height="40" rx="8" fill="#ffffff"/>`,
+		`<feGaussianBlur stdDeviation="4"/>`, // 阴影
+		`<linearGradient`, `stop-color="#ec4899"`,
+		`stroke="url(#grad`,                            // 渐变边框
+		`<pattern`,                                     // 锥形渐变栅格化
+		`aria-label="Revenue"`, `aria-label="slanted"`, // 文字
+		`aria-label="Tenon"`, // 渐变字：字形裁剪 + 渐变
+		`skewX(-12.032)`,     // 合成斜体
+		`<path id="glyph`, `<use xlink:href="#glyph`,
+		`fill="#22c55e"`,                    // 图标
+		`xlink:href="data:image/png;base64`, // 图片
+		`<clipPath`, `clip-path="url(#clip`,
+		`opacity="0.5"`, `<feColorMatrix type="saturate" values="0"/>`, // 图层透明度与滤镜
+		`clip-path="url(#quad`, // 伪 3D 的投影四边形
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("SVG 里缺少 %s", want)
+		}
+	}
+}
+
+// PDF：交叉引用表的偏移都指向对象；字体是可解析的 TrueType 子集，ToUnicode 能还原出文字。
+func TestRenderPDF(t *testing.T) {
+	out, err := RenderPDF(dashboard(), 400, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("缺少 PDF 文件头或文件尾")
+	}
+	objs := pdfObjects(t, out)
+
+	var font, toUni []byte
+	for _, o := range objs {
+		switch {
+		case bytes.Contains(o, []byte("/Length1")):
+			font = pdfStream(t, o)
+		case bytes.Contains(o, []byte("/Subtype /Type0")):
+			m := regexp.MustCompile(`/ToUnicode (\d+) 0 R`).FindSubmatch(o)
+			if m == nil {
+				t.Fatal("Type0 字体缺少 ToUnicode")
+			}
+			n, _ := strconv.Atoi(string(m[1]))
+			toUni = pdfStream(t, objs[n])
+		}
+	}
+	sf, err := sfnt.Parse(font)
+	if err != nil {
+		t.Fatalf("嵌入的字体子集解析失败：%v", err)
+	}
+	full, _ := sfnt.Parse(cjkFont)
+	if sf.NumGlyphs() != full.NumGlyphs() || len(font) >= len(cjkFont)/2 {
+		t.Fatalf("子集应保留字形号、去掉字形数据：%d 字形 / %d 字节（原 %d 字节）", sf.NumGlyphs(), len(font), len(cjkFont))
+	}
+	var buf sfnt.Buffer
+	gid, _ := full.GlyphIndex(&buf, 'R')
+	if segs, err := sf.LoadGlyph(&buf, gid, 1000*64, nil); err != nil || len(segs) == 0 {
+		t.Fatalf("用到的字形 R 应在子集里：%v", err)
+	}
+	unused, _ := full.GlyphIndex(&buf, 'Q')
+	if segs, _ := sf.LoadGlyph(&buf, unused, 1000*64, nil); len(segs) != 0 {
+		t.Fatal("没用到的字形 Q 应被清空")
+	}
+	if !bytes.Contains(toUni, []byte(fmt.Sprintf("<%04X> <0052>", gid))) {
+		t.Fatalf("ToUnicode 应把 R 的字形映射回 U+0052：\n%s", toUni)
+	}
+
+	all := string(out)
+	for _, want := range []string{"/ShadingType 2", "/FunctionType 3", "/Subtype /Form", "/S /Transparency", "/SMask", "/CIDToGIDMap /Identity"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("PDF 里缺少 %s", want)
+		}
+	}
+}
+
+// pdfObjects 按交叉引用表取出各对象（下标即对象号），并校验每个偏移都指向 "n 0 obj"。
+func pdfObjects(t *testing.T, pdf []byte) map[int][]byte {
+	t.Helper()
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("缺少 startxref")
+	}
+	at, _ := strconv.Atoi(string(m[1]))
+	lines := strings.Split(string(pdf[at:]), "\n")
+	if lines[0] != "xref" {
+		t.Fatalf("startxref 没指向 xref：%q", lines[0])
+	}
+	var count int
+	fmt.Sscanf(lines[1], "0 %d", &count)
+	objs := map[int][]byte{}
+	for n := 1; n < count; n++ {
+		off, _ := strconv.Atoi(lines[2+n][:10])
+		head := fmt.Sprintf("%d 0 obj\n", n)
+		if !bytes.HasPrefix(pdf[off:], []byte(head)) {
+			t.Fatalf("对象 %d 的偏移 %d 不对", n, off)
+		}
+		body := pdf[off+len(head):]
+		objs[n] = body[:bytes.Index(body, []byte("\nendobj\n"))]
+	}
+	return objs
+}
+
+// pdfStream 解压一个 Flate 流对象的数据。
+func pdfStream(t *testing.T, obj []byte) []byte {
+	t.Helper()
+	i := bytes.Index(obj, []byte("\nstream\n"))
+	j := bytes.LastIndex(obj, []byte("\nendstream"))
+	if i < 0 || j < i {
+		t.Fatalf("不是流对象：%.80s", obj)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(obj[i+8 : j]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
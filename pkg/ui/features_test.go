@@ -23,7 +23,7 @@ func TestMultilineSpans(t *testing.T) {
 		t.Fatalf("span1=%+v want {cd 3 5}", spans[1])
 	}
 	// 行内偏移映射：行首 x 应回到该行起始字节
-	if off := spans[1].offsetInSpan(0, face, 16*1.3); off != 3 {
+	if off := spans[1].offsetInSpan(0, DirAuto, face, 16*1.3, 0); off != 3 {
 		t.Fatalf("offsetInSpan(0)=%d want 3", off)
 	}
 }
@@ -89,7 +89,7 @@ func TestRichTextLayoutBaseline(t *testing.T) {
 		{text: "B", style: styleWith(FontSize(32))},
 	}
 	(&renderNode{runs: runs}).resolveRuns(inhText{})
-	lines, maxW, h := layoutRuns(runs, 0)
+	lines, maxW, h := layoutRuns(runs, 0, DirAuto)
 	if len(lines) != 1 {
 		t.Fatalf("lines=%d want 1", len(lines))
 	}
@@ -1244,6 +1244,21 @@ func TestTransformHitTest(t *testing.T) {
 	}
 }
 
+// ScaleXY 不等比缩放：命中范围在 X、Y 方向各自按比例伸缩。
+func TestTransformHitTestScaleXY(t *testing.T) {
+	g := newGame()
+	// 100x100 的盒子横向放大 2 倍、纵向缩到一半 -> 视觉为 [-50,150]x[25,75]。
+	g.rootFiber = reconcile(nil, nil, Div(Style(Width(100), Height(100), ScaleXY(2, 0.5))))
+	layoutAll(g)
+	rn := g.rootRN
+	if hitNode(rn, 140, 50) != rn {
+		t.Fatal("(140,50) is inside the stretched visual, should hit")
+	}
+	if hitNode(rn, 50, 10) != nil {
+		t.Fatal("(50,10) is above the squashed visual, should miss")
+	}
+}
+
 // ---- 拖拽回调接线 ----
 
 func TestDragWiring(t *testing.T) {
@@ -1396,3 +1411,17 @@ func TestKeyedListReuse(t *testing.T) {
 	}
 	_ = yoga.DirectionLTR
 }
+
+// With 追加属性不改原节点；非 host 节点包一层 Div。
+func TestWithAttrs(t *testing.T) {
+	base := Div(Style(Width(10), Height(10)))
+	wide := With(base, Style(Width(30)))
+	h := Mount(Div(Style(ItemsStart), base, wide, With(Text("t"), Id("wrap"))), 100, 100)
+	kids := h.Root().Children()
+	if kids[0].Bounds().W != 10 || kids[1].Bounds().W != 30 {
+		t.Fatalf("With 应只影响副本：%v %v", kids[0].Bounds(), kids[1].Bounds())
+	}
+	if kids[2].Kind() != "box" || kids[2].Child(0).Text() != "t" {
+		t.Fatalf("文本节点应被 Div 包住：%s", kids[2].Kind())
+	}
+}
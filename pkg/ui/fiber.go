@@ -10,6 +10,7 @@ type Fiber struct {
 
 	parent   *Fiber
 	children []*Fiber
+	g        *game // 所属窗口的驱动实例（多窗口时 setter 据此排队，而不是排进 activeGame）
 
 	// component
 	fnPtr      uintptr
@@ -40,6 +41,28 @@ type Fiber struct {
 	// 错误边界（ErrorBoundary）：捕获后代 render 期的 panic
 	errBoundary bool
 	caughtErr   any
+
+	// AnimatePresence：presence 标记容器组件；exiting 标记已被移除、正在播退场动画的直接子节点
+	presence bool
+	exiting  bool
+
+	// Suspense：子树中正在挂起的 UseAsync（非 nil 即表示本 fiber 是 Suspense）
+	suspended map[*asyncHook]struct{}
+}
+
+// gameOf 返回 f 所属窗口的驱动实例；f 为 nil 或挂载早于归属记录时退回 activeGame。
+func gameOf(f *Fiber) *game {
+	if f != nil && f.g != nil {
+		return f.g
+	}
+	return activeGame
+}
+
+// markFiberDirty 把 f 排进它自己所属窗口的重渲染队列。
+func markFiberDirty(f *Fiber) {
+	if g := gameOf(f); g != nil {
+		g.markDirty(f)
+	}
 }
 
 // boundaryCount 是活动 ErrorBoundary 数量；为 0 时 renderComponent 走无 defer 的快路径。
@@ -91,7 +114,10 @@ func reconcile(parent, old *Fiber, n *Node) *Fiber {
 }
 
 func mountFiber(parent *Fiber, n *Node) *Fiber {
-	f := &Fiber{typ: n.typ, key: n.key, parent: parent}
+	f := &Fiber{typ: n.typ, key: n.key, parent: parent, g: activeGame}
+	if parent != nil {
+		f.g = parent.g
+	}
 	switch n.typ {
 	case typeComponent:
 		f.fnPtr, f.props, f.render, f.propsEqual, f.memo = n.fnPtr, n.props, n.render, n.propsEqual, n.memo
@@ -149,12 +175,15 @@ func updateFiber(f *Fiber, n *Node) {
 			subs := f.subscribers
 			f.subscribers = nil
 			for s := range subs {
-				if !s.unmounted && activeGame != nil {
-					activeGame.markDirty(s)
+				if !s.unmounted {
+					markFiberDirty(s)
 				}
 			}
 		}
 		f.children = reconcileList(f, f.children, n.kids)
+		if changed && f.ctxID == styleSheetCtx.id {
+			restyleTree(f, false) // memo 短路的子树也要按新表重算
+		}
 	case typeFragment, typePortal:
 		f.portalTrap = n.trap
 		f.children = reconcileList(f, f.children, n.kids)
@@ -180,9 +209,7 @@ func renderComponent(f *Fiber) {
 					panic(r) // 无边界可兜 -> 继续抛出
 				}
 				b.caughtErr = r
-				if activeGame != nil {
-					activeGame.markDirty(b)
-				}
+				markFiberDirty(b)
 			}
 		}()
 	}
@@ -208,10 +235,16 @@ func unmount(f *Fiber) {
 		delete(p.subscribers, f)
 	}
 	f.providerSubs = nil
-	if g := activeGame; g != nil {
+	if g := gameOf(f); g != nil {
 		if g.focusedFiber == f {
 			g.focusedFiber = nil
 		}
+		// 注销媒体查询订阅，免得注册表随挂载/卸载无限增长。
+		for _, h := range f.hooks {
+			if m, ok := h.(*mediaHook); ok {
+				delete(g.media, m)
+			}
+		}
 		// 清理指向本节点的悬空交互指针，防止在已卸载节点上回调 onPress/onDrag/onHover。
 		if rn := f.rnode; rn != nil {
 			if g.pressedNode == rn {
@@ -221,6 +254,10 @@ func unmount(f *Fiber) {
 				g.dragging = nil
 			}
 			delete(g.hovered, rn)
+			delete(g.responsive, rn)
+			if g.dnd != nil && g.dnd.over == rn {
+				g.dnd.over = nil
+			}
 		}
 	}
 	for _, h := range f.hooks {
@@ -271,6 +308,9 @@ func reconcileList(parent *Fiber, old []*Fiber, nodes []*Node) []*Fiber {
 		}
 
 		if match != nil {
+			if match.exiting {
+				setExiting(match, false) // 退场途中被加回：原 fiber 复活
+			}
 			updateFiber(match, nd)
 			out = append(out, match)
 		} else {
@@ -281,6 +321,15 @@ func reconcileList(parent *Fiber, old []*Fiber, nodes []*Node) []*Fiber {
 	for _, c := range unkeyed[min(uidx, len(unkeyed)):] {
 		unmount(c)
 	}
+	if presenceList(parent) && len(keyed) > 0 {
+		removed := make([]*Fiber, 0, len(keyed))
+		for _, c := range old {
+			if c.key != "" && keyed[c.key] == c {
+				removed = append(removed, c)
+			}
+		}
+		return keepExiting(old, out, removed)
+	}
 	for _, c := range keyed {
 		unmount(c)
 	}
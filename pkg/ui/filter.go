@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// ---- 滤镜：Blur / Grayscale / Brightness / Saturate 与 BackdropBlur ----
+//
+// 与 CSS 的 filter / backdrop-filter 对应：
+//
+//	ui.Div(ui.Style(ui.Blur(4), ui.Grayscale(1)), ...)        // 元素及其子树先模糊再去色
+//	ui.Div(ui.Style(ui.BackdropBlur(12), ui.Bg(glass)), ...)  // 毛玻璃：模糊元素背后已画出的内容
+//
+// filter 走图层：有滤镜的元素像有变换一样整棵子树画进 BeginLayer/EndLayer，滤镜按书写顺序
+// 随 layerTransform 交给后端合成。gio 后端能原生合成的只有模糊（多次错位叠画取平均）；
+// 去色/亮度/饱和度改走离屏：把图层渲染成像素，在 CPU 上逐像素处理（applyFilters）再贴回。
+// CPU 这套实现与后端无关，Screenshot 与黄金测试据此校验滤镜结果；录制后端把滤镜记在
+// unlayer 指令的 Filter 上（CSS 写法，如 "blur(4) grayscale(1)"）。
+//
+// backdrop-filter 不需要后端读回帧缓冲：背景就是按绘制顺序排在本元素之前的那些节点，
+// 于是在元素自身之前把它们裁到元素的圆角矩形里再画一遍、整层模糊即可（paintBackdrop）。
+// 代价是被盖住的那部分场景多画一次；重画期间不再嵌套 BackdropBlur。
+
+type filterKind uint8
+
+const (
+	filterBlur filterKind = iota
+	filterGrayscale
+	filterBrightness
+	filterSaturate
+)
+
+// filterOp 是一个滤镜函数：模糊半径（物理像素）或颜色矩阵的参数。
+type filterOp struct {
+	kind filterKind
+	v    float32
+}
+
+func (f filterOp) String() string {
+	name := [...]string{"blur", "grayscale", "brightness", "saturate"}[f.kind]
+	return fmt.Sprintf("%s(%g)", name, f.v)
+}
+
+// filterString 把滤镜链写成 CSS 形式，空链为 ""。
+func filterString(fs []filterOp) string {
+	parts := make([]string, len(fs))
+	for i, f := range fs {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+// Blur 高斯模糊元素及其子树，radius 为标准差（逻辑像素，同 CSS blur()）。
+func Blur(radius float32) StyleOpt {
+	return func(s *StyleProps) { s.filters = append(s.filters, filterOp{filterBlur, radius}) }
+}
+
+// Grayscale 去色：amount 0 为原色，1 为完全灰度。
+func Grayscale(amount float32) StyleOpt {
+	return func(s *StyleProps) { s.filters = append(s.filters, filterOp{filterGrayscale, amount}) }
+}
+
+// Brightness 调整亮度：1 为原样，0 全黑，大于 1 变亮。
+func Brightness(f float32) StyleOpt {
+	return func(s *StyleProps) { s.filters = append(s.filters, filterOp{filterBrightness, f}) }
+}
+
+// Saturate 调整饱和度：1 为原样，0 为灰度，大于 1 更鲜艳。
+func Saturate(f float32) StyleOpt {
+	return func(s *StyleProps) { s.filters = append(s.filters, filterOp{filterSaturate, f}) }
+}
+
+// BackdropBlur 模糊元素背后（按绘制顺序在它之前）的内容，裁到元素的圆角矩形内。
+// 配合半透明 Bg 即毛玻璃效果。
+func BackdropBlur(radius float32) StyleOpt {
+	return func(s *StyleProps) { s.backdropBlur = radius }
+}
+
+// scaledFilters 把模糊半径换算到物理像素。
+func scaledFilters(fs []filterOp, k float32) []filterOp {
+	if len(fs) == 0 {
+		return nil
+	}
+	out := make([]filterOp, len(fs))
+	for i, f := range fs {
+		if f.kind == filterBlur {
+			f.v *= k
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// onlyBlur 报告滤镜链是否只有模糊（gio 后端可以原生合成，不必离屏）。
+func onlyBlur(fs []filterOp) bool {
+	for _, f := range fs {
+		if f.kind != filterBlur {
+			return false
+		}
+	}
+	return true
+}
+
+// filterMargin 返回滤镜让内容向外扩散的距离：模糊约 3σ 之外可忽略。
+func filterMargin(fs []filterOp) float32 {
+	var m float32
+	for _, f := range fs {
+		if f.kind == filterBlur {
+			m += 3 * f.v
+		}
+	}
+	return m
+}
+
+// ---- 背景模糊 ----
+
+// backdropStop 非 nil 时处于背景重画：画到该节点就停下，之后的节点一概跳过。
+var (
+	backdropStop *renderNode
+	backdropDone bool
+)
+
+// paintBackdrop 在 rn 自身之前画出它的背景：把本帧排在 rn 之前的内容裁到 rn 的圆角矩形里
+// 重画一遍，整层模糊。
+func paintBackdrop(p painter, rn *renderNode) {
+	g := gameOf(rn.owner)
+	if g == nil || backdropStop != nil {
+		return
+	}
+	b := rn.bounds
+	p.PushClip(b, rn.radius)
+	p.BeginLayer()
+	backdropStop, backdropDone = rn, false
+	for _, r := range g.paintRoots() {
+		paint(p, r)
+		if backdropDone {
+			break
+		}
+	}
+	backdropStop, backdropDone = nil, false
+	p.EndLayer(layerTransform{
+		cx: b.X + b.W/2, cy: b.Y + b.H/2, w: b.W, h: b.H,
+		scaleX: 1, scaleY: 1, opacity: 1,
+		filters: []filterOp{{filterBlur, rn.backdropBlur}},
+	})
+	p.PopClip()
+}
+
+// paintRoots 返回一帧的绘制根：主树，然后是各 Portal 浮层（后画的在上面）。
+func (g *game) paintRoots() []*renderNode {
+	var out []*renderNode
+	if g.rootRN != nil {
+		out = append(out, g.rootRN)
+	}
+	for _, pf := range g.portals {
+		if pf.overlayRoot != nil {
+			out = append(out, pf.overlayRoot)
+		}
+	}
+	return out
+}
+
+// ---- CPU 实现 ----
+
+// applyFilters 按顺序把滤镜链作用到 img（预乘 alpha 的 RGBA，就地修改并返回）。
+// 颜色矩阵只作用于 RGB，与预乘可交换；模糊在预乘空间里做，半透明边缘不会发灰。
+func applyFilters(img *image.RGBA, fs []filterOp) *image.RGBA {
+	for _, f := range fs {
+		switch f.kind {
+		case filterBlur:
+			blurRGBA(img, f.v)
+		case filterGrayscale:
+			colorMatrix(img, saturateMatrix(1-clampf(f.v, 0, 1)))
+		case filterSaturate:
+			colorMatrix(img, saturateMatrix(max(f.v, 0)))
+		case filterBrightness:
+			v := max(f.v, 0)
+			colorMatrix(img, [9]float32{v, 0, 0, 0, v, 0, 0, 0, v})
+		}
+	}
+	return img
+}
+
+// saturateMatrix 是 CSS Filter Effects 规范里 saturate(s) 的颜色矩阵；grayscale(a) = saturate(1-a)。
+func saturateMatrix(s float32) [9]float32 {
+	return [9]float32{
+		0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s,
+		0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s,
+		0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s,
+	}
+}
+
+// colorMatrix 把 3×3 矩阵作用到每个像素的 RGB，结果钳到 [0, A]（预乘下颜色不能超过 alpha）。
+func colorMatrix(img *image.RGBA, m [9]float32) {
+	px := img.Pix
+	for i := 0; i+3 < len(px); i += 4 {
+		a := float32(px[i+3])
+		if a == 0 {
+			continue
+		}
+		r, g, b := float32(px[i]), float32(px[i+1]), float32(px[i+2])
+		px[i] = uint8(clampf(m[0]*r+m[1]*g+m[2]*b, 0, a) + 0.5)
+		px[i+1] = uint8(clampf(m[3]*r+m[4]*g+m[5]*b, 0, a) + 0.5)
+		px[i+2] = uint8(clampf(m[6]*r+m[7]*g+m[8]*b, 0, a) + 0.5)
+	}
+}
+
+// blurRGBA 用三遍盒式模糊近似标准差为 sigma 的高斯模糊（W3C 推荐的做法）。
+// 图像之外视为透明，所以内容会向边缘淡出。
+func blurRGBA(img *image.RGBA, sigma float32) {
+	if sigma <= 0 {
+		return
+	}
+	// 三遍盒宽 d 近似高斯：d = floor(sigma * 3*sqrt(2π)/4 + 0.5)
+	d := int(math.Floor(float64(sigma)*3*math.Sqrt(2*math.Pi)/4 + 0.5))
+	if d < 1 {
+		return
+	}
+	// d 为偶数时盒子无法以像素为中心：按规范前两遍分别偏左、偏右半个像素，第三遍用 d+1 居中
+	r := d / 2
+	wins := [3][2]int{{-r, r}, {-r, r}, {-r, r}}
+	if d%2 == 0 {
+		wins = [3][2]int{{-r, r - 1}, {-r + 1, r}, {-r, r}}
+	}
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	tmp := make([]uint8, len(img.Pix))
+	for _, win := range wins {
+		boxBlur(img.Pix, tmp, w, h, 4, img.Stride, win[0], win[1]) // 横向：每行一条线
+		boxBlur(tmp, img.Pix, h, w, img.Stride, 4, win[0], win[1]) // 纵向：每列一条线
+	}
+}
+
+// boxBlur 沿一个方向做窗口 [lo,hi] 的盒式模糊：n 条线、每条 length 个像素，
+// step 是线内相邻像素的字节距离，lineStep 是相邻两条线的字节距离。
+func boxBlur(src, dst []uint8, length, n, step, lineStep, lo, hi int) {
+	d := hi - lo + 1
+	var sum [4]int
+	for ln := 0; ln < n; ln++ {
+		base := ln * lineStep
+		sum = [4]int{}
+		for k := lo; k <= hi; k++ {
+			if k >= 0 && k < length {
+				o := base + k*step
+				for c := 0; c < 4; c++ {
+					sum[c] += int(src[o+c])
+				}
+			}
+		}
+		for i := 0; i < length; i++ {
+			o := base + i*step
+			for c := 0; c < 4; c++ {
+				dst[o+c] = uint8((sum[c] + d/2) / d)
+			}
+			if k := i + lo; k >= 0 {
+				o := base + k*step
+				for c := 0; c < 4; c++ {
+					sum[c] -= int(src[o+c])
+				}
+			}
+			if k := i + hi + 1; k < length {
+				o := base + k*step
+				for c := 0; c < 4; c++ {
+					sum[c] += int(src[o+c])
+				}
+			}
+		}
+	}
+}
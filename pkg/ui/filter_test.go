@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func solidRGBA(w, h int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// CPU 颜色滤镜：去色后三通道相等，亮度按比例缩放，saturate(1) 不变。
+func TestApplyColorFilters(t *testing.T) {
+	img := applyFilters(solidRGBA(2, 2, color.RGBA{255, 0, 0, 255}), []filterOp{{filterGrayscale, 1}})
+	if c := img.RGBAAt(0, 0); c.R != c.G || c.G != c.B || c.R != 54 {
+		t.Fatalf("grayscale(1) 红色 = %v", c)
+	}
+	img = applyFilters(solidRGBA(1, 1, color.RGBA{200, 100, 50, 255}), []filterOp{{filterBrightness, 0.5}})
+	if c := img.RGBAAt(0, 0); c != (color.RGBA{100, 50, 25, 255}) {
+		t.Fatalf("brightness(0.5) = %v", c)
+	}
+	img = applyFilters(solidRGBA(1, 1, color.RGBA{200, 100, 50, 255}), []filterOp{{filterSaturate, 1}})
+	if c := img.RGBAAt(0, 0); c != (color.RGBA{200, 100, 50, 255}) {
+		t.Fatalf("saturate(1) 应保持原色，实际 %v", c)
+	}
+	// 预乘下颜色不超过 alpha
+	img = applyFilters(solidRGBA(1, 1, color.RGBA{100, 100, 100, 128}), []filterOp{{filterBrightness, 3}})
+	if c := img.RGBAAt(0, 0); c.R != 128 {
+		t.Fatalf("brightness(3) 应钳到 alpha，实际 %v", c)
+	}
+}
+
+// CPU 模糊：中心的小块向四周对称扩散，总量大致守恒。
+func TestApplyBlur(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 21, 21))
+	for i := 0; i < 9; i++ { // 中心 3×3 的白块，让取整误差可以忽略
+		img.SetRGBA(9+i%3, 9+i/3, color.RGBA{255, 255, 255, 255})
+	}
+	applyFilters(img, []filterOp{{filterBlur, 2}})
+	if c := img.RGBAAt(10, 10); c.A == 0 || c.A == 255 {
+		t.Fatalf("中心应被摊薄，实际 %v", c)
+	}
+	if l, r := img.RGBAAt(6, 10).A, img.RGBAAt(14, 10).A; l == 0 || l != r {
+		t.Fatalf("应左右对称地扩散：%d vs %d", l, r)
+	}
+	if u, d := img.RGBAAt(10, 6).A, img.RGBAAt(10, 14).A; u != d {
+		t.Fatalf("应上下对称地扩散：%d vs %d", u, d)
+	}
+	sum := 0
+	for i := 3; i < len(img.Pix); i += 4 {
+		sum += int(img.Pix[i])
+	}
+	if want := 9 * 255; sum < want*9/10 || sum > want*11/10 {
+		t.Fatalf("alpha 总量 %d，应约为 %d", sum, want)
+	}
+}
+
+// 有滤镜的元素走图层，unlayer 记录滤镜链（按书写顺序）。
+func TestFilterRecordedOnLayer(t *testing.T) {
+	h := Mount(Div(Style(Width(100), Height(40), Bg(Red), Blur(4), Grayscale(1))), 100, 40)
+	for _, op := range h.Paint() {
+		if op.Kind == "unlayer" {
+			if op.Filter != "blur(4) grayscale(1)" {
+				t.Fatalf("Filter = %q", op.Filter)
+			}
+			return
+		}
+	}
+	t.Fatal("带滤镜的元素应画成图层")
+}
+
+// BackdropBlur 先把排在它之前的内容裁到自身矩形里重画一遍并模糊，再画自身。
+func TestBackdropBlurRepaintsBehind(t *testing.T) {
+	h := Mount(Div(Style(Width(200), Height(100)),
+		Div(Style(Width(200), Height(100), Bg(Red))),
+		Div(Style(Absolute, Left(20), Top(20), Width(80), Height(40), Bg(White.Alpha(0.5)), BackdropBlur(8))),
+	), 200, 100)
+	var kinds []string
+	var filter string
+	for _, op := range h.Paint() {
+		switch {
+		case op.Kind == "rect" && op.Color == Red:
+			kinds = append(kinds, "red")
+		case op.Kind == "rect" && op.Color == White.Alpha(0.5):
+			kinds = append(kinds, "glass")
+		case op.Kind == "clip" && op.Rect == (Rect{20, 20, 80, 40}):
+			kinds = append(kinds, "clip")
+		case op.Kind == "unlayer":
+			kinds = append(kinds, "unlayer")
+			filter = op.Filter
+		case op.Kind == "layer" || op.Kind == "unclip":
+			kinds = append(kinds, op.Kind)
+		}
+	}
+	want := "red clip layer red unlayer unclip glass"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Fatalf("绘制顺序 = %q，应为 %q", got, want)
+	}
+	if filter != "blur(8)" {
+		t.Fatalf("背景层滤镜 = %q", filter)
+	}
+}
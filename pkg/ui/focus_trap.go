@@ -13,9 +13,17 @@ func TrapFocus() *Node {
 
 // trapScope 返回当前生效的焦点陷阱范围（最上层带 TrapFocus 的浮层根），无则返回 nil。
 func (g *game) trapScope() *renderNode {
+	if pf := g.trapPortal(); pf != nil {
+		return pf.overlayRoot
+	}
+	return nil
+}
+
+// trapPortal 返回最上层带 TrapFocus 的 Portal fiber，无则返回 nil。
+func (g *game) trapPortal() *Fiber {
 	for i := len(g.portals) - 1; i >= 0; i-- {
 		if pf := g.portals[i]; pf.portalTrap && pf.overlayRoot != nil {
-			return pf.overlayRoot
+			return pf
 		}
 	}
 	return nil
@@ -26,7 +26,7 @@ func TestProjectedOutlineIsTrapezoidNotParallelogram(t *testing.T) {
 	p.BeginLayer()
 	p.FillRect(90, 60, 120, 120, 0, Color{0, 0, 0, 255})
 	p.EndLayer(layerTransform{
-		cx: 150, cy: 120, w: 120, h: 120, scale: 1, opacity: 1,
+		cx: 150, cy: 120, w: 120, h: 120, scaleX: 1, scaleY: 1, opacity: 1,
 		rotateY: 45, perspective: 300,
 	})
 	if err := win.Frame(&ops); err != nil {
@@ -77,7 +77,7 @@ func TestProjectedContentFillsClipQuad(t *testing.T) {
 		{45, 0, 900}, {45, 20, 900}, {35, 20, 700}, {60, 15, 400}, {0, 50, 500}, {30, -40, 600},
 	} {
 		tr := layerTransform{
-			cx: 150, cy: 150, w: 150, h: 180, scale: 1, opacity: 1,
+			cx: 150, cy: 150, w: 150, h: 180, scaleX: 1, scaleY: 1, opacity: 1,
 			rotateX: tc.rx, rotateY: tc.ry, perspective: tc.pers,
 		}
 		var ops op.Ops
@@ -31,7 +31,7 @@ func TestPseudo3DDoesNotAffectLayout(t *testing.T) {
 
 // 投影的基本性质：无 3D 参数时应恒等；rotateY 让左右两侧到中心的距离不再相等（透视）。
 func TestProject3DBasics(t *testing.T) {
-	base := layerTransform{cx: 100, cy: 100, w: 80, h: 60, scale: 1, opacity: 1}
+	base := layerTransform{cx: 100, cy: 100, w: 80, h: 60, scaleX: 1, scaleY: 1, opacity: 1}
 
 	// 没有 3D 时，投影只是把偏移搬到中心
 	if got := project3D(base, 10, -5); got != (pt{110, 95}) {
@@ -92,7 +92,7 @@ func TestGio3DCardIsProjected(t *testing.T) {
 		p.DrawText("Hi", gioNewFont(20, 400, false), Color{255, 255, 255, 255}, 110, 100, false, false)
 		p.EndLayer(layerTransform{
 			cx: 150, cy: 120, w: 120, h: 120,
-			scale: 1, opacity: 1,
+			scaleX: 1, scaleY: 1, opacity: 1,
 			rotateY: rotY, perspective: 300,
 		})
 		if err := win.Frame(&ops); err != nil {
@@ -155,7 +155,7 @@ func TestGio3DWarpsTextToo(t *testing.T) {
 		p.DrawText("MMMM", gioNewFont(28, 700, false), Color{0, 0, 0, 255}, 100, 100, false, false)
 		p.EndLayer(layerTransform{
 			cx: 150, cy: 120, w: 120, h: 60,
-			scale: 1, opacity: 1,
+			scaleX: 1, scaleY: 1, opacity: 1,
 			rotateY: rotY, perspective: 300,
 		})
 		if err := win.Frame(&ops); err != nil {
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"image"
+
+	"gioui.org/text"
+)
+
+// ---- gio 后端：矢量导出的句柄适配 ----
+//
+// export_svg.go / export_pdf.go 是纯 Go 的矢量后端，不认识任何 gio 类型（见 backend.go 的
+// 边界规则）。但引擎手里的 fontFace/bitmap/vecPath 是当前激活后端（gio）建的，所以由这里
+// 把它们拆成中立数据：整形后的字形序列、源图像、SVG 路径的 d。
+// 文字沿用 gio 的 shaper 整形，导出的字形位置与屏幕上逐一对应。
+
+// shapedGlyph 是整形后的一个字形。
+type shapedGlyph struct {
+	gid  uint16  // 内置字体里的字形号
+	x, y float32 // 相对行首基线原点的位置（物理像素，y 向下）
+	text string  // 所在字形簇对应的原文，只记在簇首字形上（PDF 的 ToUnicode 据此复制出文字）
+}
+
+// shapedRun 是一行整形结果：px 为字号（物理像素），ascent 为基线到行顶的距离。
+type shapedRun struct {
+	px, ascent float32
+	glyphs     []shapedGlyph
+}
+
+// exportShape 用 gio 的 shaper 整形一行文本；位置算法与 gio 的 Shaper.Shape 一致。
+func exportShape(face fontFace, s string) shapedRun {
+	f := face.(*gioFont)
+	run := shapedRun{px: f.px, ascent: f.ascent}
+	if s == "" {
+		return run
+	}
+	sh := gioShaper()
+	sh.LayoutString(f.params(), s)
+	var glyphs []text.Glyph
+	for {
+		g, ok := sh.NextGlyph()
+		if !ok {
+			break
+		}
+		glyphs = append(glyphs, g)
+	}
+	runes := []rune(s)
+	ri, start := 0, 0
+	for i, g := range glyphs {
+		if i == 0 {
+			run.px = float32(g.ID>>32&0xffff) / 64 // shaper 实际用的 ppem（26.6 定点）
+		}
+		run.glyphs = append(run.glyphs, shapedGlyph{
+			gid: uint16(g.ID),
+			x:   float32(g.X-glyphs[0].X-g.Offset.X) / 64,
+			y:   -float32(g.Offset.Y) / 64,
+		})
+		if g.Flags&text.FlagClusterBreak != 0 {
+			end := min(ri+int(g.Runes), len(runes))
+			run.glyphs[start].text = string(runes[ri:end])
+			ri, start = end, i+1
+		}
+	}
+	return run
+}
+
+// exportImage 取位图句柄的源图像。
+func exportImage(b bitmap) image.Image { return b.(*gioImage).src }
+
+// exportPath 取矢量句柄的 SVG 路径 d 与缩放。
+func exportPath(v vecPath) (d string, scale float32) {
+	gp := v.(*gioPath)
+	return gp.d, gp.scale
+}
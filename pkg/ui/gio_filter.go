@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"image"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/gpu/headless"
+	"gioui.org/op"
+	gpaint "gioui.org/op/paint"
+)
+
+// ---- gio 后端：图层滤镜 ----
+//
+// gio 没有滤镜/混合模式原语，所以分两条路（见 filter.go）：
+//
+//   - 只有模糊：把图层错位叠画若干次取平均。第 i 次用 1/i 的透明度叠上去，
+//     不透明内容上正好是各次的等权平均 —— 一个圆盘形的模糊核，不必离屏。
+//   - 含颜色滤镜：用一个复用的无头窗口把图层渲染成像素，applyFilters 在 CPU 上处理后
+//     当贴图画回。无头 GPU 不可用时退回只做模糊（颜色滤镜被忽略，内容照常显示）。
+//
+// 两条路都只产出「滤镜后的内容」（未变换的坐标），变换与整组透明度仍由 EndLayer 施加。
+
+// blurTaps 是模糊叠画的采样点（以 σ 为单位）：中心 + σ 一圈 8 个 + 2σ 一圈 8 个（错开 22.5°）。
+var blurTaps = func() []f32.Point {
+	taps := []f32.Point{{}}
+	for ring := 1; ring <= 2; ring++ {
+		for i := 0; i < 8; i++ {
+			a := (float64(i) + float64(ring-1)/2) * math.Pi / 4
+			taps = append(taps, f32.Pt(float32(ring)*float32(math.Cos(a)), float32(ring)*float32(math.Sin(a))))
+		}
+	}
+	return taps
+}()
+
+// filterLayer 返回施加了 t.filters 之后的图层内容。
+func (p *gioPainter) filterLayer(call op.CallOp, t layerTransform) op.CallOp {
+	if !onlyBlur(t.filters) {
+		if c, ok := p.filterOffscreen(call, t); ok {
+			return c
+		}
+	}
+	// 多个模糊串联等价于一个模糊，σ 按平方和合成
+	var s2 float32
+	for _, f := range t.filters {
+		if f.kind == filterBlur {
+			s2 += f.v * f.v
+		}
+	}
+	sigma := float32(math.Sqrt(float64(s2)))
+	if sigma < 0.5 {
+		return call
+	}
+	m := op.Record(p.ops)
+	for i, d := range blurTaps {
+		tr := op.Affine(gioOffset(d.X*sigma, d.Y*sigma)).Push(p.ops)
+		os := gpaint.PushOpacity(p.ops, 1/float32(i+1))
+		call.Add(p.ops)
+		os.Pop()
+		tr.Pop()
+	}
+	return m.Stop()
+}
+
+// filterOffscreen 把图层渲染到离屏像素、在 CPU 上施加滤镜，返回画回该贴图的宏。
+// 渲染范围是元素盒向外扩 filterMargin（模糊扩散），并裁到窗口内。
+func (p *gioPainter) filterOffscreen(call op.CallOp, t layerTransform) (op.CallOp, bool) {
+	m := filterMargin(t.filters)
+	box := image.Rect(
+		int(math.Floor(float64(t.cx-t.w/2-m))), int(math.Floor(float64(t.cy-t.h/2-m))),
+		int(math.Ceil(float64(t.cx+t.w/2+m))), int(math.Ceil(float64(t.cy+t.h/2+m))),
+	).Intersect(image.Rect(0, 0, p.w, p.h))
+	if box.Empty() {
+		return op.CallOp{}, false
+	}
+	img, ok := renderOffscreen(call, box)
+	if !ok {
+		return op.CallOp{}, false
+	}
+	applyFilters(img, t.filters)
+	rec := op.Record(p.ops)
+	tr := op.Offset(box.Min).Push(p.ops)
+	gpaint.NewImageOp(img).Add(p.ops)
+	gpaint.PaintOp{}.Add(p.ops)
+	tr.Pop()
+	return rec.Stop(), true
+}
+
+// offscreen 是复用的无头窗口（尺寸变了才重建）；建窗失败后不再重试，免得每帧都失败一次。
+var offscreen struct {
+	win    *headless.Window
+	size   image.Point
+	failed bool
+}
+
+// renderOffscreen 把 call 中 box 范围内的内容渲染成像素（透明底，预乘 alpha）。
+func renderOffscreen(call op.CallOp, box image.Rectangle) (*image.RGBA, bool) {
+	if offscreen.failed {
+		return nil, false
+	}
+	size := box.Size()
+	if offscreen.win == nil || offscreen.size != size {
+		if offscreen.win != nil {
+			offscreen.win.Release()
+			offscreen.win = nil
+		}
+		win, err := headless.NewWindow(size.X, size.Y)
+		if err != nil {
+			offscreen.failed = true
+			return nil, false
+		}
+		offscreen.win, offscreen.size = win, size
+	}
+	var ops op.Ops
+	tr := op.Offset(box.Min.Mul(-1)).Push(&ops)
+	call.Add(&ops)
+	tr.Pop()
+	if err := offscreen.win.Frame(&ops); err != nil {
+		return nil, false
+	}
+	img := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	if err := offscreen.win.Screenshot(img); err != nil {
+		return nil, false
+	}
+	return img, true
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"io"
+	"strings"
 
 	"gioui.org/io/event"
 	"gioui.org/io/key"
@@ -27,8 +28,12 @@ type gioInput struct {
 	keyJust        [inKeyCount]bool
 	mods           key.Modifiers
 	typed          []rune
-	focused        bool       // gio 是否已把键盘焦点给了 gioTag
-	snippetReq     *key.Range // 本帧输入法索要的上下文范围（SnippetEvent）
+	strokes        []keyStroke       // 本帧按下的键（带修饰键），供 OnKeyDown / UseHotkey
+	strokeFx       []strokeFx        // 与 strokes 一一对应：每次按键在本帧留下的边沿与编辑
+	lastPress      int               // 上一个事件若是按下，为其在 strokes 里的下标，否则 -1
+	held           map[key.Name]bool // 按住未放的键，用来认出自动重复
+	focused        bool              // gio 是否已把键盘焦点给了 gioTag
+	snippetReq     *key.Range        // 本帧输入法索要的上下文范围（SnippetEvent）
 
 	// 输入法编辑：EditEvent 是「替换某 rune 区间」，不是「在光标处追加」。
 	edits      []gioEdit  // 本帧的替换（组字更新与提交都走这里）
@@ -36,6 +41,14 @@ type gioInput struct {
 	composeEvt *key.Range // 组字中的区间（用于预编辑下划线）；空区间表示组字结束
 }
 
+// strokeFx 记下一次按键在本帧的效果，按键被消费时只撤掉它自己的这些效果。
+type strokeFx struct {
+	just    inKey // 这次按键置起的 keyJust
+	hasJust bool
+	edit    int  // 紧随这次按下的编辑（即这一键打出的字符）在 edits 里的下标，-1 表示没有
+	paste   bool // 这一键发起了粘贴
+}
+
 // gioEdit 是一次「把 rng 这段 rune 替换成 text」的编辑。
 type gioEdit struct {
 	rng  key.Range
@@ -52,6 +65,7 @@ func (g *gioInput) wheel() (float32, float32)        { return g.wheelX, g.wheelY
 func (g *gioInput) mousePressed(b mouseBtn) bool     { return g.btnDown[b] }
 func (g *gioInput) mouseJustPressed(b mouseBtn) bool { return g.btnJust[b] }
 func (g *gioInput) typedChars() []rune               { return g.typed }
+func (g *gioInput) keyStrokes() []keyStroke          { return g.strokes }
 
 func (g *gioInput) keyPressed(k inKey) bool {
 	switch k {
@@ -73,11 +87,59 @@ func (g *gioInput) resetFrame() {
 	g.btnJust = [2]bool{}
 	g.keyJust = [inKeyCount]bool{}
 	g.typed = g.typed[:0]
+	g.strokes = g.strokes[:0]
+	g.strokeFx = g.strokeFx[:0]
+	g.lastPress = -1
 	g.snippetReq = nil
 	g.edits = g.edits[:0]
 	g.selEvt, g.composeEvt = nil, nil
 }
 
+// dropStrokes 撤掉被 OnKeyDown 的 PreventDefault 或快捷键消费的按键（drop[i] 对应
+// strokes[i]）：清掉它置起的边沿、它打出的字符与它发起的粘贴。同一帧里的其余按键与
+// 输入法编辑不受影响 —— 快速打字时 gio 会把好几个键攒进一帧。
+func (g *gioInput) dropStrokes(drop []bool) {
+	var dropEdit []bool
+	for i, d := range drop {
+		if !d || i >= len(g.strokeFx) {
+			continue
+		}
+		fx := g.strokeFx[i]
+		if fx.hasJust && !g.justKept(fx.just, drop) {
+			g.keyJust[fx.just] = false
+		}
+		if fx.edit >= 0 {
+			if dropEdit == nil {
+				dropEdit = make([]bool, len(g.edits))
+			}
+			dropEdit[fx.edit] = true
+		}
+		if fx.paste {
+			gioClip.pasteRequested = false
+		}
+	}
+	if dropEdit == nil {
+		return
+	}
+	kept := g.edits[:0]
+	for i, e := range g.edits {
+		if !dropEdit[i] {
+			kept = append(kept, e)
+		}
+	}
+	g.edits = kept
+}
+
+// justKept 报告本帧是否还有没被消费的按键也置起了 k 的边沿。
+func (g *gioInput) justKept(k inKey, drop []bool) bool {
+	for i, fx := range g.strokeFx {
+		if fx.hasJust && fx.just == k && (i >= len(drop) || !drop[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 // gioKeyName 把 gio 键名映射到中立键（仅特殊/导航/快捷键；普通字符走 EditEvent）。
 var gioKeyName = map[key.Name]inKey{
 	key.NameTab: keyTab, key.NameEscape: keyEscape,
@@ -89,6 +151,80 @@ var gioKeyName = map[key.Name]inKey{
 	"A": keyA, "C": keyC, "X": keyX, "V": keyV, "F12": keyF12,
 }
 
+// gioStrokeName 把 gio 的符号键名换成快捷键的规范键名；其余（字母、数字、F1…）原样。
+var gioStrokeName = map[key.Name]string{
+	key.NameLeftArrow: "Left", key.NameRightArrow: "Right", key.NameUpArrow: "Up", key.NameDownArrow: "Down",
+	key.NameReturn: "Enter", key.NameEnter: "Enter", key.NameEscape: "Esc",
+	key.NameHome: "Home", key.NameEnd: "End", key.NamePageUp: "PageUp", key.NamePageDown: "PageDown",
+	key.NameDeleteBackward: "Backspace", key.NameDeleteForward: "Delete",
+}
+
+// gioStroke 把一次 gio 按键换成 keyStroke。
+func gioStroke(ev key.Event) keyStroke {
+	s := keyStroke{key: string(ev.Name)}
+	if n, ok := gioStrokeName[ev.Name]; ok {
+		s.key = n
+	}
+	m := ev.Modifiers
+	if m.Contain(key.ModCtrl) {
+		s.mods |= modCtrl
+	}
+	if m.Contain(key.ModAlt) {
+		s.mods |= modAlt
+	}
+	if m.Contain(key.ModShift) {
+		s.mods |= modShift
+	}
+	if m&(key.ModCommand|key.ModSuper) != 0 {
+		s.mods |= modMeta
+	}
+	return s
+}
+
+// gioKeyFilters 为注册表里每个快捷键的每一步登记 key.Filter —— gio 只投递有过滤器的按键。
+// 修饰键设为 Required，不带修饰的普通字符仍走 EditEvent（打字不受影响）。
+// 焦点链上有 OnKeyDown 时再加一个空 Name 的兜底过滤器，把其余按键也投递过来
+// （gio 的字符输入与按键事件是分开发出的，兜底不会吞掉 EditEvent）。
+func gioKeyFilters(g *game) []event.Filter {
+	if g == nil {
+		return nil
+	}
+	back := map[string]key.Name{}
+	for n, s := range gioStrokeName {
+		back[s] = n
+	}
+	var fs []event.Filter
+	for _, r := range g.hotkeys {
+		for _, s := range r.steps {
+			name, ok := back[s.key]
+			if !ok {
+				name = key.Name(s.key)
+			}
+			var req key.Modifiers
+			if s.mods&modCtrl != 0 {
+				req |= key.ModCtrl
+			}
+			if s.mods&modAlt != 0 {
+				req |= key.ModAlt
+			}
+			if s.mods&modShift != 0 {
+				req |= key.ModShift
+			}
+			if s.mods&modMeta != 0 {
+				req |= key.ModCommand
+			}
+			fs = append(fs, key.Filter{Focus: gioTag, Name: name, Required: req})
+		}
+	}
+	if g.inspectorKeyOn() {
+		fs = append(fs, key.Filter{Focus: gioTag, Name: key.Name(inspectorKey.key), Required: key.ModCtrl | key.ModShift})
+	}
+	if g.wantsAllKeys() {
+		fs = append(fs, key.Filter{Focus: gioTag, Optional: key.ModCtrl | key.ModAlt | key.ModShift | key.ModCommand | key.ModSuper})
+	}
+	return fs
+}
+
 // gioInputFilters 是每帧向 Source 注册的事件过滤器。
 //
 // key.FocusFilter 必不可少：gio 只用它把 handler 标成 focusable，而
@@ -97,7 +233,7 @@ var gioKeyName = map[key.Name]inKey{
 //
 // 少了它，就会既打不了字，又陷入「每帧请求焦点→每帧被撤销」的抖动。
 // 注意 key.Filter 不会设置 focusable，两者都要注册。
-func gioInputFilters() []event.Filter {
+func gioInputFilters(extra ...event.Filter) []event.Filter {
 	sc := pointer.ScrollRange{Min: -100000, Max: 100000}
 	navOpt := key.ModShift | key.ModShortcut | key.ModShortcutAlt
 	fs := []event.Filter{
@@ -116,14 +252,14 @@ func gioInputFilters() []event.Filter {
 	}
 	// 系统剪贴板读回的数据（Ctrl+V 发出 ReadCmd 后经此送达）。
 	fs = append(fs, transfer.TargetFilter{Target: gioTag, Type: gioClipMime})
-	return fs
+	return append(fs, extra...)
 }
 
 // gioProcessInput 排空本帧队列并更新 gioIn。返回是否收到过任何事件（用于判断是否需重绘）。
 func (g *gioInput) process(src interface {
 	Event(...event.Filter) (event.Event, bool)
-}) {
-	filters := gioInputFilters()
+}, hot []event.Filter) {
+	filters := gioInputFilters(hot...)
 	for {
 		ev, ok := src.Event(filters...)
 		if !ok {
@@ -131,6 +267,7 @@ func (g *gioInput) process(src interface {
 		}
 		switch ev := ev.(type) {
 		case pointer.Event:
+			g.lastPress = -1
 			debugPointer(ev)
 			g.setCursor(ev.Position.X, ev.Position.Y) // 原样透传，不取整
 			g.mods = ev.Modifiers
@@ -154,15 +291,30 @@ func (g *gioInput) process(src interface {
 			}
 		case key.Event:
 			g.mods = ev.Modifiers
+			if ev.State == key.Press {
+				s := gioStroke(ev)
+				s.repeat = g.held[ev.Name]
+				g.strokes = append(g.strokes, s)
+				g.strokeFx = append(g.strokeFx, strokeFx{edit: -1})
+				g.lastPress = len(g.strokes) - 1
+				if g.held == nil {
+					g.held = map[key.Name]bool{}
+				}
+				g.held[ev.Name] = true
+			} else {
+				delete(g.held, ev.Name)
+			}
 			k, ok := gioKeyName[ev.Name]
 			switch {
 			case !ok:
 			case k == keyV && ev.State == key.Press && ev.Modifiers.Contain(key.ModShortcut):
 				// 粘贴由后端接管：系统剪贴板是异步读的，让引擎同步读缓存会粘到过期内容。
 				gioClip.pasteRequested = true
+				g.strokeFx[len(g.strokeFx)-1].paste = true
 			case ev.State == key.Press:
 				if !g.keyDown[k] {
 					g.keyJust[k] = true
+					g.strokeFx[len(g.strokeFx)-1].just, g.strokeFx[len(g.strokeFx)-1].hasJust = k, true
 				}
 				g.keyDown[k] = true
 			default:
@@ -172,6 +324,13 @@ func (g *gioInput) process(src interface {
 			// 必须按 Range 替换：组字过程中输入法反复用新串替换上一次的区间
 			// （"s" -> 用 "shi" 替换 [0,1)）。当成追加会写出 "sshi"。
 			g.edits = append(g.edits, gioEdit{rng: ev.Range, text: ev.Text})
+			// 紧跟在按下之后的编辑是这一键打出的字符：按键被消费时它也要撤掉。带 Ctrl/Alt/Meta
+			// 的组合键一般不打字，只有编辑正是这个键的字母时才算它的（Ctrl+K 后跟 "k"）。
+			if p := g.lastPress; p >= 0 && p < len(g.strokeFx) &&
+				(g.strokes[p].mods&^modShift == 0 || strings.EqualFold(ev.Text, g.strokes[p].key)) {
+				g.strokeFx[p].edit = len(g.edits) - 1
+			}
+			g.lastPress = -1
 		case key.FocusEvent:
 			g.focused = ev.Focus
 		case key.SnippetEvent: // 输入法索要上下文文本，本帧稍后回它
@@ -204,13 +363,23 @@ func gioCursor(g *game) pointer.Cursor {
 	if g.rootRN == nil {
 		return pointer.CursorDefault
 	}
-	x, y := gioIn.cursor()
+	if s := g.dnd; s != nil && s.active {
+		if s.over != nil {
+			return pointer.CursorGrabbing
+		}
+		return pointer.CursorNotAllowed
+	}
+	x, y := input.cursor() // 跑帧时 input 是当前窗口的输入源
 	for c := g.hitTop(x, y); c != nil; c = c.parent {
 		switch {
+		case c.dragSource:
+			return pointer.CursorGrab
 		case c.kind == rnInput:
 			return pointer.CursorText
 		case c.onClick != nil:
 			return pointer.CursorPointer
+		case c.selectable:
+			return pointer.CursorText
 		}
 	}
 	return pointer.CursorDefault
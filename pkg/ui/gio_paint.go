@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"math"
@@ -130,25 +131,67 @@ func (p *gioPainter) FillRect(x, y, w, h, r float32, c Color) {
 	p.fillShape(p.rrectOp(x, y, w, h, r), c)
 }
 
-func (p *gioPainter) FillGradient(x, y, w, h, r float32, from, to Color, angle float32) {
-	if w <= 0 || h <= 0 {
+func (p *gioPainter) FillGradient(x, y, w, h, r float32, g *Gradient) {
+	if w <= 0 || h <= 0 || len(g.Stops) == 0 {
 		return
 	}
 	st := p.rrectOp(x, y, w, h, r).Push(p.ops)
-	rad := float64(angle) * math.Pi / 180
-	dx, dy := float32(math.Cos(rad)), float32(math.Sin(rad))
-	cx, cy := x+w/2, y+h/2
-	half := (absf(w*dx) + absf(h*dy)) / 2 // 沿渐变方向的近似半跨度
-	gpaint.LinearGradientOp{
-		Stop1:  f32.Pt(cx-dx*half, cy-dy*half),
-		Color1: nrgba(from),
-		Stop2:  f32.Pt(cx+dx*half, cy+dy*half),
-		Color2: nrgba(to),
-	}.Add(p.ops)
-	gpaint.PaintOp{}.Add(p.ops)
+	p.paintGradient(g, x, y, w, h)
+	st.Pop()
+}
+
+// StrokeGradient 用渐变描边；渐变铺满 (x,y,w,h)，与 FillGradient 同一坐标。
+func (p *gioPainter) StrokeGradient(x, y, w, h, r, width float32, g *Gradient) {
+	if width <= 0 || w <= 0 || h <= 0 || len(g.Stops) == 0 {
+		return
+	}
+	st := clip.Stroke{Path: p.rrectPath(x, y, w, h, r), Width: width}.Op().Push(p.ops)
+	// 描边有一半落在盒外，铺渐变的区域向外扩半个线宽
+	hw := width / 2
+	p.paintGradient(g, x-hw, y-hw, w+width, h+width)
 	st.Pop()
 }
 
+// paintGradient 在当前裁剪内铺上渐变并 PaintOp，渐变铺满 (x,y,w,h)。
+// 两色线性渐变用 gio 原生的 LinearGradientOp；多色标 / 径向 / 锥形先栅格化成贴图。
+func (p *gioPainter) paintGradient(g *Gradient, x, y, w, h float32) {
+	if g.twoStopLinear() {
+		x0, y0, x1, y1 := g.linearSpan(w, h)
+		gpaint.LinearGradientOp{
+			Stop1:  f32.Pt(x+x0, y+y0),
+			Color1: nrgba(g.Stops[0].Color),
+			Stop2:  f32.Pt(x+x1, y+y1),
+			Color2: nrgba(g.Stops[1].Color),
+		}.Add(p.ops)
+		gpaint.PaintOp{}.Add(p.ops)
+		return
+	}
+	iw, ih := int(math.Ceil(float64(w))), int(math.Ceil(float64(h)))
+	if iw <= 0 || ih <= 0 {
+		return
+	}
+	aff := op.Affine(gioOffset(x, y)).Push(p.ops)
+	gpaint.NewImageOp(gradientImage(g, iw, ih)).Add(p.ops)
+	gpaint.PaintOp{}.Add(p.ops)
+	aff.Pop()
+}
+
+// gradientCache 缓存栅格化后的渐变贴图：同一渐变、同一尺寸每帧都会重画，逐像素重算太贵。
+var gradientCache = map[string]*image.NRGBA{}
+
+func gradientImage(g *Gradient, w, h int) *image.NRGBA {
+	key := fmt.Sprintf("%s %dx%d", g, w, h)
+	if img, ok := gradientCache[key]; ok {
+		return img
+	}
+	if len(gradientCache) >= 32 {
+		clear(gradientCache)
+	}
+	img := g.raster(w, h)
+	gradientCache[key] = img
+	return img
+}
+
 // StrokeRect 描边（可含圆角的）矩形。与填充共用同一条 float 圆角路径，两者始终一致。
 func (p *gioPainter) StrokeRect(x, y, w, h, r, width float32, c Color) {
 	if c.A == 0 || width <= 0 || w <= 0 || h <= 0 {
@@ -198,7 +241,22 @@ func (p *gioPainter) Line(x0, y0, x1, y1 float32, c Color) {
 }
 
 func (p *gioPainter) DrawText(s string, face fontFace, c Color, x, y float32, fauxBold, fauxItalic bool) {
-	drawGioText(p.ops, face.(*gioFont), s, c, x, y, fauxBold, fauxItalic)
+	drawGioText(p.ops, face.(*gioFont), s, x, y, fauxBold, fauxItalic, func(f32.Affine2D) {
+		gpaint.ColorOp{Color: nrgba(c)}.Add(p.ops)
+		gpaint.PaintOp{}.Add(p.ops)
+	})
+}
+
+// DrawTextGradient 用渐变填充文字：在字形裁剪内退回屏幕坐标，再铺满 box。
+func (p *gioPainter) DrawTextGradient(s string, face fontFace, g *Gradient, box Rect, x, y float32, fauxBold, fauxItalic bool) {
+	if len(g.Stops) == 0 {
+		return
+	}
+	drawGioText(p.ops, face.(*gioFont), s, x, y, fauxBold, fauxItalic, func(aff f32.Affine2D) {
+		inv := op.Affine(aff.Invert()).Push(p.ops)
+		p.paintGradient(g, box.X, box.Y, box.W, box.H)
+		inv.Pop()
+	})
 }
 
 func (p *gioPainter) DrawImage(img bitmap, d Rect, opacity float32) {
@@ -261,6 +319,9 @@ func (p *gioPainter) EndLayer(t layerTransform) {
 	n := len(p.layers) - 1
 	call := p.layers[n].Stop()
 	p.layers = p.layers[:n]
+	if len(t.filters) > 0 {
+		call = p.filterLayer(call, t)
+	}
 
 	if t.is3D() { // 伪 3D：单个仿射表达不了透视，改走裁剪+仿射的投影路径
 		p.drawProjected(call, t)
@@ -270,8 +331,15 @@ func (p *gioPainter) EndLayer(t layerTransform) {
 	// 纯 2D：绕中心缩放/旋转 + 平移。
 	aff := f32.Affine2D{}
 	ctr := f32.Pt(t.cx, t.cy)
-	if t.scale != 1 && t.scale != 0 {
-		aff = aff.Scale(ctr, f32.Pt(t.scale, t.scale))
+	sx, sy := t.scaleX, t.scaleY
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+	if sx != 1 || sy != 1 {
+		aff = aff.Scale(ctr, f32.Pt(sx, sy))
 	}
 	if t.rotate != 0 {
 		aff = aff.Rotate(ctr, float32(float64(t.rotate)*math.Pi/180))
@@ -145,7 +145,7 @@ func TestGioLayerRenders(t *testing.T) {
 		p.FillRect(0, 0, 100, 100, 0, Color{255, 255, 255, 255})
 		p.BeginLayer()
 		p.FillRect(10, 10, 30, 30, 0, Color{0, 0, 0, 255})
-		p.EndLayer(layerTransform{cx: 25, cy: 25, scale: 1, opacity: 1})
+		p.EndLayer(layerTransform{cx: 25, cy: 25, scaleX: 1, scaleY: 1, opacity: 1})
 		p.FillRect(60, 60, 30, 30, 0, Color{0, 0, 0, 255}) // 图层之后
 	})
 	if n := darkPixels(img, 10, 40, 10, 40); n == 0 {
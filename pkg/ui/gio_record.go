@@ -0,0 +1,300 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+	"gioui.org/io/transfer"
+)
+
+// ---- 输入会话录制 ----
+//
+// 用户报来的界面 bug 往往复现不了：不知道他们具体做了什么。TENON_RECORD=session.json 让
+// 主窗口把每帧交给 gioInput.process 的事件（指针、按键、输入法、粘贴）连同帧时刻与窗口尺寸
+// 写进文件；Harness.Replay 把它原样喂回同一条输入路径。
+//
+// 文件是 JSON Lines：第一行是会话头，之后每帧一行。每帧写完就 Flush，程序崩溃时前面的帧
+// 也都在。坐标、尺寸、滚动量都换算成逻辑像素，回放不依赖录制机器的缩放。
+//
+// 会话里有用户键入与粘贴的原文，只在显式开启时录制。
+
+const sessionVersion = 1
+
+// sessionHeader 是会话文件的第一行。
+type sessionHeader struct {
+	Tenon   string  `json:"tenon"` // 恒为 "session"，用来认出文件
+	Version int     `json:"version"`
+	Width   int     `json:"width"` // 逻辑像素
+	Height  int     `json:"height"`
+	Scale   float32 `json:"scale"` // 录制时的设备缩放，仅供参考
+}
+
+// sessionFrame 是一帧：T 为距会话开始的毫秒数，Size 只在窗口尺寸变化时出现。
+type sessionFrame struct {
+	T      float64        `json:"t"`
+	Size   *[2]int        `json:"size,omitempty"`
+	Events []sessionEvent `json:"events,omitempty"`
+}
+
+// sessionEvent 是一个归一化的输入事件。Type 决定哪些字段有意义：
+//
+//	pointer  Kind(press/release/move/drag/scroll/cancel) X Y Buttons DX DY Mods
+//	key      Kind(press/release) Key Mods
+//	edit     Start End Text（rune 区间替换）
+//	compose  Start End（组字区间，空区间表示结束）
+//	select   Start End
+//	paste    Text（系统剪贴板读回的文本）
+type sessionEvent struct {
+	Type    string  `json:"type"`
+	Kind    string  `json:"kind,omitempty"`
+	X       float32 `json:"x,omitempty"`
+	Y       float32 `json:"y,omitempty"`
+	DX      float32 `json:"dx,omitempty"`
+	DY      float32 `json:"dy,omitempty"`
+	Buttons int     `json:"buttons,omitempty"` // 1 左键，2 右键
+	Key     string  `json:"key,omitempty"`
+	Mods    string  `json:"mods,omitempty"` // "Ctrl+Shift"，写法同 UseHotkey
+	Start   int     `json:"start,omitempty"`
+	End     int     `json:"end,omitempty"`
+	Text    string  `json:"text,omitempty"`
+}
+
+var sessionPointerKinds = map[pointer.Kind]string{
+	pointer.Press: "press", pointer.Release: "release", pointer.Move: "move",
+	pointer.Drag: "drag", pointer.Scroll: "scroll", pointer.Cancel: "cancel",
+}
+
+// sessionMods 与 modNames 同序，写出的修饰键与 UseHotkey 一致。
+var sessionMods = []struct {
+	m    key.Modifiers
+	name string
+}{{key.ModCtrl, "Ctrl"}, {key.ModAlt, "Alt"}, {key.ModShift, "Shift"}, {key.ModCommand | key.ModSuper, "Meta"}}
+
+func sessionModString(m key.Modifiers) string {
+	var parts []string
+	for _, n := range sessionMods {
+		if m&n.m != 0 {
+			parts = append(parts, n.name)
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+func sessionModParse(s string) key.Modifiers {
+	var m key.Modifiers
+	for _, p := range strings.Split(s, "+") {
+		for _, n := range sessionMods {
+			if p == n.name {
+				m |= n.m &^ key.ModSuper // Meta 回放成 ModCommand
+			}
+		}
+	}
+	return m
+}
+
+// toSession 把一个 gio 事件归一化；与输入无关的事件（焦点、输入法索要上下文）返回 false。
+// scale 是录制时的设备缩放：gio 给的是物理像素，会话里存逻辑像素。
+func toSession(ev event.Event, scale float32) (sessionEvent, bool) {
+	switch ev := ev.(type) {
+	case pointer.Event:
+		kind, ok := sessionPointerKinds[ev.Kind]
+		if !ok {
+			return sessionEvent{}, false
+		}
+		var btns int
+		if ev.Buttons&pointer.ButtonPrimary != 0 {
+			btns |= 1
+		}
+		if ev.Buttons&pointer.ButtonSecondary != 0 {
+			btns |= 2
+		}
+		return sessionEvent{Type: "pointer", Kind: kind, X: ev.Position.X / scale, Y: ev.Position.Y / scale,
+			DX: ev.Scroll.X / scale, DY: ev.Scroll.Y / scale, Buttons: btns, Mods: sessionModString(ev.Modifiers)}, true
+	case key.Event:
+		kind := "press"
+		if ev.State == key.Release {
+			kind = "release"
+		}
+		return sessionEvent{Type: "key", Kind: kind, Key: string(ev.Name), Mods: sessionModString(ev.Modifiers)}, true
+	case key.EditEvent:
+		return sessionEvent{Type: "edit", Start: ev.Range.Start, End: ev.Range.End, Text: ev.Text}, true
+	case key.CompositionEvent:
+		return sessionEvent{Type: "compose", Start: ev.Start, End: ev.End}, true
+	case key.SelectionEvent:
+		return sessionEvent{Type: "select", Start: ev.Start, End: ev.End}, true
+	case transfer.DataEvent:
+		if ev.Type != gioClipMime {
+			return sessionEvent{}, false
+		}
+		// 读掉就没了：把文本存下来，再换一个能重新读的事件交给 process
+		return sessionEvent{Type: "paste", Text: readDataEvent(ev)}, true
+	}
+	return sessionEvent{}, false
+}
+
+// fromSession 是 toSession 的逆：还原成喂给 gioInput.process 的 gio 事件（逻辑像素 = 缩放 1）。
+func fromSession(e sessionEvent) (event.Event, error) {
+	switch e.Type {
+	case "pointer":
+		ev := pointer.Event{Position: f32.Pt(e.X, e.Y), Scroll: f32.Pt(e.DX, e.DY), Modifiers: sessionModParse(e.Mods)}
+		if e.Buttons&1 != 0 {
+			ev.Buttons |= pointer.ButtonPrimary
+		}
+		if e.Buttons&2 != 0 {
+			ev.Buttons |= pointer.ButtonSecondary
+		}
+		for k, name := range sessionPointerKinds {
+			if name == e.Kind {
+				ev.Kind = k
+				return ev, nil
+			}
+		}
+	case "key":
+		ev := key.Event{Name: key.Name(e.Key), Modifiers: sessionModParse(e.Mods), State: key.Press}
+		switch e.Kind {
+		case "press":
+			return ev, nil
+		case "release":
+			ev.State = key.Release
+			return ev, nil
+		}
+	case "edit":
+		return key.EditEvent{Range: key.Range{Start: e.Start, End: e.End}, Text: e.Text}, nil
+	case "compose":
+		return key.CompositionEvent{Start: e.Start, End: e.End}, nil
+	case "select":
+		return key.SelectionEvent{Start: e.Start, End: e.End}, nil
+	case "paste":
+		return textDataEvent(e.Text), nil
+	default:
+		return nil, fmt.Errorf("unknown event type %q", e.Type)
+	}
+	return nil, fmt.Errorf("unknown %s kind %q", e.Type, e.Kind)
+}
+
+func readDataEvent(ev transfer.DataEvent) string {
+	rc := ev.Open()
+	if rc == nil {
+		return ""
+	}
+	defer rc.Close()
+	b, _ := io.ReadAll(rc)
+	return string(b)
+}
+
+// textDataEvent 造一个剪贴板读回 text 的 DataEvent。
+func textDataEvent(text string) transfer.DataEvent {
+	return transfer.DataEvent{Type: gioClipMime, Open: func() io.ReadCloser {
+		return io.NopCloser(strings.NewReader(text))
+	}}
+}
+
+// sessionRecorder 把主窗口的输入逐帧写进会话文件。只在持有 uiMu 的帧内调用。
+type sessionRecorder struct {
+	f      *os.File
+	w      *bufio.Writer
+	start  time.Time
+	w0, h0 int // 上一次写出的逻辑尺寸
+	err    error
+}
+
+// newSessionRecorder 创建（覆盖）会话文件。
+func newSessionRecorder(path string) (*sessionRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &sessionRecorder{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// recordingSource 把 process 取走的事件顺手记下来。
+type recordingSource struct {
+	src interface {
+		Event(...event.Filter) (event.Event, bool)
+	}
+	got []event.Event
+}
+
+func (s *recordingSource) Event(fs ...event.Filter) (event.Event, bool) {
+	ev, ok := s.src.Event(fs...)
+	if ok {
+		if d, isData := ev.(transfer.DataEvent); isData && d.Type == gioClipMime {
+			ev = textDataEvent(readDataEvent(d))
+		}
+		s.got = append(s.got, ev)
+	}
+	return ev, ok
+}
+
+// frame 写出一帧：now 是帧时刻，w×h 是物理尺寸，scale 是设备缩放。
+func (r *sessionRecorder) frame(now time.Time, w, h int, scale float32, evs []event.Event) {
+	if r == nil || r.err != nil {
+		return
+	}
+	lw, lh := int(float32(w)/scale+0.5), int(float32(h)/scale+0.5)
+	if r.start.IsZero() {
+		r.start, r.w0, r.h0 = now, lw, lh
+		r.write(sessionHeader{Tenon: "session", Version: sessionVersion, Width: lw, Height: lh, Scale: scale})
+	}
+	fr := sessionFrame{T: float64(now.Sub(r.start).Microseconds()) / 1000}
+	if lw != r.w0 || lh != r.h0 {
+		r.w0, r.h0 = lw, lh
+		fr.Size = &[2]int{lw, lh}
+	}
+	for _, ev := range evs {
+		if e, ok := toSession(ev, scale); ok {
+			fr.Events = append(fr.Events, e)
+		}
+	}
+	r.write(fr)
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	if r.err != nil {
+		fmt.Fprintf(os.Stderr, "tenon: record: %v\n", r.err)
+	}
+}
+
+func (r *sessionRecorder) write(v any) {
+	if r.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err == nil {
+		b = append(b, '\n')
+		_, err = r.w.Write(b)
+	}
+	r.err = err
+}
+
+func (r *sessionRecorder) close() {
+	if r == nil {
+		return
+	}
+	r.w.Flush()
+	r.f.Close()
+}
+
+// recordFromEnv 在设置了 TENON_RECORD 时开始录制主窗口的输入（App.Run 调用）。
+func (a *App) recordFromEnv() {
+	path := os.Getenv("TENON_RECORD")
+	if path == "" {
+		return
+	}
+	rec, err := newSessionRecorder(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tenon: record: %v\n", err)
+		return
+	}
+	a.rec = rec
+	fmt.Fprintf(os.Stderr, "tenon: recording input to %s\n", path)
+}
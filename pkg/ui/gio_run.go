@@ -2,11 +2,13 @@ package ui
 
 import (
 	"image"
+	"sync"
 	"time"
 
 	"gioui.org/app"
 	"gioui.org/io/event"
 	"gioui.org/io/key"
+	"gioui.org/io/system"
 	"gioui.org/op"
 	"gioui.org/op/clip"
 	gpaint "gioui.org/op/paint"
@@ -30,6 +32,7 @@ func init() {
 		return &gioPath{d: d, scale: scale}
 	}
 	backendRun = gioRun
+	backendOpenWindow = gioOpenWindow
 	input = gioIn
 }
 
@@ -50,84 +53,169 @@ func gioWindowOptions(cfg windowConfig) []app.Option {
 	return opts
 }
 
+// uiMu 串行化各窗口的帧：每个窗口在自己的 goroutine 里跑 gio 事件循环，但引擎依赖包级的
+// activeGame / uiScale / input 等渲染期状态，同一时刻只能有一个窗口在 reconcile/布局/绘制。
+var uiMu sync.Mutex
+
+// gioWindow 是一个 gio 窗口及其输入状态。输入、输入法状态是按窗口的（gio 的事件与
+// SelectionCmd 都针对某个窗口）；第一个窗口沿用包级的 gioIn/gioIME。
+type gioWindow struct {
+	w   *Window
+	win *app.Window
+	in  *gioInput
+	ime *gioIMEState
+	rec *sessionRecorder // 仅主窗口录制（TENON_RECORD）
+
+	// 标题/关闭不能在别的窗口的帧里直接做：app.Window.Option/Perform 会等该窗口的事件循环
+	// 执行完才返回，而那个循环可能正卡在 uiMu 上等我们 —— 死锁。所以先记下，由窗口自己的
+	// 循环在锁外执行。只在持有 uiMu 时读写。
+	pendingTitle *string
+	pendingClose bool
+}
+
+// gioPrimary 是正在使用包级 gioIn/gioIME 的窗口。gioOpenWindow 可能在 App.Run 里（不持
+// uiMu）调用，所以单独加锁。
+var (
+	gioPrimaryMu sync.Mutex
+	gioPrimary   *gioWindow
+)
+
+func (gw *gioWindow) setTitle(t string) { gw.pendingTitle = &t; gw.win.Invalidate() }
+func (gw *gioWindow) close()            { gw.pendingClose = true; gw.win.Invalidate() }
+func (gw *gioWindow) wake()             { gw.win.Invalidate() }
+
 func gioRun(root *Node, cfg windowConfig) {
-	g := &game{root: root, w: cfg.w, h: cfg.h}
-	activeGame = g
+	a := NewApp()
+	a.openWindow(root, cfg)
+	a.Run()
+}
 
-	win := new(app.Window)
-	win.Option(gioWindowOptions(cfg)...)
-	backendWake = win.Invalidate // 后台任务（Post）用它唤醒睡着的循环；可跨 goroutine 调用
-	defer func() { backendWake = nil }()
+// gioOpenWindow 为 w 建 gio 窗口并在新 goroutine 里跑它的事件循环。
+func gioOpenWindow(w *Window) windowHandle {
+	gw := &gioWindow{w: w, win: new(app.Window), in: &gioInput{}, ime: &gioIMEState{}}
+	gioPrimaryMu.Lock()
+	if gioPrimary == nil {
+		gioPrimary, gw.in, gw.ime = gw, gioIn, &gioIME
+		gw.rec = w.app.rec
+	}
+	gioPrimaryMu.Unlock()
+	gw.win.Option(gioWindowOptions(w.cfg)...)
+	w.app.wg.Add(1)
+	go func() {
+		defer w.app.wg.Done()
+		gw.loop()
+	}()
+	return gw
+}
 
+// loop 是单个窗口的事件循环。阻塞在 win.Event() 时不持锁，处理事件时持 uiMu。
+func (gw *gioWindow) loop() {
+	w, g := gw.w, gw.w.g
 	var ops op.Ops
 	last := time.Time{}
 	for {
-		switch e := win.Event().(type) {
-		case app.DestroyEvent:
-			return
-		case app.FrameEvent:
-			scale := e.Metric.PxPerDp
-			if scale < 1 {
-				scale = 1
-			}
-			if e.Size.X > 0 && (e.Size.X != g.w || e.Size.Y != g.h || scale != uiScale) {
-				uiScale = scale
-				g.w, g.h = e.Size.X, e.Size.Y
-				g.needsLayout = true
+		ev := gw.win.Event()
+		uiMu.Lock()
+		if _, ok := ev.(app.DestroyEvent); ok {
+			w.teardown()
+			uiMu.Unlock()
+			gioPrimaryMu.Lock()
+			if gioPrimary == gw {
+				gioPrimary = nil
 			}
+			gioPrimaryMu.Unlock()
+			return
+		}
+		if e, ok := ev.(app.FrameEvent); ok {
+			gw.frame(e, g, &ops, &last)
+		}
+		title, closing := gw.pendingTitle, gw.pendingClose
+		gw.pendingTitle, gw.pendingClose = nil, false
+		uiMu.Unlock()
 
-			// 排空本帧输入事件到 gioIn，再驱动一帧（handleInput 读取 gioIn）。
-			gioIn.resetFrame()
-			gioIn.process(e.Source)
-			// 输入法的编辑按 Range 替换，先落到聚焦输入框上，再让引擎跑这一帧。
-			gioIME.applyEdits(g, gioIn)
-			// 剪贴板同样先落地再跑帧：粘贴的文本本帧就能画出来，不用等下一帧。
-			gioClip.flush(e.Source, g)
-
-			// dt 用浮点毫秒：整数 Milliseconds() 会把高刷新率下的帧间隔截断（144Hz 的
-			// 6.9ms 变 6），帧间隔不足 1ms 时更会截成 0，而 tickAnims/tickLoops 遇 dt<=0
-			// 直接返回 —— 动画会卡住不动。
-			var dt float32
-			now := e.Now
-			if !last.IsZero() {
-				dt = float32(now.Sub(last).Seconds() * 1000)
-			}
-			last = now
-			layoutMoving := gioFrame(g, dt)
-
-			ops.Reset()
-			gpaint.ColorOp{Color: nrgba(Color{247, 248, 250, 255})}.Add(&ops)
-			gpaint.PaintOp{}.Add(&ops)
-			p := newGioPainter(&ops, g.w, g.h)
-			if g.rootRN != nil {
-				paint(p, g.rootRN)
-			}
-			for _, pf := range g.portals {
-				if pf.overlayRoot != nil {
-					paint(p, pf.overlayRoot)
-				}
-			}
-			// 声明整窗为输入命中区（引擎自管内部焦点，这里整窗恒接收）。
-			area := clip.Rect{Max: e.Size}.Push(&ops)
-			event.Op(&ops, gioTag)
-			key.InputHintOp{Tag: gioTag, Hint: key.HintAny}.Add(&ops)
-			gioCursor(g).Add(&ops) // 文本框 I 型、可点击手型
-			area.Pop()
-			// 仅在尚未获得焦点时请求一次；每帧无脑请求会和 gio 的焦点管理打架。
-			if !gioIn.focused {
-				e.Source.Execute(key.FocusCmd{Tag: gioTag})
-			}
-			// 把聚焦输入框的光标位置与上下文文本发布给输入法（否则无法组字，只能打英文）。
-			if gioIn.snippetReq != nil {
-				gioIME.handleSnippetReq(e.Source, g, *gioIn.snippetReq)
-			}
-			gioIME.sync(e.Source, g)
+		if title != nil {
+			gw.win.Option(app.Title(*title))
+		}
+		if closing {
+			gw.win.Perform(system.ActionClose)
+		}
+	}
+}
 
-			e.Frame(&ops)
-			scheduleNextFrame(e, g, layoutMoving)
-			debugFrame(g, wantsNextFrame(g, layoutMoving))
+// frame 处理一帧。调用方持有 uiMu。
+func (gw *gioWindow) frame(e app.FrameEvent, g *game, ops *op.Ops, last *time.Time) {
+	scale := e.Metric.PxPerDp
+	if scale < 1 {
+		scale = 1
+	}
+	if e.Size.X > 0 && (e.Size.X != g.w || e.Size.Y != g.h || scale != g.scale) {
+		g.scale = scale
+		g.w, g.h = e.Size.X, e.Size.Y
+		g.needsLayout = true
+	}
+	g.activate()
+	input = gw.in
+
+	// 排空本帧输入事件到本窗口的输入源，再驱动一帧（handleInput 经 input 读取）。
+	gw.in.resetFrame()
+	if gw.rec != nil {
+		src := &recordingSource{src: e.Source}
+		gw.in.process(src, gioKeyFilters(g))
+		gw.rec.frame(e.Now, g.w, g.h, scale, src.got)
+	} else {
+		gw.in.process(e.Source, gioKeyFilters(g))
+	}
+	// 按键先分发（OnKeyDown 冒泡、快捷键）：被消费或 PreventDefault 的按键不能再被
+	// 下面的输入法编辑打进输入框。之后 handleInput 里的 handleKeys 只剩内置处理要做。
+	if g.rootRN != nil {
+		if drop := g.dispatchKeys(); drop != nil {
+			gw.in.dropStrokes(drop)
 		}
+		gw.in.strokes = gw.in.strokes[:0] // 已分发过，别让 handleKeys 再发一遍
+	}
+	// 输入法的编辑按 Range 替换，先落到聚焦输入框上，再让引擎跑这一帧。
+	gw.ime.applyEdits(g, gw.in)
+	// 剪贴板同样先落地再跑帧：粘贴的文本本帧就能画出来，不用等下一帧。
+	gioClip.flush(e.Source, g)
+
+	// dt 用浮点毫秒：整数 Milliseconds() 会把高刷新率下的帧间隔截断（144Hz 的
+	// 6.9ms 变 6），帧间隔不足 1ms 时更会截成 0，而 tickAnims/tickLoops 遇 dt<=0
+	// 直接返回 —— 动画会卡住不动。
+	var dt float32
+	now := e.Now
+	if !last.IsZero() {
+		dt = float32(now.Sub(*last).Seconds() * 1000)
+	}
+	*last = now
+	layoutMoving := gioFrame(g, dt)
+
+	ops.Reset()
+	gpaint.ColorOp{Color: nrgba(canvasBg)}.Add(ops)
+	gpaint.PaintOp{}.Add(ops)
+	p := newGioPainter(ops, g.w, g.h)
+	for _, r := range g.paintRoots() {
+		paint(p, r)
 	}
+	g.paintInspector(p)
+	// 声明整窗为输入命中区（引擎自管内部焦点，这里整窗恒接收）。
+	area := clip.Rect{Max: e.Size}.Push(ops)
+	event.Op(ops, gioTag)
+	key.InputHintOp{Tag: gioTag, Hint: key.HintAny}.Add(ops)
+	gioCursor(g).Add(ops) // 文本框 I 型、可点击手型
+	area.Pop()
+	// 仅在尚未获得焦点时请求一次；每帧无脑请求会和 gio 的焦点管理打架。
+	if !gw.in.focused {
+		e.Source.Execute(key.FocusCmd{Tag: gioTag})
+	}
+	// 把聚焦输入框的光标位置与上下文文本发布给输入法（否则无法组字，只能打英文）。
+	if gw.in.snippetReq != nil {
+		gw.ime.handleSnippetReq(e.Source, g, *gw.in.snippetReq)
+	}
+	gw.ime.sync(e.Source, g)
+
+	e.Frame(ops)
+	scheduleNextFrame(e, g, layoutMoving)
+	debugFrame(g, wantsNextFrame(g, layoutMoving))
 }
 
 // caretBlinkPeriod 是光标闪烁的半周期，须与 caretVisible() 的判据一致。
@@ -146,7 +234,7 @@ const caretBlinkPeriod = 500 * time.Millisecond
 // wantsNextFrame 表示引擎还有事情要做、需要立刻再出一帧。
 func wantsNextFrame(g *game, layoutMoving bool) bool {
 	return g.needsLayout || len(g.dirty) > 0 || len(g.anims) > 0 || len(g.loops) > 0 ||
-		layoutMoving || g.inputSelecting || g.imeComposing
+		layoutMoving || g.inputSelecting || g.imeComposing || (g.win != nil && g.win.hasPosts())
 }
 
 func scheduleNextFrame(e app.FrameEvent, g *game, layoutMoving bool) {
@@ -170,9 +258,13 @@ func gioFrame(g *game, dt float32) (layoutMoving bool) {
 		g.needsLayout = true
 	} else {
 		drainPosts()
+		if g.win != nil {
+			g.win.drainPosts()
+		}
 		g.handleInput() // 读取 gioIn（指针/键盘/滚轮/编辑），分发命中/焦点/拖拽/文本编辑
 		g.tickAnims(dt)
 		g.tickLoops(dt)
+		g.syncMedia()
 		for guard := 0; len(g.dirty) > 0 && guard < 100; guard++ {
 			g.flushDirty()
 		}
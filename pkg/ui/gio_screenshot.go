@@ -8,10 +8,19 @@ import (
 	gpaint "gioui.org/op/paint"
 )
 
-// Screenshot 无头渲染一棵 UI 树并返回像素（不需要窗口）。用于像素级黄金测试与调试：
-// 与真实运行走同一条 gio 绘制路径（gioPainter），因此能复现真机上的渲染问题。
-// 需要可用的 GPU/驱动；不可用时返回 error。
-func Screenshot(root *Node, w, h int) (*image.RGBA, error) {
+// Screenshot 无头渲染一棵 UI 树并返回像素（不需要窗口）。用于像素级黄金测试与调试。
+//
+// 默认（GPUBackend）与真实运行走同一条 gio 绘制路径（gioPainter），因此能复现真机上的
+// 渲染问题；需要可用的 GPU/驱动，不可用时返回 error。传 SoftwareBackend 则改用纯 Go 的
+// CPU 光栅化（见 soft_paint.go），没有 GPU 的 CI 上也能跑：
+//
+//	img, err := ui.Screenshot(root, w, h, ui.SoftwareBackend)
+func Screenshot(root *Node, w, h int, backend ...Backend) (*image.RGBA, error) {
+	if len(backend) > 0 && backend[0] == SoftwareBackend {
+		hn := Mount(root, w, h)
+		defer hn.Window().Close() // 临时挂载的树截完即卸载，effect 的清理照常执行
+		return softScreenshot(hn.g)
+	}
 	win, err := headless.NewWindow(w, h)
 	if err != nil {
 		return nil, err
@@ -19,19 +28,15 @@ func Screenshot(root *Node, w, h int) (*image.RGBA, error) {
 	defer win.Release()
 
 	hn := Mount(root, w, h)
+	defer hn.Window().Close()
 	g := hn.g
 
 	var ops op.Ops
-	gpaint.ColorOp{Color: nrgba(Color{247, 248, 250, 255})}.Add(&ops)
+	gpaint.ColorOp{Color: nrgba(canvasBg)}.Add(&ops)
 	gpaint.PaintOp{}.Add(&ops)
 	p := newGioPainter(&ops, g.w, g.h)
-	if g.rootRN != nil {
-		paint(p, g.rootRN)
-	}
-	for _, pf := range g.portals {
-		if pf.overlayRoot != nil {
-			paint(p, pf.overlayRoot)
-		}
+	for _, r := range g.paintRoots() {
+		paint(p, r)
 	}
 	if err := win.Frame(&ops); err != nil {
 		return nil, err
@@ -8,7 +8,6 @@ import (
 	"gioui.org/font/opentype"
 	"gioui.org/op"
 	"gioui.org/op/clip"
-	gpaint "gioui.org/op/paint"
 	"gioui.org/text"
 	"golang.org/x/image/math/fixed"
 )
@@ -131,7 +130,8 @@ const fauxItalicShear = 0.21
 // drawGioText 把一行文本绘制到 ops：(x,y) 为该行左上角，基线落在 y+ascent。
 // fauxBold/fauxItalic 由调用方从 Metrics 取得：内置字体只有一张常规face，粗体与斜体
 // 必须在这里合成，否则 ui.Bold 会完全没有效果。
-func drawGioText(ops *op.Ops, f *gioFont, s string, c Color, x, y float32, fauxBold, fauxItalic bool) {
+// fill 在字形裁剪内铺上材质并 PaintOp；它拿到字形坐标到屏幕坐标的仿射，渐变据此回到屏幕空间。
+func drawGioText(ops *op.Ops, f *gioFont, s string, x, y float32, fauxBold, fauxItalic bool, fill func(aff f32.Affine2D)) {
 	if s == "" {
 		return
 	}
@@ -156,15 +156,14 @@ func drawGioText(ops *op.Ops, f *gioFont, s string, c Color, x, y float32, fauxB
 		aff = aff.Mul(f32.Affine2D{}.Shear(f32.Pt(0, 0), -fauxItalicShear, 0))
 	}
 	tr := op.Affine(aff).Push(ops)
-	gpaint.ColorOp{Color: nrgba(c)}.Add(ops)
 	path := sh.Shape(glyphs)
 	cl := clip.Outline{Path: path}.Op().Push(ops)
-	gpaint.PaintOp{}.Add(ops)
+	fill(aff)
 	cl.Pop()
 	if fauxBold {
 		// 在填充之外再描一圈轮廓：字形整体向外扩，即合成粗体
 		st := clip.Stroke{Path: path, Width: fauxBoldWidth(f.px)}.Op().Push(ops)
-		gpaint.PaintOp{}.Add(ops)
+		fill(aff)
 		st.Pop()
 	}
 	if call := sh.Bitmaps(glyphs); call != (op.CallOp{}) {
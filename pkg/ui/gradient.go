@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+// ---- 渐变：线性 / 径向 / 锥形，多色标 ----
+//
+// Gradient 描述一个渐变，可用作背景（BgGradient）、边框（BorderGradient）与文字（TextGradient）：
+//
+//	hero := ui.NewLinearGradient(135,
+//	    ui.Stop(0, ui.Hex("#6366f1")), ui.Stop(0.5, ui.Hex("#ec4899")), ui.Stop(1, ui.Hex("#f59e0b")))
+//	ui.Div(ui.Style(ui.BgGradient(hero), ui.Radius(16)), ...)
+//	ui.Div(ui.Style(ui.BgGradient(ui.NewRadialGradient(ui.Stop(0, ui.White), ui.Stop(1, ui.Blue)).At(0.3, 0.3))))
+//	ui.Text("Tenon", ui.TextGradient(hero))
+//
+// 坐标都相对元素的边框盒：角度 0 指向右、90 指向下（顺时针，与 LinearGradient 一致）；
+// 径向 / 锥形的圆心用占盒宽高的比例表示（默认 0.5,0.5 即正中）。
+
+// GradientKind 是渐变的种类。
+type GradientKind uint8
+
+const (
+	GradientLinear GradientKind = iota
+	GradientRadial
+	GradientConic
+)
+
+func (k GradientKind) String() string {
+	switch k {
+	case GradientRadial:
+		return "radial"
+	case GradientConic:
+		return "conic"
+	}
+	return "linear"
+}
+
+// ColorStop 是渐变上的一个色标：Offset 为 0..1 的位置。
+type ColorStop struct {
+	Offset float32
+	Color  Color
+}
+
+// Stop 构造一个色标。
+func Stop(offset float32, c Color) ColorStop { return ColorStop{Offset: offset, Color: c} }
+
+// Gradient 是一个渐变。用 NewLinearGradient / NewRadialGradient / NewConicGradient 构造。
+type Gradient struct {
+	Kind   GradientKind
+	Stops  []ColorStop // 按 Offset 升序
+	Angle  float32     // 线性：方向角；锥形：起始角（度）
+	CX, CY float32     // 径向 / 锥形的圆心（占盒宽高的比例）
+	Radius float32     // 径向半径（逻辑像素）；0 = 到最远的角
+}
+
+// NewLinearGradient 构造线性渐变：angleDeg 为方向角（0=左→右，90=上→下）。
+func NewLinearGradient(angleDeg float32, stops ...ColorStop) Gradient {
+	return Gradient{Kind: GradientLinear, Stops: stops, Angle: angleDeg, CX: 0.5, CY: 0.5}
+}
+
+// NewRadialGradient 构造从圆心向外的径向渐变（默认圆心在正中，半径到最远的角）。
+func NewRadialGradient(stops ...ColorStop) Gradient {
+	return Gradient{Kind: GradientRadial, Stops: stops, CX: 0.5, CY: 0.5}
+}
+
+// NewConicGradient 构造绕圆心一周的锥形渐变，fromDeg 为 Offset 0 所在的角度。
+func NewConicGradient(fromDeg float32, stops ...ColorStop) Gradient {
+	return Gradient{Kind: GradientConic, Stops: stops, Angle: fromDeg, CX: 0.5, CY: 0.5}
+}
+
+// At 返回把圆心移到 (cx, cy) 的副本（占盒宽高的比例）。
+func (g Gradient) At(cx, cy float32) Gradient { g.CX, g.CY = cx, cy; return g }
+
+// WithRadius 返回径向半径为 r（逻辑像素）的副本。
+func (g Gradient) WithRadius(r float32) Gradient { g.Radius = r; return g }
+
+// String 返回类似 CSS 的描述，便于测试断言与调试输出。
+func (g Gradient) String() string {
+	var b strings.Builder
+	b.WriteString(g.Kind.String())
+	b.WriteString("(")
+	switch g.Kind {
+	case GradientLinear:
+		fmt.Fprintf(&b, "%gdeg", g.Angle)
+	case GradientRadial:
+		fmt.Fprintf(&b, "at %g %g r=%g", g.CX, g.CY, g.Radius)
+	case GradientConic:
+		fmt.Fprintf(&b, "from %gdeg at %g %g", g.Angle, g.CX, g.CY)
+	}
+	for _, s := range g.Stops {
+		fmt.Fprintf(&b, ", #%02x%02x%02x%02x %g", s.Color.R, s.Color.G, s.Color.B, s.Color.A, s.Offset)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// BgGradient 用渐变作为背景填充（遵循圆角）。
+func BgGradient(g Gradient) StyleOpt {
+	return func(s *StyleProps) { s.bgGrad = &g }
+}
+
+// BorderGradient 用渐变描边框；宽度仍由 Border 决定（Border 的颜色被渐变取代）。
+func BorderGradient(g Gradient) StyleOpt {
+	return func(s *StyleProps) { s.borderGrad = &g }
+}
+
+// TextGradient 用渐变填充文字，渐变铺满文本节点的盒。放在容器上时由其中的文字继承
+// （显式 TextColor 的文字除外）。
+func TextGradient(g Gradient) StyleOpt {
+	return func(s *StyleProps) { s.textGrad = &g }
+}
+
+// twoStopLinear 报告 g 是否是后端可以原生绘制的两色线性渐变。
+func (g *Gradient) twoStopLinear() bool {
+	return g.Kind == GradientLinear && len(g.Stops) == 2 && g.Stops[0].Offset == 0 && g.Stops[1].Offset == 1
+}
+
+// scaled 返回半径换算到物理像素后的副本。
+func (g *Gradient) scaled(k float32) *Gradient {
+	if g == nil {
+		return nil
+	}
+	c := *g
+	c.Radius *= k
+	return &c
+}
+
+// linearSpan 返回线性渐变在 w×h 盒上的起止点（相对盒左上角）：过盒心、沿方向角，
+// 长度取盒在该方向上的投影，两端正好落在最远的角上（同 CSS）。
+func (g *Gradient) linearSpan(w, h float32) (x0, y0, x1, y1 float32) {
+	rad := float64(g.Angle) * math.Pi / 180
+	dx, dy := float32(math.Cos(rad)), float32(math.Sin(rad))
+	half := (absf(w*dx) + absf(h*dy)) / 2
+	cx, cy := w/2, h/2
+	return cx - dx*half, cy - dy*half, cx + dx*half, cy + dy*half
+}
+
+// radialRadius 是径向渐变在 w×h 盒上的实际半径：Radius 为 0 时到最远的角。
+func (g *Gradient) radialRadius(w, h float32) float32 {
+	if g.Radius > 0 {
+		return g.Radius
+	}
+	cx, cy := g.CX*w, g.CY*h
+	return float32(math.Hypot(float64(max(cx, w-cx)), float64(max(cy, h-cy))))
+}
+
+// offsetAt 返回盒内点 (x, y)（相对盒左上角）在渐变上的位置 t。
+func (g *Gradient) offsetAt(x, y, w, h float32) float32 {
+	switch g.Kind {
+	case GradientRadial:
+		cx, cy := g.CX*w, g.CY*h
+		r := g.radialRadius(w, h)
+		if r <= 0 {
+			return 0
+		}
+		return float32(math.Hypot(float64(x-cx), float64(y-cy))) / r
+	case GradientConic:
+		a := math.Atan2(float64(y-g.CY*h), float64(x-g.CX*w))*180/math.Pi - float64(g.Angle)
+		a = math.Mod(a, 360)
+		if a < 0 {
+			a += 360
+		}
+		return float32(a / 360)
+	}
+	x0, y0, x1, y1 := g.linearSpan(w, h)
+	dx, dy := x1-x0, y1-y0
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return 0
+	}
+	return ((x-x0)*dx + (y-y0)*dy) / l2
+}
+
+// colorAt 返回位置 t 处的颜色：在相邻色标间线性插值，两端之外取端点色。
+func (g *Gradient) colorAt(t float32) Color {
+	st := g.Stops
+	if len(st) == 0 {
+		return Color{}
+	}
+	if t <= st[0].Offset {
+		return st[0].Color
+	}
+	for i := 1; i < len(st); i++ {
+		if t <= st[i].Offset {
+			a, b := st[i-1], st[i]
+			if b.Offset <= a.Offset {
+				return b.Color
+			}
+			return Mix(a.Color, b.Color, (t-a.Offset)/(b.Offset-a.Offset))
+		}
+	}
+	return st[len(st)-1].Color
+}
+
+// raster 把渐变按 w×h 像素栅格化（像素中心采样），供没有原生渐变的后端当贴图用。
+func (g *Gradient) raster(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := g.colorAt(g.offsetAt(float32(x)+0.5, float32(y)+0.5, float32(w), float32(h)))
+			i := img.PixOffset(x, y)
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	return img
+}
+
+// alpha 返回所有色标乘上透明度 o 后的副本（元素 Opacity）。
+func (g *Gradient) alpha(o float32) *Gradient {
+	if o >= 1 {
+		return g
+	}
+	c := *g
+	c.Stops = make([]ColorStop, len(g.Stops))
+	for i, s := range g.Stops {
+		c.Stops[i] = ColorStop{s.Offset, s.Color.Alpha(o)}
+	}
+	return &c
+}
@@ -0,0 +1,89 @@
+package ui
+
+import "testing"
+
+// 多色标按相邻色标插值，两端之外取端点色。
+func TestGradientColorAtMultiStop(t *testing.T) {
+	g := NewLinearGradient(0, Stop(0, Hex("#ff0000")), Stop(0.5, Hex("#00ff00")), Stop(1, Hex("#0000ff")))
+	for _, c := range []struct {
+		t    float32
+		want Color
+	}{
+		{-1, Hex("#ff0000")},
+		{0.5, Hex("#00ff00")},
+		{0.75, Mix(Hex("#00ff00"), Hex("#0000ff"), 0.5)},
+		{2, Hex("#0000ff")},
+	} {
+		if got := g.colorAt(c.t); got != c.want {
+			t.Errorf("colorAt(%v) = %v, want %v", c.t, got, c.want)
+		}
+	}
+}
+
+// 径向从圆心向外、锥形从起始角顺时针一周。
+func TestGradientRadialConicOffsets(t *testing.T) {
+	r := NewRadialGradient(Stop(0, White), Stop(1, Black)).WithRadius(50)
+	if got := r.offsetAt(50, 50, 100, 100); got != 0 {
+		t.Fatalf("圆心处 t = %v", got)
+	}
+	if got := r.offsetAt(75, 50, 100, 100); absf(got-0.5) > 1e-4 {
+		t.Fatalf("半径一半处 t = %v", got)
+	}
+	c := NewConicGradient(0, Stop(0, White), Stop(1, Black))
+	if got := c.offsetAt(50, 100, 100, 100); absf(got-0.25) > 1e-4 {
+		t.Fatalf("正下方应为 90°（t=0.25），实际 %v", got)
+	}
+	c = NewConicGradient(90, Stop(0, White), Stop(1, Black))
+	if got := c.offsetAt(50, 100, 100, 100); absf(got) > 1e-4 {
+		t.Fatalf("起始角 90° 时正下方 t = %v", got)
+	}
+}
+
+// 背景、边框、文字渐变都记录为带完整色标的绘制指令；文字渐变由容器继承，显式 TextColor 除外。
+func TestGradientPaintOps(t *testing.T) {
+	bg := NewRadialGradient(Stop(0, White), Stop(0.4, Hex("#ec4899")), Stop(1, Black)).At(0.3, 0.3)
+	border := NewConicGradient(45, Stop(0, Hex("#ff0000")), Stop(1, Hex("#0000ff")))
+	txt := NewLinearGradient(90, Stop(0, Hex("#6366f1")), Stop(1, Hex("#f59e0b")))
+	h := Mount(Div(Style(Width(200), Height(100), Border(2, Black), BgGradient(bg), BorderGradient(border), TextGradient(txt)),
+		Text("inherit"),
+		Text("plain", TextColor(Black)),
+	), 200, 100)
+	var got = map[string]*Gradient{}
+	var texts = map[string]string{}
+	for _, op := range h.Paint() {
+		switch op.Kind {
+		case "gradient", "strokegradient":
+			got[op.Kind] = op.Gradient
+		case "textgradient":
+			texts[op.Text] = op.Gradient.String()
+		case "text":
+			texts[op.Text] = "color"
+		}
+	}
+	if g := got["gradient"]; g == nil || g.String() != bg.String() {
+		t.Fatalf("背景渐变 = %v，应为 %v", g, bg)
+	}
+	if g := got["strokegradient"]; g == nil || g.String() != border.String() {
+		t.Fatalf("边框渐变 = %v，应为 %v", g, border)
+	}
+	if texts["inherit"] != txt.String() {
+		t.Fatalf("继承的文字渐变 = %q", texts["inherit"])
+	}
+	if texts["plain"] != "color" {
+		t.Fatalf("显式 TextColor 不应被渐变取代：%q", texts["plain"])
+	}
+}
+
+// LinearGradient 仍是两色线性渐变的简写。
+func TestLinearGradientShorthand(t *testing.T) {
+	h := Mount(Div(Style(Width(100), Height(40), LinearGradient(Hex("#ff0000"), Hex("#0000ff"), 45))), 100, 40)
+	for _, op := range h.Paint() {
+		if op.Kind == "gradient" {
+			if want := "linear(45deg, #ff0000ff 0, #0000ffff 1)"; op.Gradient.String() != want {
+				t.Fatalf("gradient = %s, want %s", op.Gradient, want)
+			}
+			return
+		}
+	}
+	t.Fatal("没有记录 gradient 指令")
+}
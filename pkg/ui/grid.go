@@ -0,0 +1,153 @@
+package ui
+
+import "github.com/sjm1327605995/tenon/yoga"
+
+// ---- 网格布局（CSS Grid） ----
+//
+// Grid 把容器切成行列轨道，子元素按线定位或自动排布 —— 表单的「标签 | 输入框」对齐、
+// 仪表盘卡片这类二维布局不必再用嵌套 Row + 固定宽度去凑。算法在 yoga/grid.go。
+//
+//	ui.Div(ui.Style(ui.Grid, ui.GridCols(ui.Px(120), ui.Fr(1)), ui.Gap(8)),
+//	    ui.Text("用户名"), ui.Input(...),
+//	    ui.Text("备注"), ui.Input(...),
+//	    ui.Div(ui.Style(ui.GridCol(1, -1)), ui.Button(...)), // 占满整行
+//	)
+//
+// 轨道：Px 固定、Pct 百分比、Fr 按比例分剩余空间、Auto 随内容、MinMax(min, max)。
+// 对齐：ItemsStart/Center/End 控制格子内的纵向位置（默认拉伸）；JustifyCenter 等在列
+// 总宽小于容器时分配多余空间，ContentCenter 等对行同理。默认时 Auto 轨道会拉伸填满。
+
+// Track 是一条网格轨道的尺寸（见 GridCols / GridRows）。
+type Track struct{ t yoga.GridTrack }
+
+// Px 是固定宽度（逻辑像素）的轨道。
+func Px(v float32) Track { return Track{yoga.GridTrackPoints(v)} }
+
+// Pct 是占网格内容区百分比的轨道。
+func Pct(v float32) Track { return Track{yoga.GridTrackPercent(v)} }
+
+// Fr 按比例分配剩余空间，但不小于内容的最小宽度（同 CSS 的 minmax(auto, Nfr)）。
+func Fr(v float32) Track { return Track{yoga.GridTrackFr(v)} }
+
+// Auto 是随内容伸缩的轨道。
+func Auto() Track { return Track{} }
+
+// MinMax 取 min 的下限与 max 的上限，如 MinMax(Px(100), Fr(1))。
+func MinMax(min, max Track) Track { return Track{yoga.GridTrackMinMax(min.t.Min, max.t.Max)} }
+
+// scaled 把固定尺寸换算到物理像素。
+func (t Track) scaled(k float32) yoga.GridTrack {
+	g := t.t
+	if g.Min.Unit == yoga.GridUnitPoint {
+		g.Min.Value *= k
+	}
+	if g.Max.Unit == yoga.GridUnitPoint {
+		g.Max.Value *= k
+	}
+	return g
+}
+
+func scaledTracks(ts []Track, k float32) []yoga.GridTrack {
+	out := make([]yoga.GridTrack, len(ts))
+	for i, t := range ts {
+		out[i] = t.scaled(k)
+	}
+	return out
+}
+
+// Grid 让元素以网格排布子元素。
+func Grid(s *StyleProps) { s.grid = true }
+
+// GridCols / GridRows 设置显式的列 / 行轨道。超出的子元素落在隐式轨道上（见 GridAutoRows）。
+func GridCols(tracks ...Track) StyleOpt { return func(s *StyleProps) { s.gridCols = tracks } }
+func GridRows(tracks ...Track) StyleOpt { return func(s *StyleProps) { s.gridRows = tracks } }
+
+// GridAutoCols / GridAutoRows 设置隐式列 / 行的尺寸（默认 Auto）。
+func GridAutoCols(t Track) StyleOpt { return func(s *StyleProps) { s.gridAutoCols = t } }
+func GridAutoRows(t Track) StyleOpt { return func(s *StyleProps) { s.gridAutoRows = t } }
+
+// GridFlowColumn 让自动排布先填满一列再换列（默认按行）。
+func GridFlowColumn(s *StyleProps) { s.gridFlowCol = true }
+
+// GridDense 让自动排布回填前面留下的空格。
+func GridDense(s *StyleProps) { s.gridDense = true }
+
+// RowGap / ColGap 单独设置行间距 / 列间距（覆盖 Gap）。
+func RowGap(v float32) StyleOpt { return func(s *StyleProps) { s.rowGap = v } }
+func ColGap(v float32) StyleOpt { return func(s *StyleProps) { s.colGap = v } }
+
+// gridLine 把线号换成 yoga 的表示：0 为 auto，负数从显式网格末尾数起（-1 为最后一条线）。
+func gridLine(n int) yoga.GridLine {
+	if n == 0 {
+		return yoga.GridLineAuto()
+	}
+	return yoga.GridLineAt(n)
+}
+
+// GridCol 按列线放置：从第 start 条线到第 end 条线（线从 1 开始，-1 为最后一条）。
+// end 为 0 时只占一列。GridCol(1, -1) 占满所有显式列。
+func GridCol(start, end int) StyleOpt {
+	return func(s *StyleProps) { s.gridColStart, s.gridColEnd = gridLine(start), gridLine(end) }
+}
+
+// GridRow 按行线放置，规则同 GridCol。
+func GridRow(start, end int) StyleOpt {
+	return func(s *StyleProps) { s.gridRowStart, s.gridRowEnd = gridLine(start), gridLine(end) }
+}
+
+// GridArea 一次给出四条线，顺序同 CSS grid-area：行起、列起、行止、列止（0 为 auto）。
+func GridArea(rowStart, colStart, rowEnd, colEnd int) StyleOpt {
+	return Styles(GridRow(rowStart, rowEnd), GridCol(colStart, colEnd))
+}
+
+// ColSpan 让元素跨 n 列；未指定列线时由自动排布决定位置。
+func ColSpan(n int) StyleOpt {
+	return func(s *StyleProps) {
+		if s.gridColStart.Span || s.gridColStart == (yoga.GridLine{}) {
+			s.gridColStart = yoga.GridLineSpan(n)
+		} else {
+			s.gridColEnd = yoga.GridLineSpan(n)
+		}
+	}
+}
+
+// RowSpan 让元素跨 n 行。
+func RowSpan(n int) StyleOpt {
+	return func(s *StyleProps) {
+		if s.gridRowStart.Span || s.gridRowStart == (yoga.GridLine{}) {
+			s.gridRowStart = yoga.GridLineSpan(n)
+		} else {
+			s.gridRowEnd = yoga.GridLineSpan(n)
+		}
+	}
+}
+
+// syncGrid 把网格相关样式写入 yoga 节点。
+func syncGrid(yn *yoga.Node, s StyleProps, k float32) {
+	if s.grid {
+		yn.StyleSetDisplay(yoga.DisplayGrid)
+	} else {
+		yn.StyleSetDisplay(yoga.DisplayFlex)
+	}
+	yn.StyleSetGap(yoga.GutterRow, s.rowGap*k)
+	yn.StyleSetGap(yoga.GutterColumn, s.colGap*k)
+	yn.StyleSetGridColumn(s.gridColStart, s.gridColEnd)
+	yn.StyleSetGridRow(s.gridRowStart, s.gridRowEnd)
+	if !s.grid {
+		return
+	}
+	yn.StyleSetGridTemplateColumns(scaledTracks(s.gridCols, k)...)
+	yn.StyleSetGridTemplateRows(scaledTracks(s.gridRows, k)...)
+	yn.StyleSetGridAutoColumns(s.gridAutoCols.scaled(k))
+	yn.StyleSetGridAutoRows(s.gridAutoRows.scaled(k))
+	flow := yoga.GridAutoFlowRow
+	switch {
+	case s.gridFlowCol && s.gridDense:
+		flow = yoga.GridAutoFlowColumnDense
+	case s.gridFlowCol:
+		flow = yoga.GridAutoFlowColumn
+	case s.gridDense:
+		flow = yoga.GridAutoFlowRowDense
+	}
+	yn.StyleSetGridAutoFlow(flow)
+}
@@ -0,0 +1,77 @@
+package ui
+
+import "testing"
+
+// 典型表单：标签列固定、输入列吃剩余宽度，每行的输入框左缘对齐。
+func TestGridFormColumnsAlign(t *testing.T) {
+	h := Mount(Div(Style(Grid, GridCols(Px(80), Fr(1)), Gap(10), Width(300)),
+		Text("name"), Input(Placeholder("n")),
+		Text("a longer label"), Input(Placeholder("m")),
+	), 300, 200)
+
+	a, b := h.Root().ByPlaceholder("n").Bounds(), h.Root().ByPlaceholder("m").Bounds()
+	if a.X != 90 || b.X != 90 || a.W != 210 || b.W != 210 {
+		t.Fatalf("输入框 %+v / %+v 应都从 x=90 起、宽 210", a, b)
+	}
+	if b.Y <= a.Y {
+		t.Fatalf("第二行 y=%v 应在第一行 y=%v 之下", b.Y, a.Y)
+	}
+	if l := h.Root().ByText("a longer label").Bounds(); l.X != 0 || l.W != 80 {
+		t.Fatalf("标签 %+v 应落在 80 宽的第一列里（超长就折行）", l)
+	}
+}
+
+// GridArea / GridCol(1,-1) / ColSpan：按线定位与跨列。
+func TestGridAreaAndSpan(t *testing.T) {
+	cell := func(label string, opts ...StyleOpt) *Node {
+		return Div(Style(opts...), Text(label))
+	}
+	// 外层是普通的列容器，网格高度随内容；隐式行固定 20，side 不设高度、拉伸到两行
+	h := Mount(Div(Div(Style(Grid, GridCols(Fr(1), Fr(1), Fr(1)), GridAutoRows(Px(20)), Width(300)),
+		cell("header", GridCol(1, -1)),
+		cell("side", GridArea(2, 3, 4, 0)),
+		cell("wide", ColSpan(2)),
+		cell("a"), cell("b"),
+	)), 300, 200)
+
+	order := map[string]int{"header": 0, "side": 1, "wide": 2, "a": 3, "b": 4}
+	box := func(label string) Rect { return h.Root().Child(0).Child(order[label]).Bounds() }
+	if b := box("header"); b.X != 0 || b.W != 300 {
+		t.Fatalf("header %+v 应占满整行", b)
+	}
+	if b := box("side"); b.X != 200 || b.Y != 20 || b.H != 40 {
+		t.Fatalf("side %+v 应在第 3 列、跨第 2~3 行", b)
+	}
+	if b := box("wide"); b.X != 0 || b.W != 200 || b.Y != 20 {
+		t.Fatalf("wide %+v 应跨前两列", b)
+	}
+	if a, b := box("a"), box("b"); a.Y != 40 || b.Y != 40 || b.X != 100 {
+		t.Fatalf("a %+v b %+v 应排在第 3 行的前两列（第 3 列被 side 占着）", a, b)
+	}
+}
+
+// Fr 列里的长文本在列宽内折行，而不是把网格撑宽。
+func TestGridFrColumnWrapsText(t *testing.T) {
+	long := "one two three four five six seven eight nine ten"
+	h := Mount(Div(Style(Grid, GridCols(Fr(1), Fr(1)), Width(200)),
+		Text(long), Text("x"),
+	), 200, 300)
+	tb := h.Root().ByText(long).Bounds()
+	if tb.W != 100 {
+		t.Fatalf("长文本列宽 %v want 100", tb.W)
+	}
+	if x := h.Root().ByText("x").Bounds(); x.X != 100 || x.Y != tb.Y {
+		t.Fatalf("第二列 %+v 应与长文本同行、从 x=100 起", x)
+	}
+	if tb.H <= 30 {
+		t.Fatalf("长文本高 %v，应折成多行", tb.H)
+	}
+}
+
+// 最小内容宽度只在网格轨道里用：普通 flex 容器宽 0、子项不拉伸时，文字照常不折行。
+func TestFlexZeroWidthTextNotMinContent(t *testing.T) {
+	h := Mount(Div(Style(Column, ItemsStart, Width(0)), Text("hello world")), 200, 100)
+	if b := h.Root().ByText("hello world").Bounds(); b.H > 25 {
+		t.Fatalf("文字高 %v，不应按最小内容折成多行", b.H)
+	}
+}
@@ -1,6 +1,9 @@
 package ui
 
-import "unicode/utf8"
+import (
+	"image"
+	"unicode/utf8"
+)
 
 // Headless test harness — mount a component tree and drive real interactions
 // (click / hover / press / drag / keyboard-style text input) without opening an
@@ -26,7 +29,8 @@ import "unicode/utf8"
 
 // Harness drives a mounted component tree for tests. Not safe for concurrent use.
 type Harness struct {
-	g *game
+	g  *game
+	in *gioInput // 输入源：光标、按住的按钮与键跨 Dispatch 保留
 }
 
 // Mount reconciles root into a virtual w×h window and settles it (layout +
@@ -39,11 +43,19 @@ func Mount(root *Node, w, h int) *Harness {
 	if h <= 0 {
 		h = 600
 	}
+	a := &App{headless: true}
+	return a.openWindow(root, windowConfig{w: w, h: h, title: "Tenon UI"}).h
+}
+
+// mountHeadless reconciles a headless window's root in place and settles it.
+// The window becomes the active one, like a real window whose frame just ran.
+func mountHeadless(w *Window) *Harness {
 	uiScale = 1
-	g := &game{root: root, w: w, h: h}
-	activeGame = g
-	g.rootFiber = reconcile(nil, nil, root)
+	g := w.g
+	g.activate()
+	g.rootFiber = reconcile(nil, nil, g.root)
 	hn := &Harness{g: g}
+	w.h = hn
 	hn.settle()
 	return hn
 }
@@ -51,6 +63,76 @@ func Mount(root *Node, w, h int) *Harness {
 // MountDefault mounts root in a default 800×600 window.
 func MountDefault(root *Node) *Harness { return Mount(root, 800, 600) }
 
+// Window returns the headless window this harness drives. Every mounted tree
+// has one, so components using UseWindow work under test.
+func (h *Harness) Window() *Window { return h.g.win }
+
+// OpenWindow opens another headless window in the same App and returns its
+// harness. Windows share nothing but the App: each has its own fiber root,
+// Portal overlays, focus, Esc stack and hover chain. Size defaults to 800×600.
+func (h *Harness) OpenWindow(root *Node, opts ...WindowOpt) *Harness {
+	w := h.g.win.app.OpenWindow(root, opts...)
+	h.g.activate()
+	return w.h
+}
+
+// Windows returns a harness per open window of the App, in opening order —
+// including windows opened by components via UseWindow().App().OpenWindow.
+func (h *Harness) Windows() []*Harness {
+	var out []*Harness
+	for _, w := range h.g.win.app.Windows() {
+		out = append(out, w.h)
+	}
+	return out
+}
+
+// Flush runs everything queued with Post and Window.Post (as the next frame
+// would), then settles. Use it after code that posts from a goroutine.
+func (h *Harness) Flush() {
+	drainPosts()
+	for _, w := range h.g.win.app.Windows() {
+		if w.h == nil {
+			continue
+		}
+		w.g.activate()
+		w.drainPosts()
+	}
+	h.settle()
+}
+
+// WaitAsync blocks until every in-flight UseAsync task of the App has returned,
+// applies their results (Flush) and repeats while settling starts new ones — so
+// a test sees the final loaded state deterministically. To observe the pending
+// state instead, have fn block on a channel the test controls and assert before
+// releasing it. fn must return (or honor ctx) or WaitAsync never returns.
+func (h *Harness) WaitAsync() {
+	for i := 0; i < 100; i++ {
+		for _, w := range h.g.win.app.Windows() {
+			w.g.async.Wait()
+		}
+		if !h.hasPosts() {
+			return
+		}
+		h.Flush()
+	}
+}
+
+// hasPosts reports whether anything is queued with Post or Window.Post.
+func (h *Harness) hasPosts() bool {
+	postMu.Lock()
+	n := len(postFns)
+	postMu.Unlock()
+	if n > 0 {
+		return true
+	}
+	for _, w := range h.g.win.app.Windows() {
+		if w.hasPosts() {
+			return true
+		}
+	}
+	return false
+}
+
 // Root returns a Query for the root render node.
 func (h *Harness) Root() *Query {
 	return &Query{rn: rootRenderNode(h.g.rootFiber), h: h}
@@ -70,9 +152,18 @@ func (h *Harness) Paint() []PaintOp {
 			paint(rp, pf.overlayRoot)
 		}
 	}
+	h.g.paintInspector(rp)
 	return rp.ops
 }
 
+// Screenshot renders the current frame — main tree, Portal overlays and the
+// inspector highlight, exactly what Paint records — into pixels with the
+// software backend. It needs no GPU, so pixel assertions run on any CI runner;
+// results match the GPU path in geometry and differ only in anti-aliasing.
+func (h *Harness) Screenshot() (*image.RGBA, error) {
+	return softScreenshot(h.g)
+}
+
 // Overlays returns a Query per live Portal overlay (dialogs, dropdowns,
 // tooltips…), in paint order (last is topmost). Portals live outside the main
 // tree, so Root().Find does not reach them — search these instead.
@@ -86,6 +177,21 @@ func (h *Harness) Overlays() []*Query {
 	return out
 }
 
+// AccessibilityTree returns a snapshot of the window's accessibility tree:
+// roles, names, states, bounds and Tab order, as a screen reader would see it.
+func (h *Harness) AccessibilityTree() *AccessNode { return h.g.accessibilityTree() }
+
+// ByRole returns a Query for the first node with the given accessibility role
+// and name (any name if name is ""), so tests can act on semantics —
+// h.ByRole(ui.RoleCheckbox, "Remember me").Click() — instead of on text or
+// structure. Empty Query on a miss.
+func (h *Harness) ByRole(role AriaRole, name string) *Query {
+	if n := h.AccessibilityTree().Find(role, name); n != nil {
+		return &Query{rn: n.rn, h: h}
+	}
+	return &Query{h: h}
+}
+
 // Size reports the current virtual window size in logical pixels.
 func (h *Harness) Size() (w, hgt int) { return h.g.w, h.g.h }
 
@@ -123,7 +229,8 @@ func (h *Harness) ClickAt(x, y float32) bool {
 }
 
 // MouseDown replays a full left mousedown at a screen point (logical px): it
-// does focus management (focus the input under the point, else clear focus) AND
+// does focus management (focus the input or OnKeyDown element under the point,
+// else clear focus) AND
 // fires the nearest onClick up the chain — the real engine does both in one
 // press, so tests that split them (ClickAt only clicks) can miss ordering bugs.
 // Returns a Query for the focused node afterward (empty if focus cleared).
@@ -134,6 +241,8 @@ func (h *Harness) MouseDown(x, y float32) *Query {
 		h.g.focusedFiber = n.owner
 		n.caretPos = n.caretAt(x, y)
 		n.selAnchor = n.caretPos
+	} else if t := keyTarget(n); t != nil {
+		h.g.focusedFiber = t.owner
 	} else {
 		h.g.focusedFiber = nil
 	}
@@ -169,6 +278,15 @@ func (h *Harness) Focused() *Query {
 	return &Query{rn: h.g.focusedFiber.rnode, h: h}
 }
 
+// SelectedText returns the current Selectable text selection (what Ctrl+C
+// would copy), or "" when nothing is selected.
+func (h *Harness) SelectedText() string {
+	if h.g.textSel == nil {
+		return ""
+	}
+	return h.g.textSel.text()
+}
+
 // Tab moves focus to the next focusable element (Tab key), wrapping around, and
 // returns the newly focused node. Focusables are buttons and inputs, in tree
 // order, including Portal overlays.
@@ -218,12 +336,7 @@ func (h *Harness) Enter() bool {
 }
 
 // focusedRN 返回当前聚焦节点的 renderNode（无焦点时为 nil）。
-func (h *Harness) focusedRN() *renderNode {
-	if h.g.focusedFiber == nil || h.g.focusedFiber.unmounted {
-		return nil
-	}
-	return h.g.focusedFiber.rnode
-}
+func (h *Harness) focusedRN() *renderNode { return h.g.focusedRNode() }
 
 // Escape fires the Esc action: the topmost UseEscape handler if any is active,
 // otherwise it clears focus. Settles afterward.
@@ -234,9 +347,28 @@ func (h *Harness) Escape() {
 
 // settle drives the tree to a fixed point: drain the re-render queue, lay out,
 // run pending effects, and repeat until nothing is dirty and no effects remain.
+// Other windows of the same App are settled too (a handler here may have set
+// state over there), and h's window is left active.
 func (h *Harness) settle() {
+	h.settleOne()
+	for _, w := range h.g.win.app.Windows() {
+		if w.g != h.g && w.h != nil {
+			w.h.settleOne()
+		}
+	}
+	h.g.activate()
+}
+
+func (h *Harness) settleOne() {
 	g := h.g
+	if g.win != nil && g.win.closed.Load() {
+		return
+	}
+	g.activate()
 	for i := 0; i < 100; i++ {
+		g.syncFocus()
+		g.syncSheetStates()
+		g.syncMedia()
 		for guard := 0; len(g.dirty) > 0 && guard < 100; guard++ {
 			g.flushDirty()
 		}
@@ -298,6 +430,27 @@ func (q *Query) Value() string {
 	return ""
 }
 
+// Selection returns an input's selected text, or "" when the selection is
+// empty or this is not an input.
+func (q *Query) Selection() string {
+	if !q.Exists() || q.rn.kind != rnInput {
+		return ""
+	}
+	val := q.rn.value
+	a, c := clampi(q.rn.selAnchor, 0, len(val)), clampi(q.rn.caretPos, 0, len(val))
+	return val[min(a, c):max(a, c)]
+}
+
+// Preedit returns the part of an input's value the IME is still composing
+// (underlined, not yet committed), or "" when not composing.
+func (q *Query) Preedit() string {
+	if !q.Exists() || q.rn.kind != rnInput || q.rn.composeHi <= q.rn.composeLo {
+		return ""
+	}
+	val := q.rn.value
+	return val[clampi(q.rn.composeLo, 0, len(val)):clampi(q.rn.composeHi, 0, len(val))]
+}
+
 // Bounds returns the node's absolute layout rectangle (logical px).
 func (q *Query) Bounds() Rect {
 	if !q.Exists() {
@@ -306,6 +459,29 @@ func (q *Query) Bounds() Rect {
 	return q.rn.bounds
 }
 
+// Role returns the node's effective accessibility role (declared via Role, or
+// inferred: input → textbox, clickable → button…); "" for plain containers.
+func (q *Query) Role() AriaRole {
+	if !q.Exists() {
+		return RoleNone
+	}
+	return implicitRole(q.rn)
+}
+
+// AccessibleName returns the name a screen reader would announce for the node.
+func (q *Query) AccessibleName() string {
+	if !q.Exists() {
+		return ""
+	}
+	root := &AccessNode{}
+	b := a11yBuilder{g: q.h.g, ids: map[string]*renderNode{}}
+	b.add(root, q.rn)
+	if len(root.Children) == 0 || root.Children[0].rn != q.rn {
+		return ""
+	}
+	return root.Children[0].Name
+}
+
 // Focusable reports whether the node participates in Tab focus navigation.
 func (q *Query) Focusable() bool { return q.Exists() && q.rn.focusable }
 
@@ -371,6 +547,9 @@ func (q *Query) walk(fn func(*renderNode)) {
 	}
 	var rec func(*renderNode)
 	rec = func(n *renderNode) {
+		if n.hidden {
+			return // 隐藏的子树（如挂起中的 Suspense 内容）对用户不可见，查询也找不到
+		}
 		fn(n)
 		for _, c := range n.children {
 			rec(c)
@@ -380,11 +559,14 @@ func (q *Query) walk(fn func(*renderNode)) {
 }
 
 // Find returns the first node in this subtree (self included, depth-first) that
-// matches pred, or an empty Query.
+// matches pred, or an empty Query. Like FindAll it skips hidden subtrees.
 func (q *Query) Find(pred func(*Query) bool) *Query {
 	var found *renderNode
 	var rec func(*renderNode) bool
 	rec = func(n *renderNode) bool {
+		if n.hidden {
+			return false
+		}
 		if pred(&Query{rn: n, h: q.h}) {
 			found = n
 			return true
@@ -509,8 +691,8 @@ func (q *Query) Drag(dx, dy float32) bool {
 	return false
 }
 
-// Focus makes this node the focused element (as a click on an input would).
-// Returns the same Query for chaining.
+// Focus makes this node the focused element (as a click on an input would),
+// firing OnBlur/OnFocus. Returns the same Query for chaining.
 func (q *Query) Focus() *Query {
 	if q.Exists() && q.rn.owner != nil {
 		q.h.g.focusedFiber = q.rn.owner
@@ -518,6 +700,7 @@ func (q *Query) Focus() *Query {
 			q.rn.caretPos = len(q.rn.value)
 			q.rn.selAnchor = q.rn.caretPos
 		}
+		q.h.settle()
 	}
 	return q
 }
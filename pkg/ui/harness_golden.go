@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// updateGolden 报告是否重写黄金图/快照而不是比对：环境变量 TENON_UPDATE_GOLDEN
+// 非空且不为 "0"，或测试包自己声明的 -update 布尔参数为 true。pkg/ui 不注册命令行参数，
+// 以免与引入它的包各自的 -update 冲突（flag redefined）。
+func updateGolden() bool {
+	if v := os.Getenv("TENON_UPDATE_GOLDEN"); v != "" && v != "0" {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			on, _ := g.Get().(bool)
+			return on
+		}
+	}
+	return false
+}
+
+// GoldenOpt configures Harness.AssertGolden.
+type GoldenOpt func(*goldenConfig)
+
+type goldenConfig struct {
+	tolerance uint8  // 每个通道允许的最大差值
+	maxDiff   int    // 允许超出容差的像素数
+	dir       string // 黄金图目录
+}
+
+// GoldenTolerance sets the per-channel difference (0–255) under which two
+// pixels still count as equal. The default, 2, absorbs floating-point rounding
+// differences between CPU architectures.
+func GoldenTolerance(perChannel uint8) GoldenOpt {
+	return func(c *goldenConfig) { c.tolerance = perChannel }
+}
+
+// GoldenMaxDiff lets up to n pixels exceed the tolerance before the assertion
+// fails. The default is 0.
+func GoldenMaxDiff(n int) GoldenOpt { return func(c *goldenConfig) { c.maxDiff = n } }
+
+// GoldenDir reads and writes golden images under dir instead of "testdata".
+func GoldenDir(dir string) GoldenOpt { return func(c *goldenConfig) { c.dir = dir } }
+
+// AssertGolden renders the current frame with the software backend (see
+// Screenshot) and compares it with testdata/<name>.png. name may contain
+// slashes to group images in subdirectories.
+//
+// On a mismatch the test fails and <name>.expected.png, <name>.actual.png and
+// <name>.diff.png (differing pixels in red over a faded copy of the expected
+// image) are written under testdata/failures/; a later passing run removes
+// them. Run `TENON_UPDATE_GOLDEN=1 go test` to create or rewrite the golden
+// images instead. pkg/ui registers no flags; if the test package declares its
+// own boolean -update flag, `go test -update` works too.
+func (h *Harness) AssertGolden(t testing.TB, name string, opts ...GoldenOpt) {
+	t.Helper()
+	cfg := goldenConfig{tolerance: 2, dir: "testdata"}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	got, err := h.Screenshot()
+	if err != nil {
+		t.Fatalf("ui: golden %s: %v", name, err)
+		return
+	}
+	path := filepath.Join(cfg.dir, filepath.FromSlash(name)+".png")
+	fail := filepath.Join(cfg.dir, "failures", filepath.FromSlash(name))
+
+	if updateGolden() {
+		if err := writeGoldenPNG(path, got); err != nil {
+			t.Fatalf("ui: golden %s: %v", name, err)
+			return
+		}
+		removeGoldenFailures(fail)
+		t.Logf("ui: wrote golden %s", path)
+		return
+	}
+
+	want, err := readGoldenPNG(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeGoldenPNG(fail+".actual.png", got)
+		t.Errorf("ui: golden %s does not exist (actual frame: %s); run `TENON_UPDATE_GOLDEN=1 go test` to create it", path, fail+".actual.png")
+		return
+	}
+	if err != nil {
+		t.Fatalf("ui: golden %s: %v", name, err)
+		return
+	}
+	if want.Rect.Size() != got.Rect.Size() {
+		writeGoldenPNG(fail+".expected.png", want)
+		writeGoldenPNG(fail+".actual.png", got)
+		t.Errorf("ui: %s: frame is %v, golden is %v; see %s.{expected,actual}.png", name, got.Rect.Size(), want.Rect.Size(), fail)
+		return
+	}
+	diff, n := goldenDiff(want, got, cfg.tolerance)
+	if n <= cfg.maxDiff {
+		removeGoldenFailures(fail)
+		return
+	}
+	writeGoldenPNG(fail+".expected.png", want)
+	writeGoldenPNG(fail+".actual.png", got)
+	writeGoldenPNG(fail+".diff.png", diff)
+	t.Errorf("ui: %s differs from golden in %d pixels (tolerance %d, %d allowed); see %s.{expected,actual,diff}.png, or run `TENON_UPDATE_GOLDEN=1 go test` to accept",
+		name, n, cfg.tolerance, cfg.maxDiff, fail)
+}
+
+// goldenDiff 逐像素比对（预乘 RGBA，任一通道差值超过 tol 即算不同），返回差异图与不同的像素数。
+// 差异图：不同处为纯红，其余是期望图褪色后的灰度，一眼能看出差在哪。
+func goldenDiff(want, got *image.RGBA, tol uint8) (*image.RGBA, int) {
+	diff := image.NewRGBA(want.Rect)
+	n := 0
+	for i := 0; i < len(want.Pix); i += 4 {
+		w, g := want.Pix[i:i+4], got.Pix[i:i+4]
+		same := true
+		for c := range 4 {
+			if d := int(w[c]) - int(g[c]); d > int(tol) || -d > int(tol) {
+				same = false
+			}
+		}
+		if !same {
+			n++
+			copy(diff.Pix[i:], []uint8{255, 0, 0, 255})
+			continue
+		}
+		y := color.GrayModel.Convert(color.RGBA{w[0], w[1], w[2], w[3]}).(color.Gray).Y
+		v := 192 + y/4
+		copy(diff.Pix[i:], []uint8{v, v, v, 255})
+	}
+	return diff, n
+}
+
+func readGoldenPNG(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out, nil
+}
+
+func writeGoldenPNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, encodePNG(img), 0o644)
+}
+
+// removeGoldenFailures 删掉上一次失败留下的对比图。
+func removeGoldenFailures(fail string) {
+	for _, suffix := range []string{".expected.png", ".actual.png", ".diff.png"} {
+		os.Remove(fail + suffix)
+	}
+}
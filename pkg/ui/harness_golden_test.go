@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试包自己声明 -update：pkg/ui 不注册这个参数，所以不会 flag redefined，
+// AssertGolden / AssertSnapshot 会按名字查到它。
+var updateFlag = flag.Bool("update", false, "rewrite golden images and snapshots instead of comparing")
+
+// compareMode 让本测试从比对模式开始：验证比对逻辑的测试不受外部 -update 影响。
+func compareMode(t *testing.T) {
+	t.Setenv("TENON_UPDATE_GOLDEN", "")
+	old := *updateFlag
+	*updateFlag = false
+	t.Cleanup(func() { *updateFlag = old })
+}
+
+// 更新模式既认 TENON_UPDATE_GOLDEN，也认测试包声明的 -update。
+func TestUpdateGoldenSources(t *testing.T) {
+	compareMode(t)
+	if updateGolden() {
+		t.Fatal("默认不应处于更新模式")
+	}
+	*updateFlag = true
+	if !updateGolden() {
+		t.Fatal("-update 应开启更新模式")
+	}
+	*updateFlag = false
+	t.Setenv("TENON_UPDATE_GOLDEN", "1")
+	if !updateGolden() {
+		t.Fatal("TENON_UPDATE_GOLDEN=1 应开启更新模式")
+	}
+}
+
+// failTB 截下 AssertGolden 的失败，用来验证「该失败时确实失败」。
+type failTB struct {
+	testing.TB
+	msgs []string
+}
+
+func (f *failTB) Helper()                        {}
+func (f *failTB) Logf(string, ...any)            {}
+func (f *failTB) Errorf(format string, a ...any) { f.msgs = append(f.msgs, fmt.Sprintf(format, a...)) }
+func (f *failTB) Fatalf(format string, a ...any) { f.msgs = append(f.msgs, fmt.Sprintf(format, a...)) }
+
+func goldenApp(_ struct{}) *Node {
+	on, setOn := UseState(false)
+	bg := Hex("#e5e7eb")
+	if on {
+		bg = Hex("#2563eb")
+	}
+	return Div(Style(Padding(10)),
+		Div(Style(Width(60), Height(30), Radius(15), Bg(bg)), OnClick(func() { setOn(!on) })),
+	)
+}
+
+// 黄金图：更新模式写入后同一帧通过；画面变化后失败，并写出期望 / 实际 / 差异三张图；
+// 再次通过时清掉这些失败产物。
+func TestAssertGolden(t *testing.T) {
+	compareMode(t)
+	dir := t.TempDir()
+	h := Mount(Use(goldenApp, struct{}{}), 100, 60)
+
+	t.Setenv("TENON_UPDATE_GOLDEN", "1")
+	h.AssertGolden(t, "toggle/off", GoldenDir(dir))
+	t.Setenv("TENON_UPDATE_GOLDEN", "")
+	if _, err := os.Stat(filepath.Join(dir, "toggle", "off.png")); err != nil {
+		t.Fatalf("更新模式应写出黄金图：%v", err)
+	}
+	h.AssertGolden(t, "toggle/off", GoldenDir(dir))
+
+	h.ClickAt(40, 25)
+	ft := &failTB{TB: t}
+	h.AssertGolden(ft, "toggle/off", GoldenDir(dir))
+	if len(ft.msgs) != 1 || !strings.Contains(ft.msgs[0], "differs from golden") {
+		t.Fatalf("画面变了应失败：%q", ft.msgs)
+	}
+	fail := filepath.Join(dir, "failures", "toggle", "off")
+	for _, suffix := range []string{".expected.png", ".actual.png", ".diff.png"} {
+		if _, err := os.Stat(fail + suffix); err != nil {
+			t.Errorf("失败时应写出 %s：%v", suffix, err)
+		}
+	}
+	diff, err := readGoldenPNG(fail + ".diff.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := diff.RGBAAt(40, 25); c.R != 255 || c.G != 0 {
+		t.Errorf("变化处在差异图里应是红色：%v", c)
+	}
+	if c := diff.RGBAAt(95, 55); c.R != c.G {
+		t.Errorf("未变处在差异图里应是灰色：%v", c)
+	}
+
+	// 放宽到允许这么多像素不同就通过，并清掉失败产物
+	h.AssertGolden(t, "toggle/off", GoldenDir(dir), GoldenMaxDiff(100*60))
+	if _, err := os.Stat(fail + ".diff.png"); !os.IsNotExist(err) {
+		t.Error("通过后应删掉上次的失败产物")
+	}
+}
+
+// 容差：每通道差值在容差内算相同；缺少黄金图时失败并提示如何生成。
+func TestAssertGoldenToleranceAndMissing(t *testing.T) {
+	compareMode(t)
+	dir := t.TempDir()
+	h := Mount(Use(goldenApp, struct{}{}), 100, 60)
+	t.Setenv("TENON_UPDATE_GOLDEN", "1")
+	h.AssertGolden(t, "base", GoldenDir(dir))
+	t.Setenv("TENON_UPDATE_GOLDEN", "")
+
+	h.ClickAt(40, 25) // #e5e7eb -> #2563eb：红通道差 192
+	h.AssertGolden(t, "base", GoldenDir(dir), GoldenTolerance(255))
+	ft := &failTB{TB: t}
+	h.AssertGolden(ft, "base", GoldenDir(dir), GoldenTolerance(191))
+	if len(ft.msgs) != 1 {
+		t.Fatalf("差值超出容差应失败：%q", ft.msgs)
+	}
+
+	ft = &failTB{TB: t}
+	h.AssertGolden(ft, "missing", GoldenDir(dir))
+	if len(ft.msgs) != 1 || !strings.Contains(ft.msgs[0], "TENON_UPDATE_GOLDEN=1") {
+		t.Fatalf("缺少黄金图应失败并提示如何生成：%q", ft.msgs)
+	}
+}
@@ -1,5 +1,16 @@
 package ui
 
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"gioui.org/f32"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+)
+
 // ---- 无头测试用的输入源 ----
 //
 // Harness 过去靠直接调 g.focusNext()/g.activateFocused() 来「按键」，那是与生产平行的
@@ -7,42 +18,398 @@ package ui
 // 两条路一分岔，测试就会给出与用户实际操作不同的答案 —— OnSubmit 的双重提交就是这么
 // 溜过去的（harness 只跑了 activateFocused，没跑真实按键路径，全绿）。
 //
-// harnessInput 让测试可以真的「按下一个键」，然后跑生产同款的处理顺序。
-
-type harnessInput struct {
-	x, y    float32
-	just    [inKeyCount]bool
-	held    [inKeyCount]bool
-	chars   []rune
-	wheelX  float32
-	wheelY  float32
-	btnHeld [3]bool
-	btnJust [3]bool
-}
-
-func (i *harnessInput) cursor() (float32, float32) { return i.x, i.y }
-func (i *harnessInput) wheel() (float32, float32)  { return i.wheelX, i.wheelY }
-func (i *harnessInput) mousePressed(b mouseBtn) bool {
-	return int(b) < len(i.btnHeld) && i.btnHeld[b]
-}
-func (i *harnessInput) mouseJustPressed(b mouseBtn) bool {
-	return int(b) < len(i.btnJust) && i.btnJust[b]
-}
-func (i *harnessInput) keyPressed(k inKey) bool     { return int(k) < len(i.held) && i.held[k] }
-func (i *harnessInput) keyJustPressed(k inKey) bool { return int(k) < len(i.just) && i.just[k] }
-func (i *harnessInput) typedChars() []rune          { return i.chars }
-
-// pressKey 模拟按下一个键并跑一遍生产帧循环里处理按键的那两步，顺序与 run.go 的
-// updateInput 一致：handleKeyboardNav（Tab/Esc/Enter 激活）在前，editFocusedInput
-// （文本编辑、单行回车提交）在后。顺序必须与生产一致，否则测不出两者的相互作用。
-func (h *Harness) pressKey(k inKey) {
+// 所以 harness 的输入源就是 gio 后端的 gioInput：Dispatch 把事件换成 gio 的
+// pointer/key/edit 事件，经 gioInput.process 累积成可轮询状态，再按 gioWindow.frame 的
+// 顺序跑一帧（dispatchKeys → applyEdits → 粘贴 → handleInput）。gio_input.go 与
+// gio_ime.go 里的每一步在测试里都真的执行了一遍。
+
+// InputEvent is an event for Harness.Dispatch: PointerEvent, WheelEvent,
+// KeyEvent, TextEvent, EditEvent, CompositionEvent or SelectionEvent.
+type InputEvent interface{ inputEvent() }
+
+// PointerKind is what a PointerEvent does.
+type PointerKind int
+
+const (
+	PointerMove    PointerKind = iota // 移动（有按钮按住时即拖动）
+	PointerPress                      // 按下 Button
+	PointerRelease                    // 松开 Button
+)
+
+// MouseButton names a pointer button.
+type MouseButton int
+
+const (
+	LeftButton MouseButton = iota
+	RightButton
+)
+
+// PointerEvent moves the pointer to (X, Y) (logical px) and presses or
+// releases Button. Buttons stay held across Dispatch calls until released, so
+// a Press, some Moves and a Release make a drag.
+type PointerEvent struct {
+	Kind   PointerKind
+	X, Y   float32
+	Button MouseButton // Press / Release 的按钮；Move 时不用
+	Ctrl   bool
+	Alt    bool
+	Shift  bool
+	Meta   bool
+}
+
+// WheelEvent scrolls by (DX, DY) logical px with the pointer at (X, Y); the
+// nearest scroll container under the point scrolls. Positive DY scrolls down.
+type WheelEvent struct {
+	X, Y   float32
+	DX, DY float32
+}
+
+// TextEvent types Text into the focused input as the keyboard (or an IME
+// commit) delivers it: it replaces the current selection.
+type TextEvent struct {
+	Text string
+}
+
+// EditEvent is an IME edit: replace runes [Start, End) of the focused input's
+// value with Text. Offsets count runes, as the platform IME protocol does.
+type EditEvent struct {
+	Start, End int
+	Text       string
+}
+
+// CompositionEvent marks runes [Start, End) of the focused input as the IME
+// preedit (underlined); an empty range ends composition.
+type CompositionEvent struct {
+	Start, End int
+}
+
+// SelectionEvent is the IME moving the focused input's selection to runes
+// [Start, End); End is the caret.
+type SelectionEvent struct {
+	Start, End int
+}
+
+func (PointerEvent) inputEvent()     {}
+func (WheelEvent) inputEvent()       {}
+func (KeyEvent) inputEvent()         {}
+func (TextEvent) inputEvent()        {}
+func (EditEvent) inputEvent()        {}
+func (CompositionEvent) inputEvent() {}
+func (SelectionEvent) inputEvent()   {}
+
+// harnessEvents 是喂给 gioInput.process 的事件队列（过滤由 gio 负责，这里全部投递）。
+type harnessEvents []event.Event
+
+func (q *harnessEvents) Event(...event.Filter) (event.Event, bool) {
+	if len(*q) == 0 {
+		return nil, false
+	}
+	ev := (*q)[0]
+	*q = (*q)[1:]
+	return ev, true
+}
+
+// source 返回 harness 的输入源；光标起初在窗口外，免得第一次按键就 hover 左上角。
+func (h *Harness) source() *gioInput {
+	if h.in == nil {
+		h.in = &gioInput{curX: -1, curY: -1}
+	}
+	return h.in
+}
+
+// Dispatch delivers events as one frame of platform input and runs that frame
+// through the real backend path: OnKeyDown and UseHotkey, IME edits, paste,
+// then hit-testing, focus, clicks, presses, text selection, drag, scrolling
+// and built-in keyboard handling. Events in one call arrive together, like a
+// burst between two frames; call Dispatch again for the next frame. A KeyEvent
+// is a key tap — it is released (with its modifiers) after the frame. Settles
+// afterward.
+func (h *Harness) Dispatch(events ...InputEvent) {
+	var evs, after harnessEvents
+	for _, e := range events {
+		evs = append(evs, h.gioEvents(e, &after)...)
+	}
+	h.frame(evs, after, true)
+}
+
+// frame 把 evs 当作一帧的输入跑一遍，after 在帧后处理（松开按键）。readClipboard 为真时
+// Ctrl+V 立即读当前剪贴板 —— 无头环境里系统剪贴板的读取即时返回；回放时粘贴的文本来自
+// 会话里录下的 DataEvent，不能再读一遍。
+func (h *Harness) frame(evs, after harnessEvents, readClipboard bool) {
+	in := h.source()
 	old := input
-	hi := &harnessInput{}
-	hi.just[k], hi.held[k] = true, true
-	input = hi
+	input = in
 	defer func() { input = old }()
 
-	h.g.handleKeyboardNav()
-	h.g.editFocusedInput()
+	g := h.g
+	g.activate()
+	in.resetFrame()
+	in.process(&evs, nil)
+	// 与 gioWindow.frame 同序：按键先分发，被消费的按键不再落到输入框
+	if g.rootRN != nil {
+		if drop := g.dispatchKeys(); drop != nil {
+			in.dropStrokes(drop)
+		}
+		in.strokes = in.strokes[:0]
+	}
+	gioIME.applyEdits(g, in)
+	if gioClip.pasteRequested {
+		gioClip.pasteRequested = false
+		if readClipboard {
+			pasteIntoFocused(g, getClipboard())
+		}
+	}
+	if t := gioClip.pastedText; t != nil {
+		gioClip.pastedText = nil
+		clipboardText = *t
+		pasteIntoFocused(g, *t)
+	}
+	g.handleInput()
+	in.process(&after, nil) // 松开本帧按下的键：不再算按住，也就不会触发长按重复
 	h.settle()
 }
+
+// gioEvents 把一个 InputEvent 换成 gio 事件；按键的松开放进 after，帧后再处理。
+func (h *Harness) gioEvents(e InputEvent, after *harnessEvents) []event.Event {
+	in := h.source()
+	switch e := e.(type) {
+	case PointerEvent:
+		btns := harnessButtons(in)
+		b := pointer.ButtonPrimary
+		if e.Button == RightButton {
+			b = pointer.ButtonSecondary
+		}
+		ev := pointer.Event{Position: f32.Pt(e.X, e.Y), Modifiers: gioMods(e.Ctrl, e.Alt, e.Shift, e.Meta)}
+		switch e.Kind {
+		case PointerPress:
+			ev.Kind, ev.Buttons = pointer.Press, btns|b
+		case PointerRelease:
+			ev.Kind, ev.Buttons = pointer.Release, btns&^b
+		default:
+			ev.Kind, ev.Buttons = pointer.Move, btns
+			if btns != 0 {
+				ev.Kind = pointer.Drag
+			}
+		}
+		return []event.Event{ev}
+	case WheelEvent:
+		return []event.Event{pointer.Event{Kind: pointer.Scroll, Position: f32.Pt(e.X, e.Y),
+			Buttons: harnessButtons(in), Scroll: f32.Pt(e.DX, e.DY)}}
+	case *KeyEvent:
+		return h.gioEvents(*e, after)
+	case KeyEvent:
+		name := harnessKeyName(e.Key)
+		if e.Repeat {
+			if in.held == nil {
+				in.held = map[key.Name]bool{}
+			}
+			in.held[name] = true
+		}
+		mods := gioMods(e.Ctrl, e.Alt, e.Shift, e.Meta)
+		*after = append(*after, key.Event{Name: name, State: key.Release})
+		return []event.Event{key.Event{Name: name, Modifiers: mods, State: key.Press}}
+	case TextEvent:
+		lo, hi := h.imeRange(false)
+		return []event.Event{key.EditEvent{Range: key.Range{Start: lo, End: hi}, Text: e.Text}}
+	case EditEvent:
+		return []event.Event{key.EditEvent{Range: key.Range{Start: e.Start, End: e.End}, Text: e.Text}}
+	case CompositionEvent:
+		return []event.Event{key.CompositionEvent{Start: e.Start, End: e.End}}
+	case SelectionEvent:
+		return []event.Event{key.SelectionEvent{Start: e.Start, End: e.End}}
+	}
+	panic(fmt.Sprintf("ui: Harness.Dispatch: unknown event %T", e))
+}
+
+func harnessButtons(in *gioInput) pointer.Buttons {
+	var b pointer.Buttons
+	if in.btnDown[btnLeft] {
+		b |= pointer.ButtonPrimary
+	}
+	if in.btnDown[btnRight] {
+		b |= pointer.ButtonSecondary
+	}
+	return b
+}
+
+func gioMods(ctrl, alt, shift, meta bool) key.Modifiers {
+	var m key.Modifiers
+	if ctrl {
+		m |= key.ModCtrl
+	}
+	if alt {
+		m |= key.ModAlt
+	}
+	if shift {
+		m |= key.ModShift
+	}
+	if meta {
+		m |= key.ModCommand
+	}
+	return m
+}
+
+// harnessKeyName 把规范键名（"Up"、"Esc" …）换回 gio 键名；其余原样。
+func harnessKeyName(s string) key.Name {
+	for n, c := range gioStrokeName {
+		if c == s {
+			return n
+		}
+	}
+	return key.Name(s)
+}
+
+// imeRange 是输入法下一次编辑要替换的 rune 区间：组字中取组字区间（preedit 为真时），
+// 否则取聚焦输入框的选区 —— 即 gio 从上一帧 SelectionCmd 得知的范围。
+func (h *Harness) imeRange(preedit bool) (int, int) {
+	rn := focusedInput(h.g)
+	if rn == nil {
+		return 0, 0
+	}
+	val := rn.value
+	if preedit && rn.composeHi > rn.composeLo {
+		return runeIdx(val, rn.composeLo), runeIdx(val, rn.composeHi)
+	}
+	a, c := runeIdx(val, rn.selAnchor), runeIdx(val, rn.caretPos)
+	return min(a, c), max(a, c)
+}
+
+// pressKey 按一下中立键 k（无修饰键），走 Dispatch 的完整按键路径。
+func (h *Harness) pressKey(k inKey) {
+	for name, ik := range strokeKeys {
+		if ik == k {
+			h.Dispatch(KeyEvent{Key: name})
+			return
+		}
+	}
+}
+
+// strokeKeys 把规范键名映射到引擎内置处理认识的中立键。
+var strokeKeys = map[string]inKey{
+	"Tab": keyTab, "Esc": keyEscape, "Enter": keyEnter, "Space": keySpace,
+	"Up": keyUp, "Down": keyDown, "Left": keyLeft, "Right": keyRight,
+	"Backspace": keyBackspace, "Delete": keyDelete, "Home": keyHome, "End": keyEnd,
+	"A": keyA, "C": keyC, "X": keyX, "V": keyV, "F12": keyF12,
+}
+
+// Key presses a key combination through the real keyboard path — the focused
+// element's OnKeyDown (bubbling) and UseHotkey first, then built-in handling (Tab focus, Enter activation, Esc, arrows,
+// editing shortcuts like Ctrl+A, Shift+arrow selection, Ctrl+C / Ctrl+V).
+// combo uses UseHotkey syntax ("Ctrl+S", "Shift+Tab", "Esc"); a chord
+// ("Ctrl+K Ctrl+C") presses each step in turn, one frame each.
+// Text is typed with TypeText or Query.Type, not Key. Panics on an invalid combo.
+// Settles after each step.
+func (h *Harness) Key(combo string) {
+	steps, err := parseHotkey(combo)
+	if err != nil {
+		panic(fmt.Sprintf("ui: Harness.Key(%q): %v", combo, err))
+	}
+	for _, s := range steps {
+		e := newKeyEvent(s)
+		h.Dispatch(*e)
+	}
+}
+
+// TypeText types text into the focused input the way the platform delivers
+// keyboard text — one edit replacing the current selection. Unlike Query.Type
+// it goes through the backend's IME/edit path. Settles afterward.
+func (h *Harness) TypeText(text string) { h.Dispatch(TextEvent{Text: text}) }
+
+// IMEPreedit updates the IME composition in the focused input: text replaces
+// the current preedit (or the selection when not composing) and stays marked
+// as composing, as when typing pinyin before picking a candidate.
+func (h *Harness) IMEPreedit(text string) {
+	lo, hi := h.imeRange(true)
+	h.Dispatch(EditEvent{Start: lo, End: hi, Text: text},
+		CompositionEvent{Start: lo, End: lo + utf8.RuneCountInString(text)})
+}
+
+// IMECommit replaces the current preedit (or the selection) with text and ends
+// composition — picking a candidate. IMECommit("") cancels the preedit.
+func (h *Harness) IMECommit(text string) {
+	lo, hi := h.imeRange(true)
+	h.Dispatch(EditEvent{Start: lo, End: hi, Text: text}, CompositionEvent{})
+}
+
+// Copy presses Mod+C (Ctrl+C, ⌘C on macOS) and returns the clipboard text.
+func (h *Harness) Copy() string {
+	h.Key("Mod+C")
+	return Clipboard()
+}
+
+// Paste puts text on the clipboard and presses Mod+V, pasting into the focused
+// input over its selection.
+func (h *Harness) Paste(text string) {
+	SetClipboardText(text)
+	h.Key("Mod+V")
+}
+
+// pointer 在 (x,y)（逻辑像素，harness 的缩放恒为 1）上发一个左键指针事件。
+func (h *Harness) pointer(kind PointerKind, x, y float32) {
+	h.Dispatch(PointerEvent{Kind: kind, X: x, Y: y})
+}
+
+// PointerDown presses the left button at (x, y) through the real input path
+// (focus, click, press, text selection, drag and drag-and-drop start). Two
+// presses at the same point count as a double-click. Settles afterward.
+func (h *Harness) PointerDown(x, y float32) { h.pointer(PointerPress, x, y) }
+
+// PointerMove moves the pointer to (x, y), keeping the buttons in whatever
+// state the last PointerDown/PointerUp left them — a held move drags. Settles.
+func (h *Harness) PointerMove(x, y float32) { h.pointer(PointerMove, x, y) }
+
+// PointerUp releases the left button at (x, y), ending a drag or dropping on
+// the DropTarget under the pointer. Settles afterward.
+func (h *Harness) PointerUp(x, y float32) { h.pointer(PointerRelease, x, y) }
+
+// MultiClickAt clicks the left button n times at (x, y) in quick succession:
+// 2 selects a word in an input or Selectable text, 3 selects all. Earlier
+// clicks never combine with these. Settles after each press and release.
+func (h *Harness) MultiClickAt(x, y float32, n int) {
+	h.g.lastClickAt = time.Time{} // 与之前的点击断开
+	for range n {
+		h.PointerDown(x, y)
+		h.PointerUp(x, y)
+	}
+}
+
+// DoubleClickAt is MultiClickAt(x, y, 2).
+func (h *Harness) DoubleClickAt(x, y float32) { h.MultiClickAt(x, y, 2) }
+
+// TripleClickAt is MultiClickAt(x, y, 3).
+func (h *Harness) TripleClickAt(x, y float32) { h.MultiClickAt(x, y, 3) }
+
+// DragWith presses button at (x0, y0), moves to the midpoint and then to
+// (x1, y1), and releases there — a drag with either button. A right press
+// opens the OnContextMenu under (x0, y0) first, as it does for users.
+func (h *Harness) DragWith(button MouseButton, x0, y0, x1, y1 float32) {
+	h.Dispatch(PointerEvent{Kind: PointerPress, X: x0, Y: y0, Button: button})
+	h.Dispatch(PointerEvent{Kind: PointerMove, X: (x0 + x1) / 2, Y: (y0 + y1) / 2})
+	h.Dispatch(PointerEvent{Kind: PointerMove, X: x1, Y: y1})
+	h.Dispatch(PointerEvent{Kind: PointerRelease, X: x1, Y: y1, Button: button})
+}
+
+// WheelAt scrolls the mouse wheel by (dx, dy) logical px with the pointer at
+// (x, y): the nearest scroll container under the point scrolls. Settles.
+func (h *Harness) WheelAt(x, y, dx, dy float32) {
+	h.Dispatch(WheelEvent{X: x, Y: y, DX: dx, DY: dy})
+}
+
+// DragTo drags this node onto target with the real pointer path: press at this
+// node's center, move past the drag threshold to target's center, release.
+// Returns whether a DropTarget accepted the drop.
+func (q *Query) DragTo(target *Query) bool {
+	if !q.Exists() || !target.Exists() {
+		return false
+	}
+	h := q.h
+	a, b := q.Bounds(), target.Bounds()
+	ax, ay := a.X+a.W/2, a.Y+a.H/2
+	bx, by := b.X+b.W/2, b.Y+b.H/2
+	h.PointerDown(ax, ay)
+	h.PointerMove((ax+bx)/2, (ay+by)/2)
+	h.PointerMove(bx, by)
+	dropped := h.g.dnd != nil && h.g.dnd.active && h.g.dnd.over != nil
+	h.PointerUp(bx, by)
+	return dropped
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func dispatchInputApp(initial string) func(struct{}) *Node {
+	return func(_ struct{}) *Node {
+		v, setV := UseState(initial)
+		return Div(Style(Padding(10)),
+			Input(Value(v), OnChange(setV), Style(Width(300), Height(30), FontSize(16))),
+		)
+	}
+}
+
+// 键盘：Shift+方向键扩展选区，Ctrl+C 复制选区，Ctrl+V 覆盖选区粘贴，Ctrl+A 后打字替换全部。
+func TestDispatchSelectionAndClipboard(t *testing.T) {
+	h := Mount(Use(dispatchInputApp("hello world"), struct{}{}), 320, 50)
+	b := h.Root().ByKind("input").Bounds()
+	h.PointerDown(b.X+b.W-2, b.Y+b.H/2)
+	h.PointerUp(b.X+b.W-2, b.Y+b.H/2)
+	h.Key("End")
+	for range 5 {
+		h.Key("Shift+Left")
+	}
+	in := h.Root().ByKind("input")
+	if got := in.Selection(); got != "world" {
+		t.Fatalf("Shift+← ×5 应选中 world：%q", got)
+	}
+	if got := h.Copy(); got != "world" {
+		t.Fatalf("Ctrl+C 应复制选区：%q", got)
+	}
+	h.Paste("there")
+	if got := h.Root().ByKind("input").Value(); got != "hello there" {
+		t.Fatalf("Ctrl+V 应替换选区：%q", got)
+	}
+	h.Key("Ctrl+A")
+	h.TypeText("x")
+	if got := h.Root().ByKind("input").Value(); got != "x" {
+		t.Fatalf("全选后打字应替换全部：%q", got)
+	}
+}
+
+// 输入法：预编辑反复替换自己的区间并标为组字中，提交后换成候选字、组字结束。
+func TestDispatchIMEPreeditAndCommit(t *testing.T) {
+	h := Mount(Use(dispatchInputApp("ab"), struct{}{}), 320, 50)
+	h.Root().ByKind("input").Focus() // 光标在末尾
+	h.IMEPreedit("n")
+	h.IMEPreedit("ni")
+	in := h.Root().ByKind("input")
+	if in.Value() != "abni" || in.Preedit() != "ni" {
+		t.Fatalf("预编辑：value=%q preedit=%q", in.Value(), in.Preedit())
+	}
+	if !h.g.imeComposing {
+		t.Error("组字中应让帧循环保持活跃")
+	}
+	h.IMECommit("你")
+	in = h.Root().ByKind("input")
+	if in.Value() != "ab你" || in.Preedit() != "" || h.g.imeComposing {
+		t.Fatalf("提交：value=%q preedit=%q composing=%v", in.Value(), in.Preedit(), h.g.imeComposing)
+	}
+	h.IMEPreedit("x")
+	h.IMECommit("")
+	if got := h.Root().ByKind("input").Value(); got != "ab你" {
+		t.Fatalf("IMECommit(\"\") 应取消预编辑：%q", got)
+	}
+}
+
+// 被 OnKeyDown PreventDefault 的按键，输入法同一帧的编辑也不能落进输入框；按键在帧后松开。
+func TestDispatchKeyEventModifiers(t *testing.T) {
+	var got []string
+	app := func(_ struct{}) *Node {
+		v, setV := UseState("")
+		return Input(Value(v), OnChange(setV), Style(Width(200), Height(30)),
+			OnKeyDown(func(e *KeyEvent) {
+				got = append(got, fmt.Sprintf("%s ctrl=%v shift=%v repeat=%v", e.Key, e.Ctrl, e.Shift, e.Repeat))
+				if e.Is("Ctrl+K") {
+					e.PreventDefault()
+				}
+			}))
+	}
+	h := Mount(Use(app, struct{}{}), 240, 40)
+	h.Root().ByKind("input").Focus()
+	h.Dispatch(KeyEvent{Key: "K", Ctrl: true}, TextEvent{Text: "k"})
+	h.Dispatch(&KeyEvent{Key: "Down", Shift: true, Repeat: true})
+	want := []string{"K ctrl=true shift=false repeat=false", "Down ctrl=false shift=true repeat=true"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("OnKeyDown 收到 %q，期望 %q", got, want)
+	}
+	if v := h.Root().ByKind("input").Value(); v != "" {
+		t.Errorf("被 PreventDefault 的这一键打出的字不应进输入框：%q", v)
+	}
+	if h.source().keyPressed(keyCtrl) || h.source().keyPressed(keyDown) {
+		t.Error("帧后按键与修饰键应已松开")
+	}
+}
+
+// 指针：双击选词、三击全选；右键拖动先触发 OnContextMenu，且不算左键拖动；滚轮滚的是坐标下的容器。
+func TestDispatchPointerGestures(t *testing.T) {
+	h := Mount(Use(dispatchInputApp("alpha beta gamma"), struct{}{}), 320, 50)
+	b := h.Root().ByKind("input").Bounds()
+	x, y := b.X+4, b.Y+b.H/2
+	h.DoubleClickAt(x, y)
+	if got := h.Root().ByKind("input").Selection(); got != "alpha" {
+		t.Fatalf("双击应选中单词：%q", got)
+	}
+	h.TripleClickAt(x, y)
+	if got := h.Root().ByKind("input").Selection(); got != "alpha beta gamma" {
+		t.Fatalf("三击应全选：%q", got)
+	}
+
+	var menuAt [2]float32
+	drags := 0
+	rows := []*Node{Style(Width(100), Height(100), Column)}
+	for i := range 5 {
+		rows = append(rows, Div(Style(Height(40)), Text(fmt.Sprintf("row%d", i))))
+	}
+	h = Mount(Div(Style(Row),
+		ScrollView(rows...),
+		Div(Style(Width(100), Height(100)),
+			OnContextMenu(func(x, y float32) { menuAt = [2]float32{x, y} }),
+			OnDrag(func(dx, dy float32) { drags++ })),
+	), 200, 100)
+	h.DragWith(RightButton, 120, 10, 180, 90)
+	if menuAt != [2]float32{120, 10} || drags != 0 {
+		t.Fatalf("右键拖动：菜单坐标 %v，左键拖动回调 %d 次", menuAt, drags)
+	}
+	h.DragWith(LeftButton, 120, 10, 180, 90)
+	if drags == 0 {
+		t.Fatal("左键拖动应回调 OnDrag")
+	}
+
+	y0 := h.Root().ByText("row0").Bounds().Y
+	h.WheelAt(150, 50, 0, 30)
+	if got := h.Root().ByText("row0").Bounds().Y; got != y0 {
+		t.Fatalf("滚轮不在滚动容器上时不应滚动：%v -> %v", y0, got)
+	}
+	h.WheelAt(50, 50, 0, 30)
+	if got := h.Root().ByText("row0").Bounds().Y; got != y0-30 {
+		t.Fatalf("滚轮应滚动坐标下的容器：%v -> %v", y0, got)
+	}
+}
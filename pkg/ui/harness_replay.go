@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReplayOpt configures Harness.Replay.
+type ReplayOpt func(*replayConfig)
+
+type replayConfig struct {
+	shotDir   string // 截图目录；空则不截图
+	shotEvery int    // 每隔多少帧截一张
+}
+
+// ReplayScreenshots saves a software-rendered screenshot (see Screenshot) to
+// dir every n frames and after the last one, as frame-000042.png — numbered by
+// frame — to attach to a bug report.
+func ReplayScreenshots(dir string, n int) ReplayOpt {
+	return func(c *replayConfig) { c.shotDir, c.shotEvery = dir, max(n, 1) }
+}
+
+// replayEpoch 是回放时钟的起点：固定的时刻让光标闪烁等依赖时钟的画面每次回放都一样。
+var replayEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Replay plays an input session recorded with TENON_RECORD=session.json
+// against the mounted tree, frame by frame: the window is resized to the
+// recorded size, each frame's pointer, key, IME and paste events go through
+// the same path as Dispatch, and animations advance by the recorded frame
+// times. The clock the engine uses for double-clicks, key repeat, chords and
+// caret blink follows the recording, so a replay is deterministic. Mount the
+// same root the recorded app ran. Returns an error for an unreadable or
+// malformed session; the frames before it have been played.
+func (h *Harness) Replay(path string, opts ...ReplayOpt) error {
+	var cfg replayConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20) // 一帧一行，粘贴的大段文本也在行内
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("ui: replay %s: empty session", path)
+	}
+	var hdr sessionHeader
+	if err := json.Unmarshal(sc.Bytes(), &hdr); err != nil || hdr.Tenon != "session" {
+		return fmt.Errorf("ui: replay %s: not a session file", path)
+	}
+	if hdr.Version > sessionVersion {
+		return fmt.Errorf("ui: replay %s: session version %d is newer than %d", path, hdr.Version, sessionVersion)
+	}
+	if hdr.Width > 0 && hdr.Height > 0 {
+		h.Resize(hdr.Width, hdr.Height)
+	}
+
+	old := clock
+	defer func() { clock = old }()
+	var prevT float64
+	frames, shot := 0, 0
+	for sc.Scan() {
+		var fr sessionFrame
+		if err := json.Unmarshal(sc.Bytes(), &fr); err != nil {
+			return fmt.Errorf("ui: replay %s: frame %d: %v", path, frames+1, err)
+		}
+		var evs harnessEvents
+		for _, e := range fr.Events {
+			ev, err := fromSession(e)
+			if err != nil {
+				return fmt.Errorf("ui: replay %s: frame %d: %v", path, frames+1, err)
+			}
+			evs = append(evs, ev)
+		}
+		now := replayEpoch.Add(time.Duration(fr.T * float64(time.Millisecond)))
+		clock = func() time.Time { return now }
+		if fr.Size != nil {
+			h.Resize(fr.Size[0], fr.Size[1])
+		}
+		h.frame(evs, nil, false)
+		if dt := fr.T - prevT; dt > 0 {
+			h.Step(float32(dt))
+		}
+		prevT = fr.T
+		frames++
+		if cfg.shotDir != "" && frames%cfg.shotEvery == 0 {
+			if err := h.replayShot(cfg.shotDir, frames); err != nil {
+				return err
+			}
+			shot = frames
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("ui: replay %s: %v", path, err)
+	}
+	if cfg.shotDir != "" && shot != frames {
+		return h.replayShot(cfg.shotDir, frames)
+	}
+	return nil
+}
+
+func (h *Harness) replayShot(dir string, frame int) error {
+	img, err := h.Screenshot()
+	if err == nil {
+		err = writeGoldenPNG(filepath.Join(dir, fmt.Sprintf("frame-%06d.png", frame)), img)
+	}
+	if err != nil {
+		return fmt.Errorf("ui: replay screenshot: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
+)
+
+// recordSession 用录制器（与 gio 帧里同一套 recordingSource + frame）写出一个会话：
+// frames[i] 是第 i 帧的 gio 事件，at[i] 是帧时刻（毫秒），坐标是 scale 倍的物理像素。
+func recordSession(t *testing.T, scale float32, sizes [][2]int, at []float64, frames [][]event.Event) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.json")
+	rec, err := newSessionRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i, evs := range frames {
+		q := harnessEvents(evs)
+		src := &recordingSource{src: &q}
+		for _, ok := src.Event(); ok; _, ok = src.Event() {
+		}
+		w, h := sizes[i][0], sizes[i][1]
+		rec.frame(start.Add(time.Duration(at[i]*float64(time.Millisecond))), w, h, scale, src.got)
+	}
+	rec.close()
+	return path
+}
+
+func press(x, y float32) pointer.Event {
+	return pointer.Event{Kind: pointer.Press, Position: f32.Pt(x, y), Buttons: pointer.ButtonPrimary}
+}
+
+func release(x, y float32) pointer.Event {
+	return pointer.Event{Kind: pointer.Release, Position: f32.Pt(x, y)}
+}
+
+// 录制 -> 回放：2 倍缩放下录的坐标按逻辑像素还原；打字、Shift+← 选区、粘贴、窗口尺寸都重现。
+// 两次点击隔了 600ms，即使回放瞬间跑完也不能算成双击 —— 时钟跟着录制走。
+func TestRecordReplayRoundTrip(t *testing.T) {
+	const s = 2
+	in := func(x, y float32) (float32, float32) { return x * s, y * s } // 逻辑 -> 物理
+	x, y := in(20, 25)
+	size := [2]int{320 * s, 50 * s}
+	path := recordSession(t, s,
+		[][2]int{size, size, size, size, size, size, {400 * s, 60 * s}},
+		[]float64{0, 16, 600, 616, 700, 800, 900},
+		[][]event.Event{
+			{press(x, y)},
+			{release(x, y)},
+			{press(x, y)}, // 600ms 后：单击，不是双击
+			{release(x, y), key.EditEvent{Text: "hello"}},
+			{key.Event{Name: key.NameLeftArrow, Modifiers: key.ModShift, State: key.Press}},
+			{key.Event{Name: key.NameLeftArrow, State: key.Release}, key.Event{Name: "V", Modifiers: key.ModCtrl, State: key.Press}, textDataEvent("p!")},
+			nil,
+		})
+
+	raw, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	if len(lines) != 8 || !strings.Contains(lines[0], `"width":320`) || !strings.Contains(lines[1], `"x":20,"y":25`) {
+		t.Fatalf("会话文件应为会话头 + 7 帧、坐标为逻辑像素：\n%s", raw)
+	}
+	if !strings.Contains(lines[7], `"size":[400,60]`) {
+		t.Errorf("尺寸变化的帧应带 size：%s", lines[7])
+	}
+
+	h := Mount(Use(dispatchInputApp(""), struct{}{}), 100, 100)
+	shots := t.TempDir()
+	if err := h.Replay(path, ReplayScreenshots(shots, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Root().ByKind("input").Value(); got != "hellp!" {
+		t.Fatalf("回放后的输入框：%q，期望 hellp!（Shift+← 选中 o，粘贴替换）", got)
+	}
+	if h.g.clickCount != 1 {
+		t.Errorf("隔 600ms 的两次点击回放后应是单击：连击数 %d", h.g.clickCount)
+	}
+	if w, hgt := h.Size(); w != 400 || hgt != 60 {
+		t.Errorf("回放应重现窗口尺寸：%dx%d", w, hgt)
+	}
+	for _, name := range []string{"frame-000003.png", "frame-000006.png", "frame-000007.png"} {
+		if _, err := os.Stat(filepath.Join(shots, name)); err != nil {
+			t.Errorf("应每 3 帧及最后一帧截图：%v", err)
+		}
+	}
+}
+
+// 坏文件报错而不是静默通过。
+func TestReplayRejectsBadSession(t *testing.T) {
+	dir := t.TempDir()
+	h := Mount(Div(), 10, 10)
+	for name, body := range map[string]string{
+		"empty.json":   "",
+		"other.json":   `{"hello":1}`,
+		"future.json":  `{"tenon":"session","version":99}`,
+		"badtype.json": `{"tenon":"session","version":1}` + "\n" + `{"t":0,"events":[{"type":"telepathy"}]}`,
+	} {
+		p := filepath.Join(dir, name)
+		os.WriteFile(p, []byte(body), 0o644)
+		if err := h.Replay(p); err == nil {
+			t.Errorf("%s 应报错", name)
+		}
+	}
+	if err := h.Replay(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("文件不存在应报错")
+	}
+}
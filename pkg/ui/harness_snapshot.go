@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Snapshot returns a stable, indented text dump of the mounted tree — the main
+// tree, then each Portal overlay under a "portal" line. Each node is one line:
+//
+//	box#id.class key=row-1 @10,20 200x40 clickable focusable bg=#ffffff radius=8 border=1 #e5e7eb
+//	  text "Save" @22,31 30x18 color=#111827 size=14 weight=600
+//
+// i.e. kind (box, text, input, image, scroll, icon), id and classes, key,
+// text / value / placeholder, bounds rounded to whole pixels, flags (clickable,
+// focusable, focused, hidden, clip) and the visual styles that differ from their
+// defaults. Use it with AssertSnapshot so component refactors show up as
+// reviewable text diffs.
+func (h *Harness) Snapshot() string {
+	var b strings.Builder
+	focused := h.g.focusedRNode()
+	if rn := rootRenderNode(h.g.rootFiber); rn != nil {
+		writeSnapshot(&b, rn, 0, focused)
+	}
+	for _, pf := range h.g.portals {
+		if pf.overlayRoot != nil {
+			b.WriteString("portal\n")
+			writeSnapshot(&b, pf.overlayRoot, 1, focused)
+		}
+	}
+	return b.String()
+}
+
+func writeSnapshot(b *strings.Builder, rn *renderNode, depth int, focused *renderNode) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(snapshotLine(rn, focused))
+	b.WriteByte('\n')
+	for _, c := range rn.children {
+		writeSnapshot(b, c, depth+1, focused)
+	}
+}
+
+// snapshotLine 是一个节点的一行描述；字段顺序固定，只写与默认值不同的样式。
+func snapshotLine(rn *renderNode, focused *renderNode) string {
+	var parts []string
+	add := func(format string, a ...any) { parts = append(parts, fmt.Sprintf(format, a...)) }
+
+	head := (&Query{rn: rn}).Kind()
+	if rn.kind == rnIcon {
+		head = "icon"
+	}
+	if rn.id != "" {
+		head += "#" + rn.id
+	}
+	for _, c := range rn.classes {
+		head += "." + c
+	}
+	parts = append(parts, head)
+	if k := snapshotKey(rn); k != "" {
+		add("key=%s", k)
+	}
+	switch rn.kind {
+	case rnText:
+		add("%s", strconv.Quote(rn.text))
+	case rnInput:
+		add("value=%s", strconv.Quote(rn.value))
+		if rn.placeholder != "" {
+			add("placeholder=%s", strconv.Quote(rn.placeholder))
+		}
+	case rnImage:
+		if rn.imgSrc != "" {
+			add("src=%s", strconv.Quote(rn.imgSrc))
+		}
+	}
+	r := rn.bounds
+	add("@%d,%d %dx%d", snapRound(r.X), snapRound(r.Y), snapRound(r.W), snapRound(r.H))
+
+	if rn.onClick != nil {
+		add("clickable")
+	}
+	if rn.focusable {
+		add("focusable")
+	}
+	if rn == focused {
+		add("focused")
+	}
+	if rn.hidden {
+		add("hidden")
+	}
+	if rn.clip && !rn.scroll {
+		add("clip")
+	}
+
+	switch {
+	case rn.bgGrad != nil:
+		add("bg=%s", rn.bgGrad)
+	case rn.bg.A != 0:
+		add("bg=%s", snapColor(rn.bg))
+	}
+	switch {
+	case rn.borderW > 0 && rn.borderGrad != nil:
+		add("border=%s %s", fmtNum(rn.borderW), rn.borderGrad)
+	case rn.borderW > 0 && rn.borderColor.A != 0:
+		add("border=%s %s", fmtNum(rn.borderW), snapColor(rn.borderColor))
+	}
+	if rn.radius > 0 {
+		add("radius=%s", fmtNum(rn.radius))
+	}
+	if rn.hasShadow {
+		add("shadow=%s %s %s %s %s", snapColor(rn.shadowColor), fmtNum(rn.shadowX), fmtNum(rn.shadowY), fmtNum(rn.shadowBlur), fmtNum(rn.shadowSpread))
+	}
+	if rn.opacity != 1 {
+		add("opacity=%s", fmtNum(rn.opacity))
+	}
+	if len(rn.filters) > 0 {
+		add("filter=%s", strconv.Quote(filterString(rn.filters)))
+	}
+	if rn.scaleX != rn.scaleY {
+		add("scale=%s,%s", fmtNum(rn.scaleX), fmtNum(rn.scaleY))
+	} else if rn.scaleX != 1 {
+		add("scale=%s", fmtNum(rn.scaleX))
+	}
+	if rn.rotate != 0 {
+		add("rotate=%s", fmtNum(rn.rotate))
+	}
+	if rn.transX != 0 || rn.transY != 0 {
+		add("translate=%s,%s", fmtNum(rn.transX), fmtNum(rn.transY))
+	}
+	if rn.rotateX != 0 {
+		add("rotateX=%s", fmtNum(rn.rotateX))
+	}
+	if rn.rotateY != 0 {
+		add("rotateY=%s", fmtNum(rn.rotateY))
+	}
+	if rn.transZ != 0 {
+		add("translateZ=%s", fmtNum(rn.transZ))
+	}
+	if rn.perspective != 0 {
+		add("perspective=%s", fmtNum(rn.perspective))
+	}
+	if rn.zIndex != 0 {
+		add("z=%d", rn.zIndex)
+	}
+	if rn.kind == rnText || rn.kind == rnInput || rn.kind == rnIcon {
+		switch {
+		case rn.textGrad != nil:
+			add("color=%s", rn.textGrad)
+		default:
+			add("color=%s", snapColor(rn.color))
+		}
+	}
+	if rn.kind == rnText || rn.kind == rnInput {
+		add("size=%s", fmtNum(rn.effSize))
+		if rn.effWeight != 0 && rn.effWeight != 400 {
+			add("weight=%d", rn.effWeight)
+		}
+		if rn.effItalic {
+			add("italic")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// snapshotKey 是节点的 Key：host 自身的，否则取包着它的组件上的（列表项常把 Key 给组件）。
+func snapshotKey(rn *renderNode) string {
+	for f := rn.owner; f != nil; f = f.parent {
+		if f.key != "" {
+			return f.key
+		}
+		if p := f.parent; p == nil || p.typ != typeComponent || len(p.children) != 1 {
+			return ""
+		}
+	}
+	return ""
+}
+
+func snapRound(v float32) int { return int(math.Round(float64(v))) }
+
+// snapColor 写成 #rrggbb，半透明时带上 alpha：#rrggbbaa。
+func snapColor(c Color) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// AssertSnapshot compares Snapshot() with testdata/<name>.snap and fails the
+// test with a line diff when they differ. Like AssertGolden, TENON_UPDATE_GOLDEN=1
+// writes the file instead, name may contain slashes, and GoldenDir moves the
+// directory (the other GoldenOpts do not apply).
+func (h *Harness) AssertSnapshot(t testing.TB, name string, opts ...GoldenOpt) {
+	t.Helper()
+	cfg := goldenConfig{dir: "testdata"}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	got := h.Snapshot()
+	path := filepath.Join(cfg.dir, filepath.FromSlash(name)+".snap")
+	if updateGolden() {
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(got), 0o644)
+		}
+		if err != nil {
+			t.Fatalf("ui: snapshot %s: %v", name, err)
+			return
+		}
+		t.Logf("ui: wrote snapshot %s", path)
+		return
+	}
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ui: snapshot %s does not exist; run `TENON_UPDATE_GOLDEN=1 go test` to create it. Current tree:\n%s", path, got)
+		return
+	}
+	if err != nil {
+		t.Fatalf("ui: snapshot %s: %v", name, err)
+		return
+	}
+	if string(want) != got {
+		t.Errorf("ui: %s differs from snapshot (- want, + got); run `TENON_UPDATE_GOLDEN=1 go test` to accept:\n%s", name, snapshotDiff(string(want), got))
+	}
+}
+
+// snapshotDiff 逐行比对（最长公共子序列），只列出变化的行及其前后各 2 行上下文。
+func snapshotDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	// lcs[i][j] = a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	type line struct {
+		op byte // ' ' 相同，'-' 只在期望里，'+' 只在实际里
+		s  string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+	const context = 2
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op != ' ' {
+			for c := max(k-context, 0); c <= min(k+context, len(lines)-1); c++ {
+				keep[c] = true
+			}
+		}
+	}
+	var out strings.Builder
+	skipped := false
+	for k, l := range lines {
+		if !keep[k] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			out.WriteString("  ...\n")
+			skipped = false
+		}
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.s)
+	}
+	if skipped {
+		out.WriteString("  ...\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func snapshotApp(_ struct{}) *Node {
+	items, setItems := UseState([]string{"a", "b"})
+	row := func(k string) *Node {
+		return Keyed(k, Use(func(_ struct{}) *Node {
+			return Div(Style(Width(40), Height(20), Bg(Hex("#f1f5f9"))), Class("row item"))
+		}, struct{}{}))
+	}
+	var rows []*Node
+	for _, k := range items {
+		rows = append(rows, row(k))
+	}
+	return Div(Style(Column, Padding(8), Gap(4)), Id("root"),
+		Div(append([]*Node{Style(Row, Gap(4))}, rows...)...),
+		Input(Value("hi"), Placeholder("Name"), Style(Width(100), Height(28), Radius(6), Border(1, Hex("#e5e7eb")))),
+		Div(Style(Width(30), Height(30), Bg(Hex("#2563eb80")), Opacity(0.5), Rotate(10), Clip),
+			OnClick(func() { setItems(append(items, "c")) })),
+	)
+}
+
+// 快照：固定顺序的逐行描述，带 key / id / class、取整后的位置、标志与非默认样式。
+func TestSnapshot(t *testing.T) {
+	h := Mount(Use(snapshotApp, struct{}{}), 200, 140)
+	h.Root().ByKind("input").Focus()
+	want := `box#root @0,0 200x140
+  box @8,8 184x20
+    box.row.item key=a @8,8 40x20 bg=#f1f5f9
+    box.row.item key=b @52,8 40x20 bg=#f1f5f9
+  input value="hi" placeholder="Name" @8,32 100x28 focusable focused border=1 #e5e7eb radius=6 color=#000000 size=16
+  box @8,64 30x30 clickable focusable clip bg=#2563eb80 opacity=0.5 rotate=10
+`
+	if got := h.Snapshot(); got != want {
+		t.Fatalf("快照不符：\n%s", snapshotDiff(want, got))
+	}
+}
+
+// AssertSnapshot：更新模式写入后通过；树变化后失败，差异里列出新增的行。
+func TestAssertSnapshot(t *testing.T) {
+	compareMode(t)
+	dir := t.TempDir()
+	h := Mount(Use(snapshotApp, struct{}{}), 200, 140)
+	t.Setenv("TENON_UPDATE_GOLDEN", "1")
+	h.AssertSnapshot(t, "list/rows", GoldenDir(dir))
+	t.Setenv("TENON_UPDATE_GOLDEN", "")
+	if _, err := os.Stat(filepath.Join(dir, "list", "rows.snap")); err != nil {
+		t.Fatalf("更新模式应写出快照：%v", err)
+	}
+	h.AssertSnapshot(t, "list/rows", GoldenDir(dir))
+
+	h.ClickAt(20, 75)
+	ft := &failTB{TB: t}
+	h.AssertSnapshot(ft, "list/rows", GoldenDir(dir))
+	if len(ft.msgs) != 1 || !strings.Contains(ft.msgs[0], "+     box.row.item key=c @96,8 40x20") {
+		t.Fatalf("加了一行应失败并在差异里列出：%q", ft.msgs)
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	want := "a\nb\nc\nd\ne\nf\ng\n"
+	got := "a\nb\nc\nD\ne\nf\ng\nh\n"
+	if d := snapshotDiff(want, got); d != "  ...\n  b\n  c\n- d\n+ D\n  e\n  f\n  g\n+ h\n" {
+		t.Fatalf("差异：\n%s", d)
+	}
+}
@@ -32,9 +32,18 @@ type refHook struct {
 func renderWithHooks(f *Fiber) *Node {
 	prevF, prevI := currentFiber, hookIndex
 	currentFiber, hookIndex = f, 0
-	n := f.render(f.props)
-	currentFiber, hookIndex = prevF, prevI
-	return n
+	// 用 defer 复原：渲染 panic（被 ErrorBoundary 接住）后 currentFiber 也不会残留。
+	defer func() { currentFiber, hookIndex = prevF, prevI }()
+	return f.render(f.props)
+}
+
+// renderingGame 返回正在渲染的组件所在的窗口；不在渲染中时退回当前窗口（activeGame）。
+// 渲染之外 currentFiber 为 nil，不会取到上一次渲染残留的窗口。
+func renderingGame() *game {
+	if currentFiber != nil {
+		return gameOf(currentFiber)
+	}
+	return activeGame
 }
 
 func nextHook(f *Fiber, make func() any) (int, any) {
@@ -58,9 +67,7 @@ func UseState[T any](initial T) (T, func(T)) {
 				return // bailout：值未变不触发重渲染
 			}
 			h.value = nv
-			if activeGame != nil {
-				activeGame.markDirty(f)
-			}
+			markFiberDirty(f)
 		}
 	}
 	return h.value.(T), h.setter.(func(T))
@@ -76,8 +83,8 @@ func UseEffect(fn func() Cleanup, deps ...any) {
 	}
 	h.deps = deps
 	h.hasRun = true
-	if activeGame != nil {
-		activeGame.pendingEffects = append(activeGame.pendingEffects, func() {
+	if g := gameOf(f); g != nil {
+		g.pendingEffects = append(g.pendingEffects, func() {
 			if h.cleanup != nil {
 				h.cleanup()
 			}
@@ -99,9 +106,7 @@ func UseReducer[S any, A any](reducer func(S, A) S, initial S) (S, func(A)) {
 				return
 			}
 			h.value = next
-			if activeGame != nil {
-				activeGame.markDirty(f)
-			}
+			markFiberDirty(f)
 		}
 	}
 	return h.value.(S), h.dispatch.(func(A))
@@ -0,0 +1,464 @@
+package ui
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---- 快捷键（UseHotkey） ----
+//
+// handleKeyboardNav 只认 Tab / Enter / Space / Esc / 方向键；其他按键组件无从订阅。
+// UseHotkey 给组件一个窗口级的快捷键表：
+//
+//	ui.UseHotkey("Mod+S", save, ui.HotkeyLabel("保存"))           // Mod = macOS 上的 ⌘，其他平台的 Ctrl
+//	ui.UseHotkey("Ctrl+K Ctrl+C", comment)                         // 和弦：依次按下两组
+//	ui.UseHotkey("Alt+Enter", submit, ui.HotkeyScope(ui.ScopeFocused)) // 只在焦点位于本组件子树内时生效
+//
+// 作用域：
+//   - ScopeGlobal（默认）窗口内任何时候都生效；
+//   - ScopeFocused 只在焦点落在注册组件的子树里时生效，同一组合它优先于全局的；
+//   - ScopeModal 只在有 TrapFocus 模态打开时生效。
+//
+// 有 TrapFocus 模态打开时，只有注册在最上层模态内部（Portal 内容里）的快捷键生效，
+// 背景里的一律被挡住。焦点在输入框里时，不带 Ctrl/Alt/Meta 的快捷键不触发（留给打字），
+// F1–F24 与 Esc 除外。快捷键消费了按键时，该键本帧不再做内置处理（焦点导航、文本编辑）。
+//
+// 注册表按窗口维护：Window.Hotkeys 列出全部注册项（shadcn 的菜单、Kbd 据此显示提示），
+// Window.HotkeyConflicts 找出会互相遮挡的注册。
+
+// Scope 是快捷键的生效范围。
+type Scope int
+
+const (
+	ScopeGlobal  Scope = iota // 窗口内任何时候
+	ScopeFocused              // 焦点位于注册组件的子树内
+	ScopeModal                // 有 TrapFocus 模态打开时
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeFocused:
+		return "focused"
+	case ScopeModal:
+		return "modal"
+	}
+	return "global"
+}
+
+// chordTimeout 是和弦两次按键之间允许的最长间隔。
+const chordTimeout = 1500 * time.Millisecond
+
+// keyMods 是一次按键的修饰键集合。
+type keyMods uint8
+
+const (
+	modCtrl keyMods = 1 << iota
+	modAlt
+	modShift
+	modMeta
+)
+
+// keyStroke 是一次带修饰键的按键；key 为规范键名（"S"、"1"、"F5"、"Enter"、"Up"、"/" …）。
+type keyStroke struct {
+	mods   keyMods
+	key    string
+	repeat bool // 按住不放的自动重复（只给 OnKeyDown 看，快捷键匹配不区分）
+}
+
+// hotkeyMac 决定 Mod 的含义与提示的写法（⌘S 还是 Ctrl+S）。
+var hotkeyMac = runtime.GOOS == "darwin"
+
+var modNames = []struct {
+	m         keyMods
+	name, mac string
+}{{modCtrl, "Ctrl", "⌃"}, {modAlt, "Alt", "⌥"}, {modShift, "Shift", "⇧"}, {modMeta, "Meta", "⌘"}}
+
+// String 返回规范写法，如 "Ctrl+Shift+S"，与书写顺序无关，用于比较与冲突检测。
+func (s keyStroke) String() string {
+	var b strings.Builder
+	for _, m := range modNames {
+		if s.mods&m.m != 0 {
+			b.WriteString(m.name)
+			b.WriteByte('+')
+		}
+	}
+	b.WriteString(s.key)
+	return b.String()
+}
+
+// keyAliases 把常见写法归一到规范键名（键为小写）。
+var keyAliases = map[string]string{
+	"esc": "Esc", "escape": "Esc", "enter": "Enter", "return": "Enter", "tab": "Tab", "space": "Space",
+	"up": "Up", "down": "Down", "left": "Left", "right": "Right",
+	"arrowup": "Up", "arrowdown": "Down", "arrowleft": "Left", "arrowright": "Right",
+	"backspace": "Backspace", "delete": "Delete", "del": "Delete", "home": "Home", "end": "End",
+	"pageup": "PageUp", "pgup": "PageUp", "pagedown": "PageDown", "pgdn": "PageDown", "plus": "+",
+}
+
+// parseMod 识别修饰键名；Mod 是平台主修饰键。
+func parseMod(s string) (keyMods, bool) {
+	switch strings.ToLower(s) {
+	case "ctrl", "control":
+		return modCtrl, true
+	case "alt", "option", "opt":
+		return modAlt, true
+	case "shift":
+		return modShift, true
+	case "meta", "cmd", "command", "super", "win":
+		return modMeta, true
+	case "mod":
+		if hotkeyMac {
+			return modMeta, true
+		}
+		return modCtrl, true
+	}
+	return 0, false
+}
+
+// parseHotkey 解析 "Ctrl+Shift+S" 或和弦 "Ctrl+K Ctrl+C"（各步以空格分隔）。
+func parseHotkey(combo string) ([]keyStroke, error) {
+	var steps []keyStroke
+	for _, step := range strings.Fields(combo) {
+		parts := strings.Split(step, "+")
+		var s keyStroke
+		for i, p := range parts {
+			if i < len(parts)-1 {
+				m, ok := parseMod(p)
+				if !ok {
+					return nil, fmt.Errorf("unknown modifier %q", p)
+				}
+				s.mods |= m
+				continue
+			}
+			k, err := normKey(p)
+			if err != nil {
+				return nil, err
+			}
+			s.key = k
+		}
+		steps = append(steps, s)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("empty hotkey")
+	}
+	return steps, nil
+}
+
+// normKey 把键名归一：字母大写，别名换成规范名，F1–F24 原样。
+func normKey(k string) (string, error) {
+	if k == "" {
+		return "", fmt.Errorf("missing key (write Plus for the + key)")
+	}
+	if a, ok := keyAliases[strings.ToLower(k)]; ok {
+		return a, nil
+	}
+	if len([]rune(k)) == 1 {
+		return strings.ToUpper(k), nil
+	}
+	if up := strings.ToUpper(k); up[0] == 'F' {
+		if n, err := strconv.Atoi(up[1:]); err == nil && n >= 1 && n <= 24 && strconv.Itoa(n) == up[1:] {
+			return up, nil
+		}
+	}
+	if _, ok := parseMod(k); ok {
+		return "", fmt.Errorf("%q is a modifier, not a key", k)
+	}
+	return "", fmt.Errorf("unknown key %q", k)
+}
+
+func formatSteps(steps []keyStroke) string {
+	out := make([]string, len(steps))
+	for i, s := range steps {
+		out[i] = s.String()
+	}
+	return strings.Join(out, " ")
+}
+
+// HotkeyKeys 把组合拆成要显示的键帽，每步一组：macOS 上修饰键用 ⌘⌥⇧⌃ 符号。
+// 组合无法解析时返回 nil。
+func HotkeyKeys(combo string) [][]string {
+	steps, err := parseHotkey(combo)
+	if err != nil {
+		return nil
+	}
+	out := make([][]string, len(steps))
+	for i, s := range steps {
+		for _, m := range modNames {
+			if s.mods&m.m != 0 {
+				if hotkeyMac {
+					out[i] = append(out[i], m.mac)
+				} else {
+					out[i] = append(out[i], m.name)
+				}
+			}
+		}
+		out[i] = append(out[i], s.key)
+	}
+	return out
+}
+
+// HotkeyText 返回组合的显示文本：其他平台为 "Ctrl+Shift+S"，macOS 为 "⇧⌘S"。
+func HotkeyText(combo string) string {
+	var steps []string
+	for _, keys := range HotkeyKeys(combo) {
+		if hotkeyMac {
+			steps = append(steps, strings.Join(keys, ""))
+		} else {
+			steps = append(steps, strings.Join(keys, "+"))
+		}
+	}
+	return strings.Join(steps, " ")
+}
+
+// HotkeyInfo 是注册表里的一项。
+type HotkeyInfo struct {
+	Combo string // 规范写法（如 "Ctrl+S"、"Ctrl+K Ctrl+C"）
+	Label string // HotkeyLabel 给的说明
+	Scope Scope
+}
+
+type hotkeyOpts struct {
+	scope  Scope
+	label  string
+	active bool
+}
+
+// HotkeyOpt 配置 UseHotkey。
+type HotkeyOpt func(*hotkeyOpts)
+
+// HotkeyScope 设置生效范围（默认 ScopeGlobal）。
+func HotkeyScope(s Scope) HotkeyOpt { return func(o *hotkeyOpts) { o.scope = s } }
+
+// HotkeyLabel 给快捷键一个说明，菜单与快捷键一览据此显示。
+func HotkeyLabel(label string) HotkeyOpt { return func(o *hotkeyOpts) { o.label = label } }
+
+// HotkeyWhen 为假时暂不注册（如编辑器只读时关掉「保存」）。
+func HotkeyWhen(active bool) HotkeyOpt { return func(o *hotkeyOpts) { o.active = active } }
+
+type hotkeyReg struct {
+	steps []keyStroke
+	info  HotkeyInfo
+	fn    *func()
+	fiber *Fiber
+}
+
+// UseHotkey 在组件挂载期间注册快捷键 combo，按下时调用 fn（始终调用最新一次渲染传入的 fn）。
+// combo 写法见文件头；写错是编程错误，会 panic。
+func UseHotkey(combo string, fn func(), opts ...HotkeyOpt) {
+	o := hotkeyOpts{active: true}
+	for _, op := range opts {
+		op(&o)
+	}
+	steps := UseMemo(func() []keyStroke {
+		s, err := parseHotkey(combo)
+		if err != nil {
+			panic(fmt.Sprintf("ui: UseHotkey(%q): %v", combo, err))
+		}
+		return s
+	}, combo)
+	ref := UseRef(fn)
+	*ref = fn
+	f := currentFiber
+	g := gameOf(f)
+	UseEffect(func() Cleanup {
+		if !o.active || g == nil {
+			return nil
+		}
+		r := &hotkeyReg{steps: steps, fn: ref, fiber: f,
+			info: HotkeyInfo{Combo: formatSteps(steps), Label: o.label, Scope: o.scope}}
+		g.hotkeys = append(g.hotkeys, r)
+		return func() { g.removeHotkey(r) }
+	}, combo, o.scope, o.label, o.active)
+}
+
+// UseHotkeyFor 在当前窗口的注册表里按说明查找快捷键，返回其组合（找不到为空）。
+// 菜单项据此显示「保存  Ctrl+S」而不必把组合写两遍。
+func UseHotkeyFor(label string) string {
+	g := renderingGame()
+	if g == nil || label == "" {
+		return ""
+	}
+	for _, r := range g.hotkeys {
+		if r.info.Label == label {
+			return r.info.Combo
+		}
+	}
+	return ""
+}
+
+func (g *game) removeHotkey(r *hotkeyReg) {
+	for i, x := range g.hotkeys {
+		if x == r {
+			g.hotkeys = append(g.hotkeys[:i], g.hotkeys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Hotkeys 返回本窗口当前注册的全部快捷键（按注册顺序），可用来渲染快捷键一览。
+func (w *Window) Hotkeys() []HotkeyInfo {
+	out := make([]HotkeyInfo, len(w.g.hotkeys))
+	for i, r := range w.g.hotkeys {
+		out[i] = r.info
+	}
+	return out
+}
+
+// HotkeyConflicts 找出会互相遮挡的注册：同一层（主界面或同一个模态）里同作用域的相同组合
+// （ScopeFocused 只在一个嵌套在另一个里时才算），以及一个快捷键恰好是另一组和弦的开头
+// —— 后者会让那组和弦永远按不出来。每组冲突各返回一个切片。
+func (w *Window) HotkeyConflicts() [][]HotkeyInfo {
+	regs := w.g.hotkeys
+	var out [][]HotkeyInfo
+	for i, a := range regs {
+		for _, b := range regs[i+1:] {
+			if modalLayer(a.fiber) != modalLayer(b.fiber) {
+				continue
+			}
+			same := a.info.Combo == b.info.Combo && a.info.Scope == b.info.Scope &&
+				(a.info.Scope != ScopeFocused || isAncestor(a.fiber, b.fiber) || isAncestor(b.fiber, a.fiber))
+			if same || strokesPrefix(a.steps, b.steps) || strokesPrefix(b.steps, a.steps) {
+				out = append(out, []HotkeyInfo{a.info, b.info})
+			}
+		}
+	}
+	return out
+}
+
+// strokesPrefix 报告 a 是否是 b 的真前缀。
+func strokesPrefix(a, b []keyStroke) bool {
+	return len(a) < len(b) && strokesEqual(a, b[:len(a)])
+}
+
+func strokesEqual(a, b []keyStroke) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].mods != b[i].mods || a[i].key != b[i].key {
+			return false
+		}
+	}
+	return true
+}
+
+// isAncestor 报告 a 是否是 f 自身或其祖先。
+func isAncestor(a, f *Fiber) bool {
+	for c := f; c != nil; c = c.parent {
+		if c == a {
+			return true
+		}
+	}
+	return false
+}
+
+// inHiddenSubtree 报告 f 是否位于 Hidden（display:none）的元素之内。
+func inHiddenSubtree(f *Fiber) bool {
+	for c := f; c != nil; c = c.parent {
+		if c.rnode != nil && c.rnode.hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// modalLayer 返回 f 所在的 TrapFocus 浮层（主界面为 nil）。
+func modalLayer(f *Fiber) *Fiber {
+	for c := f; c != nil; c = c.parent {
+		if c.typ == typePortal && c.portalTrap {
+			return c
+		}
+	}
+	return nil
+}
+
+// hotkeyActive 判断 r 此刻是否生效。
+func (g *game) hotkeyActive(r *hotkeyReg, trap *Fiber) bool {
+	if r.fiber.unmounted || inHiddenSubtree(r.fiber) {
+		return false // 隐藏子树（如后台标签页）里的快捷键不生效
+	}
+	if trap != nil && !isAncestor(trap, r.fiber) {
+		return false // 模态打开时背景里的快捷键一律不生效
+	}
+	switch r.info.Scope {
+	case ScopeModal:
+		return trap != nil
+	case ScopeFocused:
+		return g.focusedFiber != nil && isAncestor(r.fiber, g.focusedFiber)
+	}
+	return true
+}
+
+// hotkeyStroke 处理一次按键：补全了某个快捷键就触发，是某组和弦的开头就先记下。
+func (g *game) hotkeyStroke(s keyStroke) bool {
+	if len(g.chord) > 0 && clock().Sub(g.chordAt) > chordTimeout {
+		g.chord = nil
+	}
+	if len(g.hotkeys) == 0 {
+		return false
+	}
+	if rn := g.focusedRNode(); rn != nil && rn.kind == rnInput && s.mods&^modShift == 0 && !reservedWhileTyping(s.key) {
+		g.chord = nil
+		return false // 打字优先
+	}
+	seq := append(append([]keyStroke(nil), g.chord...), s)
+	r, prefix := g.matchHotkey(seq)
+	switch {
+	case r != nil:
+		g.chord = nil
+		(*r.fn)()
+		return true
+	case prefix:
+		g.chord, g.chordAt = seq, clock()
+		return true
+	case len(g.chord) > 0:
+		g.chord = nil // 和弦断了：把这一键当作新的开头再试一次
+		return g.hotkeyStroke(s)
+	}
+	return false
+}
+
+// reservedWhileTyping 报告输入框聚焦时仍应交给快捷键的无修饰按键。
+func reservedWhileTyping(k string) bool {
+	return k == "Esc" || (len(k) > 1 && k[0] == 'F')
+}
+
+func (g *game) focusedRNode() *renderNode {
+	if g.focusedFiber == nil || g.focusedFiber.unmounted {
+		return nil
+	}
+	return g.focusedFiber.rnode
+}
+
+// matchHotkey 返回与 seq 完全匹配且优先级最高的生效快捷键，并报告 seq 是否是某组和弦的开头。
+// 优先级：ScopeFocused（离焦点越近越优先）> ScopeModal > ScopeGlobal，同级后注册者优先。
+func (g *game) matchHotkey(seq []keyStroke) (best *hotkeyReg, prefix bool) {
+	trap := g.trapPortal()
+	bestRank := -1
+	for _, r := range g.hotkeys {
+		if !g.hotkeyActive(r, trap) {
+			continue
+		}
+		if strokesPrefix(seq, r.steps) {
+			prefix = true
+			continue
+		}
+		if !strokesEqual(r.steps, seq) {
+			continue
+		}
+		rank := 0
+		switch r.info.Scope {
+		case ScopeFocused:
+			rank = 1000 + depth(r.fiber)
+		case ScopeModal:
+			rank = 500
+		}
+		if rank >= bestRank {
+			best, bestRank = r, rank
+		}
+	}
+	return best, prefix
+}
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 解析：修饰键顺序无关、别名归一、Mod 随平台；写错给出错误。
+func TestParseHotkey(t *testing.T) {
+	defer func(m bool) { hotkeyMac = m }(hotkeyMac)
+	hotkeyMac = false
+	cases := map[string]string{
+		"ctrl+s":        "Ctrl+S",
+		"Shift+Ctrl+S":  "Ctrl+Shift+S",
+		"Mod+Enter":     "Ctrl+Enter",
+		"alt+escape":    "Alt+Esc",
+		"Ctrl+K Ctrl+C": "Ctrl+K Ctrl+C",
+		"f5":            "F5",
+		"Ctrl+Plus":     "Ctrl++",
+	}
+	for in, want := range cases {
+		steps, err := parseHotkey(in)
+		if err != nil || formatSteps(steps) != want {
+			t.Errorf("parseHotkey(%q) = %q, %v; want %q", in, formatSteps(steps), err, want)
+		}
+	}
+	for _, bad := range []string{"", "Ctrl+", "Hyper+S", "Ctrl+Shift", "F25", "Ctrl+Foo"} {
+		if _, err := parseHotkey(bad); err == nil {
+			t.Errorf("parseHotkey(%q) 应报错", bad)
+		}
+	}
+	hotkeyMac = true
+	if got := HotkeyText("Mod+Shift+S"); got != "⇧⌘S" {
+		t.Errorf("mac HotkeyText = %q", got)
+	}
+	if got := HotkeyKeys("Mod+K Mod+C"); !reflect.DeepEqual(got, [][]string{{"⌘", "K"}, {"⌘", "C"}}) {
+		t.Errorf("mac HotkeyKeys = %v", got)
+	}
+}
+
+// 全局快捷键、和弦、输入框里不抢普通按键。
+func TestUseHotkeyGlobalAndChord(t *testing.T) {
+	var log []string
+	app := func(_ struct{}) *Node {
+		v, setV := UseState("")
+		UseHotkey("Ctrl+S", func() { log = append(log, "save") }, HotkeyLabel("Save"))
+		UseHotkey("Ctrl+K Ctrl+C", func() { log = append(log, "comment") })
+		UseHotkey("G", func() { log = append(log, "g") })
+		return Div(Input(Value(v), OnChange(setV), Placeholder("in")))
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+
+	h.Key("Ctrl+S")
+	h.Key("Ctrl+K Ctrl+C")
+	h.Key("Ctrl+K") // 和弦断开：下一键不是 Ctrl+C，按新的开头处理
+	h.Key("Ctrl+S")
+	h.Key("G")
+	if want := []string{"save", "comment", "save", "g"}; !reflect.DeepEqual(log, want) {
+		t.Fatalf("log = %v want %v", log, want)
+	}
+
+	h.Root().ByPlaceholder("in").Focus()
+	log = nil
+	h.Key("G")      // 打字优先
+	h.Key("Ctrl+S") // 带 Ctrl 的照常
+	if want := []string{"save"}; !reflect.DeepEqual(log, want) {
+		t.Fatalf("输入框聚焦时 log = %v want %v", log, want)
+	}
+
+	infos := h.Window().Hotkeys()
+	if len(infos) != 3 || infos[0].Combo != "Ctrl+S" || infos[0].Label != "Save" {
+		t.Fatalf("注册表 = %+v", infos)
+	}
+}
+
+// ScopeFocused 只在焦点位于子树内时生效，且优先于同组合的全局快捷键；
+// 模态打开时背景的快捷键被挡住，模态内的与 ScopeModal 生效。
+func TestUseHotkeyScopes(t *testing.T) {
+	var log []string
+	var setModal func(bool)
+	editor := func(_ struct{}) *Node {
+		UseHotkey("Ctrl+Enter", func() { log = append(log, "editor") }, HotkeyScope(ScopeFocused))
+		return Div(Input(Placeholder("editor")))
+	}
+	dialog := func(_ struct{}) *Node {
+		UseHotkey("Ctrl+Enter", func() { log = append(log, "dialog") })
+		UseHotkey("Ctrl+W", func() { log = append(log, "modal-only") }, HotkeyScope(ScopeModal))
+		return Div(Button(OnClick(func() {}), Text("ok")))
+	}
+	app := func(_ struct{}) *Node {
+		modal, sm := UseState(false)
+		setModal = sm
+		UseHotkey("Ctrl+Enter", func() { log = append(log, "global") })
+		return Div(Input(Placeholder("other")), Use(editor, struct{}{}),
+			If(modal, Portal(TrapFocus(), Use(dialog, struct{}{}))))
+	}
+	h := Mount(Use(app, struct{}{}), 300, 200)
+
+	h.Key("Ctrl+Enter")
+	h.Root().ByPlaceholder("editor").Focus()
+	h.Key("Ctrl+Enter")
+	h.Root().ByPlaceholder("other").Focus()
+	h.Key("Ctrl+Enter")
+	setModal(true)
+	h.Flush()
+	h.Key("Ctrl+Enter")
+	h.Key("Ctrl+W")
+	want := []string{"global", "editor", "global", "dialog", "modal-only"}
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("log = %v want %v", log, want)
+	}
+}
+
+// 冲突检测：同层同作用域的相同组合、单键是和弦的开头；不同作用域的不算。
+func TestHotkeyConflicts(t *testing.T) {
+	a := func(_ struct{}) *Node {
+		UseHotkey("Ctrl+S", func() {})
+		UseHotkey("Ctrl+K", func() {})
+		return Div()
+	}
+	b := func(_ struct{}) *Node {
+		UseHotkey("ctrl+s", func() {})
+		UseHotkey("Ctrl+K Ctrl+C", func() {})
+		UseHotkey("Ctrl+S", func() {}, HotkeyScope(ScopeFocused))
+		return Div()
+	}
+	h := Mount(Div(Use(a, struct{}{}), Use(b, struct{}{})), 100, 100)
+	got := h.Window().HotkeyConflicts()
+	if len(got) != 2 {
+		t.Fatalf("conflicts = %+v, want 2", got)
+	}
+	if got[0][0].Combo != "Ctrl+S" || got[1][1].Combo != "Ctrl+K Ctrl+C" {
+		t.Fatalf("conflicts = %+v", got)
+	}
+}
+
+// Harness.Key 也走内置处理：快捷键没接住的 Tab 照常移动焦点。
+func TestHarnessKeyDrivesBuiltins(t *testing.T) {
+	h := Mount(Div(Input(Placeholder("a")), Input(Placeholder("b"))), 200, 100)
+	h.Key("Tab")
+	if h.Focused().Placeholder() != "a" {
+		t.Fatal("Tab 应聚焦第一个输入框")
+	}
+	h.Key("Shift+Tab")
+	h.Key("Tab")
+	h.Key("Tab")
+	if h.Focused().Placeholder() != "b" {
+		t.Fatalf("focused = %q want b", h.Focused().Placeholder())
+	}
+}
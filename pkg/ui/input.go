@@ -53,6 +53,8 @@ type inputSource interface {
 	keyPressed(inKey) bool          // 键当前是否按住
 	keyJustPressed(inKey) bool      // 键本帧是否刚按下
 	typedChars() []rune             // 本帧输入的字符（不含控制键）
+	keyStrokes() []keyStroke        // 本帧按下的键（带修饰键），供 UseHotkey 匹配
+	dropStrokes(drop []bool)        // 撤掉被消费的按键（drop[i] 对应 keyStrokes()[i]）及其打出的字符
 }
 
 // input 是当前后端的输入源，由 gio_run.go 的 init 设定。
@@ -64,13 +66,17 @@ const (
 	repeatInterval = 33 * time.Millisecond // ~30 次/秒
 )
 
+// clock 是输入处理读墙钟的唯一入口（连击、长按重复、快捷键和弦、光标闪烁）。
+// Harness.Replay 把它换成会话里记录的帧时刻，回放才与录制时的时序一致。
+var clock = time.Now
+
 // keyNextRepeat 记录每个键下次允许重复触发的时刻。
 var keyNextRepeat = map[inKey]time.Time{}
 
 // repeatKey 在按下瞬间触发一次，长按后按固定时间间隔重复（不依赖帧率）。
 func repeatKey(k inKey) bool {
 	if input.keyJustPressed(k) {
-		keyNextRepeat[k] = time.Now().Add(repeatDelay)
+		keyNextRepeat[k] = clock().Add(repeatDelay)
 		return true
 	}
 	if !input.keyPressed(k) {
@@ -79,10 +85,10 @@ func repeatKey(k inKey) bool {
 	}
 	next, ok := keyNextRepeat[k]
 	if !ok { // 聚焦时键已被按住：先建立计时，不立即触发
-		keyNextRepeat[k] = time.Now().Add(repeatDelay)
+		keyNextRepeat[k] = clock().Add(repeatDelay)
 		return false
 	}
-	now := time.Now()
+	now := clock()
 	if now.Before(next) {
 		return false
 	}
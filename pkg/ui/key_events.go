@@ -0,0 +1,175 @@
+package ui
+
+// ---- 元素级键盘与焦点事件 ----
+//
+// OnKeyDown 让元素直接收到按键：事件先交给聚焦元素，再像 OnClick 一样沿祖先向上冒泡，
+// 最后才到窗口级的快捷键（UseHotkey）与内置行为（Tab 导航、Enter/Space 激活、输入框编辑）。
+//
+//	ui.Input(ui.Value(v), ui.OnChange(setV), ui.OnKeyDown(func(e *ui.KeyEvent) {
+//	    if e.Is("Mod+Enter") {
+//	        send()
+//	        e.PreventDefault() // 不要再把回车交给输入框
+//	    }
+//	}))
+//
+// StopPropagation 让事件不再往祖先与快捷键传；PreventDefault 取消这一键的内置处理
+// （同一帧里的其他按键不受影响）。
+//
+// OnFocus / OnBlur 在焦点进入 / 离开元素（含其后代）时回调：焦点在子元素之间移动不算离开，
+// 所以包着输入框和「确定」按钮的行内编辑器可以在 OnBlur 里提交。
+
+// KeyEvent 是一次按键。Key 为规范键名，与 UseHotkey 的写法相同（"A"、"Enter"、"Up"、"F5" …）。
+type KeyEvent struct {
+	Key    string
+	Ctrl   bool
+	Alt    bool
+	Shift  bool
+	Meta   bool // macOS 的 ⌘ / 其他平台的 Super
+	Repeat bool // 按住不放产生的自动重复
+
+	stopped   bool
+	prevented bool
+}
+
+// StopPropagation 阻止事件继续冒泡给祖先元素和快捷键。
+func (e *KeyEvent) StopPropagation() { e.stopped = true }
+
+// PreventDefault 取消内置处理：Tab 导航、Enter/Space 激活、方向键导航、输入框编辑与粘贴。
+func (e *KeyEvent) PreventDefault() { e.prevented = true }
+
+// DefaultPrevented 报告是否已有处理器调用过 PreventDefault。
+func (e *KeyEvent) DefaultPrevented() bool { return e.prevented }
+
+// Is 报告这次按键是否正是 combo（单步写法同 UseHotkey，如 "Ctrl+Shift+Z"、"Mod+Enter"）。
+// 修饰键必须完全一致；写错或是多步和弦时返回 false。
+func (e *KeyEvent) Is(combo string) bool {
+	steps, err := parseHotkey(combo)
+	if err != nil || len(steps) != 1 {
+		return false
+	}
+	return steps[0].mods == e.stroke().mods && steps[0].key == e.Key
+}
+
+func (e *KeyEvent) stroke() keyStroke {
+	s := keyStroke{key: e.Key, repeat: e.Repeat}
+	if e.Ctrl {
+		s.mods |= modCtrl
+	}
+	if e.Alt {
+		s.mods |= modAlt
+	}
+	if e.Shift {
+		s.mods |= modShift
+	}
+	if e.Meta {
+		s.mods |= modMeta
+	}
+	return s
+}
+
+func newKeyEvent(s keyStroke) *KeyEvent {
+	return &KeyEvent{Key: s.key, Repeat: s.repeat,
+		Ctrl: s.mods&modCtrl != 0, Alt: s.mods&modAlt != 0, Shift: s.mods&modShift != 0, Meta: s.mods&modMeta != 0}
+}
+
+// OnKeyDown 在元素（或其后代）聚焦时收到按键。带 OnKeyDown 的元素可以被 Tab 与点击聚焦。
+func OnKeyDown(fn func(*KeyEvent)) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.onKeyDown = fn }}
+}
+
+// OnFocus 在焦点从外面进入本元素或其后代时回调。
+func OnFocus(fn func()) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.onFocus = fn }}
+}
+
+// OnBlur 在焦点离开本元素及其全部后代时回调（移到别处或被清空）。
+func OnBlur(fn func()) *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.onBlur = fn }}
+}
+
+// dispatchKeys 把本帧的每次按键依次交给焦点链上的 OnKeyDown（冒泡）与快捷键表；
+// 返回每次按键是否被消费（PreventDefault 或被快捷键接走），没有按键被消费时为 nil。
+// 只有被消费的那几次按键交给 dropStrokes 撤掉，同一帧里的其余按键照常走内置处理。
+func (g *game) dispatchKeys() []bool {
+	strokes := input.keyStrokes()
+	var drop []bool
+	consume := func(i int) {
+		if drop == nil {
+			drop = make([]bool, len(strokes))
+		}
+		drop[i] = true
+	}
+	for i, s := range strokes {
+		if g.inspectorKeyOn() && !s.repeat && s.mods == inspectorKey.mods && s.key == inspectorKey.key {
+			g.setInspecting(!g.inspecting)
+			consume(i)
+			continue
+		}
+		e := g.fireKeyDown(s)
+		if e.prevented {
+			consume(i)
+		}
+		if !e.stopped && g.hotkeyStroke(s) {
+			consume(i)
+		}
+	}
+	return drop
+}
+
+// fireKeyDown 从聚焦元素向上冒泡 OnKeyDown，直到有处理器 StopPropagation。
+func (g *game) fireKeyDown(s keyStroke) *KeyEvent {
+	e := newKeyEvent(s)
+	for c := g.focusedRNode(); c != nil && !e.stopped; c = c.parent {
+		if c.onKeyDown != nil && (c.owner == nil || !c.owner.unmounted) {
+			c.onKeyDown(e)
+		}
+	}
+	return e
+}
+
+// wantsAllKeys 报告焦点链上是否有 OnKeyDown —— 有的话 gio 后端要把所有按键都投递过来。
+func (g *game) wantsAllKeys() bool {
+	for c := g.focusedRNode(); c != nil; c = c.parent {
+		if c.onKeyDown != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// keyTarget 返回点击 n 时应聚焦的键盘组件：向上第一个带 OnKeyDown 的元素。
+func keyTarget(n *renderNode) *renderNode {
+	for c := n; c != nil; c = c.parent {
+		if c.onKeyDown != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// syncFocus 在焦点变化后回调 OnBlur / OnFocus：只通知真正离开 / 进入的那段祖先链，
+// 先离开（由内向外）后进入（由内向外）。
+func (g *game) syncFocus() {
+	cur := g.focusedRNode()
+	old := g.focusNotified
+	if cur == old {
+		return
+	}
+	g.focusNotified = cur
+	in := map[*renderNode]bool{}
+	for c := cur; c != nil; c = c.parent {
+		in[c] = true
+	}
+	was := map[*renderNode]bool{}
+	for c := old; c != nil; c = c.parent {
+		was[c] = true
+		if !in[c] && c.onBlur != nil && (c.owner == nil || !c.owner.unmounted) {
+			c.onBlur()
+		}
+	}
+	for c := cur; c != nil; c = c.parent {
+		if !was[c] && c.onFocus != nil {
+			c.onFocus()
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+// OnKeyDown 从聚焦元素向上冒泡，StopPropagation 之后祖先与快捷键都收不到。
+func TestOnKeyDownBubblesAndStops(t *testing.T) {
+	var log []string
+	hot := 0
+	app := func(_ struct{}) *Node {
+		UseHotkey("X", func() { hot++ })
+		return Div(
+			OnKeyDown(func(e *KeyEvent) { log = append(log, "outer:"+e.Key) }),
+			Div(Id("inner"), Style(Width(50), Height(20)),
+				OnKeyDown(func(e *KeyEvent) {
+					log = append(log, "inner:"+e.Key)
+					if e.Key == "X" {
+						e.StopPropagation()
+					}
+				})),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	h.Root().Find(func(q *Query) bool { return q.rn.id == "inner" }).Focus()
+	h.Key("Ctrl+Y")
+	h.Key("X")
+	if got := strings.Join(log, " "); got != "inner:Y outer:Y inner:X" {
+		t.Fatalf("冒泡顺序 = %q", got)
+	}
+	if hot != 0 {
+		t.Fatal("StopPropagation 之后快捷键不应触发")
+	}
+}
+
+// PreventDefault 取消内置处理：Enter 不再激活按钮，Tab 不再移动焦点，回车不再提交输入框。
+func TestKeyDownPreventDefault(t *testing.T) {
+	clicked, submitted := 0, 0
+	prevent := func(e *KeyEvent) {
+		if e.Is("Enter") || e.Is("Tab") {
+			e.PreventDefault()
+		}
+	}
+	app := func(_ struct{}) *Node {
+		return Div(
+			Button(OnClick(func() { clicked++ }), OnKeyDown(prevent), Text("ok")),
+			Input(Placeholder("q"), OnSubmit(func(string) { submitted++ }), OnKeyDown(prevent)),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	if !h.Tab().ByText("ok").Exists() {
+		t.Fatal("Tab 应先聚焦按钮")
+	}
+	h.Key("Enter")
+	if clicked != 0 {
+		t.Fatal("PreventDefault 后 Enter 不应激活按钮")
+	}
+	h.Key("Tab")
+	if !h.Focused().ByText("ok").Exists() {
+		t.Fatal("PreventDefault 后 Tab 不应移动焦点")
+	}
+	h.Root().ByText("ok").Click()
+	if clicked != 1 {
+		t.Fatal("点击不受 OnKeyDown 影响")
+	}
+	h.Root().ByPlaceholder("q").Focus()
+	h.Key("Enter")
+	if submitted != 0 {
+		t.Fatal("PreventDefault 后回车不应提交")
+	}
+}
+
+// 被消费的只是那一键：快速打字时 gio 会把几次按键攒进同一帧，其余字符照常打进输入框。
+func TestConsumedStrokeKeepsRestOfFrame(t *testing.T) {
+	hot := 0
+	app := func(_ struct{}) *Node {
+		v, setV := UseState("")
+		UseHotkey("Ctrl+K", func() { hot++ })
+		return Input(Placeholder("q"), Value(v), OnChange(setV), OnKeyDown(func(e *KeyEvent) {
+			if e.Is("X") {
+				e.PreventDefault()
+			}
+		}))
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	q := h.Root().ByPlaceholder("q").Focus()
+
+	h.Dispatch(KeyEvent{Key: "K", Ctrl: true}, TextEvent{Text: "a"})
+	if hot != 1 || q.Value() != "a" {
+		t.Fatalf("快捷键与同帧的字符都应生效：hot=%d value=%q", hot, q.Value())
+	}
+	h.Dispatch(KeyEvent{Key: "X"}, TextEvent{Text: "x"}, KeyEvent{Key: "Y"}, TextEvent{Text: "y"})
+	if got := q.Value(); got != "ay" {
+		t.Fatalf("只撤掉被 PreventDefault 的那一键打出的字符：%q", got)
+	}
+}
+
+// OnFocus / OnBlur 按「焦点进出整个子树」通知：在子元素间移动不算离开。
+func TestFocusBlurWithin(t *testing.T) {
+	var log []string
+	app := func(_ struct{}) *Node {
+		return Div(
+			Div(OnFocus(func() { log = append(log, "focus") }), OnBlur(func() { log = append(log, "blur") }),
+				Input(Placeholder("name")),
+				Button(OnClick(func() {}), Text("save")),
+			),
+			Button(OnClick(func() {}), Text("other")),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 300, 100)
+	h.Root().ByPlaceholder("name").Focus()
+	h.Tab() // 到 save，仍在编辑器里
+	if got := strings.Join(log, " "); got != "focus" {
+		t.Fatalf("子树内移动焦点 log = %q", got)
+	}
+	h.Tab() // 到 other，离开编辑器
+	h.Tab() // 回绕到 name，再次进入
+	h.Escape()
+	if got := strings.Join(log, " "); got != "focus blur focus blur" {
+		t.Fatalf("log = %q", got)
+	}
+}
+
+// 点击带 OnKeyDown 的元素（或其后代）会把焦点给它，之后按键就能送到。
+func TestClickFocusesKeyTarget(t *testing.T) {
+	var keys []string
+	app := func(_ struct{}) *Node {
+		return Div(Style(Width(200), Height(100)),
+			OnKeyDown(func(e *KeyEvent) { keys = append(keys, e.Key) }),
+			Text("canvas"),
+		)
+	}
+	h := Mount(Use(app, struct{}{}), 200, 100)
+	b := h.Root().ByText("canvas").Bounds()
+	if !h.MouseDown(b.X+1, b.Y+1).Exists() {
+		t.Fatal("点击后应聚焦带 OnKeyDown 的元素")
+	}
+	h.Key("Down")
+	if len(keys) != 1 || keys[0] != "Down" {
+		t.Fatalf("keys = %v", keys)
+	}
+}
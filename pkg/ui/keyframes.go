@@ -0,0 +1,147 @@
+package ui
+
+// ---- 关键帧与时间轴 ----
+//
+// UseTween 只有「从当前值到目标」一段。多段动画（先放大再回弹、淡入后停留再滑出）和
+// 多个值错开启动（列表项依次进场）用 UseKeyframes：每条 AnimTrack 是一串关键帧，
+// 多条 AnimTrack 并行播放，各自的 Delay 把起点错开。
+
+// Keyframe 是轨道上的一个点：At 毫秒时值为 Value。Ease 是从上一个关键帧到本帧的缓动，
+// nil 为 Linear。
+type Keyframe struct {
+	At    float32
+	Value float32
+	Ease  Easing
+}
+
+// AnimTrack 是一个值的关键帧序列（按 At 升序），Delay 毫秒后开始。开始前与第一个关键帧之前
+// 取第一帧的值，最后一帧之后保持最后的值。
+type AnimTrack struct {
+	Delay  float32
+	Frames []Keyframe
+}
+
+// Keyframes 用一串关键帧构造一条无延迟的 AnimTrack。
+func Keyframes(frames ...Keyframe) AnimTrack { return AnimTrack{Frames: frames} }
+
+// Stagger 返回 n 条同样的轨道，第 i 条延迟 i*gapMs 毫秒 —— 列表项依次进场。
+func Stagger(n int, gapMs float32, frames ...Keyframe) []AnimTrack {
+	out := make([]AnimTrack, n)
+	for i := range out {
+		out[i] = AnimTrack{Delay: float32(i) * gapMs, Frames: frames}
+	}
+	return out
+}
+
+// Sequence 把几条轨道首尾相接：每条的 Delay 再加上前面各条的总时长，依次播放。
+func Sequence(tracks ...AnimTrack) []AnimTrack {
+	out := make([]AnimTrack, len(tracks))
+	var t float32
+	for i, tr := range tracks {
+		tr.Delay += t
+		out[i] = tr
+		t = tr.end()
+	}
+	return out
+}
+
+// end 是轨道播完的时刻（毫秒，含 Delay）。
+func (tr AnimTrack) end() float32 {
+	if len(tr.Frames) == 0 {
+		return tr.Delay
+	}
+	return tr.Delay + tr.Frames[len(tr.Frames)-1].At
+}
+
+// valueAt 是轨道在 t 毫秒（从时间轴起点算）时的值。
+func (tr AnimTrack) valueAt(t float32) float32 {
+	fs := tr.Frames
+	if len(fs) == 0 {
+		return 0
+	}
+	t -= tr.Delay
+	if t <= fs[0].At {
+		return fs[0].Value
+	}
+	for i := 1; i < len(fs); i++ {
+		a, b := fs[i-1], fs[i]
+		if t >= b.At {
+			continue
+		}
+		span := b.At - a.At
+		if span <= 0 {
+			return b.Value
+		}
+		ease := b.Ease
+		if ease == nil {
+			ease = Linear
+		}
+		return a.Value + (b.Value-a.Value)*ease((t-a.At)/span)
+	}
+	return fs[len(fs)-1].Value
+}
+
+type keyframesHook struct {
+	tracks  []AnimTrack
+	deps    []any
+	started bool    // 首次渲染已开始播放
+	elapsed float32 // 毫秒
+	values  []float32
+	fiber   *Fiber
+	active  bool
+}
+
+// UseKeyframes 播放一组轨道，返回每条轨道当前的值（与 tracks 同序）。挂载时开始播放，
+// deps 变化时从头重播（比较规则同 UseEffect）；全部轨道播完后停在各自的最后一帧，
+// 不再重渲染。tracks 每次渲染都取最新的，但改动它本身不会重播。
+//
+//	v := ui.UseKeyframes(ui.Stagger(len(items), 40,
+//		ui.Keyframe{At: 0, Value: 0},
+//		ui.Keyframe{At: 200, Value: 1, Ease: ui.EaseOut}), len(items))
+func UseKeyframes(tracks []AnimTrack, deps ...any) []float32 {
+	f := currentFiber
+	_, raw := nextHook(f, func() any { return &keyframesHook{} })
+	h := raw.(*keyframesHook)
+	h.fiber = f
+	h.tracks = tracks
+	if !h.started || !depsEqual(h.deps, deps) {
+		h.started, h.deps = true, deps
+		h.elapsed = 0
+		if !h.active && h.duration() > 0 {
+			h.active = true
+			startAnim(f, h)
+		}
+	}
+	h.sample()
+	return h.values
+}
+
+func (h *keyframesHook) duration() float32 {
+	var d float32
+	for _, tr := range h.tracks {
+		d = max(d, tr.end())
+	}
+	return d
+}
+
+// sample 按 elapsed 取各轨道的值。每次返回新切片：上一次渲染拿到的值不会被悄悄改掉。
+func (h *keyframesHook) sample() {
+	vs := make([]float32, len(h.tracks))
+	for i, tr := range h.tracks {
+		vs[i] = tr.valueAt(h.elapsed)
+	}
+	h.values = vs
+}
+
+func (h *keyframesHook) owner() *Fiber { return h.fiber }
+func (h *keyframesHook) running() bool { return h.active }
+
+func (h *keyframesHook) advance(dt float32) bool {
+	h.elapsed += dt
+	if h.elapsed >= h.duration() {
+		h.elapsed = h.duration()
+		h.active = false
+		return false
+	}
+	return true
+}
@@ -6,12 +6,14 @@ type measureHook struct {
 	fiber *Fiber
 }
 
-// Viewport 返回当前视口的逻辑尺寸（用于浮层贴边翻转等）。
+// Viewport 返回当前视口的逻辑尺寸（用于浮层贴边翻转等）。多窗口时是正在渲染的组件所在
+// 窗口的视口；窗口外要取某个窗口的视口用 Window.Viewport。
 func Viewport() Rect {
-	if activeGame == nil {
+	g := renderingGame()
+	if g == nil {
 		return Rect{}
 	}
-	return Rect{W: float32(activeGame.w) / uiScale, H: float32(activeGame.h) / uiScale}
+	return g.viewport()
 }
 
 // UseMeasure 返回一个属性和该元素最近测得的矩形（逻辑像素）。
@@ -61,18 +63,14 @@ func syncMeasures(rn *renderNode) {
 		}
 		if rn.measure.rect != lg {
 			rn.measure.rect = lg
-			if activeGame != nil {
-				activeGame.markDirty(rn.measure.fiber)
-			}
+			markFiberDirty(rn.measure.fiber)
 		}
 	}
 	if rn.scrollRef != nil && rn.scroll {
 		info := ScrollInfo{Offset: rn.scrollY / uiScale, Viewport: rn.bounds.H / uiScale}
 		if rn.scrollRef.info != info {
 			rn.scrollRef.info = info
-			if activeGame != nil {
-				activeGame.markDirty(rn.scrollRef.fiber)
-			}
+			markFiberDirty(rn.scrollRef.fiber)
 		}
 	}
 	for _, c := range rn.children {
@@ -0,0 +1,156 @@
+package ui
+
+// ---- 响应式：断点、媒体查询 hook 与 At 样式 ----
+//
+//	wide := ui.UseMediaQuery(768, 0)      // 窗口宽 ≥ 768 时为 true
+//	bp := ui.UseBreakpoint()              // 当前断点（BreakpointSm、BreakpointMd…）
+//	ui.Style(ui.Column, ui.At(ui.BreakpointMd, ui.Row, ui.Gap(24)))  // md 及以上改为横排
+//
+// 窗口尺寸变化时 syncMedia 只让结果变了的订阅者重渲染：跨过阈值的 UseMediaQuery /
+// UseBreakpoint 所在组件，以及用了 At 的元素（只重算样式，不重渲染组件）。
+// 其余组件不必轮询 Viewport，拖动窗口边框也不会引起整树重渲染。
+
+// Breakpoint 是按窗口逻辑宽度划分的响应式断点（与 Tailwind 的默认断点一致）。
+type Breakpoint int
+
+const (
+	BreakpointBase Breakpoint = iota // < 640
+	BreakpointSm                     // ≥ 640
+	BreakpointMd                     // ≥ 768
+	BreakpointLg                     // ≥ 1024
+	BreakpointXl                     // ≥ 1280
+	Breakpoint2xl                    // ≥ 1536
+)
+
+var breakpointMin = [...]float32{0, 640, 768, 1024, 1280, 1536}
+
+// MinWidth 返回断点的起始宽度（逻辑像素）。
+func (b Breakpoint) MinWidth() float32 {
+	return breakpointMin[max(0, min(int(b), len(breakpointMin)-1))]
+}
+
+func (b Breakpoint) String() string {
+	return [...]string{"base", "sm", "md", "lg", "xl", "2xl"}[max(0, min(int(b), len(breakpointMin)-1))]
+}
+
+// breakpointOf 返回宽度 w 所在的断点。
+func breakpointOf(w float32) Breakpoint {
+	b := BreakpointBase
+	for i, m := range breakpointMin {
+		if w >= m {
+			b = Breakpoint(i)
+		}
+	}
+	return b
+}
+
+// viewport 返回窗口的逻辑尺寸。
+func (g *game) viewport() Rect {
+	if g.win != nil {
+		return g.win.Viewport()
+	}
+	return Rect{W: float32(g.w) / uiScale, H: float32(g.h) / uiScale}
+}
+
+// mediaHook 是一个订阅窗口宽度的 hook：eval 把宽度映射成结果，结果变了才重渲染 fiber。
+type mediaHook struct {
+	fiber *Fiber
+	eval  func(w float32) any
+	value any
+}
+
+func useMedia(eval func(w float32) any) any {
+	f := currentFiber
+	g := gameOf(f)
+	_, raw := nextHook(f, func() any {
+		h := &mediaHook{fiber: f}
+		if g != nil {
+			if g.media == nil {
+				g.media = map[*mediaHook]struct{}{}
+			}
+			g.media[h] = struct{}{}
+		}
+		return h
+	})
+	h := raw.(*mediaHook)
+	h.fiber, h.eval = f, eval
+	if g != nil {
+		h.value = eval(g.viewport().W)
+	}
+	return h.value
+}
+
+// UseMediaQuery 报告窗口逻辑宽度是否落在 [minW, maxW) 内；maxW <= 0 表示不设上限。
+// 只有结果翻转时调用它的组件才会重渲染。
+func UseMediaQuery(minW, maxW float32) bool {
+	return useMedia(func(w float32) any {
+		return w >= minW && (maxW <= 0 || w < maxW)
+	}).(bool)
+}
+
+// UseBreakpoint 返回窗口当前所在的断点；跨过断点时调用它的组件重渲染。
+func UseBreakpoint() Breakpoint {
+	return useMedia(func(w float32) any { return breakpointOf(w) }).(Breakpoint)
+}
+
+// At 只在窗口宽度达到断点 bp 时应用 opts（移动优先，同 Tailwind 的 md: 前缀）：
+//
+//	ui.Style(ui.Column, ui.At(ui.BreakpointMd, ui.Row))
+//
+// 跨过断点时元素自动重算样式，组件本身不重渲染。
+func At(bp Breakpoint, opts ...StyleOpt) StyleOpt {
+	return func(s *StyleProps) {
+		s.responsive = true
+		g := activeGame
+		if g == nil || g.viewport().W < bp.MinWidth() {
+			return
+		}
+		for _, o := range opts {
+			o(s)
+		}
+	}
+}
+
+// trackResponsive 登记（或注销）用了 At 的元素，供跨断点时重算样式。
+func (g *game) trackResponsive(rn *renderNode, on bool) {
+	if !on {
+		delete(g.responsive, rn)
+		return
+	}
+	if g.responsive == nil {
+		g.responsive = map[*renderNode]struct{}{}
+	}
+	g.responsive[rn] = struct{}{}
+}
+
+// syncMedia 在窗口宽度变化后重算媒体查询：结果变了的 hook 标记其组件重渲染，
+// 跨过断点时重算 At 元素的样式。每帧在重渲染之前调用，宽度不变时直接返回。
+// 订阅在 unmount 时注销，这里遇到已卸载的只是兜底。
+func (g *game) syncMedia() {
+	w := g.viewport().W
+	if w == g.mediaW {
+		return
+	}
+	g.mediaW = w
+	for h := range g.media {
+		if h.fiber == nil || h.fiber.unmounted {
+			delete(g.media, h)
+			continue
+		}
+		if v := h.eval(w); v != h.value {
+			h.value = v
+			markFiberDirty(h.fiber)
+		}
+	}
+	if bp := breakpointOf(w); bp != g.breakpoint {
+		g.breakpoint = bp
+		for rn := range g.responsive {
+			if rn.owner == nil || rn.owner.unmounted {
+				delete(g.responsive, rn)
+				continue
+			}
+			rn.restyle()
+		}
+		g.needsLayout = true
+	}
+}
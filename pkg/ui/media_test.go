@@ -0,0 +1,114 @@
+package ui
+
+import "testing"
+
+// 窗口跨过阈值时只有结果变了的订阅者重渲染；阈值内拖动不引起任何重渲染。
+func TestMediaQueryRerendersOnlySubscribers(t *testing.T) {
+	var wideRenders, bpRenders, plainRenders int
+	var wide bool
+	var bp Breakpoint
+	wideComp := func(_ struct{}) *Node {
+		wideRenders++
+		wide = UseMediaQuery(700, 0)
+		return Text("wide")
+	}
+	bpComp := func(_ struct{}) *Node {
+		bpRenders++
+		bp = UseBreakpoint()
+		return Text("bp")
+	}
+	plain := func(_ struct{}) *Node {
+		plainRenders++
+		return Text("plain")
+	}
+	h := Mount(Div(Use(wideComp, struct{}{}), Use(bpComp, struct{}{}), Use(plain, struct{}{})), 800, 600)
+	if !wide || bp != BreakpointMd {
+		t.Fatalf("初始 wide=%v bp=%v", wide, bp)
+	}
+
+	h.Resize(900, 600) // 仍在 [700,∞) 与 md 内
+	if wideRenders != 1 || bpRenders != 1 || plainRenders != 1 {
+		t.Fatalf("阈值内 resize 不应重渲染：wide=%d bp=%d plain=%d", wideRenders, bpRenders, plainRenders)
+	}
+
+	h.Resize(720, 600) // 跨过 md(768) 进入 sm，但仍 ≥ 700
+	if wideRenders != 1 || bpRenders != 2 || bp != BreakpointSm {
+		t.Fatalf("跨断点：wide=%d bp=%d (%v)", wideRenders, bpRenders, bp)
+	}
+
+	h.Resize(500, 600)
+	if wideRenders != 2 || wide || bpRenders != 3 || bp != BreakpointBase {
+		t.Fatalf("跨两个阈值：wide=%d(%v) bp=%d(%v)", wideRenders, wide, bpRenders, bp)
+	}
+	if plainRenders != 1 {
+		t.Fatalf("未订阅的组件被重渲染了 %d 次", plainRenders)
+	}
+}
+
+// At 只在达到断点时生效，跨断点时元素重算样式（组件不重渲染）。
+func TestAtRestylesAcrossBreakpoint(t *testing.T) {
+	renders := 0
+	comp := func(_ struct{}) *Node {
+		renders++
+		box := func(id string) *Node { return Div(Id(id), Style(Width(50), Height(50))) }
+		return Div(Style(Column, At(BreakpointMd, Row, Gap(10))), box("a"), box("b"))
+	}
+	h := Mount(Use(comp, struct{}{}), 800, 600)
+	if b := byID(h, "b").Bounds(); b.X != 60 || b.Y != 0 {
+		t.Fatalf("md 及以上应横排：b=%+v", b)
+	}
+	h.Resize(600, 600)
+	if b := byID(h, "b").Bounds(); b.X != 0 || b.Y != 50 {
+		t.Fatalf("md 以下应竖排且无间距：b=%+v", b)
+	}
+	h.Resize(1000, 600)
+	if b := byID(h, "b").Bounds(); b.X != 60 {
+		t.Fatalf("回到 md 以上应恢复横排：b=%+v", b)
+	}
+	if renders != 1 {
+		t.Fatalf("At 不应让组件重渲染，renders=%d", renders)
+	}
+}
+
+// 卸载时注销订阅：反复挂载/卸载而窗口不变，注册表也不会累积。
+func TestMediaUnregistersOnUnmount(t *testing.T) {
+	child := func(_ struct{}) *Node {
+		UseMediaQuery(700, 0)
+		return Div(Style(At(BreakpointMd, Row)), Text("child"))
+	}
+	var setShow func(bool)
+	comp := func(_ struct{}) *Node {
+		show, set := UseState(true)
+		setShow = set
+		if !show {
+			return Text("none")
+		}
+		return Use(child, struct{}{})
+	}
+	h := Mount(Use(comp, struct{}{}), 800, 600)
+	if len(h.g.media) != 1 || len(h.g.responsive) != 1 {
+		t.Fatalf("挂载后应各登记一项：media=%d responsive=%d", len(h.g.media), len(h.g.responsive))
+	}
+	for range 3 {
+		setShow(false)
+		h.Step(0)
+		setShow(true)
+		h.Step(0)
+	}
+	setShow(false)
+	h.Step(0)
+	if len(h.g.media) != 0 || len(h.g.responsive) != 0 {
+		t.Fatalf("卸载后应注销：media=%d responsive=%d", len(h.g.media), len(h.g.responsive))
+	}
+}
+
+func TestBreakpointOf(t *testing.T) {
+	for w, want := range map[float32]Breakpoint{
+		0: BreakpointBase, 639: BreakpointBase, 640: BreakpointSm, 767: BreakpointSm,
+		768: BreakpointMd, 1024: BreakpointLg, 1279: BreakpointLg, 1280: BreakpointXl, 4000: Breakpoint2xl,
+	} {
+		if got := breakpointOf(w); got != want {
+			t.Errorf("breakpointOf(%v) = %v, want %v", w, got, want)
+		}
+	}
+}
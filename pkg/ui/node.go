@@ -61,10 +61,12 @@ type Node struct {
 // hostProps 是把某个 host 元素的所有属性归拢后的结果。
 type hostProps struct {
 	style   StyleProps
+	attrs   []func(*hostProps) // 产生本结果的属性列表（样式表层叠时要在规则之上重放）
 	onClick func()
 	class   string
 	id      string
 	key     string
+	a11y    a11yProps // 无障碍：角色、名称、状态（见 a11y.go）
 
 	// input
 	value         string
@@ -77,8 +79,25 @@ type hostProps struct {
 	onPress       func(bool)
 	onDrag        func(dx, dy float32)
 	onContextMenu func(x, y float32) // 右键（逻辑坐标）
-	measure       *measureHook
-	scrollRef     *scrollHook // UseScroll：把该 ScrollView 的滚动状态写回
+
+	selectable bool // Selectable()：其中的文字可选中复制（见 text_select.go）
+
+	// 键盘与焦点（见 key_events.go）
+	onKeyDown func(*KeyEvent)
+	onFocus   func()
+	onBlur    func()
+
+	// 拖放（见 dnd.go）
+	dragSource  bool
+	dragPayload any
+	dragPreview *Node
+	onDragging  func(bool)
+	dropTarget  bool
+	dropAccept  func(any) bool
+	onDrop      func(payload any, x, y float32)
+	onDragOver  func(bool)
+	measure     *measureHook
+	scrollRef   *scrollHook // UseScroll：把该 ScrollView 的滚动状态写回
 
 	// 方向键导航组（ArrowNav）：组内可聚焦项用方向键移动焦点
 	navGroup  bool
@@ -92,7 +111,10 @@ type hostProps struct {
 }
 
 func buildHostProps(n *Node) hostProps {
-	hp := hostProps{style: newStyleProps()}
+	hp := hostProps{style: newStyleProps(), attrs: n.attrList}
+	if n.tag == "button" {
+		hp.a11y.role = RoleButton // 禁用（无 OnClick）的按钮也还是按钮；显式 Role 在后面会覆盖
+	}
 	for _, ap := range n.attrList {
 		ap(&hp)
 	}
@@ -367,6 +389,26 @@ func If(cond bool, n *Node) *Node {
 	return nil
 }
 
+// With 返回 n 追加了属性 attrs 的副本（相当于 React.cloneElement），n 本身不变。
+// 用于给调用方传进来的元素补挂测量、样式等属性；n 不是 host 元素（组件、文本、图标）时
+// 属性无处可挂，改为包一层 Div 承载。
+func With(n *Node, attrs ...*Node) *Node {
+	if n == nil {
+		return nil
+	}
+	if n.typ != typeHost {
+		return Div(append(attrs, n)...)
+	}
+	c := *n
+	c.attrList = n.attrList[:len(n.attrList):len(n.attrList)] // 满容量：追加必然复制，不改到 n
+	for _, a := range attrs {
+		if a != nil && a.typ == typeAttr && a.applyAttr != nil {
+			c.attrList = append(c.attrList, a.applyAttr)
+		}
+	}
+	return &c
+}
+
 // Keyed 给任意节点（含组件）打上 key，用于列表稳定身份。
 func Keyed(k string, n *Node) *Node {
 	if n != nil {
@@ -1,14 +1,17 @@
 package ui
 
 // painter 是绘制后端的抽象：paint 遍历只依赖这些原语。当前实现为 gio（gioPainter），
-// 测试用录制后端（recordPainter，无需 GPU）。坐标均为物理像素，与 renderNode.bounds 一致。
+// 测试用录制后端（recordPainter，无需 GPU）；另有 CPU 光栅化（softPainter）与矢量导出
+// （svgPainter / pdfPainter）。坐标均为物理像素，与 renderNode.bounds 一致。
 type painter interface {
 	FillRect(x, y, w, h, r float32, c Color)
-	FillGradient(x, y, w, h, r float32, from, to Color, angle float32) // 线性渐变填充
+	FillGradient(x, y, w, h, r float32, g *Gradient) // 渐变填充（线性/径向/锥形，多色标）
 	StrokeRect(x, y, w, h, r, width float32, c Color)
+	StrokeGradient(x, y, w, h, r, width float32, g *Gradient) // 渐变描边，渐变铺满矩形
 	Shadow(x, y, w, h, r, offX, offY, blur, spread float32, c Color)
 	Line(x0, y0, x1, y1 float32, c Color)
 	DrawText(s string, face fontFace, c Color, x, y float32, fauxBold, fauxItalic bool)
+	DrawTextGradient(s string, face fontFace, g *Gradient, box Rect, x, y float32, fauxBold, fauxItalic bool) // 渐变铺满 box
 	DrawImage(img bitmap, dst Rect, opacity float32)
 	FillPath(path vecPath, x, y float32, c Color)          // 填充路径（实心图标）
 	StrokePath(path vecPath, x, y, width float32, c Color) // 描边路径（线性图标，圆角端点）
@@ -18,13 +21,18 @@ type painter interface {
 	EndLayer(t layerTransform)
 }
 
+// canvasBg 是画布底色：窗口每帧、Screenshot 与矢量导出都先铺上它。
+var canvasBg = Color{247, 248, 250, 255}
+
 // layerTransform 是一个图层合回时施加的变换 + 整组透明度（围绕中心 cx,cy）。
 type layerTransform struct {
-	cx, cy        float32 // 元素中心（变换与透视的锚点）
-	w, h          float32 // 元素尺寸（伪 3D 按四角投影求仿射时要用）
-	scale, rotate float32 // 2D 缩放 + 绕 Z 轴旋转
-	tx, ty        float32
-	opacity       float32
+	cx, cy         float32 // 元素中心（变换与透视的锚点）
+	w, h           float32 // 元素尺寸（伪 3D 按四角投影求仿射时要用）
+	scaleX, scaleY float32 // 2D 缩放（X/Y 各自）
+	rotate         float32 // 绕 Z 轴旋转
+	tx, ty         float32
+	opacity        float32
+	filters        []filterOp // 合回前按顺序施加的滤镜（见 filter.go）
 
 	// 伪 3D：绕 X/Y 轴旋转 + Z 位移 + 透视距离（0=无透视）。任一非零即走投影路径。
 	rotateX, rotateY float32
@@ -55,13 +63,15 @@ func (t layerTransform) is3D() bool {
 // PaintOp 是一条绘制指令记录。Harness.Paint 返回其有序列表，用于无头断言"画了什么"
 // （黄金测试）：颜色、位置、文本、裁剪/图层边界都可检查，无需真实渲染。
 type PaintOp struct {
-	Kind           string // rect / stroke / shadow / line / text / image / clip / unclip / layer / unlayer
+	Kind           string // rect / gradient / stroke / strokegradient / shadow / line / text / textgradient / image / path / strokepath / clip / unclip / layer / unlayer
 	Rect           Rect
 	Radius, Width  float32
 	Color          Color
 	Text           string
 	X0, Y0, X1, Y1 float32
 	Opacity        float32
+	Gradient       *Gradient // gradient / strokegradient / textgradient 的渐变（种类、色标）
+	Filter         string    // unlayer：该图层的滤镜，CSS 写法（如 "blur(4) grayscale(1)"）
 }
 
 // recordPainter 把每个原语调用记成一条 PaintOp。
@@ -70,8 +80,11 @@ type recordPainter struct{ ops []PaintOp }
 func (p *recordPainter) FillRect(x, y, w, h, r float32, c Color) {
 	p.ops = append(p.ops, PaintOp{Kind: "rect", Rect: Rect{x, y, w, h}, Radius: r, Color: c})
 }
-func (p *recordPainter) FillGradient(x, y, w, h, r float32, from, to Color, angle float32) {
-	p.ops = append(p.ops, PaintOp{Kind: "gradient", Rect: Rect{x, y, w, h}, Radius: r, Color: from})
+func (p *recordPainter) FillGradient(x, y, w, h, r float32, g *Gradient) {
+	p.ops = append(p.ops, PaintOp{Kind: "gradient", Rect: Rect{x, y, w, h}, Radius: r, Color: g.colorAt(0), Gradient: g})
+}
+func (p *recordPainter) StrokeGradient(x, y, w, h, r, width float32, g *Gradient) {
+	p.ops = append(p.ops, PaintOp{Kind: "strokegradient", Rect: Rect{x, y, w, h}, Radius: r, Width: width, Color: g.colorAt(0), Gradient: g})
 }
 func (p *recordPainter) StrokeRect(x, y, w, h, r, width float32, c Color) {
 	p.ops = append(p.ops, PaintOp{Kind: "stroke", Rect: Rect{x, y, w, h}, Radius: r, Width: width, Color: c})
@@ -85,6 +98,9 @@ func (p *recordPainter) Line(x0, y0, x1, y1 float32, c Color) {
 func (p *recordPainter) DrawText(s string, face fontFace, c Color, x, y float32, fb, fi bool) {
 	p.ops = append(p.ops, PaintOp{Kind: "text", Text: s, Color: c, X0: x, Y0: y})
 }
+func (p *recordPainter) DrawTextGradient(s string, face fontFace, g *Gradient, box Rect, x, y float32, fb, fi bool) {
+	p.ops = append(p.ops, PaintOp{Kind: "textgradient", Text: s, Rect: box, Color: g.colorAt(0), X0: x, Y0: y, Gradient: g})
+}
 func (p *recordPainter) DrawImage(img bitmap, d Rect, opacity float32) {
 	p.ops = append(p.ops, PaintOp{Kind: "image", Rect: d, Opacity: opacity})
 }
@@ -100,5 +116,5 @@ func (p *recordPainter) PushClip(r Rect, radius float32) {
 func (p *recordPainter) PopClip()    { p.ops = append(p.ops, PaintOp{Kind: "unclip"}) }
 func (p *recordPainter) BeginLayer() { p.ops = append(p.ops, PaintOp{Kind: "layer"}) }
 func (p *recordPainter) EndLayer(t layerTransform) {
-	p.ops = append(p.ops, PaintOp{Kind: "unlayer", Opacity: t.opacity})
+	p.ops = append(p.ops, PaintOp{Kind: "unlayer", Opacity: t.opacity, Filter: filterString(t.filters)})
 }
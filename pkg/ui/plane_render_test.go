@@ -109,7 +109,7 @@ func TestPlaneImageCachesAndInvalidates(t *testing.T) {
 	t.Cleanup(func() { planeCache = map[string]planeCacheEntry{} })
 
 	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
-	base := layerTransform{cx: 200, cy: 150, w: 400, h: 300, scale: 1, opacity: 1,
+	base := layerTransform{cx: 200, cy: 150, w: 400, h: 300, scaleX: 1, scaleY: 1, opacity: 1,
 		rotateX: 45, perspective: 700}
 
 	if _, _, ok := planeBitmap("k", img, base); !ok {
@@ -134,7 +134,7 @@ func TestPlaneImageCachesAndInvalidates(t *testing.T) {
 		{"尺寸（窗口缩放）", func(t *layerTransform) { t.w, t.h = 500, 375 }},
 		{"位置", func(t *layerTransform) { t.cx = 250 }},
 		{"绕Y", func(t *layerTransform) { t.rotateY = 10 }},
-		{"缩放", func(t *layerTransform) { t.scale = 1.5 }},
+		{"缩放", func(t *layerTransform) { t.scaleX, t.scaleY = 1.5, 1.5 }},
 	} {
 		before := len(planeCache)
 		tr := base
@@ -9,7 +9,7 @@ func TestPlaneHomographyHitsAllFourCorners(t *testing.T) {
 		{45, 0, 900}, {45, 20, 900}, {35, 20, 700}, {60, 15, 400}, {0, 50, 500}, {0, 0, 0},
 	} {
 		tr := layerTransform{
-			cx: 400, cy: 300, w: 640, h: 420, scale: 1, opacity: 1,
+			cx: 400, cy: 300, w: 640, h: 420, scaleX: 1, scaleY: 1, opacity: 1,
 			rotateX: tc.rx, rotateY: tc.ry, perspective: tc.pers,
 		}
 		d0, d1, d2, d3 := projCorners(tr)
@@ -42,7 +42,7 @@ func TestPlaneHomographyHitsAllFourCorners(t *testing.T) {
 // 这是「为什么需要 PlaneImage」的量化依据。
 func TestHomographyBeatsAffineOnFourthCorner(t *testing.T) {
 	tr := layerTransform{
-		cx: 400, cy: 300, w: 640, h: 420, scale: 1, opacity: 1,
+		cx: 400, cy: 300, w: 640, h: 420, scaleX: 1, scaleY: 1, opacity: 1,
 		rotateX: 50, perspective: 1000,
 	}
 	_, _, _, d3 := projCorners(tr)
@@ -67,7 +67,7 @@ func TestHomographyBeatsAffineOnFourthCorner(t *testing.T) {
 // 预变形靠的就是这条（对每个目标像素反解出源像素）。
 func TestPlaneHomographyInverseRoundTrips(t *testing.T) {
 	tr := layerTransform{
-		cx: 400, cy: 300, w: 640, h: 420, scale: 1, opacity: 1,
+		cx: 400, cy: 300, w: 640, h: 420, scaleX: 1, scaleY: 1, opacity: 1,
 		rotateX: 45, rotateY: 15, perspective: 800,
 	}
 	m := planeHomography(tr)
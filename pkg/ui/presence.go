@@ -0,0 +1,203 @@
+package ui
+
+// ---- AnimatePresence：退场动画 ----
+//
+// 列表里删掉一项时，协调器会立即卸载它的 fiber，UseTransition 来不及播退场。
+// AnimatePresence 下被移除的带 key 子节点先留在原位进入「退场中」：子树里的 UsePresence
+// 得知 present=false，播完退场动画后调用 done，所有 done 到齐才真正卸载。卸载后兄弟节点
+// 的位置跳变交给 Animated（FLIP）平滑过渡。
+
+type presenceProps struct {
+	children []*Node
+}
+
+// AnimatePresence 让被移除的带 key 子节点播完退场动画再卸载（类似 framer-motion 的同名组件）。
+// 子节点不产生额外的 host 元素，直接排进外层容器。被移除的子节点保持在原来的位置，
+// 若其子树没有 UsePresence 则与普通列表一样立即卸载；退场途中同 key 的节点再次出现时
+// 原 fiber 复活（状态保留），不重新挂载。不带 key 的子节点不参与退场。
+//
+//	ui.Div(ui.AnimatePresence(rows...)) // rows[i] = ui.Keyed(id, ui.Use(Row, item))
+//
+//	func Row(it Item) *ui.Node {
+//	    p := ui.UsePresenceTransition(200)
+//	    return ui.Div(ui.Style(ui.Animated, ui.Opacity(p)), ...)
+//	}
+func AnimatePresence(children ...*Node) *Node {
+	return Use(presenceC, presenceProps{children: children})
+}
+
+func presenceC(p presenceProps) *Node {
+	currentFiber.presence = true // 必须在子树协调前就位：reconcileList 据此保留退场节点
+	return Fragment(p.children...)
+}
+
+// presenceList 判断 f 是否为 AnimatePresence 直接管理的子节点列表（其 Fragment）。
+func presenceList(f *Fiber) bool {
+	return f != nil && f.typ == typeFragment && f.parent != nil && f.parent.presence
+}
+
+type presenceHook struct {
+	item  *Fiber // AnimatePresence 的直接子 fiber（本组件或其祖先）
+	fiber *Fiber
+	done  bool
+}
+
+// UsePresence 报告所在子树是否仍在 AnimatePresence 中：被移除后 present 为 false，
+// 组件应播放退场动画并在结束时调用 done（可重复调用）；子树中所有 UsePresence 都 done 后
+// 才卸载。不在 AnimatePresence 下时 present 恒为 true，done 无作用。
+//
+//	present, done := ui.UsePresence()
+//	mounted, p := ui.UseTransition(present, 200)
+//	if !mounted {
+//	    done()
+//	}
+func UsePresence() (present bool, done func()) {
+	f := currentFiber
+	_, raw := nextHook(f, func() any {
+		h := &presenceHook{fiber: f}
+		for a := f; a != nil && a.parent != nil; a = a.parent {
+			if presenceList(a.parent) {
+				h.item = a
+				break
+			}
+		}
+		return h
+	})
+	h := raw.(*presenceHook)
+	if h.item == nil {
+		return true, func() {}
+	}
+	if !h.item.exiting {
+		h.done = false // 退场途中又被加回来：下次退场重新等待
+		return true, func() {}
+	}
+	return false, func() {
+		if h.done || !h.item.exiting {
+			return
+		}
+		h.done = true
+		// 不在这里直接卸载（可能正处于 render 中）：让 AnimatePresence 重渲染，由协调统一摘除。
+		markFiberDirty(h.item.parent.parent)
+	}
+}
+
+// UsePresenceTransition 是 UsePresence 的常用搭配：挂载时 progress 0→1 进场，被移除后
+// 1→0 退场，退场结束自动 done。返回值可直接用作透明度、缩放或高度的比例。
+func UsePresenceTransition(durationMs float32) float32 {
+	present, done := UsePresence()
+	f := currentFiber
+	_, raw := nextHook(f, func() any { return &tweenHook{} })
+	h := raw.(*tweenHook)
+	h.fiber = f
+	h.ease = EaseOut
+	if durationMs <= 0 {
+		durationMs = 1
+	}
+	h.duration = durationMs
+
+	if target := boolf(present); h.target != target {
+		h.from = h.cur
+		h.target = target
+		h.elapsed = 0
+		if !h.active {
+			h.active = true
+			startAnim(f, h)
+		}
+	}
+	if !present && !h.active && h.cur == 0 {
+		done()
+	}
+	return h.cur
+}
+
+// presenceHooks 收集 item 子树中属于它的 UsePresence（嵌套 AnimatePresence 内部的不算）。
+func presenceHooks(item *Fiber) []*presenceHook {
+	var out []*presenceHook
+	var walk func(f *Fiber)
+	walk = func(f *Fiber) {
+		for _, h := range f.hooks {
+			if p, ok := h.(*presenceHook); ok && p.item == item {
+				out = append(out, p)
+			}
+		}
+		for _, c := range f.children {
+			walk(c)
+		}
+	}
+	walk(item)
+	return out
+}
+
+// exitDone 判断退场中的 item 是否可以卸载：子树里没有 UsePresence，或全部已 done。
+func exitDone(item *Fiber) bool {
+	for _, h := range presenceHooks(item) {
+		if !h.done {
+			return false
+		}
+	}
+	return true
+}
+
+// setExiting 切换 item 的退场状态，并让子树中的 UsePresence 重渲染（memo 组件也能得知）。
+func setExiting(item *Fiber, exiting bool) {
+	item.exiting = exiting
+	for _, h := range presenceHooks(item) {
+		markFiberDirty(h.fiber)
+	}
+}
+
+// keepExiting 是 AnimatePresence 列表协调的收尾：removed 中带 key、且未播完退场的节点
+// 留下来，按旧列表中的相对顺序插回 out（紧跟在旧列表里它前面最近的、仍在 out 中的节点之后）；
+// 其余照常卸载。同 key 已被别的类型的新节点占用时旧节点直接卸载，列表里 key 保持唯一。
+func keepExiting(old, out, removed []*Fiber) []*Fiber {
+	taken := make(map[string]bool, len(out))
+	for _, c := range out {
+		if c.key != "" {
+			taken[c.key] = true
+		}
+	}
+	keep := make(map[*Fiber]bool)
+	for _, c := range removed {
+		if c.key == "" || taken[c.key] {
+			unmount(c)
+			continue
+		}
+		if !c.exiting {
+			setExiting(c, true)
+		}
+		if exitDone(c) {
+			unmount(c)
+			continue
+		}
+		keep[c] = true
+	}
+	if len(keep) == 0 {
+		return out
+	}
+	in := make(map[*Fiber]bool, len(out))
+	for _, c := range out {
+		in[c] = true
+	}
+	for i, c := range old {
+		if !keep[c] {
+			continue
+		}
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if in[old[j]] {
+				for k, o := range out {
+					if o == old[j] {
+						at = k + 1
+						break
+					}
+				}
+				break
+			}
+		}
+		out = append(out, nil)
+		copy(out[at+1:], out[at:])
+		out[at] = c
+		in[c] = true
+	}
+	return out
+}
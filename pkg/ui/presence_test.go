@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+)
+
+// presenceRow 用 UsePresenceTransition 把进退场进度写进文本，行高 20、开 Animated。
+func presenceRow(label string) *Node {
+	p := UsePresenceTransition(100)
+	return Div(Style(Animated, Height(20)),
+		Text(fmt.Sprintf("%s %.2f", label, p)))
+}
+
+// presenceApp 渲染 items 的 AnimatePresence 列表；set 替换列表。
+func presenceApp(set *func([]string), initial ...string) *Node {
+	return Use(func(_ struct{}) *Node {
+		items, setItems := UseState(initial)
+		*set = setItems
+		rows := make([]*Node, len(items))
+		for i, it := range items {
+			rows[i] = Keyed(it, Use(presenceRow, it))
+		}
+		return Div(Style(Column), AnimatePresence(rows...))
+	}, struct{}{})
+}
+
+func rowTexts(h *Harness) []string { return h.Root().Texts() }
+
+// 删除一项：留在原位播完退场才卸载；卸载后下面的兄弟经 FLIP 从原位置滑上来。
+func TestAnimatePresenceKeepsExitingChild(t *testing.T) {
+	var set func([]string)
+	h := Mount(presenceApp(&set, "a", "b", "c"), 100, 100)
+	h.Step(200) // 进场播完
+	if got := fmt.Sprint(rowTexts(h)); got != "[a 1.00 b 1.00 c 1.00]" {
+		t.Fatalf("进场后：%s", got)
+	}
+	c := h.Root().ByText("c 1.00")
+	y0 := c.Bounds().Y
+
+	set([]string{"a", "c"})
+	h.Step(0)
+	h.Step(50)
+	b := h.Root().Find(func(q *Query) bool { return len(q.Text()) > 0 && q.Text()[0] == 'b' })
+	if !b.Exists() || b.Text() == "b 1.00" || b.Text() == "b 0.00" {
+		t.Fatalf("退场途中 b 应留在原位并在淡出：%v", rowTexts(h))
+	}
+	if got := rowTexts(h); got[1][0] != 'b' {
+		t.Fatalf("退场节点应保持原来的顺序：%v", got)
+	}
+	h.Step(100)
+	if got := fmt.Sprint(rowTexts(h)); got != "[a 1.00 c 1.00]" {
+		t.Fatalf("退场结束应卸载：%s", got)
+	}
+	c = h.Root().ByText("c 1.00")
+	if c.Bounds().Y >= y0 {
+		t.Fatalf("c 应上移到 b 的位置：%v -> %v", y0, c.Bounds().Y)
+	}
+	h.Step(16)
+	if c.rn.parent.offY <= 0 { // Animated 在行 Div 上
+		t.Errorf("Animated 的兄弟应从原位置滑入（残余偏移 >0）：%v", c.rn.parent.offY)
+	}
+}
+
+// 退场途中同 key 再次出现：原 fiber 复活并淡回，不重新挂载。
+func TestAnimatePresenceRevive(t *testing.T) {
+	var set func([]string)
+	h := Mount(presenceApp(&set, "a", "b"), 100, 100)
+	h.Step(200)
+	find := func() *Query {
+		return h.Root().Find(func(q *Query) bool { return len(q.Text()) > 0 && q.Text()[0] == 'b' })
+	}
+	before := find().rn.owner
+	set([]string{"a"})
+	h.Step(0)
+	h.Step(50)
+	set([]string{"a", "b"})
+	h.Step(0)
+	h.Step(200)
+	if q := find(); q.rn.owner != before || q.Text() != "b 1.00" {
+		t.Fatalf("复活应复用原 fiber 并回到 1：%q", q.Text())
+	}
+}
+
+// 子树里没有 UsePresence 的节点、不带 key 的节点照常立即卸载。
+func TestAnimatePresenceWithoutHookUnmountsImmediately(t *testing.T) {
+	var set func(bool)
+	root := Use(func(_ struct{}) *Node {
+		show, setShow := UseState(true)
+		set = setShow
+		return Div(AnimatePresence(
+			If(show, Keyed("k", Text("keyed"))),
+			If(show, Text("plain")),
+		))
+	}, struct{}{})
+	h := Mount(root, 100, 100)
+	set(false)
+	h.Step(0)
+	if got := rowTexts(h); len(got) != 0 {
+		t.Fatalf("没有退场动画的节点应立即卸载：%v", got)
+	}
+}
+
+// 多个 UsePresence 都 done 才卸载；不在 AnimatePresence 下 present 恒为 true。
+func TestUsePresenceWaitsForAllHooks(t *testing.T) {
+	var dones []func()
+	child := func(name string) *Node {
+		present, done := UsePresence()
+		if !present {
+			dones = append(dones, done)
+		}
+		return Text(name)
+	}
+	var set func(bool)
+	root := Use(func(_ struct{}) *Node {
+		show, setShow := UseState(true)
+		set = setShow
+		return Div(AnimatePresence(If(show, Keyed("item", Div(Use(child, "x"), Use(child, "y"))))))
+	}, struct{}{})
+	h := Mount(root, 100, 100)
+	set(false)
+	h.Step(0)
+	if len(dones) != 2 {
+		t.Fatalf("两个 UsePresence 都应得知退场：%d", len(dones))
+	}
+	dones[0]()
+	h.Step(0)
+	if got := rowTexts(h); len(got) != 2 {
+		t.Fatalf("还有一个没 done，不应卸载：%v", got)
+	}
+	dones[1]()
+	h.Step(0)
+	if got := rowTexts(h); len(got) != 0 {
+		t.Fatalf("全部 done 后应卸载：%v", got)
+	}
+
+	var present bool
+	Mount(Use(func(_ struct{}) *Node { present, _ = UsePresence(); return nil }, struct{}{}), 10, 10)
+	if !present {
+		t.Error("不在 AnimatePresence 下 present 应为 true")
+	}
+}
@@ -59,8 +59,10 @@ func project3D(t layerTransform, ox, oy float32) pt {
 		x2 /= denom
 		y2 /= denom
 	}
-	if s := float64(t.scale); s != 0 {
+	if s := float64(t.scaleX); s != 0 {
 		x2 *= s
+	}
+	if s := float64(t.scaleY); s != 0 {
 		y2 *= s
 	}
 	orgX, orgY := t.origin() // 无相机=元素中心，有相机=场景中心
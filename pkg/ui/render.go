@@ -11,7 +11,6 @@ import (
 	"sort"
 	"strings"
 	"time"
-	"unicode/utf8"
 
 	"github.com/sjm1327605995/tenon/yoga"
 )
@@ -45,10 +44,8 @@ type renderNode struct {
 
 	// box / input
 	bg          Color
-	hasGradient bool
-	gradFrom    Color
-	gradTo      Color
-	gradAngle   float32
+	bgGrad      *Gradient // 物理像素（半径已换算）
+	borderGrad  *Gradient
 	radius      float32
 	borderW     float32
 	borderColor Color
@@ -81,12 +78,18 @@ type renderNode struct {
 	effItalic      bool
 	inhColor       Color // box 向下传递的颜色
 	hasInhColor    bool
-	inhSize        float32 // box 向下传递的字号
+	inhTextGrad    *Gradient // 容器上的 TextGradient，由其中的文字继承
+	ownTextGrad    *Gradient // 文本节点自己的 TextGradient
+	textGrad       *Gradient // 生效的文字渐变（resolveInherited 决定）
+	inhSize        float32   // box 向下传递的字号
 	hasInhSize     bool
 	inhWeight      int
 	hasInhWeight   bool
 	inhItalic      bool
 	hasInhItalic   bool
+	ownDir         TextDirection // 本节点显式设置的书写方向
+	inhDir         TextDirection // box 向下传递的书写方向
+	textDir        TextDirection // 生效的书写方向（含继承；DirAuto 表示按内容判断）
 
 	// input
 	value       string
@@ -105,11 +108,32 @@ type renderNode struct {
 	onPress       func(bool)
 	onDrag        func(dx, dy float32)
 	onContextMenu func(x, y float32)
+	onKeyDown     func(*KeyEvent)
+	onFocus       func()
+	onBlur        func()
+	selectable    bool // Selectable() 区域的根
+	dragSource    bool // 拖放源 / 目标（见 dnd.go）
+	dragPayload   any
+	dragPreview   *Node
+	onDragging    func(bool)
+	dropTarget    bool
+	dropAccept    func(any) bool
+	onDrop        func(payload any, x, y float32)
+	onDragOver    func(bool)
 	measure       *measureHook
 	scrollRef     *scrollHook // UseScroll：写回滚动状态
 	focusable     bool
 	navGroup      bool // ArrowNav：本节点是方向键导航组
 	navOrient     NavOrient
+	id            string             // Id(...)：AriaDescribedBy 据此引用
+	classes       []string           // Class(...) 按空格拆开，样式表据此匹配
+	hostAttrs     []func(*hostProps) // 最近一次的属性列表：伪类状态变化时据此重算样式
+	sheetState    pseudoState        // 样式表伪类状态（见 stylesheet.go）
+	sheetDynamic  bool               // 样式依赖某个元素的交互状态
+
+	inspectEdits map[string]string // 检查器里现场改的样式（原始输入，见 devtools.go）
+	inspectStyle []StyleOpt        // inspectEdits 解析后的样式，叠在最后
+	a11y         a11yProps         // 无障碍属性（见 a11y.go）
 
 	// image
 	imgSrc    string
@@ -135,7 +159,9 @@ type renderNode struct {
 	contentH float32
 
 	opacity        float32
-	scale          float32
+	filters        []filterOp // Blur/Grayscale/...，模糊半径已换算到物理像素
+	backdropBlur   float32
+	scaleX, scaleY float32
 	rotate         float32
 	transX, transY float32
 
@@ -145,6 +171,7 @@ type renderNode struct {
 	perspective      float32
 	scene3D          bool
 	zIndex           int
+	hidden           bool // 整个子树不绘制、不命中、不参与焦点与无障碍树
 
 	// 投影（box-shadow）
 	shadowColor              Color
@@ -168,11 +195,11 @@ func (rn *renderNode) has3D() bool {
 }
 
 func (rn *renderNode) hasTransform() bool {
-	return rn.scale != 1 || rn.rotate != 0 || rn.effTransX() != 0 || rn.effTransY() != 0 || rn.has3D()
+	return rn.scaleX != 1 || rn.scaleY != 1 || rn.rotate != 0 || rn.effTransX() != 0 || rn.effTransY() != 0 || rn.has3D()
 }
 
 func (rn *renderNode) needsLayer() bool {
-	return rn.hasTransform() || (rn.opacity < 1 && len(rn.children) > 0)
+	return rn.hasTransform() || (rn.opacity < 1 && len(rn.children) > 0) || len(rn.filters) > 0
 }
 
 func (rn *renderNode) container() bool { return rn.kind == rnBox || rn.kind == rnScroll }
@@ -184,24 +211,27 @@ func newHostRenderNode(tag string) *renderNode {
 	case "img":
 		return newImageRenderNode()
 	case "scroll":
-		return &renderNode{yn: yoga.NewNode(), kind: rnScroll, clip: true, scroll: true, opacity: 1, scale: 1}
+		return &renderNode{yn: yoga.NewNode(), kind: rnScroll, clip: true, scroll: true, opacity: 1, scaleX: 1, scaleY: 1}
 	default:
 		return newBoxRenderNode()
 	}
 }
 
 func newBoxRenderNode() *renderNode {
-	return &renderNode{yn: yoga.NewNode(), kind: rnBox, opacity: 1, scale: 1}
+	return &renderNode{yn: yoga.NewNode(), kind: rnBox, opacity: 1, scaleX: 1, scaleY: 1}
 }
 
 func newTextRenderNode(s string, st StyleProps, runs []textRun) *renderNode {
-	rn := &renderNode{yn: yoga.NewNode(), kind: rnText, text: s, runs: runs, opacity: 1, scale: 1}
+	rn := &renderNode{yn: yoga.NewNode(), kind: rnText, text: s, runs: runs, opacity: 1, scaleX: 1, scaleY: 1}
 	rn.applyTextStyle(st)
 	rn.yn.SetMeasureFunc(func(_ *yoga.Node, w float32, wm yoga.MeasureMode, _ float32, _ yoga.MeasureMode) yoga.Size {
 		avail := float32(0)
 		if wm == yoga.MeasureModeExactly || wm == yoga.MeasureModeAtMost {
 			avail = w
 		}
+		if wm == yoga.MeasureModeMinContent {
+			avail = 1 // 最小内容宽度：能断就断，宽度取最长的不可断片段（网格 auto/fr 轨道的下限）
+		}
 		if len(rn.runs) > 0 {
 			_, mw, h := rn.richLayout(avail)
 			return yoga.Size{Width: mw, Height: h}
@@ -216,7 +246,7 @@ func newTextRenderNode(s string, st StyleProps, runs []textRun) *renderNode {
 }
 
 func newInputRenderNode() *renderNode {
-	rn := &renderNode{yn: yoga.NewNode(), kind: rnInput, opacity: 1, scale: 1}
+	rn := &renderNode{yn: yoga.NewNode(), kind: rnInput, opacity: 1, scaleX: 1, scaleY: 1}
 	rn.applyTextStyle(newStyleProps())
 	rn.yn.SetMeasureFunc(func(_ *yoga.Node, w float32, wm yoga.MeasureMode, _ float32, _ yoga.MeasureMode) yoga.Size {
 		if rn.face == nil {
@@ -252,7 +282,7 @@ func newInputRenderNode() *renderNode {
 }
 
 func newImageRenderNode() *renderNode {
-	rn := &renderNode{yn: yoga.NewNode(), kind: rnImage, opacity: 1, scale: 1}
+	rn := &renderNode{yn: yoga.NewNode(), kind: rnImage, opacity: 1, scaleX: 1, scaleY: 1}
 	rn.yn.SetMeasureFunc(func(_ *yoga.Node, _ float32, _ yoga.MeasureMode, _ float32, _ yoga.MeasureMode) yoga.Size {
 		if rn.img == nil {
 			return yoga.Size{}
@@ -265,7 +295,7 @@ func newImageRenderNode() *renderNode {
 
 func newIconRenderNode(d string, size, stroke float32, raw bool, w, h float32, st StyleProps) *renderNode {
 	rn := &renderNode{yn: yoga.NewNode(), kind: rnIcon, iconPath: d, iconSize: size, iconStroke: stroke,
-		iconRaw: raw, iconW: w, iconH: h, opacity: 1, scale: 1}
+		iconRaw: raw, iconW: w, iconH: h, opacity: 1, scaleX: 1, scaleY: 1}
 	rn.applyTextStyle(st)                     // 复用文本颜色继承（currentColor）
 	rn.yn.StyleSetAlignSelf(yoga.AlignCenter) // 固定尺寸（不随 align-items:stretch 拉伸），并在交叉轴居中
 	rn.yn.SetMeasureFunc(func(_ *yoga.Node, _ float32, _ yoga.MeasureMode, _ float32, _ yoga.MeasureMode) yoga.Size {
@@ -318,6 +348,8 @@ func (rn *renderNode) applyTextStyle(st StyleProps) {
 	rn.ownWeight = st.weight
 	rn.explicitItalic = st.hasItalic
 	rn.ownItalic = st.italic
+	rn.ownDir = st.textDir
+	rn.ownTextGrad = st.textGrad.scaled(uiScale)
 	if rn.effSize == 0 { // 初始回退，保证在 resolve 前也有可用字体
 		rn.setEffectiveText(Black, 16, 400, false)
 	}
@@ -352,6 +384,8 @@ type inhText struct {
 	hasColor, hasSize  bool
 	hasWeight, hasItal bool
 	italic             bool
+	dir                TextDirection
+	textGrad           *Gradient
 }
 
 // resolveInherited 自顶向下解析文本继承（颜色/字号/字重/斜体）；文本/输入节点未显式设置时采用继承值。
@@ -367,6 +401,14 @@ func resolveInherited(rn *renderNode, ctx inhText) {
 		}
 		rn.color = c
 	case rnText, rnInput:
+		rn.textDir = ctx.dir
+		if rn.ownDir != DirAuto {
+			rn.textDir = rn.ownDir
+		}
+		rn.textGrad = rn.ownTextGrad
+		if rn.textGrad == nil && !rn.explicitColor {
+			rn.textGrad = ctx.textGrad
+		}
 		if rn.kind == rnText && len(rn.runs) > 0 {
 			rn.resolveRuns(ctx)
 			return
@@ -399,6 +441,10 @@ func resolveInherited(rn *renderNode, ctx inhText) {
 	default:
 		if rn.hasInhColor {
 			ctx.color, ctx.hasColor = rn.inhColor, true
+			ctx.textGrad = nil // 更近的 TextColor 盖过外层的 TextGradient
+		}
+		if rn.inhTextGrad != nil {
+			ctx.textGrad = rn.inhTextGrad
 		}
 		if rn.hasInhSize {
 			ctx.size, ctx.hasSize = rn.inhSize, true
@@ -409,6 +455,9 @@ func resolveInherited(rn *renderNode, ctx inhText) {
 		if rn.hasInhItalic {
 			ctx.italic, ctx.hasItal = rn.inhItalic, true
 		}
+		if rn.inhDir != DirAuto {
+			ctx.dir = rn.inhDir
+		}
 		for _, ch := range rn.children {
 			resolveInherited(ch, ctx)
 		}
@@ -479,11 +528,11 @@ func (rn *renderNode) loadImage() {
 				if w.imgSrc == src { // 期间 src 可能已改，仅回填仍需要它的节点
 					w.img = ei
 					w.yn.MarkDirty()
+					if g := gameOf(w.owner); g != nil {
+						g.needsLayout = true // 等图的节点可能在别的窗口
+					}
 				}
 			}
-			if activeGame != nil {
-				activeGame.needsLayout = true
-			}
 		})
 	}()
 }
@@ -513,17 +562,37 @@ func decodeImageSource(src string) (image.Image, error) {
 
 // applyHostProps 把某个 host 元素的属性写入其 renderNode。
 func applyHostProps(rn *renderNode, hp hostProps) {
+	rn.id = hp.id
+	rn.classes = strings.Fields(hp.class)
+	rn.a11y = hp.a11y
+	rn.hostAttrs = hp.attrs
+	rn.sheetDynamic = false
+	if sheetsInstalled && rn.owner != nil {
+		if sheets := sheetsOf(rn.owner); len(sheets) > 0 {
+			hp.style = rn.cascadeStyle(sheets, hp.attrs)
+		}
+	}
+	for _, o := range rn.inspectStyle {
+		o(&hp.style)
+	}
 	syncYoga(rn, hp.style)
+	if g := gameOf(rn.owner); g != nil {
+		g.trackResponsive(rn, hp.style.responsive)
+	}
 	rn.onClick = hp.onClick
 	rn.onHover = hp.onHover
 	rn.onPress = hp.onPress
 	rn.onDrag = hp.onDrag
 	rn.onContextMenu = hp.onContextMenu
+	rn.onKeyDown, rn.onFocus, rn.onBlur = hp.onKeyDown, hp.onFocus, hp.onBlur
+	rn.selectable = hp.selectable
+	rn.dragSource, rn.dragPayload, rn.dragPreview, rn.onDragging = hp.dragSource, hp.dragPayload, hp.dragPreview, hp.onDragging
+	rn.dropTarget, rn.dropAccept, rn.onDrop, rn.onDragOver = hp.dropTarget, hp.dropAccept, hp.onDrop, hp.onDragOver
 	rn.measure = hp.measure
 	rn.scrollRef = hp.scrollRef
 	rn.navGroup = hp.navGroup
 	rn.navOrient = hp.navOrient
-	rn.focusable = rn.kind == rnInput || hp.onClick != nil
+	rn.focusable = rn.kind == rnInput || hp.onClick != nil || hp.onKeyDown != nil
 	switch rn.kind {
 	case rnInput:
 		rn.applyTextStyle(hp.style)
@@ -635,6 +704,11 @@ func syncYoga(rn *renderNode, s StyleProps) {
 	if s.hasJu {
 		yn.StyleSetJustifyContent(s.justify)
 	}
+	yn.StyleSetDirection(yogaDirection(s.textDir))
+	syncGrid(yn, s, k)
+	if s.hidden {
+		yn.StyleSetDisplay(yoga.DisplayNone)
+	}
 	if s.borderW > 0 {
 		yn.StyleSetBorder(yoga.EdgeAll, s.borderW*k)
 	}
@@ -650,13 +724,15 @@ func syncYoga(rn *renderNode, s StyleProps) {
 	setPos(yn, yoga.EdgeLeft, s.posL*k)
 
 	rn.bg = s.bg
-	rn.hasGradient = s.hasGradient
-	rn.gradFrom, rn.gradTo, rn.gradAngle = s.gradFrom, s.gradTo, s.gradAngle
+	rn.bgGrad, rn.borderGrad = s.bgGrad.scaled(k), s.borderGrad.scaled(k)
+	rn.inhTextGrad = s.textGrad.scaled(k)
 	rn.radius = s.radius * k
 	rn.borderW = s.borderW * k
 	rn.borderColor = s.borderColor
 	rn.opacity = s.opacity
-	rn.scale = s.scale
+	rn.filters = scaledFilters(s.filters, k)
+	rn.backdropBlur = s.backdropBlur * k
+	rn.scaleX, rn.scaleY = s.scaleX, s.scaleY
 	rn.rotate = s.rotate
 	rn.transX, rn.transY = s.transX*k, s.transY*k
 	rn.rotateX, rn.rotateY = s.rotateX, s.rotateY
@@ -664,6 +740,10 @@ func syncYoga(rn *renderNode, s StyleProps) {
 	rn.perspective = s.perspective * k // 透视距离同为物理像素，与投影坐标同一量纲
 	rn.scene3D = s.scene3D
 	rn.zIndex = s.zIndex
+	if s.hidden && !rn.hidden {
+		blurHidden(rn)
+	}
+	rn.hidden = s.hidden
 
 	rn.hasShadow = s.hasShadow
 	rn.shadowColor = s.shadowColor
@@ -682,10 +762,11 @@ func syncYoga(rn *renderNode, s StyleProps) {
 	rn.inhWeight = s.weight
 	rn.hasInhItalic = s.hasItalic
 	rn.inhItalic = s.italic
+	rn.inhDir = s.textDir
 
 	rn.animatedLayout = s.animateLayout
-	if s.animateLayout && activeGame != nil {
-		activeGame.hasLayoutAnim = true
+	if g := gameOf(rn.owner); s.animateLayout && g != nil {
+		g.hasLayoutAnim = true
 	}
 }
 
@@ -745,6 +826,15 @@ func cameraOf(rn *renderNode) *camera3D {
 	}
 }
 
+// blurHidden 在 rn 刚被隐藏时把焦点移出它的子树：隐藏的元素看不见也点不到，
+// 焦点留在里面会让按键落进后台的标签页。
+func blurHidden(rn *renderNode) {
+	g := gameOf(rn.owner)
+	if g != nil && g.focusedFiber != nil && rn.owner != nil && isAncestor(rn.owner, g.focusedFiber) {
+		g.focusedFiber = nil
+	}
+}
+
 // layerOf 构造某节点合成时施加的变换。绘制与命中测试必须共用它 —— 两边各算一套正是
 // 「画得到却点不到」的根源（本仓已因此修过一次：e19e310）。
 func layerOf(rn *renderNode, cam *camera3D) layerTransform {
@@ -752,10 +842,11 @@ func layerOf(rn *renderNode, cam *camera3D) layerTransform {
 	t := layerTransform{
 		cx: b.X + b.W/2, cy: b.Y + b.H/2,
 		w: b.W, h: b.H,
-		scale: rn.scale, rotate: rn.rotate,
+		scaleX: rn.scaleX, scaleY: rn.scaleY, rotate: rn.rotate,
 		tx: rn.effTransX(), ty: rn.effTransY(), opacity: rn.opacity,
 		rotateX: rn.rotateX, rotateY: rn.rotateY,
 		transZ: rn.transZ, perspective: rn.perspective,
+		filters: rn.filters,
 	}
 	if cam != nil {
 		t.camX, t.camY, t.hasCam = cam.ox, cam.oy, true
@@ -792,6 +883,16 @@ func paint(p painter, rn *renderNode) { paintIn(p, rn, nil) }
 
 // paintIn 绘制 rn；cam 非 nil 表示 rn 是某个 Scene3D 的直接子元素，应透过该相机投影。
 func paintIn(p painter, rn *renderNode, cam *camera3D) {
+	if rn.hidden || backdropDone {
+		return
+	}
+	if rn == backdropStop { // 背景重画到此为止（见 paintBackdrop）
+		backdropDone = true
+		return
+	}
+	if rn.backdropBlur > 0 {
+		paintBackdrop(p, rn)
+	}
 	if rn.scene3D {
 		paintScene(p, rn, cam)
 		return
@@ -884,24 +985,31 @@ func paintSelf(p painter, rn *renderNode) {
 	}
 	switch rn.kind {
 	case rnBox, rnScroll:
-		if rn.hasGradient {
-			p.FillGradient(b.X, b.Y, b.W, b.H, rn.radius, rn.gradFrom.Alpha(o), rn.gradTo.Alpha(o), rn.gradAngle)
+		if rn.bgGrad != nil {
+			p.FillGradient(b.X, b.Y, b.W, b.H, rn.radius, rn.bgGrad.alpha(o))
 		} else {
 			p.FillRect(b.X, b.Y, b.W, b.H, rn.radius, rn.bg.Alpha(o))
 		}
-		if rn.borderW > 0 {
+		if rn.borderW > 0 && rn.borderGrad != nil {
+			p.StrokeGradient(b.X, b.Y, b.W, b.H, rn.radius, rn.borderW, rn.borderGrad.alpha(o))
+		} else if rn.borderW > 0 {
 			p.StrokeRect(b.X, b.Y, b.W, b.H, rn.radius, rn.borderW, rn.borderColor.Alpha(o))
 		}
 	case rnInput:
 		paintInput(p, rn)
 	case rnText:
+		paintTextSelection(p, rn)
 		if len(rn.runs) > 0 {
 			paintRichText(p, rn, o)
 			break
 		}
-		lines, _ := rn.wrapped(b.W)
-		for i, ln := range lines {
-			p.DrawText(ln, rn.face, rn.color.Alpha(o), b.X, b.Y+float32(i)*float32(rn.lineH), rn.fauxBold, rn.fauxItalic)
+		for i, bl := range rn.bidiLines(b.W) {
+			x, y := b.X+bl.startX(b.W), b.Y+float32(i)*float32(rn.lineH)
+			if rn.textGrad != nil {
+				bl.drawGradient(p, rn.face, rn.textGrad.alpha(o), b, x, y, rn.fauxBold, rn.fauxItalic)
+				continue
+			}
+			bl.draw(p, rn.face, rn.color.Alpha(o), x, y, rn.fauxBold, rn.fauxItalic)
 		}
 	case rnImage:
 		if rn.img != nil {
@@ -968,29 +1076,31 @@ func paintInput(p painter, rn *renderNode) {
 	selHi := clampi(max(rn.selAnchor, rn.caretPos), 0, len(rn.value))
 	hasSel := preLo < 0 && selLo != selHi
 
+	avail := b.W - padL*2
 	if rn.multiline {
 		ty := b.Y + padT
-		avail := b.W - padL*2
 		if usePlaceholder {
 			for i, sp := range wrapSpans(rn.placeholder, rn.face, rn.lineH, avail) {
-				p.DrawText(sp.text, rn.face, Gray, tx, ty+float32(i)*lineH, rn.fauxBold, rn.fauxItalic)
+				bl := sp.bidi(rn.textDir, rn.face, rn.lineH)
+				bl.draw(p, rn.face, Gray, tx+bl.startX(avail), ty+float32(i)*lineH, rn.fauxBold, rn.fauxItalic)
 			}
 			return
 		}
 		spans := wrapSpans(val, rn.face, rn.lineH, avail)
 		if hasSel {
-			paintSpanRange(p, spans, selLo, selHi, rn.face, rn.lineH, tx, ty, lineH, false)
+			paintSpanRange(p, rn, spans, selLo, selHi, tx, ty, avail, false)
 		}
 		if preLo >= 0 { // 预编辑下划线
-			paintSpanRange(p, spans, preLo, preHi, rn.face, rn.lineH, tx, ty, lineH, true)
+			paintSpanRange(p, rn, spans, preLo, preHi, tx, ty, avail, true)
 		}
 		for i, sp := range spans {
-			p.DrawText(sp.text, rn.face, rn.color, tx, ty+float32(i)*lineH, rn.fauxBold, rn.fauxItalic)
+			bl := sp.bidi(rn.textDir, rn.face, rn.lineH)
+			bl.draw(p, rn.face, rn.color, tx+bl.startX(avail), ty+float32(i)*lineH, rn.fauxBold, rn.fauxItalic)
 		}
 		if isFocused(rn) && caretVisible() {
 			for i, sp := range spans {
 				if caret <= sp.end {
-					cx := sp.xInSpan(caret, rn.face, rn.lineH, tx)
+					cx := sp.xInSpan(caret, rn.textDir, rn.face, rn.lineH, tx, avail)
 					cy := ty + float32(i)*lineH
 					p.Line(cx, cy, cx, cy+lineH, rn.color)
 					break
@@ -1001,51 +1111,71 @@ func paintInput(p painter, rn *renderNode) {
 	}
 
 	ty := b.Y + (b.H-lineH)/2
-
-	if hasSel && rn.face != nil {
-		wa := measureW(val[:selLo], rn.face, rn.lineH)
-		wc := measureW(val[:selHi], rn.face, rn.lineH)
-		p.FillRect(tx+wa, ty, wc-wa, lineH, 0, Color{R: 59, G: 130, B: 246, A: 90})
-	}
-	if preLo >= 0 && rn.face != nil { // 预编辑下划线
-		wa := measureW(val[:preLo], rn.face, rn.lineH)
-		wc := measureW(val[:preHi], rn.face, rn.lineH)
-		p.Line(tx+wa, ty+lineH-1, tx+wc, ty+lineH-1, rn.color)
+	if rn.face == nil {
+		return
 	}
-
 	if usePlaceholder {
-		p.DrawText(rn.placeholder, rn.face, Gray, tx, ty, rn.fauxBold, rn.fauxItalic)
-	} else {
-		p.DrawText(val, rn.face, rn.color, tx, ty, rn.fauxBold, rn.fauxItalic)
+		bl := newBidiLine(rn.placeholder, rn.textDir, rn.face, rn.lineH)
+		bl.draw(p, rn.face, Gray, tx+bl.startX(avail), ty, rn.fauxBold, rn.fauxItalic)
+	}
+	bl := newBidiLine(val, rn.textDir, rn.face, rn.lineH)
+	lx := tx + bl.startX(avail)
+	if hasSel {
+		for _, xs := range bl.rangeXs(selLo, selHi, rn.face, rn.lineH) {
+			p.FillRect(lx+xs[0], ty, xs[1]-xs[0], lineH, 0, selectionColor)
+		}
 	}
-	if isFocused(rn) && caretVisible() {
-		cx := tx
-		if rn.face != nil && caret > 0 {
-			cx += measureW(val[:caret], rn.face, rn.lineH)
+	if preLo >= 0 { // 预编辑下划线
+		for _, xs := range bl.rangeXs(preLo, preHi, rn.face, rn.lineH) {
+			p.Line(lx+xs[0], ty+lineH-1, lx+xs[1], ty+lineH-1, rn.color)
 		}
+	}
+	if !usePlaceholder {
+		bl.draw(p, rn.face, rn.color, lx, ty, rn.fauxBold, rn.fauxItalic)
+	}
+	if isFocused(rn) && caretVisible() {
+		cx := lx + bl.caretX(caret, rn.face, rn.lineH)
 		p.Line(cx, ty, cx, ty+lineH, rn.color)
 	}
 }
 
 // paintSpanRange 逐行为字节区间 [lo,hi) 绘制高亮块或底部下划线（多行输入用）。
-func paintSpanRange(p painter, spans []wrapSpan, lo, hi int, face fontFace, lineH float64, tx, ty, lh float32, underline bool) {
+// 混排行里一个逻辑区间可能对应几块不相连的视觉区间。
+func paintSpanRange(p painter, rn *renderNode, spans []wrapSpan, lo, hi int, tx, ty, avail float32, underline bool) {
+	lh := float32(rn.lineH)
 	for i, sp := range spans {
 		a, c := max(lo, sp.start), min(hi, sp.end)
+		a, c = min(a, sp.start+len(sp.text)), min(c, sp.start+len(sp.text))
 		if a >= c {
 			continue
 		}
-		x0 := sp.xInSpan(a, face, lineH, tx)
-		x1 := sp.xInSpan(c, face, lineH, tx)
+		bl := sp.bidi(rn.textDir, rn.face, rn.lineH)
+		lx := tx + bl.startX(avail)
 		y := ty + float32(i)*lh
-		if underline {
-			p.Line(x0, y+lh-1, x1, y+lh-1, Color{R: 30, G: 30, B: 30, A: 255})
-		} else {
-			p.FillRect(x0, y, x1-x0, lh, 0, Color{R: 59, G: 130, B: 246, A: 90})
+		for _, xs := range bl.rangeXs(a-sp.start, c-sp.start, rn.face, rn.lineH) {
+			if underline {
+				p.Line(lx+xs[0], y+lh-1, lx+xs[1], y+lh-1, Color{R: 30, G: 30, B: 30, A: 255})
+			} else {
+				p.FillRect(lx+xs[0], y, xs[1]-xs[0], lh, 0, selectionColor)
+			}
 		}
 	}
 }
 
-func caretVisible() bool { return (time.Now().UnixMilli()/500)%2 == 0 }
+// selectionColor 是选区高亮（输入框与 Selectable 文字共用）。
+var selectionColor = Color{R: 59, G: 130, B: 246, A: 90}
+
+func caretVisible() bool { return (clock().UnixMilli()/500)%2 == 0 }
+
+func clampf(v, lo, hi float32) float32 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
 
 func clampi(v, lo, hi int) int {
 	if v < lo {
@@ -1063,25 +1193,9 @@ func (rn *renderNode) caretFromX(px float32) int {
 		return 0
 	}
 	padL := rn.yn.LayoutPadding(yoga.EdgeLeft)
-	rel := px - (rn.bounds.X + padL)
-	if rel <= 0 {
-		return 0
-	}
-	prevIdx, prevW := 0, float32(0)
-	for i := 1; i <= len(rn.value); i++ {
-		if i < len(rn.value) && !utf8.RuneStart(rn.value[i]) {
-			continue
-		}
-		w := measureW(rn.value[:i], rn.face, rn.lineH)
-		if w >= rel {
-			if w-rel < rel-prevW {
-				return i
-			}
-			return prevIdx
-		}
-		prevIdx, prevW = i, w
-	}
-	return len(rn.value)
+	bl := newBidiLine(rn.value, rn.textDir, rn.face, rn.lineH)
+	rel := px - (rn.bounds.X + padL) - bl.startX(rn.bounds.W-padL*2)
+	return bl.offsetAt(rel, rn.face, rn.lineH)
 }
 
 // caretFromPoint 把绝对屏幕点映射到多行输入内的字节偏移。
@@ -1099,7 +1213,7 @@ func (rn *renderNode) caretFromPoint(px, py float32) int {
 	if row >= len(spans) {
 		row = len(spans) - 1
 	}
-	return spans[row].offsetInSpan(px-(rn.bounds.X+padL), rn.face, rn.lineH)
+	return spans[row].offsetInSpan(px-(rn.bounds.X+padL), rn.textDir, rn.face, rn.lineH, rn.bounds.W-padL*2)
 }
 
 // caretRect 返回 caret 处的屏幕矩形（物理像素），用于放置 IME 候选窗。
@@ -1115,15 +1229,16 @@ func (rn *renderNode) caretRect(caret int) image.Rectangle {
 		cx, cy = tx, b.Y+padT
 		for i, sp := range wrapSpans(rn.value, rn.face, rn.lineH, b.W-padL*2) {
 			if caret <= sp.end {
-				cx = sp.xInSpan(caret, rn.face, rn.lineH, tx)
+				cx = sp.xInSpan(caret, rn.textDir, rn.face, rn.lineH, tx, b.W-padL*2)
 				cy = b.Y + padT + float32(i)*lineH
 				break
 			}
 		}
 	} else {
 		cx = b.X + padL
-		if rn.face != nil && caret > 0 {
-			cx += measureW(rn.value[:caret], rn.face, rn.lineH)
+		if rn.face != nil {
+			bl := newBidiLine(rn.value, rn.textDir, rn.face, rn.lineH)
+			cx += bl.startX(b.W-padL*2) + bl.caretX(caret, rn.face, rn.lineH)
 		}
 		cy = b.Y + (b.H-lineH)/2
 	}
@@ -1139,7 +1254,8 @@ func (rn *renderNode) caretAt(px, py float32) int {
 }
 
 func isFocused(rn *renderNode) bool {
-	return activeGame != nil && activeGame.focusedFiber != nil && activeGame.focusedFiber.rnode == rn
+	g := gameOf(rn.owner)
+	return g != nil && g.focusedFiber != nil && g.focusedFiber.rnode == rn
 }
 
 // hitTest 返回命中点最深、且带 onClick 的处理器（兼容旧调用）。
@@ -1172,15 +1288,20 @@ func (rn *renderNode) invTransform(px, py float32, cam *camera3D) (float32, floa
 		cos, sin := float32(math.Cos(rad)), float32(math.Sin(rad))
 		x, y = x*cos-y*sin, x*sin+y*cos
 	}
-	if rn.scale != 0 {
-		x /= rn.scale
-		y /= rn.scale
+	if rn.scaleX != 0 {
+		x /= rn.scaleX
+	}
+	if rn.scaleY != 0 {
+		y /= rn.scaleY
 	}
 	return x + cx, y + cy
 }
 
 // collectFocusables 按树序收集可聚焦节点（输入框与可点击元素）。
 func collectFocusables(rn *renderNode, out *[]*renderNode) {
+	if rn.hidden {
+		return
+	}
 	if rn.focusable {
 		*out = append(*out, rn)
 	}
@@ -1231,6 +1352,9 @@ func hitNode(rn *renderNode, px, py float32) *renderNode { return hitNodeIn(rn,
 //   - 其他节点：子树随本节点一起被变换、在层内是扁平的（对应 paintLayer 传 nil），
 //     所以子节点拿反变换后的点、且不再带相机。
 func hitNodeIn(rn *renderNode, px, py float32, cam *camera3D) *renderNode {
+	if rn.hidden {
+		return nil
+	}
 	lx, ly := rn.invTransform(px, py, cam)
 	inside := rn.bounds.contains(lx, ly)
 	if rn.clip && !inside {
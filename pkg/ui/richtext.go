@@ -28,6 +28,8 @@ type richSeg struct {
 	run  int
 	text string
 	x    float32 // 相对行首的 x（物理像素）
+	off  int     // 在全部 runs 文本首尾相接后的字节偏移（文本选择用）
+	rtl  bool    // 所在 bidi 段从右往左（段内字形由后端倒排）
 }
 
 // richLine 是排版后的一行。
@@ -36,6 +38,7 @@ type richLine struct {
 	width  float32
 	ascent float32 // 行内最大 ascent，用于混排基线对齐
 	height float32 // 行高 = 行内最大 lineH
+	rtl    bool    // 段落方向从右往左：整行贴右
 }
 
 // runStyleEqual 只比较文本相关字段（RichText 只从 Text 节点取这些）。
@@ -123,11 +126,18 @@ func (rn *renderNode) resolveRuns(ctx inhText) {
 
 // layoutRuns 把多段 runs 排成若干行（贪心折行，规则与 wrapForWidth 一致，跨 run 边界允许换行）。
 // 返回行、最大行宽、总高度（均为物理像素）。width<=0 表示不约束（不折行，仅按 '\n' 断行）。
-func layoutRuns(runs []textRun, width float32) ([]richLine, float32, float32) {
+// 每行折好后按 dir 重排为视觉顺序（见 reorderRichLine）。
+func layoutRuns(runs []textRun, width float32, dir TextDirection) ([]richLine, float32, float32) {
 	var lines []richLine
 	cur := richLine{}
 	curX := float32(0)
 	maxW := float32(0)
+	// 各行按所在段落（跨 run、'\n' 之间）的方向重排，续行不按自己的首个强字符重判。
+	var sb strings.Builder
+	for _, r := range runs {
+		sb.WriteString(r.text)
+	}
+	full, paraStart := sb.String(), 0
 
 	grow := func(r *textRun) {
 		if r.ascent > cur.ascent {
@@ -142,13 +152,17 @@ func layoutRuns(runs []textRun, width float32) ([]richLine, float32, float32) {
 		if curX > maxW {
 			maxW = curX
 		}
+		reorderRichLine(&cur, runs, paragraphDirAt(full, paraStart, dir))
 		lines = append(lines, cur)
 		cur = richLine{}
 		curX = 0
 	}
 
+	runOff := 0
 	for ri := range runs {
 		r := &runs[ri]
+		off := runOff
+		runOff += len(r.text)
 		if r.face == nil {
 			continue
 		}
@@ -156,17 +170,23 @@ func layoutRuns(runs []textRun, width float32) ([]richLine, float32, float32) {
 			if pi > 0 {
 				grow(r) // 让（可能为空的）当前行有合理行高
 				pushLine()
+				off++ // 跳过 '\n'
+				paraStart = off
 			}
 			for _, tk := range tokenize(para) {
+				tkOff := off
+				off += len(tk)
 				trimmed := strings.TrimRight(tk, " ")
 				if width > 0 && len(cur.segs) > 0 && curX+measureW(trimmed, r.face, r.lineH) > width {
 					pushLine()
-					tk = strings.TrimLeft(tk, " ")
+					t := strings.TrimLeft(tk, " ")
+					tkOff += len(tk) - len(t)
+					tk = t
 					if tk == "" {
 						continue
 					}
 				}
-				cur.segs = append(cur.segs, richSeg{ri, tk, curX})
+				cur.segs = append(cur.segs, richSeg{ri, tk, curX, tkOff, false})
 				curX += measureW(tk, r.face, r.lineH)
 				grow(r)
 			}
@@ -192,10 +212,18 @@ func paintRichText(p painter, rn *renderNode, o float32) {
 	y := b.Y
 	for _, ln := range lines {
 		baseline := y + ln.ascent
+		x0 := b.X
+		if ln.rtl && b.W > ln.width {
+			x0 += b.W - ln.width
+		}
 		for _, sg := range ln.segs {
 			r := &rn.runs[sg.run]
 			ty := baseline - r.ascent
-			p.DrawText(sg.text, r.face, r.color.Alpha(o), b.X+sg.x, ty, r.fauxBold, r.fauxItalic)
+			if rn.textGrad != nil {
+				p.DrawTextGradient(sg.text, r.face, rn.textGrad.alpha(o), b, x0+sg.x, ty, r.fauxBold, r.fauxItalic)
+				continue
+			}
+			p.DrawText(sg.text, r.face, r.color.Alpha(o), x0+sg.x, ty, r.fauxBold, r.fauxItalic)
 		}
 		y += ln.height
 	}
@@ -3,6 +3,7 @@ package ui
 import (
 	"math"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/sjm1327605995/tenon/yoga"
@@ -16,6 +17,8 @@ var activeGame *game
 var uiScale float32 = 1
 
 type game struct {
+	win       *Window // 所属窗口（多窗口时每个窗口一个 game）
+	scale     float32 // 该窗口的设备像素比；跑帧前由 activate 装进 uiScale
 	root      *Node
 	rootFiber *Fiber
 	rootRN    *renderNode
@@ -25,16 +28,43 @@ type game struct {
 	pendingEffects []func()
 	needsLayout    bool
 	focusedFiber   *Fiber
-	anims          []*tweenHook
+	anims          []animation
 	loops          []*loopHook
 	lastFrame      time.Time
 	hovered        map[*renderNode]bool
+	sheetWatch     map[*renderNode]struct{} // 样式表里伪类要跟踪状态的元素（见 stylesheet.go）
+	sheetHover     map[*renderNode]bool     // 光标下的元素链（:hover）
+	sheetPressed   bool                     // 左键按住（:active）
+
+	media      map[*mediaHook]struct{}  // UseMediaQuery / UseBreakpoint 订阅者（见 media.go）
+	responsive map[*renderNode]struct{} // 用了 At 的元素
+	mediaW     float32                  // 上次 syncMedia 时的窗口逻辑宽度
+	breakpoint Breakpoint               // 上次 syncMedia 时的断点
 
 	dragging             *renderNode
 	dragLastX, dragLastY float32
 	pressedNode          *renderNode
 	inputSelecting       bool
 
+	hotkeys []*hotkeyReg // UseHotkey 注册表（按注册顺序）
+	chord   []keyStroke  // 已按下的和弦前缀
+	chordAt time.Time
+
+	textSel *textSelection // Selectable 文字的当前选区（nil = 无）
+
+	focusNotified *renderNode // 最近一次回调过 OnFocus 的焦点节点（syncFocus 据此算出离开/进入）
+
+	dnd       *dndSession // 进行中的拖放（DragSource → DropTarget）
+	dragLayer *Fiber      // 拖放预览所在的浮层，不属于用户的 fiber 树
+
+	// 检查器（见 devtools.go）
+	inspecting   bool
+	inspectLayer *Fiber      // 检查器面板所在的浮层，不属于用户的 fiber 树
+	inspectStale bool        // 面板需要重建（用户树有提交 / 布局变了 / 面板里有交互）
+	inspectRev   int         // 面板重建次数，作为面板 props 让它重渲染
+	inspectHover *renderNode // 高亮的元素
+	inspectSel   *Fiber      // 面板里选中的 fiber
+
 	hoverX, hoverY float32 // 上次计算悬停链时的光标位置（用于空闲时跳过重算）
 
 	// 多击检测（双击选词 / 三击选全部）
@@ -51,6 +81,8 @@ type game struct {
 
 	// imeComposing 为真表示正在输入法组字（预编辑）。gio 的 IME 组字后续再接。
 	imeComposing bool
+
+	async sync.WaitGroup // UseAsync 在途的后台任务（Harness.WaitAsync 据此等待）
 }
 
 // FrameSync 预留：控制是否跟随刷新率（gio 循环当前恒重绘，暂未使用）。
@@ -65,6 +97,7 @@ type windowConfig struct {
 	maxW, maxH int // 0 = 不限制
 	fullscreen bool
 	sync       bool
+	inspectKey bool // Ctrl+Shift+I 开关检查器（默认关闭）
 }
 
 var winCfg = windowConfig{w: 800, h: 600, title: "Tenon UI"}
@@ -88,6 +121,9 @@ func WindowMaxSize(w, h int) { winCfg.maxW, winCfg.maxH = w, h }
 // WindowFullscreen 让窗口以全屏启动。
 func WindowFullscreen(on bool) { winCfg.fullscreen = on }
 
+// WindowInspectorKey 让 Ctrl+Shift+I 开关检查器面板（默认关闭，这个组合键交给应用自己）。
+func WindowInspectorKey(on bool) { winCfg.inspectKey = on }
+
 // Run 启动应用；root 通常是一个 Use(...) 组件节点。窗口与事件循环由 gio 后端（backendRun）提供。
 // 窗口相关设置（WindowSize/WindowTitle/...）须在此之前调用。
 func Run(root *Node) {
@@ -150,6 +186,21 @@ func (g *game) markDirty(f *Fiber) {
 	f.queued = true
 	f.dirty = true
 	g.dirty = append(g.dirty, f)
+	// 别的窗口里触发的更新（检查器改主窗口的状态）：该窗口可能正睡着，叫醒它来出帧。
+	if g != activeGame && g.win != nil {
+		if h := g.win.backend(); h != nil {
+			h.wake()
+		}
+	}
+}
+
+// activate 把 g 设为当前窗口：hooks 注册动画/effect、Viewport、绘制焦点环都经由 activeGame，
+// 坐标换算经由 uiScale。后端跑某个窗口的帧之前必须先调它。
+func (g *game) activate() {
+	activeGame = g
+	if g.scale > 0 {
+		uiScale = g.scale
+	}
 }
 
 func (g *game) flushDirty() {
@@ -165,22 +216,24 @@ func (g *game) flushDirty() {
 		renderComponent(f)
 	}
 	g.needsLayout = true
+	g.inspectStale = g.inspectStale || g.inspecting
 }
 
-// tickAnims 推进所有活动补间动画，并把其所属组件标记为需重渲染。
+// tickAnims 推进所有活动动画（补间、弹簧、关键帧），并把其所属组件标记为需重渲染。
 func (g *game) tickAnims(dt float32) {
 	if len(g.anims) == 0 || dt <= 0 {
 		return
 	}
 	live := g.anims[:0]
 	for _, h := range g.anims {
-		if h.fiber.unmounted || !h.active {
+		f := h.owner()
+		if f.unmounted || !h.running() {
 			continue
 		}
 		if h.advance(dt) {
 			live = append(live, h)
 		}
-		g.markDirty(h.fiber)
+		g.markDirty(f)
 	}
 	g.anims = live
 }
@@ -231,14 +284,23 @@ func (g *game) layout() {
 		computeBounds(g.rootRN, 0, 0)
 		syncMeasures(g.rootRN)
 		g.boundsDirty = false
+		g.inspectStale = g.inspectStale || g.inspecting
 	}
+	g.syncInspector()
 	g.layoutPortals(windowChanged)
+	g.syncTextSel()
 }
 
 // layoutPortals 为每个 Portal 建立全屏独立布局根并计算其 bounds（同样按需重算）。
 func (g *game) layoutPortals(windowChanged bool) {
 	g.portals = g.portals[:0]
 	collectPortals(g.rootFiber, &g.portals)
+	if g.inspectLayer != nil {
+		collectPortals(g.inspectLayer, &g.portals)
+	}
+	if g.dragLayer != nil {
+		g.portals = append(g.portals, g.dragLayer) // 拖放预览总在最上层
+	}
 	for _, pf := range g.portals {
 		root := pf.overlayRoot
 		var kids []*renderNode
@@ -272,6 +334,9 @@ func (g *game) layoutPortals(windowChanged bool) {
 // 看起来就是 hover 框在闪。
 func (g *game) hitTop(x, y float32) *renderNode {
 	for i := len(g.portals) - 1; i >= 0; i-- {
+		if g.portals[i] == g.dragLayer {
+			continue // 预览就在光标下，不能挡住它下面的放置目标
+		}
 		if r := g.portals[i].overlayRoot; r != nil {
 			if h := hitNode(r, x, y); h != nil && h != r {
 				return h
@@ -289,6 +354,8 @@ func (g *game) handleInput() {
 		return
 	}
 	g.updateHover()
+	g.trackSheetPointer()
+	g.trackInspectHover()
 
 	// 滚轮：把滚动施加到光标下最近的可滚动祖先。
 	// wheel() 与 bounds 同为物理像素，直接相加即可 —— 不要再乘 uiScale，那会在高 DPI 上
@@ -309,20 +376,11 @@ func (g *game) handleInput() {
 		x, y := input.cursor()
 		n := g.hitTop(x, y)
 		// 聚焦：命中 input 则聚焦；单击定位光标并开始拖选，双击选词，三击选全部
+		clicks := g.countClick(x, y)
 		if n != nil && n.kind == rnInput {
 			g.focusedFiber = n.owner
 			c := n.caretAt(x, y)
-			now := time.Now()
-			if absf(x-g.lastClickX) < 4 && absf(y-g.lastClickY) < 4 && now.Sub(g.lastClickAt) < 400*time.Millisecond {
-				g.clickCount++
-			} else {
-				g.clickCount = 1
-			}
-			if g.clickCount > 3 {
-				g.clickCount = 1
-			}
-			g.lastClickAt, g.lastClickX, g.lastClickY = now, x, y
-			switch g.clickCount {
+			switch clicks {
 			case 2: // 双击选词
 				n.selAnchor, n.caretPos = wordAt(n.value, c)
 				g.inputSelecting = false
@@ -333,10 +391,13 @@ func (g *game) handleInput() {
 				n.caretPos, n.selAnchor = c, c
 				g.inputSelecting = true
 			}
+		} else if t := keyTarget(n); t != nil {
+			g.focusedFiber = t.owner // 自己处理按键的组件：点一下就能接收键盘
 		} else {
 			g.focusedFiber = nil
-			g.clickCount = 0
 		}
+		// 静态文字选区：Selectable 区域内开始/按连击扩展，区域外清除
+		g.textSelPress(n, x, y, clicks)
 		// 按压开始：向上找第一个 onPress
 		for c := n; c != nil; c = c.parent {
 			if c.onPress != nil {
@@ -353,6 +414,7 @@ func (g *game) handleInput() {
 				break
 			}
 		}
+		g.dndPress(n, float32(x), float32(y))
 		// 点击冒泡：从命中节点向上找第一个 onClick
 		for c := n; c != nil; c = c.parent {
 			if c.onClick != nil {
@@ -373,9 +435,23 @@ func (g *game) handleInput() {
 	}
 	g.updatePress()
 	g.updateInputSelection()
-	g.handleKeyboardNav()
+	g.updateTextSelection()
 	g.updateDrag()
+	g.updateDnD()
+	g.handleKeys()
+	g.syncFocus()
+	g.syncSheetStates()
+}
+
+// handleKeys 处理本帧的键盘：先交给焦点链上的 OnKeyDown 与快捷键表，撤掉被消费或
+// PreventDefault 的那几次按键，其余的再走内置的焦点导航与文本编辑。
+func (g *game) handleKeys() {
+	if drop := g.dispatchKeys(); drop != nil {
+		input.dropStrokes(drop)
+	}
+	g.handleKeyboardNav()
 	g.editFocusedInput()
+	g.copyTextSelection()
 }
 
 // updateInputSelection 在聚焦输入框上拖动鼠标时扩展选区（单行）。
@@ -434,20 +510,29 @@ func (g *game) handleKeyboardNav() {
 	}
 }
 
-// focusNext 把焦点移到 Tab 顺序中的下一个（forward）或上一个可聚焦元素（含浮层），
-// 环形回绕；返回新的焦点节点，无可聚焦元素时返回 nil。
-func (g *game) focusNext(forward bool) *renderNode {
+// focusOrder 返回 Tab 顺序中的全部可聚焦节点：主树在前、浮层按打开顺序在后；
+// 有模态浮层时只有它里面的。无障碍树的 TabIndex 也取自这里，两者不能分岔。
+func (g *game) focusOrder() []*renderNode {
 	var list []*renderNode
 	if scope := g.trapScope(); scope != nil {
 		collectFocusables(scope, &list) // 模态：焦点只在浮层内循环
-	} else {
+		return list
+	}
+	if g.rootRN != nil {
 		collectFocusables(g.rootRN, &list)
-		for _, pf := range g.portals {
-			if pf.overlayRoot != nil {
-				collectFocusables(pf.overlayRoot, &list)
-			}
+	}
+	for _, pf := range g.portals {
+		if pf.overlayRoot != nil {
+			collectFocusables(pf.overlayRoot, &list)
 		}
 	}
+	return list
+}
+
+// focusNext 把焦点移到 Tab 顺序中的下一个（forward）或上一个可聚焦元素（含浮层），
+// 环形回绕；返回新的焦点节点，无可聚焦元素时返回 nil。
+func (g *game) focusNext(forward bool) *renderNode {
+	list := g.focusOrder()
 	var cur *renderNode
 	if g.focusedFiber != nil {
 		cur = g.focusedFiber.rnode
@@ -508,6 +593,21 @@ func (g *game) updateDrag() {
 	}
 }
 
+// updateDnD 按住左键时推进拖放（预览、悬停目标），松开则投放。
+func (g *game) updateDnD() {
+	if g.dnd == nil {
+		return
+	}
+	x, y := input.cursor()
+	if !input.mousePressed(btnLeft) {
+		g.dndRelease(float32(x), float32(y))
+		return
+	}
+	if float32(x) != g.dnd.x || float32(y) != g.dnd.y {
+		g.dndMove(float32(x), float32(y))
+	}
+}
+
 // editFocusedInput 处理聚焦输入框的键盘编辑：文本输入、选区、剪切/复制/粘贴/全选（受控回流）。
 // 注：CJK 输入法组字（IME 预编辑）尚未在 gio 后端接入，此处只做手动编辑。
 func (g *game) editFocusedInput() {
@@ -602,7 +702,8 @@ func (g *game) editFocusedInput() {
 			anchor = caret
 		}
 	}
-	if repeatKey(keyLeft) {
+	// 左右键按逻辑顺序移动：光标处是 RTL 文字时方向对调（← 走向逻辑上的下一个字符）。
+	back := func() {
 		if hasSel() && !shift {
 			caret = selLo()
 		} else if caret > 0 {
@@ -614,7 +715,7 @@ func (g *game) editFocusedInput() {
 		}
 		afterMove()
 	}
-	if repeatKey(keyRight) {
+	forward := func() {
 		if hasSel() && !shift {
 			caret = selHi()
 		} else if caret < len(val) {
@@ -626,6 +727,20 @@ func (g *game) editFocusedInput() {
 		}
 		afterMove()
 	}
+	if repeatKey(keyLeft) {
+		if rtlAt(val, caret, rn.textDir) {
+			forward()
+		} else {
+			back()
+		}
+	}
+	if repeatKey(keyRight) {
+		if rtlAt(val, caret, rn.textDir) {
+			back()
+		} else {
+			forward()
+		}
+	}
 	if input.keyJustPressed(keyHome) {
 		caret = 0
 		afterMove()
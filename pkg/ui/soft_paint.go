@@ -0,0 +1,391 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// ---- 软件后端：纯 Go 的 CPU 光栅化 ----
+//
+// softPainter 是 painter 的又一个实现，把一帧直接画进 *image.RGBA，不需要窗口、也不需要
+// GPU —— 没有显卡的 CI 上照样能跑像素测试：
+//
+//	img, err := ui.Screenshot(root, 800, 600, ui.SoftwareBackend)
+//	img, err := ui.Mount(root, 800, 600).Screenshot()
+//
+// 与 gio 后端的对应关系：
+//
+//   - 形状：路径展平后交给 golang.org/x/image/vector 按覆盖率光栅化（抗锯齿）；描边在
+//     soft_path.go 展开成多边形。圆角与 gio 是同一套三次贝塞尔（rrectTo）。
+//   - 裁剪：每层一个 alpha 蒙版栈，嵌套裁剪逐层求交。
+//   - 文字：gio 的 shaper 整形（字形位置与屏幕一致），轮廓取自内置字体；合成粗体 / 斜体
+//     与屏幕同样是描边加粗、剪切出斜体。彩色位图字形（emoji）不画。
+//   - 渐变、阴影：逐像素求色；阴影与 gio 一样叠几层渐扩的半透明圆角矩形。
+//   - 图层：先画进一张离屏画布，施加 CPU 版滤镜（filter.go 的 applyFilters）与整组透明度，
+//     再按 layerMatrix 双线性重采样合回；伪 3D 另按 projCorners 的四边形裁出轮廓。
+//
+// 与 GPU 结果的差异只在抗锯齿与重采样的细节上，几何位置一致。
+
+// Backend 选择 Screenshot 的渲染后端。
+type Backend int
+
+const (
+	// GPUBackend 走 gio 的 GPU 路径，与真实窗口逐像素一致；需要可用的 GPU/驱动。默认。
+	GPUBackend Backend = iota
+	// SoftwareBackend 走纯 Go 的 CPU 光栅化（softPainter），不需要 GPU。
+	SoftwareBackend
+)
+
+// softScreenshot 用软件后端画一帧：底色、主树与各 Portal 浮层，以及打开时的检查器高亮。
+func softScreenshot(g *game) (*image.RGBA, error) {
+	f, err := newExportFont()
+	if err != nil {
+		return nil, err
+	}
+	p := newSoftPainter(g.w, g.h, f)
+	exportPaint(p, g)
+	g.paintInspector(p)
+	return p.layers[0].img, nil
+}
+
+// softLayer 是一个图层的画布与其中的裁剪栈。
+type softLayer struct {
+	img   *image.RGBA
+	clips []*image.Alpha // 栈顶是当前生效的裁剪（已与外层求交，Rect 即其外接矩形）
+}
+
+func (l *softLayer) clip() *image.Alpha {
+	if len(l.clips) == 0 {
+		return nil
+	}
+	return l.clips[len(l.clips)-1]
+}
+
+type softPainter struct {
+	w, h   int
+	font   *exportFont
+	z      vector.Rasterizer
+	layers []*softLayer
+}
+
+func newSoftPainter(w, h int, f *exportFont) *softPainter {
+	p := &softPainter{w: w, h: h, font: f}
+	p.BeginLayer()
+	return p
+}
+
+func (p *softPainter) top() *softLayer { return p.layers[len(p.layers)-1] }
+
+// coverage 把路径光栅化成覆盖率蒙版（画布坐标，已乘上当前裁剪）；什么都盖不到时返回 nil。
+func (p *softPainter) coverage(path *softPath) *image.Alpha {
+	x0, y0, x1, y1 := path.bounds()
+	r := image.Rect(x0, y0, x1, y1).Intersect(image.Rect(0, 0, p.w, p.h))
+	clip := p.top().clip()
+	if clip != nil {
+		r = r.Intersect(clip.Rect)
+	}
+	if r.Empty() {
+		return nil
+	}
+	p.z.Reset(r.Dx(), r.Dy())
+	p.z.DrawOp = draw.Src
+	path.rasterize(&p.z, r.Min.X, r.Min.Y)
+	mask := image.NewAlpha(image.Rect(0, 0, r.Dx(), r.Dy()))
+	p.z.Draw(mask, mask.Rect, image.Opaque, image.Point{})
+	mask.Rect = r // 像素不动，只把坐标系挪到画布上
+	if clip != nil {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			m := mask.Pix[mask.PixOffset(r.Min.X, y):][:r.Dx()]
+			c := clip.Pix[clip.PixOffset(r.Min.X, y):][:r.Dx()]
+			for i := range m {
+				m[i] = uint8((uint32(m[i])*uint32(c[i]) + 127) / 255)
+			}
+		}
+	}
+	return mask
+}
+
+// fill 用纯色填充路径。
+func (p *softPainter) fill(path *softPath, c Color) {
+	if c.A == 0 {
+		return
+	}
+	if mask := p.coverage(path); mask != nil {
+		draw.DrawMask(p.top().img, mask.Rect, image.NewUniform(nrgba(c)), image.Point{}, mask, mask.Rect.Min, draw.Over)
+	}
+}
+
+// fillGradient 用渐变填充路径；渐变铺满 box。
+func (p *softPainter) fillGradient(path *softPath, g *Gradient, box Rect) {
+	if len(g.Stops) == 0 || box.W <= 0 || box.H <= 0 {
+		return
+	}
+	mask := p.coverage(path)
+	if mask == nil {
+		return
+	}
+	r := mask.Rect
+	src := image.NewNRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := g.colorAt(g.offsetAt(float32(x)+0.5-box.X, float32(y)+0.5-box.Y, box.W, box.H))
+			i := src.PixOffset(x, y)
+			src.Pix[i], src.Pix[i+1], src.Pix[i+2], src.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	draw.DrawMask(p.top().img, r, src, r.Min, mask, r.Min, draw.Over)
+}
+
+func softRRect(x, y, w, h, r float32) *softPath {
+	s := newSoftPath(affine2D{sx: 1, sy: 1})
+	rrectTo(s, x, y, w, h, r)
+	return s
+}
+
+// softRing 是圆角矩形描边的轮廓：外圈向外扩半个线宽，内圈向内收半个线宽并反向（挖空）。
+// 与 gio 的 clip.Stroke 一样，外圈圆角变大、内圈变小。
+func softRing(x, y, w, h, r, width float32) *softPath {
+	hw := width / 2
+	r = clampRadius(w, h, r)
+	s := softRRect(x-hw, y-hw, w+width, h+width, r+hw)
+	if w > width && h > width {
+		rrectTo(s, x+hw, y+hw, w-width, h-width, max(r-hw, 0))
+		s.reverse()
+	}
+	return s
+}
+
+func (p *softPainter) FillRect(x, y, w, h, r float32, c Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	p.fill(softRRect(x, y, w, h, r), c)
+}
+
+func (p *softPainter) FillGradient(x, y, w, h, r float32, g *Gradient) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	p.fillGradient(softRRect(x, y, w, h, r), g, Rect{x, y, w, h})
+}
+
+// StrokeGradient 用渐变描边；与 gio 后端一样，铺渐变的区域向外扩半个线宽。
+func (p *softPainter) StrokeGradient(x, y, w, h, r, width float32, g *Gradient) {
+	if width <= 0 || w <= 0 || h <= 0 {
+		return
+	}
+	hw := width / 2
+	p.fillGradient(softRing(x, y, w, h, r, width), g, Rect{x - hw, y - hw, w + width, h + width})
+}
+
+func (p *softPainter) StrokeRect(x, y, w, h, r, width float32, c Color) {
+	if c.A == 0 || width <= 0 || w <= 0 || h <= 0 {
+		return
+	}
+	p.fill(softRing(x, y, w, h, r, width), c)
+}
+
+// Shadow 与 gioPainter.Shadow 同一画法：几层渐扩的半透明圆角矩形。
+func (p *softPainter) Shadow(x, y, w, h, r, offX, offY, blur, spread float32, c Color) {
+	if c.A == 0 || w <= 0 || h <= 0 {
+		return
+	}
+	bx, by := x+offX-spread, y+offY-spread
+	bw, bh := w+spread*2, h+spread*2
+	br := max(r+spread, 0)
+	if blur <= 0 {
+		p.FillRect(bx, by, bw, bh, br, c)
+		return
+	}
+	const layers = 5
+	la := c.Alpha(1.0 / float32(layers))
+	for i := layers; i >= 1; i-- {
+		g := blur * float32(i) / float32(layers)
+		p.FillRect(bx-g, by-g, bw+g*2, bh+g*2, br+g, la)
+	}
+	p.FillRect(bx, by, bw, bh, br, la)
+}
+
+func (p *softPainter) Line(x0, y0, x1, y1 float32, c Color) {
+	s := newSoftPath(affine2D{sx: 1, sy: 1})
+	s.moveTo(x0, y0)
+	s.lineTo(x1, y1)
+	p.fill(s.stroke(1, false), c)
+}
+
+// glyphPath 把一行字形的轮廓摆到画布上：(x,y) 为行左上角，基线在 y+ascent；
+// 合成斜体绕行首基线剪切（与 drawGioText 同序：先剪切、再平移）。
+func (p *softPainter) glyphPath(run shapedRun, x, y float32, fauxItalic bool) *softPath {
+	k := run.px / p.font.upem
+	var sh float32
+	if fauxItalic {
+		sh = float32(math.Tan(fauxItalicShear))
+	}
+	s := &softPath{}
+	base := y + run.ascent
+	for _, g := range run.glyphs {
+		s.m = affine2D{sx: k, hx: -sh * k, ox: x + g.x - sh*g.y, sy: k, oy: base + g.y}
+		open := false
+		for _, seg := range p.font.segments(g.gid) {
+			a := seg.Args
+			switch seg.Op {
+			case sfnt.SegmentOpMoveTo:
+				if open {
+					s.closePath()
+				}
+				s.moveTo(fx(a[0].X), fx(a[0].Y))
+				open = true
+			case sfnt.SegmentOpLineTo:
+				s.lineTo(fx(a[0].X), fx(a[0].Y))
+			case sfnt.SegmentOpQuadTo:
+				s.quadTo(fx(a[0].X), fx(a[0].Y), fx(a[1].X), fx(a[1].Y))
+			case sfnt.SegmentOpCubeTo:
+				s.cubeTo(fx(a[0].X), fx(a[0].Y), fx(a[1].X), fx(a[1].Y), fx(a[2].X), fx(a[2].Y))
+			}
+		}
+		if open {
+			s.closePath()
+		}
+	}
+	return s
+}
+
+// text 画一行字：填充字形，合成粗体时再描一圈轮廓（与 drawGioText 一致）。
+func (p *softPainter) text(s string, face fontFace, x, y float32, fauxBold, fauxItalic bool, fill func(*softPath)) {
+	if s == "" {
+		return
+	}
+	run := exportShape(face, s)
+	path := p.glyphPath(run, x, y, fauxItalic)
+	fill(path)
+	if fauxBold {
+		fill(path.stroke(fauxBoldWidth(run.px), false))
+	}
+}
+
+func (p *softPainter) DrawText(s string, face fontFace, c Color, x, y float32, fauxBold, fauxItalic bool) {
+	if c.A == 0 {
+		return
+	}
+	p.text(s, face, x, y, fauxBold, fauxItalic, func(path *softPath) { p.fill(path, c) })
+}
+
+func (p *softPainter) DrawTextGradient(s string, face fontFace, g *Gradient, box Rect, x, y float32, fauxBold, fauxItalic bool) {
+	p.text(s, face, x, y, fauxBold, fauxItalic, func(path *softPath) { p.fillGradient(path, g, box) })
+}
+
+// DrawImage 把源图双线性缩放到 d（亚像素对齐），整图透明度并进目标蒙版。
+func (p *softPainter) DrawImage(img bitmap, d Rect, opacity float32) {
+	src := exportImage(img)
+	b := src.Bounds()
+	if opacity <= 0 || d.W <= 0 || d.H <= 0 || b.Empty() {
+		return
+	}
+	sx, sy := float64(d.W)/float64(b.Dx()), float64(d.H)/float64(b.Dy())
+	s2d := f64.Aff3{sx, 0, float64(d.X) - sx*float64(b.Min.X), 0, sy, float64(d.Y) - sy*float64(b.Min.Y)}
+	xdraw.BiLinear.Transform(p.top().img, s2d, src, b, draw.Over, &xdraw.Options{DstMask: p.dstMask(opacity)})
+}
+
+// dstMask 是当前裁剪乘上透明度 o 的目标蒙版；都不起作用时为 nil（注意不能返回带类型的 nil）。
+func (p *softPainter) dstMask(o float32) image.Image {
+	a := uint8(clampf(o, 0, 1)*255 + 0.5)
+	clip := p.top().clip()
+	switch {
+	case clip == nil && a == 255:
+		return nil
+	case clip == nil:
+		return image.NewUniform(color.Alpha{a})
+	case a == 255:
+		return clip
+	}
+	m := image.NewAlpha(clip.Rect)
+	for i, v := range clip.Pix {
+		m.Pix[i] = uint8((uint32(v)*uint32(a) + 127) / 255)
+	}
+	return m
+}
+
+func (p *softPainter) pathOf(path vecPath, x, y float32) *softPath {
+	d, scale := exportPath(path)
+	s := newSoftPath(affine2D{sx: 1, ox: x, sy: 1, oy: y})
+	parseSVGInto(d, scale, s)
+	return s
+}
+
+func (p *softPainter) FillPath(path vecPath, x, y float32, c Color) {
+	p.fill(p.pathOf(path, x, y), c)
+}
+
+func (p *softPainter) StrokePath(path vecPath, x, y, width float32, c Color) {
+	if width <= 0 {
+		return
+	}
+	p.fill(p.pathOf(path, x, y).stroke(width, true), c)
+}
+
+func (p *softPainter) PushClip(r Rect, radius float32) {
+	l := p.top()
+	mask := p.coverage(softRRect(r.X, r.Y, r.W, r.H, radius))
+	if mask == nil {
+		mask = image.NewAlpha(image.Rectangle{}) // 什么都不透过
+	}
+	l.clips = append(l.clips, mask)
+}
+
+func (p *softPainter) PopClip() {
+	l := p.top()
+	l.clips = l.clips[:len(l.clips)-1]
+}
+
+// BeginLayer 开一张透明的离屏画布；图层内的裁剪从空栈开始，外层裁剪在合回时施加。
+func (p *softPainter) BeginLayer() {
+	p.layers = append(p.layers, &softLayer{img: image.NewRGBA(image.Rect(0, 0, p.w, p.h))})
+}
+
+func (p *softPainter) EndLayer(t layerTransform) {
+	n := len(p.layers) - 1
+	img := p.layers[n].img
+	p.layers = p.layers[:n]
+	if len(t.filters) > 0 {
+		img = applyFilters(img, t.filters)
+	}
+	if t.opacity < 1 { // 预乘像素：整组透明度就是各通道同乘
+		a := uint32(clampf(t.opacity, 0, 1)*255 + 0.5)
+		for i, v := range img.Pix {
+			img.Pix[i] = uint8((uint32(v)*a + 127) / 255)
+		}
+	}
+
+	var mask image.Image
+	if clip := p.top().clip(); clip != nil {
+		mask = clip
+	}
+	if t.is3D() { // 伪 3D：内容按 contentAffine 合回，轮廓裁到投影四边形
+		d0, d1, d2, d3 := projCorners(t)
+		quad := &softPath{}
+		quad.polygon([]pt{d0, d1, d3, d2})
+		qm := p.coverage(quad)
+		if qm == nil {
+			return
+		}
+		mask = qm
+	}
+	a := layerMatrix(t)
+	if a.identity() {
+		draw.DrawMask(p.top().img, img.Rect, img, image.Point{}, mask, image.Point{}, draw.Over)
+		return
+	}
+	s2d := f64.Aff3{float64(a.sx), float64(a.hx), float64(a.ox), float64(a.hy), float64(a.sy), float64(a.oy)}
+	xdraw.BiLinear.Transform(p.top().img, s2d, img, img.Rect, draw.Over, &xdraw.Options{DstMask: mask})
+}
+
+// fx 把 26.6 定点数转成 float32。
+func fx(v fixed.Int26_6) float32 { return float32(v) / 64 }
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func softShot(t *testing.T, root *Node, w, h int) *image.RGBA {
+	t.Helper()
+	img, err := Screenshot(root, w, h, SoftwareBackend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// near 判断两色每个通道相差不超过 tol（抗锯齿与 8 位量化留的余量）。
+func near(got color.Color, want Color, tol int) bool {
+	c := color.RGBAModel.Convert(got).(color.RGBA)
+	d := func(a, b uint8) bool { return int(a)-int(b) <= tol && int(b)-int(a) <= tol }
+	return d(c.R, want.R) && d(c.G, want.G) && d(c.B, want.B) && d(c.A, want.A)
+}
+
+// 形状：圆角填充、圆角外的角落露出底色、描边中间是空的、裁剪之外不画。
+func TestSoftwareShapesAndClip(t *testing.T) {
+	red, blue := Hex("#ff0000"), Hex("#0000ff")
+	img := softShot(t, Div(Style(Column, Padding(10), Gap(10)),
+		Div(Style(Width(60), Height(40), Bg(red), Radius(12))),
+		Div(Style(Width(60), Height(40), Border(2, blue))),
+		Div(Style(Width(30), Height(20), Clip, Radius(4)), Div(Style(Width(200), Height(20), Bg(blue)))),
+	), 200, 160)
+
+	for _, c := range []struct {
+		x, y int
+		want Color
+		what string
+	}{
+		{40, 30, red, "圆角矩形中心"},
+		{10, 10, canvasBg, "圆角之外的角落"},
+		{10, 70, blue, "描边（居中落在盒边上）"},
+		{40, 80, canvasBg, "描边中间"},
+		{25, 120, blue, "裁剪之内"},
+		{60, 120, canvasBg, "裁剪之外"},
+	} {
+		if got := img.At(c.x, c.y); !near(got, c.want, 2) {
+			t.Errorf("%s (%d,%d)：得到 %v，期望 %v", c.what, c.x, c.y, got, c.want)
+		}
+	}
+}
+
+// 文字：用内置字体的轮廓画出来；合成粗体的墨迹比常规体多，合成斜体照样画得出来。
+func TestSoftwareText(t *testing.T) {
+	ink := func(opts ...StyleOpt) int {
+		img := softShot(t, Div(Style(Padding(4)), Text("Tenon", append(opts, TextColor(Black))...)), 120, 40)
+		return darkPixels(img, 0, 120, 0, 40)
+	}
+	regular, bold, italic := ink(), ink(Bold), ink(Italic)
+	if regular == 0 {
+		t.Fatal("文字没画出来")
+	}
+	if bold <= regular {
+		t.Errorf("合成粗体应更粗：粗体 %d 像素，常规 %d 像素", bold, regular)
+	}
+	if italic == 0 {
+		t.Error("斜体没画出来")
+	}
+}
+
+// 渐变、图片与带透明度 / 滤镜 / 平移的图层。
+func TestSoftwareGradientImageAndLayers(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{0, 200, 0, 255})
+	}
+	grad := NewLinearGradient(0, Stop(0, Hex("#000000")), Stop(1, Hex("#ffffff")))
+	img := softShot(t, Div(Style(Column, Padding(10), Gap(10)),
+		Div(Style(Width(100), Height(20), BgGradient(grad))),
+		Img(SrcImage("mem:soft", src), Style(Width(20), Height(20))),
+		Div(Style(Width(20), Height(20), Bg(Hex("#ff0000")), Opacity(0.5))),
+		Div(Style(Width(20), Height(20), Bg(Hex("#ff0000")), Grayscale(1))),
+		Div(Style(Width(20), Height(20), Bg(Hex("#0000ff")), TranslateXY(50, 0))),
+	), 200, 160)
+
+	if l, r := img.RGBAAt(12, 20), img.RGBAAt(107, 20); l.R > 20 || r.R < 235 {
+		t.Errorf("0° 线性渐变应从左黑到右白：左 %v，右 %v", l, r)
+	}
+	if got := img.At(20, 50); !near(got, Color{0, 200, 0, 255}, 2) {
+		t.Errorf("图片像素：%v", got)
+	}
+	if got := img.At(20, 80); !near(got, Mix(canvasBg, Hex("#ff0000"), 0.5), 3) {
+		t.Errorf("半透明图层应与底色各半混合：%v", got)
+	}
+	if c := img.RGBAAt(20, 110); c.R != c.G || c.G != c.B {
+		t.Errorf("Grayscale(1) 之后应是灰色：%v", c)
+	}
+	if a, b := img.At(20, 140), img.At(70, 140); !near(a, canvasBg, 0) || !near(b, Hex("#0000ff"), 2) {
+		t.Errorf("平移 50px 的图层应画在右边：原位 %v，新位 %v", a, b)
+	}
+}
+
+// 伪 3D：RotateY(40) 加透视后投影成梯形 —— 转向观者的左边比远离的右边高。
+func TestSoftwareProjectedLayer(t *testing.T) {
+	img := softShot(t, Div(Style(Padding(20)),
+		Div(Style(Width(100), Height(100), Bg(Hex("#000000")), RotateY(40), Perspective(300))),
+	), 140, 140)
+	column := func(x int) int { return darkPixels(img, x, x+1, 0, 140) }
+	left, right := column(35), column(100)
+	if right == 0 || left <= right {
+		t.Fatalf("投影后左边应比右边高：左 %d，右 %d", left, right)
+	}
+}
+
+// Screenshot 临时挂载的树截完就卸载：effect 的清理会执行。
+func TestScreenshotUnmountsTree(t *testing.T) {
+	cleaned := 0
+	comp := func(_ struct{}) *Node {
+		UseEffect(func() Cleanup { return func() { cleaned++ } })
+		return Div(Style(Width(20), Height(20)))
+	}
+	softShot(t, Use(comp, struct{}{}), 40, 40)
+	if cleaned != 1 {
+		t.Fatalf("cleanup 执行了 %d 次，want 1", cleaned)
+	}
+}
+
+// Harness.Screenshot 画的是当前帧：与同一棵树的 Screenshot(SoftwareBackend) 逐像素一致，
+// 交互改变状态后画面随之改变。
+func TestHarnessScreenshot(t *testing.T) {
+	comp := func(_ struct{}) *Node {
+		on, setOn := UseState(false)
+		bg := Hex("#ffffff")
+		if on {
+			bg = Hex("#111827")
+		}
+		return Div(Style(Width(80), Height(40), Bg(bg)), OnClick(func() { setOn(!on) }), Text("go"))
+	}
+	h := Mount(Use(comp, struct{}{}), 120, 60)
+	before, err := h.Screenshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := softShot(t, Use(comp, struct{}{}), 120, 60); diffPixels(before, want) != 0 {
+		t.Fatal("Harness.Screenshot 应与 Screenshot(SoftwareBackend) 一致")
+	}
+	h.Root().ByText("go").Click()
+	after, _ := h.Screenshot()
+	if !near(after.At(60, 30), Hex("#111827"), 0) {
+		t.Fatalf("点击后背景应变深：%v", after.At(60, 30))
+	}
+}
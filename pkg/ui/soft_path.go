@@ -0,0 +1,182 @@
+package ui
+
+import (
+	"math"
+	"slices"
+
+	"golang.org/x/image/vector"
+)
+
+// ---- 软件后端：路径展平与描边 ----
+//
+// softPath 收集画布坐标下的折线：曲线在写入时就按长度展平，填充交给 x/image/vector
+// 的覆盖率光栅化，描边则在这里展开成一组多边形（每段一个四边形，拐角与端点补圆）再填充。
+// vector 的覆盖率按绕数的绝对值累加、封顶为 1，所以描边的各块只要方向一致，重叠处就不会
+// 互相抵消；字形的内外轮廓方向相反，洞照样挖得出来。
+
+// softPath 是一组折线（画布坐标）；m 是写入时施加在每个点上的仿射。
+type softPath struct {
+	m      affine2D
+	polys  [][]pt
+	closed []bool
+	start  pt
+	last   pt
+}
+
+func newSoftPath(m affine2D) *softPath { return &softPath{m: m} }
+
+func (s *softPath) moveTo(x, y float32) {
+	s.start = s.m.transform(pt{x, y})
+	s.last = s.start
+	s.polys = append(s.polys, []pt{s.start})
+	s.closed = append(s.closed, false)
+}
+
+func (s *softPath) add(p pt) {
+	if len(s.polys) == 0 {
+		s.moveTo(0, 0)
+	}
+	n := len(s.polys) - 1
+	s.polys[n] = append(s.polys[n], p)
+	s.last = p
+}
+
+func (s *softPath) lineTo(x, y float32) { s.add(s.m.transform(pt{x, y})) }
+
+func (s *softPath) quadTo(x1, y1, x, y float32) {
+	p0, p1, p2 := s.last, s.m.transform(pt{x1, y1}), s.m.transform(pt{x, y})
+	n := flattenSteps(dist(p0, p1) + dist(p1, p2))
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		u := 1 - t
+		s.add(pt{u*u*p0.X + 2*u*t*p1.X + t*t*p2.X, u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y})
+	}
+}
+
+func (s *softPath) cubeTo(x1, y1, x2, y2, x, y float32) {
+	p0, p1, p2, p3 := s.last, s.m.transform(pt{x1, y1}), s.m.transform(pt{x2, y2}), s.m.transform(pt{x, y})
+	n := flattenSteps(dist(p0, p1) + dist(p1, p2) + dist(p2, p3))
+	for i := 1; i <= n; i++ {
+		t := float32(i) / float32(n)
+		u := 1 - t
+		a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		s.add(pt{a*p0.X + b*p1.X + c*p2.X + d*p3.X, a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y})
+	}
+}
+
+func (s *softPath) closePath() {
+	if n := len(s.polys) - 1; n >= 0 {
+		s.closed[n] = true
+		s.last = s.start
+	}
+}
+
+// flattenSteps 是一段曲线展平成几段直线：按控制多边形长度的平方根增长，
+// 小圆角几段、大弧几十段，误差都在亚像素。
+func flattenSteps(length float32) int {
+	n := int(math.Ceil(math.Sqrt(float64(length) * 2)))
+	return min(max(n, 1), 100)
+}
+
+func dist(a, b pt) float32 { return float32(math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))) }
+
+// bounds 是折线的外接矩形（整数像素，向外取整）。
+func (s *softPath) bounds() (x0, y0, x1, y1 int) {
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, poly := range s.polys {
+		for _, p := range poly {
+			minX, minY = min(minX, p.X), min(minY, p.Y)
+			maxX, maxY = max(maxX, p.X), max(maxY, p.Y)
+		}
+	}
+	if minX > maxX {
+		return 0, 0, 0, 0
+	}
+	return int(math.Floor(float64(minX))), int(math.Floor(float64(minY))),
+		int(math.Ceil(float64(maxX))), int(math.Ceil(float64(maxY)))
+}
+
+// rasterize 把折线（按各自闭合）写进光栅器，整体平移 (-ox,-oy)。
+func (s *softPath) rasterize(z *vector.Rasterizer, ox, oy int) {
+	fx, fy := float32(ox), float32(oy)
+	for _, poly := range s.polys {
+		if len(poly) < 3 {
+			continue
+		}
+		z.MoveTo(poly[0].X-fx, poly[0].Y-fy)
+		for _, p := range poly[1:] {
+			z.LineTo(p.X-fx, p.Y-fy)
+		}
+		z.ClosePath()
+	}
+}
+
+// reverse 把最后一条折线倒过来（圆角环的内圈要与外圈反向，才能挖空）。
+func (s *softPath) reverse() {
+	if n := len(s.polys) - 1; n >= 0 {
+		slices.Reverse(s.polys[n])
+	}
+}
+
+// stroke 把折线描成宽 width 的轮廓：每段一个四边形，拐角补圆（圆角连接）；
+// roundCap 为真时开放折线的两端也补圆，否则是平头。
+func (s *softPath) stroke(width float32, roundCap bool) *softPath {
+	out := &softPath{}
+	hw := width / 2
+	for i, poly := range s.polys {
+		closed := s.closed[i]
+		if closed && len(poly) > 1 && poly[0] == poly[len(poly)-1] {
+			poly = poly[:len(poly)-1]
+		}
+		n := len(poly)
+		segs := n - 1
+		if closed {
+			segs = n
+		}
+		for j := 0; j < segs; j++ {
+			a, b := poly[j], poly[(j+1)%n]
+			l := dist(a, b)
+			if l == 0 {
+				continue
+			}
+			nx, ny := -(b.Y-a.Y)/l*hw, (b.X-a.X)/l*hw
+			out.polygon([]pt{{a.X + nx, a.Y + ny}, {b.X + nx, b.Y + ny}, {b.X - nx, b.Y - ny}, {a.X - nx, a.Y - ny}})
+		}
+		for j, p := range poly {
+			end := !closed && (j == 0 || j == n-1)
+			if end && !roundCap {
+				continue
+			}
+			if n > 1 || roundCap {
+				out.disc(p, hw)
+			}
+		}
+	}
+	return out
+}
+
+// polygon 追加一个闭合多边形，统一成正向（y 向下时顺时针），重叠处才不会抵消。
+func (s *softPath) polygon(ps []pt) {
+	var area float32
+	for i := range ps {
+		a, b := ps[i], ps[(i+1)%len(ps)]
+		area += a.X*b.Y - b.X*a.Y
+	}
+	if area < 0 {
+		slices.Reverse(ps)
+	}
+	s.polys = append(s.polys, ps)
+	s.closed = append(s.closed, true)
+}
+
+// disc 追加一个半径 r 的圆（正向多边形）。
+func (s *softPath) disc(c pt, r float32) {
+	n := min(max(int(r*4), 8), 64)
+	ps := make([]pt, n)
+	for i := range ps {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		ps[i] = pt{c.X + r*float32(cos), c.Y + r*float32(sin)}
+	}
+	s.polygon(ps)
+}
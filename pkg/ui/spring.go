@@ -0,0 +1,82 @@
+package ui
+
+// ---- 弹簧动画 ----
+//
+// UseTween 按固定时长插值：中途改目标时从当前值重新开始一段补间，速度瞬间归零再加速，
+// 拖动松手、连续切换这类场景会看到明显的顿挫。弹簧没有时长，只有状态（位置 + 速度）：
+// 改目标只是换了平衡点，当前速度原样保留，运动是连续的。
+
+// 默认弹簧参数：刚度 170、阻尼 26，接近临界阻尼，不过冲、不拖沓。
+const (
+	DefaultStiffness float32 = 170
+	DefaultDamping   float32 = 26
+)
+
+// 静止阈值：离目标不到 springRestDelta、速度低于 springRestSpeed（每秒）即视为停下，
+// 对齐到目标并退出 anims。阈值对像素与 0..1 的透明度都已不可见。
+const (
+	springRestDelta = 0.001
+	springRestSpeed = 0.01
+)
+
+// springStep 是积分步长（秒）。半隐式欧拉在 1ms 步长下对刚度上千的弹簧也稳定，
+// 且与帧率无关：步长固定，帧长只决定一次推进几步（不足一步的部分留到下一帧）。
+const springStep = 0.001
+
+type springHook struct {
+	cur, vel, target   float32 // vel 为每秒的变化量
+	stiffness, damping float32
+	carry              float64 // 不足一步的剩余时间（毫秒），留到下一帧
+	fiber              *Fiber
+	active             bool
+}
+
+// UseSpring 用弹簧把数值带到 target，返回当前值。target 变化时保留当前速度继续运动
+// （不像 UseTween 那样速度突变）；离目标与速度都低于静止阈值后对齐到 target 并停下，
+// 之后不再重渲染。stiffness 为刚度、damping 为阻尼（质量为 1），<=0 时取
+// DefaultStiffness / DefaultDamping。阻尼小于 2√stiffness 时会过冲回弹。
+func UseSpring(target, stiffness, damping float32) float32 {
+	f := currentFiber
+	_, raw := nextHook(f, func() any {
+		return &springHook{cur: target, target: target}
+	})
+	h := raw.(*springHook)
+	h.fiber = f
+	if stiffness <= 0 {
+		stiffness = DefaultStiffness
+	}
+	if damping <= 0 {
+		damping = DefaultDamping
+	}
+	h.stiffness, h.damping = stiffness, damping
+
+	if h.target != target {
+		h.target = target
+		if !h.active {
+			h.active = true
+			startAnim(f, h)
+		}
+	}
+	return h.cur
+}
+
+func (h *springHook) owner() *Fiber { return h.fiber }
+func (h *springHook) running() bool { return h.active }
+
+// advance 按固定步长积分 dt 毫秒，返回是否仍在运动。
+func (h *springHook) advance(dt float32) bool {
+	h.carry += float64(dt)
+	steps := int(h.carry + 1e-6) // 每步 1ms；容差吸收 16.000001 这类舍入
+	h.carry = max(h.carry-float64(steps), 0)
+	for range steps {
+		a := -h.stiffness*(h.cur-h.target) - h.damping*h.vel
+		h.vel += a * springStep
+		h.cur += h.vel * springStep
+		if absf(h.cur-h.target) < springRestDelta && absf(h.vel) < springRestSpeed {
+			h.cur, h.vel, h.carry = h.target, 0, 0
+			h.active = false
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+)
+
+// springApp 把 UseSpring 的当前值写进文本，setTarget 改目标。
+func springApp(setTarget *func(float32), out *float32, stiffness, damping float32) func(struct{}) *Node {
+	return func(_ struct{}) *Node {
+		target, set := UseState(float32(0))
+		*setTarget = set
+		*out = UseSpring(target, stiffness, damping)
+		return Text(fmt.Sprintf("%.3f", *out))
+	}
+}
+
+// 弹簧收敛到目标后停下：退出 anims，静止帧不再重渲染。
+func TestUseSpringSettles(t *testing.T) {
+	var set func(float32)
+	var v float32
+	h := Mount(Use(springApp(&set, &v, 0, 0), struct{}{}), 100, 40)
+	set(100)
+	h.Step(0)
+	if len(h.g.anims) != 1 {
+		t.Fatalf("改目标应登记动画：anims=%d", len(h.g.anims))
+	}
+	h.Step(100)
+	if v <= 0 || v >= 100 {
+		t.Fatalf("100ms 时应在途中：%v", v)
+	}
+	for i := 0; i < 200 && len(h.g.anims) > 0; i++ {
+		h.Step(16)
+	}
+	if v != 100 || len(h.g.anims) != 0 {
+		t.Fatalf("应停在目标并退出 anims：v=%v anims=%d", v, len(h.g.anims))
+	}
+}
+
+// 中途改目标保留速度：往 100 冲的弹簧改回 0 后，下一帧仍先朝 100 的方向走（UseTween 会立即掉头）。
+func TestUseSpringKeepsVelocityOnRetarget(t *testing.T) {
+	var set func(float32)
+	var v float32
+	h := Mount(Use(springApp(&set, &v, 0, 0), struct{}{}), 100, 40)
+	set(100)
+	h.Step(0)
+	h.Step(50)
+	before := v
+	set(0)
+	h.Step(0)
+	h.Step(8)
+	if v <= before {
+		t.Fatalf("改目标后第一帧应沿原速度继续：%v -> %v", before, v)
+	}
+}
+
+// 欠阻尼的弹簧会过冲；积分与帧长无关：一次推进 300ms 与分 30 帧推进结果相同。
+func TestUseSpringOvershootAndFrameIndependence(t *testing.T) {
+	run := func(frames int) (peak, end float32) {
+		var set func(float32)
+		var v float32
+		h := Mount(Use(springApp(&set, &v, 300, 8), struct{}{}), 100, 40)
+		set(1)
+		h.Step(0)
+		for range frames {
+			h.Step(300 / float32(frames))
+			peak = max(peak, v)
+		}
+		return peak, v
+	}
+	peak, end1 := run(30)
+	if peak <= 1 {
+		t.Errorf("欠阻尼应过冲：峰值 %v", peak)
+	}
+	if _, end2 := run(1); absf(end1-end2) > 1e-3 {
+		t.Errorf("结果应与帧长无关：30 帧 %v，1 帧 %v", end1, end2)
+	}
+}
+
+// 关键帧：多段缓动按时间取值；Stagger 错开起点；Sequence 首尾相接；deps 变化从头重播。
+func TestUseKeyframes(t *testing.T) {
+	var vals []float32
+	var replay func(int)
+	comp := func(_ struct{}) *Node {
+		n, set := UseState(0)
+		replay = set
+		tracks := append(Stagger(2, 100,
+			Keyframe{At: 0, Value: 0},
+			Keyframe{At: 100, Value: 10},
+			Keyframe{At: 200, Value: 0, Ease: EaseIn},
+		), Sequence(
+			Keyframes(Keyframe{At: 0, Value: 0}, Keyframe{At: 50, Value: 1}),
+			Keyframes(Keyframe{At: 0, Value: 5}, Keyframe{At: 50, Value: 6}),
+		)...)
+		vals = UseKeyframes(tracks, n)
+		return Text(fmt.Sprint(vals))
+	}
+	h := Mount(Use(comp, struct{}{}), 100, 40)
+	if len(vals) != 4 || vals[0] != 0 || vals[1] != 0 || vals[3] != 5 {
+		t.Fatalf("初始值应取各轨道第一帧：%v", vals)
+	}
+	h.Step(50)
+	if vals[0] != 5 || vals[1] != 0 || vals[2] != 1 || vals[3] != 5 {
+		t.Fatalf("50ms：%v（第二条延迟 100ms，第四条排在第三条之后）", vals)
+	}
+	h.Step(75)
+	if vals[0] <= 5 || vals[0] >= 10 || vals[1] != 2.5 || vals[3] != 6 {
+		t.Fatalf("125ms：%v", vals)
+	}
+	h.Step(1000)
+	if vals[0] != 0 || vals[1] != 0 || len(h.g.anims) != 0 {
+		t.Fatalf("播完停在最后一帧并退出 anims：%v anims=%d", vals, len(h.g.anims))
+	}
+	replay(1)
+	h.Step(0)
+	if vals[2] != 0 || len(h.g.anims) != 1 {
+		t.Fatalf("deps 变化应从头重播：%v anims=%d", vals, len(h.g.anims))
+	}
+}
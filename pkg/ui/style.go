@@ -31,11 +31,19 @@ type StyleProps struct {
 	hasWrap bool
 	hasCont bool
 
+	// 网格（见 grid.go）
+	grid                       bool
+	gridCols, gridRows         []Track
+	gridAutoCols, gridAutoRows Track
+	gridFlowCol, gridDense     bool
+	gridColStart, gridColEnd   yoga.GridLine
+	gridRowStart, gridRowEnd   yoga.GridLine
+	rowGap, colGap             float32 // NaN 表示沿用 gap
+
 	bg          Color
-	gradFrom    Color
-	gradTo      Color
-	gradAngle   float32
-	hasGradient bool
+	bgGrad      *Gradient // 背景 / 边框 / 文字渐变（见 gradient.go）
+	borderGrad  *Gradient
+	textGrad    *Gradient
 	radius      float32
 	borderW     float32
 	borderColor Color
@@ -44,12 +52,19 @@ type StyleProps struct {
 	// 定位
 	absolute               bool
 	posT, posR, posB, posL float32
-	zIndex                 int // 绘制/命中的层叠顺序（见 ZIndex）
+	zIndex                 int  // 绘制/命中的层叠顺序（见 ZIndex）
+	hidden                 bool // display: none —— 不占位、不绘制、不可命中（Suspense 挂起时用）
 
 	opacity float32
 
+	// 滤镜（见 filter.go）：按书写顺序作用于整棵子树；BackdropBlur 作用于背后的内容
+	filters      []filterOp
+	backdropBlur float32
+
+	responsive bool // 用了 At：跨断点时要重算（见 media.go）
+
 	// 变换（围绕自身中心，2D）
-	scale          float32
+	scaleX, scaleY float32 // 缩放（X/Y 各自；Scale 同时设两者）
 	rotate         float32 // 角度（绕 Z 轴，2D 旋转）
 	transX, transY float32
 
@@ -77,6 +92,7 @@ type StyleProps struct {
 	hasWeight   bool
 	italic      bool
 	hasItalic   bool
+	textDir     TextDirection // 书写方向（见 bidi.go），DirAuto 为继承
 }
 
 // StyleOpt 是作用于 StyleProps 的选项。
@@ -88,7 +104,8 @@ func newStyleProps() StyleProps {
 		width: n, height: n, widthPct: n, heightPct: n,
 		minW: n, minH: n, maxW: n, maxH: n,
 		posT: n, posR: n, posB: n, posL: n,
-		opacity: 1, scale: 1,
+		rowGap: n, colGap: n,
+		opacity: 1, scaleX: 1, scaleY: 1,
 	}
 }
 
@@ -175,10 +192,9 @@ func Bg(c Color) StyleOpt { return func(s *StyleProps) { s.bg = c } }
 
 // LinearGradient 用线性渐变作为背景填充：颜色从 from 到 to，
 // angleDeg 为方向角（0=左→右，90=上→下，45=左上→右下）。会遵循圆角。
+// 多色标、径向、锥形渐变见 BgGradient。
 func LinearGradient(from, to Color, angleDeg float32) StyleOpt {
-	return func(s *StyleProps) {
-		s.gradFrom, s.gradTo, s.gradAngle, s.hasGradient = from, to, angleDeg, true
-	}
+	return BgGradient(NewLinearGradient(angleDeg, Stop(0, from), Stop(1, to)))
 }
 func Radius(v float32) StyleOpt { return func(s *StyleProps) { s.radius = v } }
 func Border(w float32, c Color) StyleOpt {
@@ -188,13 +204,22 @@ func Border(w float32, c Color) StyleOpt {
 // Clip 裁剪超出自身边界的子内容（overflow: hidden）。
 func Clip(s *StyleProps) { s.clip = true }
 
+// Hidden 隐藏元素（display: none）：不占位、不绘制、不可命中，子树里的快捷键不生效、
+// 焦点移出，但子树保持挂载、状态不丢（如后台的标签页）。
+func Hidden(s *StyleProps) { s.hidden = true }
+
 // Opacity 设置不透明度（0..1）。叶子节点作用于自身；有子节点时作为整组透明度。
 func Opacity(v float32) StyleOpt { return func(s *StyleProps) { s.opacity = v } }
 
 // ---- 变换（围绕元素中心）----
 
-func Scale(v float32) StyleOpt    { return func(s *StyleProps) { s.scale = v } }
+func Scale(v float32) StyleOpt    { return func(s *StyleProps) { s.scaleX, s.scaleY = v, v } }
 func Rotate(deg float32) StyleOpt { return func(s *StyleProps) { s.rotate = deg } }
+
+// ScaleXY 在 X、Y 方向各自缩放（不等比），如把一块元素补间到宽高比不同的目标上。
+func ScaleXY(x, y float32) StyleOpt {
+	return func(s *StyleProps) { s.scaleX, s.scaleY = x, y }
+}
 func TranslateXY(x, y float32) StyleOpt {
 	return func(s *StyleProps) { s.transX, s.transY = x, y }
 }
@@ -0,0 +1,429 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ---- 样式表：选择器 + 伪类 ----
+//
+// 内联 Style 之外的第二种写法：把规则按选择器集中写在一张 StyleSheet 里，经 Provider
+// 装到子树上，子树里每个 host 元素按匹配到的规则得到样式。
+//
+//	sheet := ui.NewStyleSheet().
+//	    Rule("button", ui.Radius(6), ui.PaddingXY(12, 6)).
+//	    Rule(".primary", ui.Bg(brand), ui.TextColor(ui.White)).
+//	    Rule(".primary:hover", ui.Bg(brandDark)).
+//	    Rule(".toolbar button:disabled", ui.Opacity(0.5)).
+//	    Important(".card", ui.Radius(0)) // 盖过组件自己的内联样式
+//	ui.ProvideStyleSheet(sheet, app)
+//
+// 选择器：标签（div/button/input/…，* 为任意）、.class、#id 与伪类 :hover / :focus / :active /
+// :disabled 组合成一个复合选择器；空格分隔表示后代（祖先按 fiber 链找，Portal 里的内容
+// 也算在挂出它的组件之下）；逗号分隔多个选择器。Class("a b") 的多个类名用空格分隔。
+//
+// 层叠与 CSS 相同：普通规则按特异度（id > class/伪类 > 标签）再按书写顺序，在内联 Style 之下；
+// Important 规则在内联之上。嵌套的 Provider 都生效，外层的规则在前。
+//
+// 伪类按需重算：匹配时记下「依赖哪些元素的状态」（sheetWatch），悬停 / 聚焦 / 按下变化时
+// 只重算这些元素子树里依赖状态的节点，静态规则不会因为鼠标移动而重跑。
+// :hover 跟随真实光标（Harness 里用 PointerMove 驱动），:active 为光标在其上且左键按住，
+// :focus 为元素本身聚焦，:disabled 读 AriaDisabled(true)。
+
+// pseudoState 是伪类状态位。
+type pseudoState uint8
+
+const (
+	stHover pseudoState = 1 << iota
+	stFocus
+	stActive
+	stDisabled
+
+	stDynamic = stHover | stFocus | stActive // 随交互变化、需要跟踪的状态
+)
+
+var pseudoNames = map[string]pseudoState{
+	"hover": stHover, "focus": stFocus, "active": stActive, "disabled": stDisabled,
+}
+
+// compound 是一个复合选择器，如 button.primary:hover。
+type compound struct {
+	tag     string // "" 为任意
+	id      string
+	classes []string
+	pseudo  pseudoState
+}
+
+// selector 是以空格连接的复合选择器序列，最后一个是主体。
+type selector struct {
+	parts []compound
+	spec  int // 特异度：id*10000 + (class+伪类)*100 + 标签
+}
+
+type sheetRule struct {
+	sel       selector
+	opts      []StyleOpt
+	important bool
+	order     int
+}
+
+// StyleSheet 是一组按选择器匹配的样式规则。用 NewStyleSheet 创建，ProvideStyleSheet 安装。
+type StyleSheet struct {
+	rules []sheetRule
+}
+
+// NewStyleSheet 创建一张空样式表。
+func NewStyleSheet() *StyleSheet { return &StyleSheet{} }
+
+// Rule 添加一条规则：匹配 selector 的元素得到 opts（在内联 Style 之下）。
+// selector 语法错误时 panic（与 regexp.MustCompile 一样，属于编程错误）。
+func (s *StyleSheet) Rule(selector string, opts ...StyleOpt) *StyleSheet {
+	return s.add(selector, opts, false)
+}
+
+// Important 同 Rule，但优先于元素的内联 Style —— 不改组件源码就能重新设计它的外观。
+func (s *StyleSheet) Important(selector string, opts ...StyleOpt) *StyleSheet {
+	return s.add(selector, opts, true)
+}
+
+func (s *StyleSheet) add(src string, opts []StyleOpt, important bool) *StyleSheet {
+	for _, part := range strings.Split(src, ",") {
+		sel, err := parseSelector(part)
+		if err != nil {
+			panic(fmt.Sprintf("ui: StyleSheet selector %q: %v", src, err))
+		}
+		s.rules = append(s.rules, sheetRule{sel, opts, important, len(s.rules)})
+	}
+	return s
+}
+
+var styleSheetCtx = CreateContext[*StyleSheet](nil)
+
+// sheetsInstalled 在第一次 ProvideStyleSheet 之后为真；之前 applyHostProps 不必沿 fiber 链找表。
+var sheetsInstalled bool
+
+// ProvideStyleSheet 把样式表装到子树上。换成另一张表时整棵子树重算样式。
+func ProvideStyleSheet(s *StyleSheet, children ...*Node) *Node {
+	sheetsInstalled = true
+	return styleSheetCtx.Provider(s, children...)
+}
+
+// parseSelector 解析一个（不含逗号的）选择器。
+func parseSelector(src string) (selector, error) {
+	var sel selector
+	for _, tok := range strings.Fields(src) {
+		c, err := parseCompound(tok)
+		if err != nil {
+			return sel, err
+		}
+		sel.parts = append(sel.parts, c)
+		sel.spec += c.specificity()
+	}
+	if len(sel.parts) == 0 {
+		return sel, fmt.Errorf("empty selector")
+	}
+	return sel, nil
+}
+
+func parseCompound(tok string) (compound, error) {
+	var c compound
+	i := identEnd(tok, 0)
+	c.tag = tok[:i]
+	if c.tag == "" && i < len(tok) && tok[i] == '*' {
+		i++
+	}
+	for i < len(tok) {
+		sigil := tok[i]
+		j := identEnd(tok, i+1)
+		name := tok[i+1 : j]
+		if name == "" {
+			return c, fmt.Errorf("missing name after %q", sigil)
+		}
+		switch sigil {
+		case '.':
+			c.classes = append(c.classes, name)
+		case '#':
+			c.id = name
+		case ':':
+			st, ok := pseudoNames[name]
+			if !ok {
+				return c, fmt.Errorf("unknown pseudo-class :%s", name)
+			}
+			c.pseudo |= st
+		default:
+			return c, fmt.Errorf("unexpected %q", sigil)
+		}
+		i = j
+	}
+	return c, nil
+}
+
+// identEnd 返回从 i 起标识符（字母、数字、-、_）的结束下标。
+func identEnd(s string, i int) int {
+	for i < len(s) {
+		b := s[i]
+		if b == '-' || b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80 {
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
+
+func (c compound) specificity() int {
+	n := 0
+	if c.id != "" {
+		n += 10000
+	}
+	n += 100 * len(c.classes)
+	for st := stHover; st <= stDisabled; st <<= 1 {
+		if c.pseudo&st != 0 {
+			n += 100
+		}
+	}
+	if c.tag != "" {
+		n++
+	}
+	return n
+}
+
+// sheetElem 是匹配时看到的一个 host 元素。
+type sheetElem struct {
+	rn      *renderNode
+	tag, id string
+	classes []string
+	state   pseudoState // 含静态的 stDisabled
+}
+
+func elemOf(rn *renderNode) sheetElem {
+	e := sheetElem{rn: rn, id: rn.id, classes: rn.classes, state: rn.sheetState}
+	if rn.owner != nil {
+		e.tag = rn.owner.tag
+	}
+	if rn.a11y.disabled {
+		e.state |= stDisabled
+	}
+	return e
+}
+
+// matches 报告 c 是否匹配 e；live 为假时不看会变化的伪类（用来找「可能匹配」的规则）。
+func (c *compound) matches(e sheetElem, live bool) bool {
+	if c.tag != "" && c.tag != e.tag || c.id != "" && c.id != e.id {
+		return false
+	}
+	for _, cl := range c.classes {
+		found := false
+		for _, h := range e.classes {
+			if h == cl {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	need := c.pseudo
+	if !live {
+		need &^= stDynamic
+	}
+	return e.state&need == need
+}
+
+// matches 从主体往上匹配；后代关系按最近祖先贪心即可（只有后代组合子）。
+func (s *selector) matches(self sheetElem, ancestors []sheetElem, live bool) bool {
+	i := len(s.parts) - 1
+	if !s.parts[i].matches(self, live) {
+		return false
+	}
+	i--
+	for _, a := range ancestors {
+		if i < 0 {
+			break
+		}
+		if s.parts[i].matches(a, live) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+// sheetsOf 返回 fiber 所在的所有样式表，外层在前。
+func sheetsOf(f *Fiber) []*StyleSheet {
+	var out []*StyleSheet
+	for p := f.parent; p != nil; p = p.parent {
+		if p.typ == typeProvider && p.ctxID == styleSheetCtx.id {
+			if s, _ := p.ctxValue.(*StyleSheet); s != nil {
+				out = append([]*StyleSheet{s}, out...)
+			}
+		}
+	}
+	return out
+}
+
+// hostAncestors 返回 f 之上的 host 元素，最近的在前。
+func hostAncestors(f *Fiber) []sheetElem {
+	var out []sheetElem
+	for p := f.parent; p != nil; p = p.parent {
+		if p.typ == typeHost && p.rnode != nil {
+			out = append(out, elemOf(p.rnode))
+		}
+	}
+	return out
+}
+
+// cascadeStyle 按样式表与内联属性算出 rn 的样式：普通规则 < 内联 attrs < Important 规则。
+// 同时记下 rn 是否依赖交互状态，并把需要跟踪状态的元素登记到 sheetWatch。
+func (rn *renderNode) cascadeStyle(sheets []*StyleSheet, attrs []func(*hostProps)) StyleProps {
+	self := elemOf(rn)
+	anc := hostAncestors(rn.owner)
+	type hit struct {
+		r     *sheetRule
+		sheet int
+	}
+	var hits []hit
+	rn.sheetDynamic = false
+	for si, s := range sheets {
+		for i := range s.rules {
+			r := &s.rules[i]
+			if !r.sel.matches(self, anc, false) {
+				continue
+			}
+			if rn.watchDynamic(&r.sel, self, anc) {
+				rn.sheetDynamic = true
+			}
+			if r.sel.matches(self, anc, true) {
+				hits = append(hits, hit{r, si})
+			}
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.r.sel.spec != b.r.sel.spec {
+			return a.r.sel.spec < b.r.sel.spec
+		}
+		if a.sheet != b.sheet {
+			return a.sheet < b.sheet
+		}
+		return a.r.order < b.r.order
+	})
+	hp := hostProps{style: newStyleProps()}
+	for _, h := range hits {
+		if !h.r.important {
+			for _, o := range h.r.opts {
+				o(&hp.style)
+			}
+		}
+	}
+	for _, ap := range attrs {
+		ap(&hp)
+	}
+	for _, h := range hits {
+		if h.r.important {
+			for _, o := range h.r.opts {
+				o(&hp.style)
+			}
+		}
+	}
+	return hp.style
+}
+
+// watchDynamic 为带交互伪类的选择器登记要跟踪状态的元素：主体自己，或匹配该段的祖先。
+// 返回选择器是否含交互伪类。
+func (rn *renderNode) watchDynamic(sel *selector, self sheetElem, anc []sheetElem) bool {
+	g := gameOf(rn.owner)
+	dynamic := false
+	last := len(sel.parts) - 1
+	for i := range sel.parts {
+		c := &sel.parts[i]
+		if c.pseudo&stDynamic == 0 {
+			continue
+		}
+		dynamic = true
+		if g == nil {
+			continue
+		}
+		if g.sheetWatch == nil {
+			g.sheetWatch = map[*renderNode]struct{}{}
+		}
+		if i == last {
+			g.sheetWatch[rn] = struct{}{}
+			continue
+		}
+		for _, a := range anc {
+			if c.matches(a, false) {
+				g.sheetWatch[a.rn] = struct{}{}
+			}
+		}
+	}
+	return dynamic
+}
+
+// restyle 按当前状态重算 rn 的样式（只动样式，不碰输入框的值、回调等其它属性）。
+func (rn *renderNode) restyle() {
+	if rn.owner == nil || rn.owner.unmounted {
+		return
+	}
+	st := rn.cascadeStyle(sheetsOf(rn.owner), rn.hostAttrs)
+	for _, o := range rn.inspectStyle {
+		o(&st)
+	}
+	syncYoga(rn, st)
+	if rn.kind == rnInput {
+		rn.applyTextStyle(st)
+	}
+}
+
+// restyleTree 重算 f 子树里所有 host 元素的样式；onlyDynamic 时只重算依赖交互状态的。
+func restyleTree(f *Fiber, onlyDynamic bool) {
+	if f.typ == typeHost && f.rnode != nil && (!onlyDynamic || f.rnode.sheetDynamic) {
+		f.rnode.restyle()
+	}
+	for _, c := range f.children {
+		restyleTree(c, onlyDynamic)
+	}
+}
+
+// trackSheetPointer 在处理指针输入时记下光标下的元素链与左键状态，供 :hover / :active 使用。
+func (g *game) trackSheetPointer() {
+	if len(g.sheetWatch) == 0 {
+		return
+	}
+	x, y := input.cursor()
+	g.sheetHover = map[*renderNode]bool{}
+	for c := g.hitTop(x, y); c != nil; c = c.parent {
+		g.sheetHover[c] = true
+	}
+	g.sheetPressed = input.mousePressed(btnLeft)
+}
+
+// syncSheetStates 比较被跟踪元素的伪类状态，有变化的只重算其子树里依赖状态的节点。
+func (g *game) syncSheetStates() {
+	changed := false
+	for rn := range g.sheetWatch {
+		if rn.owner == nil || rn.owner.unmounted {
+			delete(g.sheetWatch, rn)
+			continue
+		}
+		var st pseudoState
+		if g.sheetHover[rn] {
+			st |= stHover
+			if g.sheetPressed {
+				st |= stActive
+			}
+		}
+		if isFocused(rn) {
+			st |= stFocus
+		}
+		if st != rn.sheetState {
+			rn.sheetState = st
+			restyleTree(rn.owner, true)
+			changed = true
+		}
+	}
+	if changed {
+		g.needsLayout = true
+	}
+}
@@ -0,0 +1,130 @@
+package ui
+
+import "testing"
+
+func byID(h *Harness, id string) *Query {
+	return h.Root().Find(func(q *Query) bool { return q.rn.id == id })
+}
+
+// 层叠：普通规则按特异度排在内联 Style 之下，Important 盖过内联。
+func TestStyleSheetCascade(t *testing.T) {
+	sheet := NewStyleSheet().
+		Rule("#a", Bg(Green)).
+		Rule("div", Bg(Red), Radius(3)).
+		Rule(".card", Bg(Blue)).
+		Important(".forced", Bg(Gray))
+	h := Mount(ProvideStyleSheet(sheet,
+		Div(Style(Column),
+			Div(Id("a"), Class("card")),
+			Div(Id("b"), Class("card")),
+			Div(Id("c"), Class("card"), Style(Bg(White))),
+			Div(Id("d"), Class("card forced"), Style(Bg(White))),
+		)), 200, 200)
+	for id, want := range map[string]Color{"a": Green, "b": Blue, "c": White, "d": Gray} {
+		if got := byID(h, id).rn.bg; got != want {
+			t.Errorf("#%s bg = %v, want %v", id, got, want)
+		}
+	}
+	if byID(h, "b").rn.radius != 3 {
+		t.Error("低特异度规则里没被覆盖的属性仍应生效")
+	}
+}
+
+// 后代选择器按祖先链匹配，跨过组件边界。
+func TestStyleSheetDescendant(t *testing.T) {
+	sheet := NewStyleSheet().Rule(".toolbar button", Bg(Red))
+	item := func(id string) *Node { return Button(Id(id), OnClick(func() {})) }
+	h := Mount(ProvideStyleSheet(sheet,
+		Div(Div(Class("toolbar"), Use(item, "in")), Use(item, "out"))), 200, 200)
+	if byID(h, "in").rn.bg != Red {
+		t.Fatal("toolbar 里的按钮应匹配")
+	}
+	if byID(h, "out").rn.bg == Red {
+		t.Fatal("toolbar 外的按钮不应匹配")
+	}
+}
+
+// :hover / :active 跟随真实指针，只重算依赖状态的节点；:focus 跟随焦点；:disabled 读 AriaDisabled。
+func TestStyleSheetPseudoStates(t *testing.T) {
+	restyles := 0
+	count := func(*StyleProps) { restyles++ }
+	sheet := NewStyleSheet().
+		Rule(".static", count).
+		Rule("button", Bg(White)).
+		Rule("button:hover", Bg(Gray)).
+		Rule("button:active", Bg(Black)).
+		Rule("input:focus", Border(2, Blue)).
+		Rule(".row:hover .label", Bg(Red)).
+		Rule("button:disabled", Opacity(0.5))
+	h := Mount(ProvideStyleSheet(sheet,
+		Div(Style(Column),
+			Div(Class("static"), Style(Height(20))),
+			Button(Id("btn"), OnClick(func() {}), Style(Width(60), Height(20))),
+			Div(Class("row"), Style(Height(20)), Div(Id("label"), Class("label"), Style(Width(10), Height(10)))),
+			Input(Id("in"), Placeholder("q")),
+			Button(Id("off"), AriaDisabled(true)),
+		)), 200, 200)
+	btn, label := byID(h, "btn"), byID(h, "label")
+	if btn.rn.bg != White {
+		t.Fatalf("初始 bg = %v", btn.rn.bg)
+	}
+	before := restyles
+	b := btn.Bounds()
+	h.PointerMove(b.X+5, b.Y+5)
+	if btn.rn.bg != Gray {
+		t.Fatalf(":hover bg = %v", btn.rn.bg)
+	}
+	h.PointerDown(b.X+5, b.Y+5)
+	if btn.rn.bg != Black {
+		t.Fatalf(":active bg = %v", btn.rn.bg)
+	}
+	h.PointerUp(b.X+5, b.Y+5)
+	if btn.rn.bg != Gray {
+		t.Fatalf("松开后回到 :hover，bg = %v", btn.rn.bg)
+	}
+	r := label.Bounds()
+	h.PointerMove(r.X+r.W+20, r.Y+2) // 在 row 上、不在 label 上
+	if label.rn.bg != Red || btn.rn.bg != White {
+		t.Fatalf("祖先 :hover 应作用于后代：label %v, btn %v", label.rn.bg, btn.rn.bg)
+	}
+	if restyles != before {
+		t.Fatalf("静态规则不应随指针移动重算（%d 次）", restyles-before)
+	}
+	byID(h, "in").Focus()
+	if byID(h, "in").rn.borderW != 2 {
+		t.Fatal(":focus 规则未生效")
+	}
+	if byID(h, "off").rn.opacity != 0.5 {
+		t.Fatal(":disabled 规则未生效")
+	}
+}
+
+// 换一张样式表时整棵子树重算，memo 短路的组件也不例外。
+func TestStyleSheetProviderChange(t *testing.T) {
+	leaf := func(_ struct{}) *Node { return Div(Id("leaf"), Class("x")) }
+	var setDark func(bool)
+	app := func(_ struct{}) *Node {
+		dark, set := UseState(false)
+		setDark = set
+		sheet := NewStyleSheet().Rule(".x", Bg(White))
+		if dark {
+			sheet = NewStyleSheet().Rule(".x", Bg(Black))
+		}
+		return ProvideStyleSheet(sheet, Memo(leaf, struct{}{}))
+	}
+	h := Mount(Use(app, struct{}{}), 100, 100)
+	setDark(true)
+	h.settle()
+	if got := byID(h, "leaf").rn.bg; got != Black {
+		t.Fatalf("换表后 bg = %v", got)
+	}
+}
+
+func TestStyleSheetBadSelectorPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("未知伪类应 panic")
+		}
+	}()
+	NewStyleSheet().Rule("button:visited", Bg(Red))
+}
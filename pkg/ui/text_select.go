@@ -0,0 +1,349 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ---- 静态文字的选择与复制 ----
+//
+// 只有 Input 能选中文字；日志、详情这类只读面板里的 Text / RichText 也常要复制。
+// Selectable() 挂在容器（或单个 Text）上，其中所有文字连成一段可选区域，手势与输入框一致：
+// 拖动选择（可跨多个文本节点），双击选词，三击全选，Ctrl+C 复制（经 SetClipboardText），
+// 在区域内点过之后 Ctrl+A 也是全选。
+//
+//	ui.ScrollView(ui.Selectable(),
+//	    ui.Text(line1), ui.Text(line2), ...,
+//	)
+//
+// 选区记为 (文本节点, 字节偏移) 的两端，按文档顺序排列；跨节点复制时节点之间用换行连接。
+
+// Selectable 让本元素及其后代里的文字可以被选中和复制。
+func Selectable() *Node {
+	return &Node{typ: typeAttr, applyAttr: func(hp *hostProps) { hp.selectable = true }}
+}
+
+// selPoint 是选区的一端：某个文本节点内的字节偏移。
+type selPoint struct {
+	rn  *renderNode
+	off int
+}
+
+// textSelection 是窗口当前的静态文字选区（同一时刻至多一个，像浏览器一样）。
+type textSelection struct {
+	root     *renderNode // 所在的 Selectable 区域
+	anchor   selPoint
+	focus    selPoint
+	dragging bool
+	ranges   map[*renderNode][2]int // 每个被选中文本节点的 [lo,hi)，refresh 时重算
+}
+
+// selectableRoot 返回 n 向上最近的 Selectable 区域。
+func selectableRoot(n *renderNode) *renderNode {
+	for c := n; c != nil; c = c.parent {
+		if c.selectable {
+			return c
+		}
+	}
+	return nil
+}
+
+// selTexts 按文档顺序收集区域内可见的文本节点。
+func selTexts(rn *renderNode, out *[]*renderNode) {
+	if rn.hidden {
+		return
+	}
+	if rn.kind == rnText {
+		*out = append(*out, rn)
+	}
+	for _, c := range rn.children {
+		selTexts(c, out)
+	}
+}
+
+// selText 返回文本节点的全文（富文本为各段首尾相接）。
+func (rn *renderNode) selText() string {
+	if len(rn.runs) == 0 {
+		return rn.text
+	}
+	var b strings.Builder
+	for _, r := range rn.runs {
+		b.WriteString(r.text)
+	}
+	return b.String()
+}
+
+// textOffsetAt 把屏幕点映射到文本节点内最近的字节偏移（点在节点外时夹到首尾行）。
+func (rn *renderNode) textOffsetAt(x, y float32) int {
+	b := rn.bounds
+	if len(rn.runs) > 0 {
+		lines, _, _ := rn.richLayout(b.W)
+		top := b.Y
+		for i, ln := range lines {
+			if y < top+ln.height || i == len(lines)-1 {
+				return richOffsetAt(rn, ln, x-richLineX(b, ln))
+			}
+			top += ln.height
+		}
+		return 0
+	}
+	if rn.face == nil || rn.text == "" {
+		return 0
+	}
+	spans := wrapSpans(rn.text, rn.face, rn.lineH, b.W)
+	row := clampi(int((y-b.Y)/float32(rn.lineH)), 0, len(spans)-1)
+	if y < b.Y {
+		row = 0
+	}
+	return spans[row].offsetInSpan(x-b.X, rn.textDir, rn.face, rn.lineH, b.W)
+}
+
+// richLineX 返回富文本行的行首 x（RTL 行贴右，同 paintRichText）。
+func richLineX(b Rect, ln richLine) float32 {
+	if ln.rtl && b.W > ln.width {
+		return b.X + b.W - ln.width
+	}
+	return b.X
+}
+
+// richOffsetAt 返回富文本行内相对行首 relX 处最近的字节偏移。
+func richOffsetAt(rn *renderNode, ln richLine, relX float32) int {
+	if len(ln.segs) == 0 {
+		return 0
+	}
+	best, bestD := ln.segs[0].off, float32(-1)
+	for _, sg := range ln.segs {
+		r := &rn.runs[sg.run]
+		for i := range sg.text {
+			if !utf8.RuneStart(sg.text[i]) {
+				continue
+			}
+			d := absf(sg.x + measureW(sg.text[:i], r.face, r.lineH) - relX)
+			if bestD < 0 || d < bestD {
+				best, bestD = sg.off+i, d
+			}
+		}
+		end := sg.x + measureW(sg.text, r.face, r.lineH)
+		if d := absf(end - relX); d < bestD {
+			best, bestD = sg.off+len(sg.text), d
+		}
+	}
+	return best
+}
+
+// textPointAt 返回区域内离 (x,y) 最近的文字位置：先找包含该点的文本节点，否则取最近的那个。
+func textPointAt(root *renderNode, x, y float32) (selPoint, bool) {
+	var texts []*renderNode
+	selTexts(root, &texts)
+	var best *renderNode
+	bestD := float32(-1)
+	for _, t := range texts {
+		b := t.bounds
+		dx := max(b.X-x, 0, x-(b.X+b.W))
+		dy := max(b.Y-y, 0, y-(b.Y+b.H))
+		d := dx*dx + dy*dy*4 // 纵向距离更重要：行间空白里应落到同一行
+		if bestD < 0 || d < bestD {
+			best, bestD = t, d
+		}
+	}
+	if best == nil {
+		return selPoint{}, false
+	}
+	return selPoint{best, best.textOffsetAt(x, y)}, true
+}
+
+// countClick 记录一次左键按下并返回连击次数（1..3，同一位置 400ms 内算连击）。
+func (g *game) countClick(x, y float32) int {
+	now := clock()
+	if absf(x-g.lastClickX) < 4 && absf(y-g.lastClickY) < 4 && now.Sub(g.lastClickAt) < 400*time.Millisecond {
+		g.clickCount++
+	} else {
+		g.clickCount = 1
+	}
+	if g.clickCount > 3 {
+		g.clickCount = 1
+	}
+	g.lastClickAt, g.lastClickX, g.lastClickY = now, x, y
+	return g.clickCount
+}
+
+// textSelPress 在左键按下时调用：落在 Selectable 区域内则开始（或按连击扩展）选区，否则清除。
+func (g *game) textSelPress(n *renderNode, x, y float32, clicks int) {
+	root := selectableRoot(n)
+	if root == nil || n.kind == rnInput {
+		g.setTextSel(nil)
+		return
+	}
+	p, ok := textPointAt(root, x, y)
+	if !ok {
+		g.setTextSel(nil)
+		return
+	}
+	s := &textSelection{root: root, anchor: p, focus: p}
+	switch clicks {
+	case 2: // 双击选词
+		lo, hi := wordAt(p.rn.selText(), p.off)
+		s.anchor, s.focus = selPoint{p.rn, lo}, selPoint{p.rn, hi}
+	case 3: // 三击全选
+		s.selectAll()
+	default:
+		s.dragging = true
+	}
+	g.setTextSel(s)
+}
+
+// updateTextSelection 按住左键拖动时移动选区的活动端，松开结束。
+func (g *game) updateTextSelection() {
+	s := g.textSel
+	if s == nil || !s.dragging {
+		return
+	}
+	if !input.mousePressed(btnLeft) {
+		s.dragging = false
+		return
+	}
+	x, y := input.cursor()
+	if p, ok := textPointAt(s.root, x, y); ok && p != s.focus {
+		s.focus = p
+		s.refresh()
+	}
+}
+
+// copyTextSelection 处理没有输入框聚焦时的 Ctrl+C / Ctrl+A。
+func (g *game) copyTextSelection() {
+	s := g.textSel
+	if s == nil || focusedInput(g) != nil {
+		return
+	}
+	if !input.keyPressed(keyCtrl) && !input.keyPressed(keyMeta) {
+		return
+	}
+	switch {
+	case input.keyJustPressed(keyC):
+		if t := s.text(); t != "" {
+			setClipboard(t)
+		}
+	case input.keyJustPressed(keyA):
+		s.selectAll()
+		s.refresh()
+	}
+}
+
+func (g *game) setTextSel(s *textSelection) {
+	if s != nil {
+		s.refresh()
+	}
+	g.textSel = s
+}
+
+// syncTextSel 在布局后校正选区：区域被卸载则清除，否则按新的树重算各节点范围。
+func (g *game) syncTextSel() {
+	s := g.textSel
+	if s == nil {
+		return
+	}
+	if s.root.owner != nil && s.root.owner.unmounted {
+		g.textSel = nil
+		return
+	}
+	s.refresh()
+}
+
+func (s *textSelection) selectAll() {
+	var texts []*renderNode
+	selTexts(s.root, &texts)
+	if len(texts) == 0 {
+		return
+	}
+	last := texts[len(texts)-1]
+	s.anchor, s.focus = selPoint{texts[0], 0}, selPoint{last, len(last.selText())}
+}
+
+// refresh 按文档顺序重算每个文本节点上被选中的字节范围。
+func (s *textSelection) refresh() {
+	s.ranges = map[*renderNode][2]int{}
+	var texts []*renderNode
+	selTexts(s.root, &texts)
+	ai, fi := -1, -1
+	for i, t := range texts {
+		if t == s.anchor.rn {
+			ai = i
+		}
+		if t == s.focus.rn {
+			fi = i
+		}
+	}
+	if ai < 0 || fi < 0 {
+		return // 端点所在的节点已不在区域里
+	}
+	lo, hi := s.anchor, s.focus
+	if fi < ai || (fi == ai && hi.off < lo.off) {
+		lo, hi = hi, lo
+		ai, fi = fi, ai
+	}
+	for i := ai; i <= fi; i++ {
+		t := texts[i]
+		a, b := 0, len(t.selText())
+		if i == ai {
+			a = clampi(lo.off, 0, b)
+		}
+		if i == fi {
+			b = clampi(hi.off, 0, b)
+		}
+		if a < b {
+			s.ranges[t] = [2]int{a, b}
+		}
+	}
+}
+
+// text 返回选中的文字；跨节点时每个节点一行。
+func (s *textSelection) text() string {
+	var texts []*renderNode
+	selTexts(s.root, &texts)
+	var parts []string
+	for _, t := range texts {
+		if r, ok := s.ranges[t]; ok {
+			parts = append(parts, t.selText()[r[0]:r[1]])
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// paintTextSelection 在文本节点下面画出其被选中部分的高亮。
+func paintTextSelection(p painter, rn *renderNode) {
+	g := gameOf(rn.owner)
+	if g == nil || g.textSel == nil {
+		return
+	}
+	r, ok := g.textSel.ranges[rn]
+	if !ok {
+		return
+	}
+	b := rn.bounds
+	if len(rn.runs) == 0 {
+		spans := wrapSpans(rn.text, rn.face, rn.lineH, b.W)
+		paintSpanRange(p, rn, spans, r[0], r[1], b.X, b.Y, b.W, false)
+		return
+	}
+	lines, _, _ := rn.richLayout(b.W)
+	y := b.Y
+	for _, ln := range lines {
+		x0 := richLineX(b, ln)
+		for _, sg := range ln.segs {
+			a, c := max(r[0], sg.off), min(r[1], sg.off+len(sg.text))
+			if a >= c {
+				continue
+			}
+			// 每段是方向一致的一块：当作只有一段的 bidiLine，与纯文本走同一个 rangeXs，
+			// RTL 段里的选区才画在镜像后的位置上。
+			run := &rn.runs[sg.run]
+			w := measureW(sg.text, run.face, run.lineH)
+			seg := bidiLine{text: sg.text, pieces: []bidiPiece{{0, len(sg.text), sg.rtl, 0, w}}}
+			for _, xs := range seg.rangeXs(a-sg.off, c-sg.off, run.face, run.lineH) {
+				p.FillRect(x0+sg.x+xs[0], y, xs[1]-xs[0], ln.height, 0, selectionColor)
+			}
+		}
+		y += ln.height
+	}
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"math"
+	"testing"
+)
+
+func selectableLog(_ struct{}) *Node {
+	return Div(Style(Column, Padding(10)), Selectable(),
+		Text("alpha beta"),
+		Text("gamma delta"),
+	)
+}
+
+// 拖动可跨越多个文本节点选择；Ctrl+C 把选中文字（节点间换行）写进剪贴板，并画出高亮。
+func TestSelectableDragAcrossNodesAndCopy(t *testing.T) {
+	h := Mount(Use(selectableLog, struct{}{}), 300, 200)
+	a := h.Root().ByText("alpha beta").Bounds()
+	g := h.Root().ByText("gamma delta").Bounds()
+	h.PointerDown(a.X, a.Y+a.H/2)
+	h.PointerMove(g.X+g.W+20, g.Y+g.H/2) // 拖过第二行末尾也只选到行尾
+	h.PointerUp(g.X+g.W+20, g.Y+g.H/2)
+	if got := h.SelectedText(); got != "alpha beta\ngamma delta" {
+		t.Fatalf("SelectedText = %q", got)
+	}
+	hl := 0
+	for _, op := range h.Paint() {
+		if op.Kind == "rect" && op.Color == selectionColor {
+			hl++
+		}
+	}
+	if hl < 2 {
+		t.Fatalf("两个文本节点都应画出选区高亮，实际 %d 块", hl)
+	}
+	SetClipboardText("")
+	h.Key("Ctrl+C")
+	if Clipboard() != "alpha beta\ngamma delta" {
+		t.Fatalf("Ctrl+C 复制 = %q", Clipboard())
+	}
+}
+
+// 双击选词，三击选中整个区域；点到区域外清除选区。
+func TestSelectableWordAndAll(t *testing.T) {
+	h := Mount(Div(Style(Column), Use(selectableLog, struct{}{}), Div(Style(Height(40)), Text("outside"))), 300, 200)
+	g := h.Root().ByText("gamma delta").Bounds()
+	x, y := g.X+3, g.Y+g.H/2 // 落在 "gamma" 上
+	h.PointerDown(x, y)
+	h.PointerUp(x, y)
+	h.PointerDown(x, y)
+	h.PointerUp(x, y)
+	if got := h.SelectedText(); got != "gamma" {
+		t.Fatalf("双击应选中单词，实际 %q", got)
+	}
+	h.PointerDown(x, y)
+	h.PointerUp(x, y)
+	if got := h.SelectedText(); got != "alpha beta\ngamma delta" {
+		t.Fatalf("三击应全选，实际 %q", got)
+	}
+	o := h.Root().ByText("outside").Bounds()
+	h.PointerDown(o.X+1, o.Y+1)
+	h.PointerUp(o.X+1, o.Y+1)
+	if got := h.SelectedText(); got != "" {
+		t.Fatalf("点到区域外应清除选区，实际 %q", got)
+	}
+}
+
+// 富文本按各段首尾相接的文字选择。
+func TestSelectableRichText(t *testing.T) {
+	h := Mount(Div(Selectable(), RichText(Text("bold ", Bold), Text("tail"))), 300, 100)
+	q := h.Root().Find(func(q *Query) bool { return q.rn.kind == rnText && len(q.rn.runs) > 0 })
+	b := q.Bounds()
+	h.PointerDown(b.X, b.Y+b.H/2)
+	h.PointerMove(b.X+b.W, b.Y+b.H/2)
+	h.PointerUp(b.X+b.W, b.Y+b.H/2)
+	if got := h.SelectedText(); got != "bold tail" {
+		t.Fatalf("SelectedText = %q", got)
+	}
+}
+
+// 富文本里 RTL 段的选区画在镜像后的位置上，与同样内容的纯文本一致：
+// 选中第一个希伯来字母，高亮应在该段的最右端。
+func TestSelectableRichTextRTLHighlight(t *testing.T) {
+	highlight := func(n *Node) Rect {
+		h := Mount(Div(Selectable(), n), 300, 100)
+		q := h.Root().Find(func(q *Query) bool { return q.rn.kind == rnText })
+		h.g.textSel = &textSelection{root: q.rn.parent, ranges: map[*renderNode][2]int{q.rn: {3, 5}}}
+		for _, op := range h.Paint() {
+			if op.Kind == "rect" && op.Color == selectionColor {
+				return op.Rect
+			}
+		}
+		t.Fatal("没有画出选区")
+		return Rect{}
+	}
+	plain := highlight(Text("ab " + heb))
+	rich := highlight(RichText(Text("ab "), Text(heb)))
+	if math.Abs(float64(plain.X-rich.X)) > 0.5 || math.Abs(float64(plain.W-rich.W)) > 0.5 {
+		t.Fatalf("富文本选区 %+v 应与纯文本 %+v 一致", rich, plain)
+	}
+}
@@ -32,23 +32,34 @@ func measureW(s string, face fontFace, lineH float64) float32 {
 // wrapForWidth 按可用宽度对文本贪心折行；未约束或本就放得下时返回单行。
 // 返回折行结果与实际最大行宽。
 func wrapForWidth(s string, face fontFace, lineH float64, width float32) ([]string, float32) {
+	lines, _, maxW := wrapLines(s, face, lineH, width)
+	return lines, maxW
+}
+
+// wrapLines 同 wrapForWidth，另返回每行所在段落在 s 中的起始偏移（供按段落解析 bidi 方向）。
+func wrapLines(s string, face fontFace, lineH float64, width float32) ([]string, []int, float32) {
 	if face == nil || s == "" {
-		return []string{s}, 0
+		return []string{s}, []int{0}, 0
 	}
 	nat := measureW(s, face, lineH)
 	if (width <= 0 || nat <= width+0.5) && !strings.Contains(s, "\n") {
-		return []string{s}, nat
+		return []string{s}, []int{0}, nat
 	}
 
 	var lines []string
+	var paras []int
 	maxW := float32(0)
-	for _, para := range strings.Split(s, "\n") {
+	base := 0
+	for pi, para := range strings.Split(s, "\n") {
+		if pi > 0 {
+			base++ // 跳过 '\n'
+		}
 		cur := ""
 		for _, tk := range tokenize(para) {
 			trial := cur + tk
 			if cur != "" && measureW(strings.TrimRight(trial, " "), face, lineH) > width {
 				line := strings.TrimRight(cur, " ")
-				lines = append(lines, line)
+				lines, paras = append(lines, line), append(paras, base)
 				if lw := measureW(line, face, lineH); lw > maxW {
 					maxW = lw
 				}
@@ -58,12 +69,13 @@ func wrapForWidth(s string, face fontFace, lineH float64, width float32) ([]stri
 			}
 		}
 		line := strings.TrimRight(cur, " ")
-		lines = append(lines, line)
+		lines, paras = append(lines, line), append(paras, base)
 		if lw := measureW(line, face, lineH); lw > maxW {
 			maxW = lw
 		}
+		base += len(para)
 	}
-	return lines, maxW
+	return lines, paras, maxW
 }
 
 // wrapCache 缓存某文本节点上一次的折行结果，命中条件是文本/字体/行高/宽度均未变。
@@ -74,8 +86,12 @@ type wrapCache struct {
 	lineH float64
 	width float32
 	lines []string
+	paras []int // 每行所在段落的起始偏移
 	maxW  float32
 	valid bool
+
+	dir  TextDirection // bidi 的方向键；lines 变化时一并失效
+	bidi []bidiLine
 }
 
 // wrapped 返回按 width 折行的结果，优先命中节点级缓存。
@@ -84,15 +100,30 @@ func (rn *renderNode) wrapped(width float32) ([]string, float32) {
 	if c.valid && c.text == rn.text && c.face == rn.face && c.lineH == rn.lineH && c.width == width {
 		return c.lines, c.maxW
 	}
-	lines, mw := wrapForWidth(rn.text, rn.face, rn.lineH, width)
-	*c = wrapCache{rn.text, rn.face, rn.lineH, width, lines, mw, true}
+	lines, paras, mw := wrapLines(rn.text, rn.face, rn.lineH, width)
+	*c = wrapCache{text: rn.text, face: rn.face, lineH: rn.lineH, width: width, lines: lines, paras: paras, maxW: mw, valid: true}
 	return lines, mw
 }
 
+// bidiLines 返回 wrapped 各行的视觉排布，随折行缓存一起缓存。各行用所在段落的方向。
+func (rn *renderNode) bidiLines(width float32) []bidiLine {
+	lines, _ := rn.wrapped(width)
+	c := &rn.wc
+	if c.bidi == nil || c.dir != rn.textDir {
+		c.bidi = make([]bidiLine, len(lines))
+		for i, ln := range lines {
+			c.bidi[i] = newBidiLine(ln, paragraphDirAt(rn.text, c.paras[i], rn.textDir), rn.face, rn.lineH)
+		}
+		c.dir = rn.textDir
+	}
+	return c.bidi
+}
+
 // richCache 缓存富文本节点上一次的排版结果，按解析版本 runsRev + 宽度命中。
 type richCache struct {
 	rev          int
 	width        float32
+	dir          TextDirection
 	lines        []richLine
 	maxW, totalH float32
 	valid        bool
@@ -101,11 +132,11 @@ type richCache struct {
 // richLayout 返回富文本按 width 的排版结果，优先命中节点级缓存。
 func (rn *renderNode) richLayout(width float32) ([]richLine, float32, float32) {
 	c := &rn.rc
-	if c.valid && c.rev == rn.runsRev && c.width == width {
+	if c.valid && c.rev == rn.runsRev && c.width == width && c.dir == rn.textDir {
 		return c.lines, c.maxW, c.totalH
 	}
-	lines, mw, h := layoutRuns(rn.runs, width)
-	*c = richCache{rn.runsRev, width, lines, mw, h, true}
+	lines, mw, h := layoutRuns(rn.runs, width, rn.textDir)
+	*c = richCache{rn.runsRev, width, rn.textDir, lines, mw, h, true}
 	return lines, mw, h
 }
 
@@ -113,16 +144,17 @@ func (rn *renderNode) richLayout(width float32) ([]richLine, float32, float32) {
 type wrapSpan struct {
 	text       string
 	start, end int
+	paraRTL    bool // 所在段落按首个强字符解析为 RTL（DirAuto 时各行统一用它）
 }
 
 // wrapSpans 与 wrapForWidth 折行规则一致，但额外记录每行的字节偏移，用于多行输入的光标/选区映射。
 func wrapSpans(s string, face fontFace, lineH float64, width float32) []wrapSpan {
 	if face == nil || s == "" {
-		return []wrapSpan{{s, 0, len(s)}}
+		return []wrapSpan{{s, 0, len(s), paragraphRTL(s, DirAuto)}}
 	}
 	nat := measureW(s, face, lineH)
 	if (width <= 0 || nat <= width+0.5) && !strings.Contains(s, "\n") {
-		return []wrapSpan{{s, 0, len(s)}}
+		return []wrapSpan{{s, 0, len(s), paragraphRTL(s, DirAuto)}}
 	}
 	var spans []wrapSpan
 	base := 0
@@ -138,13 +170,14 @@ func wrapSpans(s string, face fontFace, lineH float64, width float32) []wrapSpan
 
 func wrapParaSpans(para string, base int, face fontFace, lineH float64, width float32) []wrapSpan {
 	var spans []wrapSpan
+	rtl := paragraphRTL(para, DirAuto)
 	lineStart := base
 	cur := ""
 	pos := base
 	for _, tk := range tokenize(para) {
 		trial := cur + tk
 		if cur != "" && measureW(strings.TrimRight(trial, " "), face, lineH) > width {
-			spans = append(spans, wrapSpan{strings.TrimRight(cur, " "), lineStart, pos})
+			spans = append(spans, wrapSpan{strings.TrimRight(cur, " "), lineStart, pos, rtl})
 			cur = tk
 			lineStart = pos
 		} else {
@@ -152,42 +185,33 @@ func wrapParaSpans(para string, base int, face fontFace, lineH float64, width fl
 		}
 		pos += len(tk)
 	}
-	spans = append(spans, wrapSpan{strings.TrimRight(cur, " "), lineStart, pos})
+	spans = append(spans, wrapSpan{strings.TrimRight(cur, " "), lineStart, pos, rtl})
 	return spans
 }
 
-// xInSpan 返回原始字节偏移 off 在该行内相对行首 tx 的 x 坐标。
-func (sp wrapSpan) xInSpan(off int, face fontFace, lineH float64, tx float32) float32 {
-	rel := off - sp.start
-	if rel < 0 {
-		rel = 0
-	}
-	if rel > len(sp.text) {
-		rel = len(sp.text)
+// bidi 返回该行的视觉排布；DirAuto 时取所在段落的方向，而不是按本行重判。
+func (sp wrapSpan) bidi(dir TextDirection, face fontFace, lineH float64) bidiLine {
+	if dir == DirAuto {
+		dir = LTR
+		if sp.paraRTL {
+			dir = RTL
+		}
 	}
-	return tx + measureW(sp.text[:rel], face, lineH)
+	return newBidiLine(sp.text, dir, face, lineH)
+}
+
+// xInSpan 返回原始字节偏移 off 在该行内的 x 坐标；tx 为行框左端，avail 为行框宽（RTL 行贴右）。
+func (sp wrapSpan) xInSpan(off int, dir TextDirection, face fontFace, lineH float64, tx, avail float32) float32 {
+	rel := clampi(off-sp.start, 0, len(sp.text))
+	bl := sp.bidi(dir, face, lineH)
+	return tx + bl.startX(avail) + bl.caretX(rel, face, lineH)
 }
 
-// offsetInSpan 返回该行内相对行首 rel-x 处最近的原始字节偏移。
-func (sp wrapSpan) offsetInSpan(relX float32, face fontFace, lineH float64) int {
-	if relX <= 0 || sp.text == "" {
+// offsetInSpan 返回该行内相对行框左端 relX 处最近的原始字节偏移。
+func (sp wrapSpan) offsetInSpan(relX float32, dir TextDirection, face fontFace, lineH float64, avail float32) int {
+	if sp.text == "" {
 		return sp.start
 	}
-	prevIdx, prevW := 0, float32(0)
-	for i := 1; i <= len(sp.text); i++ {
-		if i < len(sp.text) && !isRuneStart(sp.text[i]) {
-			continue
-		}
-		w := measureW(sp.text[:i], face, lineH)
-		if w >= relX {
-			if w-relX < relX-prevW {
-				return sp.start + i
-			}
-			return sp.start + prevIdx
-		}
-		prevIdx, prevW = i, w
-	}
-	return sp.start + len(sp.text)
+	bl := sp.bidi(dir, face, lineH)
+	return sp.start + bl.offsetAt(relX-bl.startX(avail), face, lineH)
 }
-
-func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
@@ -243,7 +243,7 @@ func measureNodeWithMeasureFunc(
 		panic("Expected node to have custom measure function")
 	}
 
-	if widthMeasureMode == MeasureModeUndefined {
+	if widthMeasureMode == MeasureModeUndefined || widthMeasureMode == MeasureModeMinContent {
 		availableWidth = Undefined
 	}
 	if heightMeasureMode == MeasureModeUndefined {
@@ -294,7 +294,7 @@ func measureNodeWithMeasureFunc(
 			boundAxis(
 				node,
 				FlexDirectionRow,
-				If(widthMeasureMode == MeasureModeUndefined || widthMeasureMode == MeasureModeAtMost, measuredSize.Width+paddingAndBorderAxisRow, availableWidth),
+				If(widthMeasureMode != MeasureModeExactly, measuredSize.Width+paddingAndBorderAxisRow, availableWidth),
 				ownerWidth,
 				ownerWidth,
 			),
@@ -1374,6 +1374,24 @@ func calculateLayoutImpl(
 	node.cloneChildrenIfNeeded()
 	node.setLayoutHadOverflow(false)
 
+	if node.getStyle().display() == DisplayGrid {
+		calculateGridLayoutImpl(
+			node,
+			availableWidth-marginAxisRow,
+			availableHeight-marginAxisColumn,
+			direction,
+			widthMeasureMode,
+			heightMeasureMode,
+			ownerWidth,
+			ownerHeight,
+			performLayout,
+			layoutMarkerData,
+			depth,
+			generationCount,
+		)
+		return
+	}
+
 	// STEP 1: CALCULATE VALUES FOR REMAINDER OF ALGORITHM
 	mainAxis := resolveDirection(node.getStyle().flexDirection(), direction)
 	crossAxis := resolveCrossDirection(mainAxis, direction)
@@ -2052,6 +2070,8 @@ func measureModeName(mode MeasureMode, performLayout bool) string {
 		} else {
 			return "AT_MOST"
 		}
+	case MeasureModeMinContent:
+		return "MIN_CONTENT"
 	}
 	return ""
 }
@@ -4,13 +4,13 @@ const (
 	AlignCount               = 9
 	DimensionCount           = 2
 	DirectionCount           = 3
-	DisplayCount             = 2
+	DisplayCount             = 3
 	EdgeCount                = 9
 	ExperimentalFeatureCount = 3
 	FlexDirectionCount       = 4
 	JustifyCount             = 6
 	LogLevelCount            = 6
-	MeasureModeCount         = 3
+	MeasureModeCount         = 4
 	NodeTypeCount            = 2
 	OverflowCount            = 3
 	PositionTypeCount        = 3
@@ -18,6 +18,7 @@ const (
 	UnitCount                = 4
 	WrapCount                = 3
 	GutterCount              = 3
+	GridAutoFlowCount        = 4
 )
 
 type Align uint8
@@ -100,6 +101,7 @@ type Display uint8
 const (
 	DisplayFlex Display = iota
 	DisplayNone
+	DisplayGrid
 )
 
 func (d Display) String() string {
@@ -108,6 +110,8 @@ func (d Display) String() string {
 		return "flex"
 	case DisplayNone:
 		return "none"
+	case DisplayGrid:
+		return "grid"
 	}
 	return "unknown"
 }
@@ -306,6 +310,10 @@ const (
 	MeasureModeUndefined MeasureMode = iota
 	MeasureModeExactly
 	MeasureModeAtMost
+	// MeasureModeMinContent asks a measure function for its min-content width (the
+	// narrowest it can get without overflowing, e.g. the longest unbreakable word).
+	// Only grid track sizing passes it, only for the width, and only to measure funcs.
+	MeasureModeMinContent
 )
 
 func (m MeasureMode) String() string {
@@ -316,6 +324,8 @@ func (m MeasureMode) String() string {
 		return "exactly"
 	case MeasureModeAtMost:
 		return "at-most"
+	case MeasureModeMinContent:
+		return "min-content"
 	}
 	return "unknown"
 }
@@ -439,3 +449,26 @@ func (w Wrap) String() string {
 	}
 	return "unknown"
 }
+
+type GridAutoFlow uint8
+
+const (
+	GridAutoFlowRow GridAutoFlow = iota
+	GridAutoFlowColumn
+	GridAutoFlowRowDense
+	GridAutoFlowColumnDense
+)
+
+func (f GridAutoFlow) String() string {
+	switch f {
+	case GridAutoFlowRow:
+		return "row"
+	case GridAutoFlowColumn:
+		return "column"
+	case GridAutoFlowRowDense:
+		return "row dense"
+	case GridAutoFlowColumnDense:
+		return "column dense"
+	}
+	return "unknown"
+}
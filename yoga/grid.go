@@ -0,0 +1,800 @@
+package yoga
+
+import "math"
+
+// CSS Grid layout (display: grid).
+//
+// This is a pragmatic subset of https://www.w3.org/TR/css-grid-1/ that sits next to
+// the flexbox algorithm in calculate_layout.go:
+//
+//   - explicit tracks (grid-template-columns/rows) with point, percent, fr, auto and
+//     minmax() sizing; implicit tracks use grid-auto-columns/rows;
+//   - line-based placement with negative lines and spans (grid-column / grid-row);
+//   - auto-placement in row or column flow, sparse or dense;
+//   - column/row gaps (the existing Gutter gaps);
+//   - justify-content / align-content distribute leftover space between tracks. The
+//     flex-start default behaves like CSS "normal": auto tracks stretch to fill;
+//   - align-items / align-self align items inside their area on the block axis. On the
+//     inline axis items stretch unless they have a definite width (start-aligned).
+//
+// Not supported: named lines and areas, subgrid, baseline alignment, the order property.
+
+// GridUnit is the unit of one side (min or max) of a track sizing function.
+type GridUnit uint8
+
+const (
+	GridUnitAuto GridUnit = iota // sized to content
+	GridUnitPoint
+	GridUnitPercent
+	GridUnitFr // flexible; only meaningful as a maximum
+)
+
+// GridTrackSize is one side of a track sizing function.
+type GridTrackSize struct {
+	Unit  GridUnit
+	Value float32
+}
+
+// GridTrack is a track sizing function, minmax(Min, Max). The zero value is auto.
+type GridTrack struct {
+	Min, Max GridTrackSize
+}
+
+// GridSizeAuto
+func GridSizeAuto() GridTrackSize { return GridTrackSize{} }
+
+// GridSizePoints
+func GridSizePoints(v float32) GridTrackSize { return GridTrackSize{GridUnitPoint, v} }
+
+// GridSizePercent
+func GridSizePercent(v float32) GridTrackSize { return GridTrackSize{GridUnitPercent, v} }
+
+// GridSizeFr
+func GridSizeFr(v float32) GridTrackSize { return GridTrackSize{GridUnitFr, v} }
+
+// GridTrackAuto is an auto track.
+func GridTrackAuto() GridTrack { return GridTrack{} }
+
+// GridTrackPoints is a fixed track.
+func GridTrackPoints(v float32) GridTrack {
+	return GridTrack{GridSizePoints(v), GridSizePoints(v)}
+}
+
+// GridTrackPercent is a track sized as a percentage of the grid's content box.
+func GridTrackPercent(v float32) GridTrack {
+	return GridTrack{GridSizePercent(v), GridSizePercent(v)}
+}
+
+// GridTrackFr is a flexible track; like CSS, Nfr means minmax(auto, Nfr).
+func GridTrackFr(v float32) GridTrack {
+	return GridTrack{GridSizeAuto(), GridSizeFr(v)}
+}
+
+// GridTrackMinMax is minmax(min, max).
+func GridTrackMinMax(min, max GridTrackSize) GridTrack {
+	if min.Unit == GridUnitFr { // fr is not a valid minimum
+		min = GridSizeAuto()
+	}
+	return GridTrack{min, max}
+}
+
+// GridLine is one end of an item's placement: auto, a line number (1-based, negative
+// counts from the end of the explicit grid) or a span. The zero value is auto.
+type GridLine struct {
+	Span  bool
+	Value int16
+}
+
+// GridLineAuto
+func GridLineAuto() GridLine { return GridLine{} }
+
+// GridLineAt is line n (n != 0).
+func GridLineAt(n int) GridLine { return GridLine{Value: int16(n)} }
+
+// GridLineSpan is "span n".
+func GridLineSpan(n int) GridLine {
+	if n < 1 {
+		n = 1
+	}
+	return GridLine{Span: true, Value: int16(n)}
+}
+
+func (l GridLine) isLine() bool { return !l.Span && l.Value != 0 }
+
+func gridTracksEqual(a, b []GridTrack) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// gridRange is a resolved placement on one axis: tracks [lo, lo+span).
+type gridRange struct {
+	lo, span int
+	definite bool
+}
+
+func (r gridRange) hi() int { return r.lo + r.span }
+
+type gridItem struct {
+	node     *Node
+	col, row gridRange
+}
+
+// resolveGridLine converts a line number to a 0-based line index.
+func resolveGridLine(l GridLine, explicit int) int {
+	n := int(l.Value)
+	if n > 0 {
+		return n - 1
+	}
+	return max(0, explicit+1+n)
+}
+
+func resolveGridRange(start, end GridLine, explicit int) gridRange {
+	switch {
+	case start.isLine() && end.isLine():
+		a, b := resolveGridLine(start, explicit), resolveGridLine(end, explicit)
+		if b < a {
+			a, b = b, a
+		}
+		if a == b {
+			b = a + 1
+		}
+		return gridRange{a, b - a, true}
+	case start.isLine():
+		span := 1
+		if end.Span {
+			span = int(end.Value)
+		}
+		return gridRange{resolveGridLine(start, explicit), span, true}
+	case end.isLine():
+		span := 1
+		if start.Span {
+			span = int(start.Value)
+		}
+		b := resolveGridLine(end, explicit)
+		if b < 1 {
+			b = 1
+		}
+		return gridRange{max(0, b-span), min(span, b), true}
+	}
+	span := 1
+	if start.Span {
+		span = int(start.Value)
+	} else if end.Span {
+		span = int(end.Value)
+	}
+	return gridRange{0, max(span, 1), false}
+}
+
+// placeGridItems runs the auto-placement algorithm and returns the column and row
+// counts of the resulting (explicit + implicit) grid.
+func placeGridItems(items []*gridItem, explicitCols, explicitRows int, flow GridAutoFlow) (int, int) {
+	colFlow := flow == GridAutoFlowColumn || flow == GridAutoFlowColumnDense
+	dense := flow == GridAutoFlowRowDense || flow == GridAutoFlowColumnDense
+
+	// major is the axis that grows while placing (rows in row flow), minor is fixed.
+	major := func(it *gridItem) *gridRange { return If(colFlow, &it.col, &it.row) }
+	minor := func(it *gridItem) *gridRange { return If(colFlow, &it.row, &it.col) }
+	minorCount := If(colFlow, explicitRows, explicitCols)
+	for _, it := range items {
+		if m := minor(it); m.definite {
+			minorCount = max(minorCount, m.hi())
+		} else {
+			minorCount = max(minorCount, m.span)
+		}
+	}
+	minorCount = max(minorCount, 1)
+
+	occupied := map[[2]int]bool{}
+	fits := func(ma, mi int, it *gridItem) bool {
+		for a := ma; a < ma+major(it).span; a++ {
+			for b := mi; b < mi+minor(it).span; b++ {
+				if occupied[[2]int{a, b}] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	place := func(ma, mi int, it *gridItem) {
+		major(it).lo, minor(it).lo = ma, mi
+		for a := ma; a < ma+major(it).span; a++ {
+			for b := mi; b < mi+minor(it).span; b++ {
+				occupied[[2]int{a, b}] = true
+			}
+		}
+	}
+
+	// 1. Items with a definite position on both axes.
+	for _, it := range items {
+		if major(it).definite && minor(it).definite {
+			place(major(it).lo, minor(it).lo, it)
+		}
+	}
+	// 2. Items locked to a major track.
+	lockCursor := map[int]int{}
+	for _, it := range items {
+		if !major(it).definite || minor(it).definite {
+			continue
+		}
+		ma := major(it).lo
+		mi := 0
+		if !dense {
+			mi = lockCursor[ma]
+		}
+		for !fits(ma, mi, it) {
+			mi++
+		}
+		place(ma, mi, it)
+		lockCursor[ma] = mi + minor(it).span
+		minorCount = max(minorCount, mi+minor(it).span)
+	}
+	// 3. Everything else, following the auto-placement cursor.
+	curMa, curMi := 0, 0
+	for _, it := range items {
+		if major(it).definite {
+			continue
+		}
+		if dense {
+			curMa, curMi = 0, 0
+		}
+		if m := minor(it); m.definite {
+			if m.lo < curMi {
+				curMa++
+			}
+			curMi = m.lo
+			for !fits(curMa, curMi, it) {
+				curMa++
+			}
+			place(curMa, curMi, it)
+			continue
+		}
+		for {
+			if curMi+minor(it).span > minorCount {
+				curMa, curMi = curMa+1, 0
+				continue
+			}
+			if fits(curMa, curMi, it) {
+				place(curMa, curMi, it)
+				break
+			}
+			curMi++
+		}
+	}
+
+	cols, rows := explicitCols, explicitRows
+	for _, it := range items {
+		cols = max(cols, it.col.hi())
+		rows = max(rows, it.row.hi())
+	}
+	return cols, rows
+}
+
+// gridTrackState is one track during sizing.
+type gridTrackState struct {
+	def        GridTrack
+	base       float32
+	limit      float32 // growth limit; +Inf while unbounded
+	contentMax float32 // largest max-content contribution among items spanning only this track
+	offset     float32 // start position inside the content box
+}
+
+func (t *gridTrackState) minSize(avail float32) (float32, bool) {
+	switch t.def.Min.Unit {
+	case GridUnitPoint:
+		return t.def.Min.Value, true
+	case GridUnitPercent:
+		if IsDefined(avail) {
+			return t.def.Min.Value * avail / 100, true
+		}
+	}
+	return 0, false
+}
+
+func (t *gridTrackState) maxSize(avail float32) (float32, bool) {
+	switch t.def.Max.Unit {
+	case GridUnitPoint:
+		return t.def.Max.Value, true
+	case GridUnitPercent:
+		if IsDefined(avail) {
+			return t.def.Max.Value * avail / 100, true
+		}
+	}
+	return 0, false
+}
+
+func (t *gridTrackState) isFlex() bool { return t.def.Max.Unit == GridUnitFr }
+
+func (t *gridTrackState) flexFactor() float32 { return max(t.def.Max.Value, 0) }
+
+func newGridTracks(explicit []GridTrack, auto GridTrack, count int) []gridTrackState {
+	tracks := make([]gridTrackState, count)
+	for i := range tracks {
+		tracks[i].def = auto
+		if i < len(explicit) {
+			tracks[i].def = explicit[i]
+		}
+	}
+	return tracks
+}
+
+// sizeGridTracks runs a simplified version of the CSS track sizing algorithm on one
+// axis. avail is the definite content-box size or Undefined (max-content sizing).
+// contrib returns an item's min-content (min=true) or max-content contribution
+// including margins. stretch lets auto tracks absorb leftover space.
+func sizeGridTracks(tracks []gridTrackState, items []*gridItem, rangeOf func(*gridItem) gridRange, avail, gap float32, stretch bool, contrib func(it *gridItem, min bool) float32) {
+	inf := float32(math.Inf(1))
+	for i := range tracks {
+		t := &tracks[i]
+		t.base, _ = t.minSize(avail)
+		t.limit = inf
+		if v, ok := t.maxSize(avail); ok {
+			t.limit = max(v, t.base)
+		}
+		t.contentMax = 0
+	}
+	intrinsicMin := func(t *gridTrackState) bool { _, ok := t.minSize(avail); return !ok }
+	intrinsicMax := func(t *gridTrackState) bool { _, ok := t.maxSize(avail); return !ok }
+
+	// Items spanning a single track.
+	for _, it := range items {
+		r := rangeOf(it)
+		if r.span != 1 {
+			continue
+		}
+		t := &tracks[r.lo]
+		if intrinsicMin(t) {
+			t.base = max(t.base, contrib(it, true))
+		}
+		if intrinsicMax(t) {
+			t.contentMax = max(t.contentMax, contrib(it, false))
+		}
+	}
+	// Spanning items: grow the intrinsic tracks they cross by the missing amount.
+	for _, it := range items {
+		r := rangeOf(it)
+		if r.span < 2 {
+			continue
+		}
+		spanned := tracks[r.lo:r.hi()]
+		gaps := gap * float32(r.span-1)
+		var minTargets, maxTargets []*gridTrackState
+		haveMin, haveMax := gaps, gaps
+		for i := range spanned {
+			t := &spanned[i]
+			haveMin += t.base
+			if intrinsicMin(t) {
+				minTargets = append(minTargets, t)
+			}
+			if intrinsicMax(t) {
+				haveMax += max(t.contentMax, t.base)
+				maxTargets = append(maxTargets, t)
+			} else {
+				haveMax += t.limit
+			}
+		}
+		if need := contrib(it, true) - haveMin; need > 0 && len(minTargets) > 0 {
+			for _, t := range minTargets {
+				t.base += need / float32(len(minTargets))
+			}
+		}
+		if need := contrib(it, false) - haveMax; need > 0 && len(maxTargets) > 0 {
+			for _, t := range maxTargets {
+				t.contentMax = max(t.contentMax, t.base) + need/float32(len(maxTargets))
+			}
+		}
+	}
+	for i := range tracks {
+		t := &tracks[i]
+		if intrinsicMax(t) && !t.isFlex() {
+			t.limit = max(t.contentMax, t.base)
+		}
+	}
+
+	gaps := gap * float32(max(len(tracks)-1, 0))
+	used := func() float32 {
+		s := gaps
+		for i := range tracks {
+			s += tracks[i].base
+		}
+		return s
+	}
+
+	// Maximize: grow non-flexible tracks toward their growth limits.
+	if IsUndefined(avail) {
+		for i := range tracks {
+			if t := &tracks[i]; !t.isFlex() && t.limit != inf {
+				t.base = t.limit
+			}
+		}
+	} else {
+		for free := avail - used(); free > 0.01; free = avail - used() {
+			var growable []*gridTrackState
+			for i := range tracks {
+				if t := &tracks[i]; !t.isFlex() && t.limit > t.base {
+					growable = append(growable, t)
+				}
+			}
+			if len(growable) == 0 {
+				break
+			}
+			share := free / float32(len(growable))
+			for _, t := range growable {
+				t.base = min(t.limit, t.base+share)
+			}
+		}
+	}
+
+	// Flexible tracks.
+	var flex []*gridTrackState
+	for i := range tracks {
+		if tracks[i].isFlex() {
+			flex = append(flex, &tracks[i])
+		}
+	}
+	if len(flex) > 0 {
+		var frSize float32
+		if IsUndefined(avail) {
+			for _, t := range flex {
+				if f := t.flexFactor(); f > 0 {
+					frSize = max(frSize, max(t.base, t.contentMax)/max(f, 1))
+				}
+			}
+		} else {
+			inflexible := map[*gridTrackState]bool{}
+			for {
+				leftover := avail - gaps
+				sum := float32(0)
+				for i := range tracks {
+					t := &tracks[i]
+					if t.isFlex() && !inflexible[t] {
+						sum += t.flexFactor()
+					} else {
+						leftover -= t.base
+					}
+				}
+				frSize = max(leftover, 0) / max(sum, 1)
+				changed := false
+				for _, t := range flex {
+					if !inflexible[t] && t.base > t.flexFactor()*frSize {
+						inflexible[t] = true
+						changed = true
+					}
+				}
+				if !changed {
+					break
+				}
+			}
+		}
+		for _, t := range flex {
+			t.base = max(t.base, t.flexFactor()*frSize)
+		}
+	} else if stretch && IsDefined(avail) {
+		// Stretch auto tracks over whatever is left.
+		var autos []*gridTrackState
+		for i := range tracks {
+			if t := &tracks[i]; t.def.Max.Unit == GridUnitAuto {
+				autos = append(autos, t)
+			}
+		}
+		if free := avail - used(); free > 0 && len(autos) > 0 {
+			for _, t := range autos {
+				t.base += free / float32(len(autos))
+			}
+		}
+	}
+}
+
+// gridContentDistribution is justify-content / align-content mapped onto tracks.
+type gridContentDistribution uint8
+
+const (
+	gridDistributeStart gridContentDistribution = iota
+	gridDistributeCenter
+	gridDistributeEnd
+	gridDistributeSpaceBetween
+	gridDistributeSpaceAround
+	gridDistributeSpaceEvenly
+)
+
+func gridDistributionFromJustify(j Justify) gridContentDistribution {
+	switch j {
+	case JustifyCenter:
+		return gridDistributeCenter
+	case JustifyFlexEnd:
+		return gridDistributeEnd
+	case JustifySpaceBetween:
+		return gridDistributeSpaceBetween
+	case JustifySpaceAround:
+		return gridDistributeSpaceAround
+	case JustifySpaceEvenly:
+		return gridDistributeSpaceEvenly
+	}
+	return gridDistributeStart
+}
+
+func gridDistributionFromAlign(a Align) gridContentDistribution {
+	switch a {
+	case AlignCenter:
+		return gridDistributeCenter
+	case AlignFlexEnd:
+		return gridDistributeEnd
+	case AlignSpaceBetween:
+		return gridDistributeSpaceBetween
+	case AlignSpaceAround:
+		return gridDistributeSpaceAround
+	case AlignSpaceEvenly:
+		return gridDistributeSpaceEvenly
+	}
+	return gridDistributeStart
+}
+
+// positionGridTracks sets each track's offset inside a content box of size inner.
+func positionGridTracks(tracks []gridTrackState, gap, inner float32, dist gridContentDistribution) {
+	n := len(tracks)
+	total := gap * float32(max(n-1, 0))
+	for i := range tracks {
+		total += tracks[i].base
+	}
+	lead, between := float32(0), gap
+	if free := inner - total; IsDefined(inner) && free > 0 && n > 0 {
+		switch dist {
+		case gridDistributeCenter:
+			lead = free / 2
+		case gridDistributeEnd:
+			lead = free
+		case gridDistributeSpaceBetween:
+			if n > 1 {
+				between += free / float32(n-1)
+			}
+		case gridDistributeSpaceAround:
+			lead = free / float32(n) / 2
+			between += free / float32(n)
+		case gridDistributeSpaceEvenly:
+			lead = free / float32(n+1)
+			between += lead
+		}
+	}
+	pos := lead
+	for i := range tracks {
+		tracks[i].offset = pos
+		pos += tracks[i].base + between
+	}
+}
+
+// gridArea returns the offset and size of tracks [r.lo, r.hi()).
+func gridArea(tracks []gridTrackState, r gridRange) (float32, float32) {
+	first, last := tracks[r.lo], tracks[r.hi()-1]
+	return first.offset, last.offset + last.base - first.offset
+}
+
+// gridTracksTotal is the size the tracks occupy including gaps.
+func gridTracksTotal(tracks []gridTrackState, gap float32) float32 {
+	total := gap * float32(max(len(tracks)-1, 0))
+	for i := range tracks {
+		total += tracks[i].base
+	}
+	return total
+}
+
+// gridChildDimension returns the child's definite style size on axis, if any.
+func gridChildDimension(child *Node, axis FlexDirection, ownerSize float32) (float32, bool) {
+	if !styleDefinesDimension(child, axis, ownerSize) {
+		return Undefined, false
+	}
+	v := resolveValue(child.getResolvedDimension(dimension(axis)), ownerSize)
+	return boundAxisWithinMinAndMax(child, axis, v, ownerSize).unwrap(), true
+}
+
+func calculateGridLayoutImpl(
+	node *Node,
+	availableWidth float32,
+	availableHeight float32,
+	direction Direction,
+	widthMeasureMode MeasureMode,
+	heightMeasureMode MeasureMode,
+	ownerWidth float32,
+	ownerHeight float32,
+	performLayout bool,
+	layoutMarkerData *LayoutData,
+	depth uint32,
+	generationCount uint32,
+) {
+	style := node.getStyle()
+	paddingAndBorderRow := paddingAndBorderForAxis(node, FlexDirectionRow, ownerWidth)
+	paddingAndBorderColumn := paddingAndBorderForAxis(node, FlexDirectionColumn, ownerWidth)
+	innerWidth := calculateAvailableInnerDimension(node, DimensionWidth, availableWidth, paddingAndBorderRow, ownerWidth)
+	innerHeight := calculateAvailableInnerDimension(node, DimensionHeight, availableHeight, paddingAndBorderColumn, ownerHeight)
+	columnGap := node.getGapForAxis(FlexDirectionRow)
+	rowGap := node.getGapForAxis(FlexDirectionColumn)
+
+	// Collect and place in-flow items.
+	var items []*gridItem
+	explicitCols, explicitRows := len(style.gridTemplateColumns()), len(style.gridTemplateRows())
+	for _, child := range node.GetChildren() {
+		child.resolveDimension()
+		if child.getStyle().display() == DisplayNone {
+			zeroOutLayoutRecursively(child)
+			child.SetHasNewLayout(true)
+			child.setDirty(false)
+			continue
+		}
+		if child.getStyle().positionType() == PositionTypeAbsolute {
+			continue
+		}
+		cs := child.getStyle()
+		items = append(items, &gridItem{
+			node: child,
+			col:  resolveGridRange(cs.gridColumnStart_, cs.gridColumnEnd_, explicitCols),
+			row:  resolveGridRange(cs.gridRowStart_, cs.gridRowEnd_, explicitRows),
+		})
+	}
+	colCount, rowCount := placeGridItems(items, explicitCols, explicitRows, style.gridAutoFlow())
+	cols := newGridTracks(style.gridTemplateColumns(), style.gridAutoColumns(), colCount)
+	rows := newGridTracks(style.gridTemplateRows(), style.gridAutoRows(), rowCount)
+
+	measure := func(child *Node, w, h float32, wm, hm MeasureMode) {
+		calculateLayoutInternal(child, w, h, direction, wm, hm, innerWidth, innerHeight,
+			false, LayoutPassReasonMeasureChild, layoutMarkerData, depth, generationCount)
+	}
+	// Definite style size on the other axis is passed down as an exact constraint.
+	heightConstraint := func(child *Node) (float32, MeasureMode) {
+		if h, ok := gridChildDimension(child, FlexDirectionColumn, innerHeight); ok {
+			return h, MeasureModeExactly
+		}
+		return Undefined, MeasureModeUndefined
+	}
+	columnContribution := func(it *gridItem, minContent bool) float32 {
+		child := it.node
+		margin := child.getMarginForAxis(FlexDirectionRow, innerWidth)
+		if w, ok := gridChildDimension(child, FlexDirectionRow, innerWidth); ok {
+			return w + margin
+		}
+		h, hm := heightConstraint(child)
+		switch {
+		case !minContent:
+			measure(child, Undefined, h, MeasureModeUndefined, hm)
+		case child.HasMeasureFunc():
+			measure(child, 0, h, MeasureModeMinContent, hm)
+		default:
+			measure(child, 0, h, MeasureModeAtMost, hm)
+		}
+		return child.getLayout().measuredDimension(DimensionWidth) + margin
+	}
+	itemWidth := func(it *gridItem) float32 {
+		child := it.node
+		if w, ok := gridChildDimension(child, FlexDirectionRow, innerWidth); ok {
+			return w
+		}
+		_, areaW := gridArea(cols, it.col)
+		return max(areaW-child.getMarginForAxis(FlexDirectionRow, innerWidth), 0)
+	}
+	rowContribution := func(it *gridItem, _ bool) float32 {
+		child := it.node
+		margin := child.getMarginForAxis(FlexDirectionColumn, innerWidth)
+		if h, ok := gridChildDimension(child, FlexDirectionColumn, innerHeight); ok {
+			return h + margin
+		}
+		measure(child, itemWidth(it), Undefined, MeasureModeExactly, MeasureModeUndefined)
+		return child.getLayout().measuredDimension(DimensionHeight) + margin
+	}
+	colRange := func(it *gridItem) gridRange { return it.col }
+	rowRange := func(it *gridItem) gridRange { return it.row }
+
+	// Size columns, then rows (row contributions depend on column widths).
+	sizeAxis := func(tracks []gridTrackState, rangeOf func(*gridItem) gridRange, inner float32, mode MeasureMode, gap float32, stretch bool, contrib func(*gridItem, bool) float32) {
+		switch mode {
+		case MeasureModeExactly:
+			sizeGridTracks(tracks, items, rangeOf, inner, gap, stretch, contrib)
+		case MeasureModeAtMost:
+			sizeGridTracks(tracks, items, rangeOf, Undefined, gap, stretch, contrib)
+			if gridTracksTotal(tracks, gap) > inner {
+				sizeGridTracks(tracks, items, rangeOf, inner, gap, stretch, contrib)
+			}
+		default:
+			sizeGridTracks(tracks, items, rangeOf, Undefined, gap, stretch, contrib)
+		}
+	}
+	sizeAxis(cols, colRange, innerWidth, widthMeasureMode, columnGap,
+		style.justifyContent() == JustifyFlexStart, columnContribution)
+	positionGridTracks(cols, columnGap, Undefined, gridDistributeStart) // item widths need offsets
+	sizeAxis(rows, rowRange, innerHeight, heightMeasureMode, rowGap,
+		style.alignContent() == AlignFlexStart || style.alignContent() == AlignStretch, rowContribution)
+
+	// Final container size.
+	if widthMeasureMode == MeasureModeExactly {
+		node.setLayoutMeasuredDimension(boundAxis(node, FlexDirectionRow, availableWidth, ownerWidth, ownerWidth), DimensionWidth)
+	} else {
+		node.setLayoutMeasuredDimension(boundAxis(node, FlexDirectionRow,
+			gridTracksTotal(cols, columnGap)+paddingAndBorderRow, ownerWidth, ownerWidth), DimensionWidth)
+	}
+	if heightMeasureMode == MeasureModeExactly {
+		node.setLayoutMeasuredDimension(boundAxis(node, FlexDirectionColumn, availableHeight, ownerHeight, ownerWidth), DimensionHeight)
+	} else {
+		node.setLayoutMeasuredDimension(boundAxis(node, FlexDirectionColumn,
+			gridTracksTotal(rows, rowGap)+paddingAndBorderColumn, ownerHeight, ownerWidth), DimensionHeight)
+	}
+	contentWidth := node.getLayout().measuredDimension(DimensionWidth) - paddingAndBorderRow
+	contentHeight := node.getLayout().measuredDimension(DimensionHeight) - paddingAndBorderColumn
+	node.setLayoutHadOverflow(gridTracksTotal(cols, columnGap) > contentWidth+0.5 ||
+		gridTracksTotal(rows, rowGap) > contentHeight+0.5)
+
+	if !performLayout {
+		return
+	}
+
+	positionGridTracks(cols, columnGap, contentWidth, gridDistributionFromJustify(style.justifyContent()))
+	positionGridTracks(rows, rowGap, contentHeight, gridDistributionFromAlign(style.alignContent()))
+
+	rtl := direction == DirectionRTL
+	layout := node.getLayout()
+	left := layout.border(EdgeLeft) + layout.padding(EdgeLeft)
+	top := layout.border(EdgeTop) + layout.padding(EdgeTop)
+	for _, it := range items {
+		child := it.node
+		childDirection := child.resolveDirection(direction)
+		areaX, areaW := gridArea(cols, it.col)
+		areaY, areaH := gridArea(rows, it.row)
+		if rtl {
+			areaX = contentWidth - areaX - areaW
+		}
+		marginLeft := If(rtl, child.getInlineEndMargin(FlexDirectionRow, childDirection, innerWidth), child.getInlineStartMargin(FlexDirectionRow, childDirection, innerWidth))
+		marginRight := child.getMarginForAxis(FlexDirectionRow, innerWidth) - marginLeft
+		marginTop := child.getInlineStartMargin(FlexDirectionColumn, childDirection, innerWidth)
+		marginBottom := child.getInlineEndMargin(FlexDirectionColumn, childDirection, innerWidth)
+
+		// Inline axis: stretch unless the item has a definite width (then start-aligned).
+		w := itemWidth(it)
+		x := areaX + marginLeft
+		if rtl {
+			x = areaX + areaW - marginRight - w
+		}
+
+		// Block axis: align-self / align-items.
+		align := resolveChildAlignment(node, child)
+		h, definiteH := gridChildDimension(child, FlexDirectionColumn, innerHeight)
+		if !definiteH {
+			if align == AlignStretch {
+				h = max(areaH-marginTop-marginBottom, 0)
+			} else {
+				measure(child, w, Undefined, MeasureModeExactly, MeasureModeUndefined)
+				h = child.getLayout().measuredDimension(DimensionHeight)
+			}
+		}
+		y := areaY + marginTop
+		switch align {
+		case AlignCenter:
+			y += (areaH - marginTop - marginBottom - h) / 2
+		case AlignFlexEnd:
+			y = areaY + areaH - marginBottom - h
+		}
+
+		calculateLayoutInternal(child, w, h, direction, MeasureModeExactly, MeasureModeExactly,
+			innerWidth, innerHeight, true, LayoutPassReasonFlexLayout, layoutMarkerData, depth, generationCount)
+
+		relX := child.relativePosition(FlexDirectionRow, childDirection, innerWidth)
+		if rtl {
+			relX = -relX
+		}
+		relY := child.relativePosition(FlexDirectionColumn, childDirection, innerHeight)
+		child.setLayoutPosition(left+x+relX, EdgeLeft)
+		child.setLayoutPosition(top+y+relY, EdgeTop)
+	}
+
+	// Absolutely positioned children are laid out against the grid container's padding box.
+	for _, child := range node.GetChildren() {
+		if child.getStyle().display() == DisplayNone ||
+			child.getStyle().positionType() != PositionTypeAbsolute {
+			continue
+		}
+		child.setPosition(child.resolveDirection(direction), contentWidth, contentHeight, contentWidth)
+		layoutAbsoluteChild(node, child, contentWidth, widthMeasureMode, contentHeight,
+			direction, layoutMarkerData, depth, generationCount)
+	}
+}
@@ -0,0 +1,213 @@
+package yoga
+
+import "testing"
+
+func newGridRoot(w, h float32, cols ...GridTrack) *Node {
+	root := NewNode()
+	root.StyleSetDisplay(DisplayGrid)
+	if w > 0 {
+		root.StyleSetWidth(w)
+	}
+	if h > 0 {
+		root.StyleSetHeight(h)
+	}
+	root.StyleSetGridTemplateColumns(cols...)
+	return root
+}
+
+func addGridChild(root *Node, w, h float32) *Node {
+	child := NewNode()
+	if w > 0 {
+		child.StyleSetWidth(w)
+	}
+	if h > 0 {
+		child.StyleSetHeight(h)
+	}
+	root.InsertChild(child, root.GetChildCount())
+	return child
+}
+
+func expectBox(t *testing.T, name string, n *Node, x, y, w, h float32) {
+	t.Helper()
+	if !inexactEqual(n.LayoutLeft(), x) || !inexactEqual(n.LayoutTop(), y) ||
+		!inexactEqual(n.LayoutWidth(), w) || !inexactEqual(n.LayoutHeight(), h) {
+		t.Fatalf("%s = (%v,%v %vx%v), want (%v,%v %vx%v)", name,
+			n.LayoutLeft(), n.LayoutTop(), n.LayoutWidth(), n.LayoutHeight(), x, y, w, h)
+	}
+}
+
+// 固定列 + fr 列 + 列间距：fr 分剩余空间，子项按行自动排布。
+func TestGridFixedAndFrColumns(t *testing.T) {
+	root := newGridRoot(300, 0, GridTrackPoints(100), GridTrackFr(1), GridTrackFr(2))
+	root.StyleSetGap(GutterColumn, 20)
+	root.StyleSetGap(GutterRow, 10)
+	var kids []*Node
+	for i := 0; i < 4; i++ {
+		kids = append(kids, addGridChild(root, 0, 30))
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// 剩余 300-100-40=160 → 1fr=53.33, 2fr=106.67
+	expectBox(t, "k0", kids[0], 0, 0, 100, 30)
+	expectBox(t, "k1", kids[1], 120, 0, 160.0/3, 30)
+	expectBox(t, "k2", kids[2], 120+160.0/3+20, 0, 320.0/3, 30)
+	expectBox(t, "k3", kids[3], 0, 40, 100, 30) // 第二行
+	if root.LayoutHeight() != 70 {
+		t.Fatalf("grid height %v want 70", root.LayoutHeight())
+	}
+}
+
+// 按线定位与跨列：显式放置的项占位，自动项绕开它们。
+func TestGridLinePlacementAndSpan(t *testing.T) {
+	root := newGridRoot(300, 0, GridTrackFr(1), GridTrackFr(1), GridTrackFr(1))
+	header := addGridChild(root, 0, 20)
+	header.StyleSetGridColumn(GridLineAt(1), GridLineAt(-1)) // 整行
+	side := addGridChild(root, 0, 50)
+	side.StyleSetGridRow(GridLineAt(2), GridLineSpan(2))
+	side.StyleSetGridColumn(GridLineAt(3), GridLineAuto())
+	a := addGridChild(root, 0, 20)
+	b := addGridChild(root, 0, 20)
+	c := addGridChild(root, 0, 20)
+	wide := addGridChild(root, 0, 20)
+	wide.StyleSetGridColumn(GridLineSpan(2), GridLineAuto())
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	expectBox(t, "header", header, 0, 0, 300, 20)
+	if side.LayoutLeft() != 200 || side.LayoutTop() != 20 {
+		t.Fatalf("side at (%v,%v) want (200,20)", side.LayoutLeft(), side.LayoutTop())
+	}
+	// a、b 填第 2 行的前两列，c 到第 3 行第 1 列，wide 放不进第 3 行剩下的一列 → 第 4 行
+	if a.LayoutLeft() != 0 || b.LayoutLeft() != 100 || a.LayoutTop() != b.LayoutTop() {
+		t.Fatalf("a=(%v,%v) b=(%v,%v)", a.LayoutLeft(), a.LayoutTop(), b.LayoutLeft(), b.LayoutTop())
+	}
+	if c.LayoutLeft() != 0 || c.LayoutTop() <= a.LayoutTop() {
+		t.Fatalf("c=(%v,%v) 应在下一行第一列", c.LayoutLeft(), c.LayoutTop())
+	}
+	if wide.LayoutWidth() != 200 || wide.LayoutTop() <= c.LayoutTop() {
+		t.Fatalf("wide=(%v,%v w=%v) 应跨两列并换到新行", wide.LayoutLeft(), wide.LayoutTop(), wide.LayoutWidth())
+	}
+}
+
+// dense 会回填前面留下的空洞。
+func TestGridAutoFlowDense(t *testing.T) {
+	build := func(flow GridAutoFlow) *Node {
+		root := newGridRoot(300, 0, GridTrackFr(1), GridTrackFr(1), GridTrackFr(1))
+		root.StyleSetGridAutoFlow(flow)
+		addGridChild(root, 0, 10)
+		wide := addGridChild(root, 0, 10)
+		wide.StyleSetGridColumn(GridLineSpan(3), GridLineAuto())
+		last := addGridChild(root, 0, 10)
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+		return last
+	}
+	if last := build(GridAutoFlowRow); last.LayoutTop() != 20 {
+		t.Fatalf("sparse: last top=%v want 20（在宽项之后）", last.LayoutTop())
+	}
+	if last := build(GridAutoFlowRowDense); last.LayoutTop() != 0 || last.LayoutLeft() != 100 {
+		t.Fatalf("dense: last=(%v,%v) want (100,0)（回填第一行）", last.LayoutLeft(), last.LayoutTop())
+	}
+}
+
+// column 流：先填满一列再换下一列。
+func TestGridAutoFlowColumn(t *testing.T) {
+	root := newGridRoot(200, 0, GridTrackFr(1), GridTrackFr(1))
+	root.StyleSetGridTemplateRows(GridTrackPoints(30), GridTrackPoints(30))
+	root.StyleSetGridAutoFlow(GridAutoFlowColumn)
+	var kids []*Node
+	for i := 0; i < 3; i++ {
+		kids = append(kids, addGridChild(root, 0, 0))
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	expectBox(t, "k0", kids[0], 0, 0, 100, 30)
+	expectBox(t, "k1", kids[1], 0, 30, 100, 30)
+	expectBox(t, "k2", kids[2], 100, 0, 100, 30)
+}
+
+// auto 列按内容取宽；minmax 给下限；没有 fr 时 auto 列吃掉剩余空间。
+func TestGridAutoAndMinMaxTracks(t *testing.T) {
+	root := newGridRoot(400, 0, GridTrackAuto(), GridTrackMinMax(GridSizePoints(50), GridSizePoints(80)), GridTrackAuto())
+	a := addGridChild(root, 60, 10)
+	b := addGridChild(root, 0, 10)
+	c := addGridChild(root, 100, 10)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// 内容：60 / 80（minmax 上限）/ 100，剩余 160 由两个 auto 列平分
+	if b.LayoutLeft() != 140 || b.LayoutWidth() != 80 {
+		t.Fatalf("b=(%v w=%v) want (140 w=80)", b.LayoutLeft(), b.LayoutWidth())
+	}
+	if a.LayoutWidth() != 60 || c.LayoutLeft() != 220 {
+		t.Fatalf("a.w=%v c.left=%v want 60, 220", a.LayoutWidth(), c.LayoutLeft())
+	}
+
+	// 宽度不定时（max-content）：grid 的宽就是各列内容之和
+	shrink := newGridRoot(0, 0, GridTrackAuto(), GridTrackAuto())
+	addGridChild(shrink, 40, 10)
+	addGridChild(shrink, 70, 10)
+	CalculateLayout(shrink, Undefined, Undefined, DirectionLTR)
+	if shrink.LayoutWidth() != 110 {
+		t.Fatalf("max-content 宽 %v want 110", shrink.LayoutWidth())
+	}
+}
+
+// 行高取该行最高的项；align-items 控制项在格子里的纵向位置。
+func TestGridRowSizingAndAlignment(t *testing.T) {
+	root := newGridRoot(200, 0, GridTrackFr(1), GridTrackFr(1))
+	root.StyleSetAlignItems(AlignCenter)
+	small := addGridChild(root, 0, 20)
+	tall := addGridChild(root, 0, 60)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	expectBox(t, "tall", tall, 100, 0, 100, 60)
+	expectBox(t, "small", small, 0, 20, 100, 20)
+	if root.LayoutHeight() != 60 {
+		t.Fatalf("height %v want 60", root.LayoutHeight())
+	}
+}
+
+// RTL：第一列在右边。
+func TestGridRTL(t *testing.T) {
+	root := newGridRoot(300, 0, GridTrackPoints(100), GridTrackFr(1))
+	first := addGridChild(root, 0, 10)
+	second := addGridChild(root, 0, 10)
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+	expectBox(t, "first", first, 200, 0, 100, 10)
+	expectBox(t, "second", second, 0, 0, 200, 10)
+}
+
+// padding 与 justify-content：固定列总宽小于容器时按 center 分配。
+func TestGridPaddingAndJustifyContent(t *testing.T) {
+	root := newGridRoot(300, 0, GridTrackPoints(50), GridTrackPoints(50))
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetJustifyContent(JustifyCenter)
+	a := addGridChild(root, 0, 10)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	// 内容盒 280，列共 100 → 左侧留 90，再加 padding 10
+	expectBox(t, "a", a, 100, 10, 50, 10)
+}
+
+// 网格求最小内容贡献时给测量函数传 MeasureModeMinContent，不借用 at-most 0。
+func TestGridMinContentMeasureMode(t *testing.T) {
+	root := newGridRoot(50, 100, GridTrackAuto())
+	child := NewNode()
+	var modes []MeasureMode
+	child.SetMeasureFunc(func(_ *Node, w float32, wm MeasureMode, _ float32, _ MeasureMode) Size {
+		modes = append(modes, wm)
+		if wm == MeasureModeMinContent {
+			return Size{Width: 30, Height: 20}
+		}
+		return Size{Width: 120, Height: 10}
+	})
+	root.InsertChild(child, 0)
+	root.CalculateLayout(Undefined, Undefined, DirectionLTR)
+	sawMin := false
+	for _, m := range modes {
+		if m == MeasureModeMinContent {
+			sawMin = true
+		}
+		if m == MeasureModeAtMost {
+			t.Fatalf("measure func got at-most; min-content must use MeasureModeMinContent: %v", modes)
+		}
+	}
+	if !sawMin {
+		t.Fatalf("grid never asked for min-content: %v", modes)
+	}
+}
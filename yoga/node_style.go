@@ -506,3 +506,86 @@ func (n *Node) StyleSetAspectRatio(aspectRatio float32) {
 func (n *Node) StyleGetAspectRatio() float32 {
 	return If(n.getStyle().aspectRatio().isUndefined(), Undefined, n.getStyle().aspectRatio().unwrap())
 }
+
+// StyleSetGridTemplateColumns
+func (n *Node) StyleSetGridTemplateColumns(tracks ...GridTrack) {
+	if !gridTracksEqual(n.getStyle().gridTemplateColumns_, tracks) {
+		n.getStyle().gridTemplateColumns_ = append([]GridTrack(nil), tracks...)
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleGetGridTemplateColumns
+func (n *Node) StyleGetGridTemplateColumns() []GridTrack {
+	return n.getStyle().gridTemplateColumns()
+}
+
+// StyleSetGridTemplateRows
+func (n *Node) StyleSetGridTemplateRows(tracks ...GridTrack) {
+	if !gridTracksEqual(n.getStyle().gridTemplateRows_, tracks) {
+		n.getStyle().gridTemplateRows_ = append([]GridTrack(nil), tracks...)
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleGetGridTemplateRows
+func (n *Node) StyleGetGridTemplateRows() []GridTrack {
+	return n.getStyle().gridTemplateRows()
+}
+
+// StyleSetGridAutoColumns
+func (n *Node) StyleSetGridAutoColumns(track GridTrack) {
+	if n.getStyle().gridAutoColumns_ != track {
+		n.getStyle().gridAutoColumns_ = track
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleSetGridAutoRows
+func (n *Node) StyleSetGridAutoRows(track GridTrack) {
+	if n.getStyle().gridAutoRows_ != track {
+		n.getStyle().gridAutoRows_ = track
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleSetGridAutoFlow
+func (n *Node) StyleSetGridAutoFlow(flow GridAutoFlow) {
+	if n.getStyle().gridAutoFlow_ != flow {
+		n.getStyle().gridAutoFlow_ = flow
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleGetGridAutoFlow
+func (n *Node) StyleGetGridAutoFlow() GridAutoFlow {
+	return n.getStyle().gridAutoFlow()
+}
+
+// StyleSetGridColumn sets grid-column-start / grid-column-end.
+func (n *Node) StyleSetGridColumn(start, end GridLine) {
+	s := n.getStyle()
+	if s.gridColumnStart_ != start || s.gridColumnEnd_ != end {
+		s.gridColumnStart_, s.gridColumnEnd_ = start, end
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleGetGridColumn
+func (n *Node) StyleGetGridColumn() (start, end GridLine) {
+	return n.getStyle().gridColumnStart_, n.getStyle().gridColumnEnd_
+}
+
+// StyleSetGridRow sets grid-row-start / grid-row-end.
+func (n *Node) StyleSetGridRow(start, end GridLine) {
+	s := n.getStyle()
+	if s.gridRowStart_ != start || s.gridRowEnd_ != end {
+		s.gridRowStart_, s.gridRowEnd_ = start, end
+		n.markDirtyAndPropagate()
+	}
+}
+
+// StyleGetGridRow
+func (n *Node) StyleGetGridRow() (start, end GridLine) {
+	return n.getStyle().gridRowStart_, n.getStyle().gridRowEnd_
+}
@@ -26,6 +26,17 @@ type Style struct {
 	maxDimensions_ [DimensionCount]CompactValue
 	// Yoga specific properties, not compatible with flexbox specification
 	aspectRatio_ FloatOptional
+
+	// CSS Grid (display: grid). Zero values mean auto, so defaultStyle needs no entries.
+	gridTemplateColumns_ []GridTrack
+	gridTemplateRows_    []GridTrack
+	gridAutoColumns_     GridTrack
+	gridAutoRows_        GridTrack
+	gridAutoFlow_        GridAutoFlow
+	gridColumnStart_     GridLine
+	gridColumnEnd_       GridLine
+	gridRowStart_        GridLine
+	gridRowEnd_          GridLine
 }
 
 const (
@@ -302,6 +313,31 @@ func (s *Style) aspectRatio() FloatOptional {
 	return s.aspectRatio_
 }
 
+// gridTemplateColumns
+func (s *Style) gridTemplateColumns() []GridTrack {
+	return s.gridTemplateColumns_
+}
+
+// gridTemplateRows
+func (s *Style) gridTemplateRows() []GridTrack {
+	return s.gridTemplateRows_
+}
+
+// gridAutoColumns
+func (s *Style) gridAutoColumns() GridTrack {
+	return s.gridAutoColumns_
+}
+
+// gridAutoRows
+func (s *Style) gridAutoRows() GridTrack {
+	return s.gridAutoRows_
+}
+
+// gridAutoFlow
+func (s *Style) gridAutoFlow() GridAutoFlow {
+	return s.gridAutoFlow_
+}
+
 // resolveColumnGap
 func (s *Style) resolveColumnGap() CompactValue {
 	if s.gap_[GutterColumn].IsDefined() {
@@ -341,5 +377,14 @@ func (s *Style) equal(other *Style) bool {
 		inexactEquals(s.dimensions_[:], other.dimensions_[:]) &&
 		inexactEquals(s.minDimensions_[:], other.minDimensions_[:]) &&
 		inexactEquals(s.maxDimensions_[:], other.maxDimensions_[:]) &&
-		inexactEquals(s.aspectRatio_, other.aspectRatio_)
+		inexactEquals(s.aspectRatio_, other.aspectRatio_) &&
+		gridTracksEqual(s.gridTemplateColumns_, other.gridTemplateColumns_) &&
+		gridTracksEqual(s.gridTemplateRows_, other.gridTemplateRows_) &&
+		s.gridAutoColumns_ == other.gridAutoColumns_ &&
+		s.gridAutoRows_ == other.gridAutoRows_ &&
+		s.gridAutoFlow_ == other.gridAutoFlow_ &&
+		s.gridColumnStart_ == other.gridColumnStart_ &&
+		s.gridColumnEnd_ == other.gridColumnEnd_ &&
+		s.gridRowStart_ == other.gridRowStart_ &&
+		s.gridRowEnd_ == other.gridRowEnd_
 }